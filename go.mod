@@ -31,18 +31,56 @@ require (
 	github.com/aws/aws-sdk-go-v2 v1.16.6
 	github.com/aws/aws-sdk-go-v2/config v1.15.12
 	github.com/aws/aws-sdk-go-v2/credentials v1.12.7
+	github.com/aws/aws-sdk-go-v2/feature/ec2/imds v1.12.7
+	github.com/aws/aws-sdk-go-v2/service/apigateway v1.15.6
+	github.com/aws/aws-sdk-go-v2/service/apigatewayv2 v1.12.7
+	github.com/aws/aws-sdk-go-v2/service/appsync v1.14.5
+	github.com/aws/aws-sdk-go-v2/service/athena v1.15.0
+	github.com/aws/aws-sdk-go-v2/service/backup v1.16.3
+	github.com/aws/aws-sdk-go-v2/service/budgets v1.12.5
+	github.com/aws/aws-sdk-go-v2/service/cloudfront v1.18.0
 	github.com/aws/aws-sdk-go-v2/service/cloudwatch v1.18.2
 	github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs v1.15.5
+	github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider v1.17.0
+	github.com/aws/aws-sdk-go-v2/service/configservice v1.21.0
 	github.com/aws/aws-sdk-go-v2/service/costexplorer v1.18.4
+	github.com/aws/aws-sdk-go-v2/service/directconnect v1.17.7
+	github.com/aws/aws-sdk-go-v2/service/docdb v1.18.2
+	github.com/aws/aws-sdk-go-v2/service/dynamodb v1.15.7
 	github.com/aws/aws-sdk-go-v2/service/ec2 v1.36.1
+	github.com/aws/aws-sdk-go-v2/service/ecs v1.18.9
+	github.com/aws/aws-sdk-go-v2/service/efs v1.17.0
+	github.com/aws/aws-sdk-go-v2/service/eks v1.21.0
+	github.com/aws/aws-sdk-go-v2/service/elasticache v1.21.0
 	github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2 v1.18.4
+	github.com/aws/aws-sdk-go-v2/service/emr v1.20.0
+	github.com/aws/aws-sdk-go-v2/service/eventbridge v1.16.0
+	github.com/aws/aws-sdk-go-v2/service/fsx v1.24.2
+	github.com/aws/aws-sdk-go-v2/service/glue v1.25.0
+	github.com/aws/aws-sdk-go-v2/service/health v1.15.1
 	github.com/aws/aws-sdk-go-v2/service/iam v1.18.4
+	github.com/aws/aws-sdk-go-v2/service/kafka v1.17.6
+	github.com/aws/aws-sdk-go-v2/service/lambda v1.23.0
+	github.com/aws/aws-sdk-go-v2/service/mq v1.13.3
+	github.com/aws/aws-sdk-go-v2/service/neptune v1.16.5
+	github.com/aws/aws-sdk-go-v2/service/opensearch v1.9.5
 	github.com/aws/aws-sdk-go-v2/service/organizations v1.15.2
+	github.com/aws/aws-sdk-go-v2/service/pi v1.14.0
 	github.com/aws/aws-sdk-go-v2/service/rds v1.20.1
+	github.com/aws/aws-sdk-go-v2/service/redshift v1.25.0
 	github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi v1.13.5
+	github.com/aws/aws-sdk-go-v2/service/route53 v1.21.0
+	github.com/aws/aws-sdk-go-v2/service/route53resolver v1.14.0
 	github.com/aws/aws-sdk-go-v2/service/s3 v1.26.12
+	github.com/aws/aws-sdk-go-v2/service/sagemaker v1.34.0
+	github.com/aws/aws-sdk-go-v2/service/servicequotas v1.12.0
+	github.com/aws/aws-sdk-go-v2/service/ses v1.13.0
+	github.com/aws/aws-sdk-go-v2/service/sfn v1.13.7
+	github.com/aws/aws-sdk-go-v2/service/shield v1.16.7
 	github.com/aws/aws-sdk-go-v2/service/sqs v1.18.4
 	github.com/aws/aws-sdk-go-v2/service/sts v1.16.8
+	github.com/aws/aws-sdk-go-v2/service/wafv2 v1.20.0
+	github.com/aws/aws-sdk-go-v2/service/workspaces v1.19.1
 	github.com/awslabs/goformation/v4 v4.1.0
 	github.com/blakesmith/ar v0.0.0-20150311145944-8bd4349a67f2
 	github.com/bsm/sarama-cluster v2.1.14-0.20180625083203-7e67d87a6b3f+incompatible
@@ -227,13 +265,13 @@ require (
 	github.com/armon/go-radix v1.0.0 // indirect
 	github.com/aws/aws-sdk-go v1.38.60 // indirect
 	github.com/aws/aws-sdk-go-v2/aws/protocol/eventstream v1.4.3 // indirect
-	github.com/aws/aws-sdk-go-v2/feature/ec2/imds v1.12.7 // indirect
 	github.com/aws/aws-sdk-go-v2/internal/configsources v1.1.13 // indirect
 	github.com/aws/aws-sdk-go-v2/internal/endpoints/v2 v2.4.7 // indirect
 	github.com/aws/aws-sdk-go-v2/internal/ini v1.3.14 // indirect
 	github.com/aws/aws-sdk-go-v2/internal/v4a v1.0.4 // indirect
 	github.com/aws/aws-sdk-go-v2/service/internal/accept-encoding v1.9.3 // indirect
 	github.com/aws/aws-sdk-go-v2/service/internal/checksum v1.1.8 // indirect
+	github.com/aws/aws-sdk-go-v2/service/internal/endpoint-discovery v1.7.6 // indirect
 	github.com/aws/aws-sdk-go-v2/service/internal/presigned-url v1.9.7 // indirect
 	github.com/aws/aws-sdk-go-v2/service/internal/s3shared v1.13.7 // indirect
 	github.com/aws/aws-sdk-go-v2/service/sso v1.11.10 // indirect
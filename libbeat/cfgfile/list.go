@@ -32,6 +32,26 @@ import (
 	"github.com/elastic/elastic-agent-libs/logp"
 )
 
+// SplitConfig is the config of one of the independent runners a config is
+// split in by a ConfigSplitter.
+type SplitConfig struct {
+	// Name identifies the part of the parent config run by the runner.
+	Name   string
+	Config *config.C
+}
+
+// ConfigSplitter is implemented by the RunnerFactories whose configs can opt
+// in to be split in the configs of independent runners. RunnerList keys these
+// runners on the config of their part, so on reload only the runners of the
+// parts of a config that changed are restarted.
+type ConfigSplitter interface {
+	// SplitConfig returns the parts of a config, or none if the config isn't
+	// split. The config of a part only contains the settings of the parent
+	// config used by its runner, so changing the settings of the other parts
+	// doesn't restart it.
+	SplitConfig(config *config.C) (parts []SplitConfig, err error)
+}
+
 // RunnerList implements a reloadable.List of Runners
 type RunnerList struct {
 	runners  map[uint64]Runner
@@ -63,15 +83,11 @@ func (r *RunnerList) Reload(configs []*reload.ConfigWithMeta) error {
 
 	r.logger.Debugf("Starting reload procedure, current runners: %d", len(stopList))
 
-	// diff current & desired state, create action lists
-	for _, config := range configs {
-		hash, err := HashConfig(config.Config)
-		if err != nil {
-			r.logger.Errorf("Unable to hash given config: %s", err)
-			errs = append(errs, errors.Wrap(err, "Unable to hash given config"))
-			continue
-		}
+	runnerConfigs, hashErrs := r.runnerConfigs(configs)
+	errs = append(errs, hashErrs...)
 
+	// diff current & desired state, create action lists
+	for hash, config := range runnerConfigs {
 		if _, ok := r.runners[hash]; ok {
 			delete(stopList, hash)
 		} else {
@@ -173,6 +189,43 @@ func HashConfig(c *config.C) (uint64, error) {
 	return hashstructure.Hash(config, nil)
 }
 
+// runnerConfigs returns the configs of the runners of the given configs, by
+// the hash identifying their runners. Configs are split with the factory if
+// it implements ConfigSplitter, and identical configs or parts run in a single
+// runner.
+func (r *RunnerList) runnerConfigs(configs []*reload.ConfigWithMeta) (map[uint64]*reload.ConfigWithMeta, multierror.Errors) {
+	var errs multierror.Errors
+	splitter, _ := r.factory.(ConfigSplitter)
+	result := make(map[uint64]*reload.ConfigWithMeta, len(configs))
+	for _, c := range configs {
+		runnerConfigs := []*reload.ConfigWithMeta{c}
+		if splitter != nil {
+			parts, err := splitter.SplitConfig(c.Config)
+			if err != nil {
+				r.logger.Debugf("Unable to split config, keeping it as is: %s", err)
+				parts = nil
+			}
+			if len(parts) > 0 {
+				runnerConfigs = make([]*reload.ConfigWithMeta, 0, len(parts))
+				for _, part := range parts {
+					runnerConfigs = append(runnerConfigs, &reload.ConfigWithMeta{Config: part.Config, Meta: c.Meta})
+				}
+			}
+		}
+
+		for _, runnerConfig := range runnerConfigs {
+			hash, err := HashConfig(runnerConfig.Config)
+			if err != nil {
+				r.logger.Errorf("Unable to hash given config: %s", err)
+				errs = append(errs, errors.Wrap(err, "Unable to hash given config"))
+				continue
+			}
+			result[hash] = runnerConfig
+		}
+	}
+	return result, errs
+}
+
 func (r *RunnerList) copyRunnerList() map[uint64]Runner {
 	list := make(map[uint64]Runner, len(r.runners))
 	for k, v := range r.runners {
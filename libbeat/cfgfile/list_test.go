@@ -18,6 +18,7 @@
 package cfgfile
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/pkg/errors"
@@ -94,6 +95,34 @@ func (r *runnerFactory) CheckConfig(config *conf.C) error {
 	return nil
 }
 
+// splittingRunnerFactory splits the configs with a list of ids in one config
+// per id, with the setting of the id in settings.id<id>.
+type splittingRunnerFactory struct {
+	runnerFactory
+}
+
+func (r *splittingRunnerFactory) SplitConfig(c *conf.C) ([]SplitConfig, error) {
+	config := struct {
+		IDs      []int64           `config:"ids"`
+		Settings map[string]string `config:"settings"`
+	}{}
+	if err := c.Unpack(&config); err != nil {
+		return nil, err
+	}
+
+	var parts []SplitConfig
+	for _, id := range config.IDs {
+		part := createConfig(id).Config
+		if setting, found := config.Settings[fmt.Sprint("id", id)]; found {
+			if err := part.SetString("setting", -1, setting); err != nil {
+				return nil, err
+			}
+		}
+		parts = append(parts, SplitConfig{Name: fmt.Sprint(id), Config: part})
+	}
+	return parts, nil
+}
+
 func TestNewConfigs(t *testing.T) {
 	factory := &runnerFactory{}
 	list := NewRunnerList("", factory, nil)
@@ -281,6 +310,68 @@ func TestCreateRunnerAddsDynamicMeta(t *testing.T) {
 	}
 }
 
+func TestReloadSplitConfigs(t *testing.T) {
+	factory := &splittingRunnerFactory{}
+	list := NewRunnerList("", factory, nil)
+
+	list.Reload([]*reload.ConfigWithMeta{
+		createMultiConfig(1, 2),
+	})
+	state := list.copyRunnerList()
+	assert.Equal(t, 2, len(state))
+
+	// Only the runner of the removed id is stopped
+	list.Reload([]*reload.ConfigWithMeta{
+		createMultiConfig(1, 3),
+	})
+	assert.Equal(t, 2, len(list.copyRunnerList()))
+	assert.Equal(t, 3, len(factory.runners))
+	for _, r := range factory.runners {
+		r := r.(*runner)
+		assert.True(t, r.started)
+		assert.Equal(t, r.id == 2, r.stopped, "runner %d", r.id)
+	}
+
+	// Only the runner of the part whose setting changed is restarted
+	stopped := map[int64]int{}
+	for _, r := range factory.runners {
+		r := r.(*runner)
+		r.OnStop = func() { stopped[r.id]++ }
+	}
+	list.Reload([]*reload.ConfigWithMeta{
+		withSetting(createMultiConfig(1, 3), 3, "changed"),
+	})
+	assert.Equal(t, 2, len(list.copyRunnerList()))
+	assert.Equal(t, 4, len(factory.runners))
+	assert.Equal(t, map[int64]int{3: 1}, stopped)
+
+	// Identical parts of different configs run in a single runner, like
+	// identical configs
+	list.Reload([]*reload.ConfigWithMeta{
+		withSetting(createMultiConfig(1, 3), 3, "changed"),
+		createMultiConfig(1, 4),
+	})
+	assert.Equal(t, 3, len(list.copyRunnerList()))
+	assert.Equal(t, 5, len(factory.runners))
+}
+
+func createMultiConfig(ids ...int64) *reload.ConfigWithMeta {
+	c := conf.NewConfig()
+	for i, id := range ids {
+		c.SetInt("ids", i, id)
+	}
+	return &reload.ConfigWithMeta{
+		Config: c,
+	}
+}
+
+// withSetting sets the setting of the part of id in a config created with
+// createMultiConfig.
+func withSetting(c *reload.ConfigWithMeta, id int64, setting string) *reload.ConfigWithMeta {
+	c.Config.SetString(fmt.Sprint("settings.id", id), -1, setting)
+	return c
+}
+
 func createConfig(id int64) *reload.ConfigWithMeta {
 	c := conf.NewConfig()
 	c.SetInt("id", -1, id)
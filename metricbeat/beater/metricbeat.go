@@ -202,6 +202,9 @@ func newMetricbeat(b *beat.Beat, c *conf.C, options ...Option) (*Metricbeat, err
 func (bt *Metricbeat) Run(b *beat.Beat) error {
 	var wg sync.WaitGroup
 
+	// Report the health of the metricsets to Elastic Agent
+	module.SetHealthReporter(b.Manager)
+
 	// Static modules (metricbeat.runners)
 	for _, r := range bt.runners {
 		r.Start()
@@ -0,0 +1,150 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package cache provides caches for the MetricSets.
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// defaultFetchTimeout is the maximum duration of the fetch of a value.
+const defaultFetchTimeout = 5 * time.Minute
+
+// errFetchFailed is returned to the callers waiting for a fetch that panicked.
+var errFetchFailed = errors.New("fetch of cached value failed")
+
+// TTLCache is a concurrency-safe cache of values that are expensive to fetch,
+// like the resources discovered from the API of a cloud provider. Values are
+// cached for a time to live, and concurrent gets of the same key wait for a
+// single fetch of its value.
+type TTLCache struct {
+	mutex        sync.Mutex
+	ttl          time.Duration
+	fetchTimeout time.Duration
+	entries      map[interface{}]*entry
+	now          func() time.Time
+}
+
+// entry is a cached value. It is in the cache while its value is fetched, so
+// concurrent gets of its key wait for the fetch in progress.
+type entry struct {
+	ready      chan struct{}
+	value      interface{}
+	err        error
+	expiration time.Time
+}
+
+// NewTTLCache returns a TTLCache caching the values for ttl.
+func NewTTLCache(ttl time.Duration) *TTLCache {
+	return &TTLCache{
+		ttl:          ttl,
+		fetchTimeout: defaultFetchTimeout,
+		entries:      map[interface{}]*entry{},
+		now:          time.Now,
+	}
+}
+
+// GetOrFetch returns the value of key, fetching it with fetch when it isn't
+// cached or it expired. Errors of fetch are returned to the callers waiting
+// for it, but they are not cached. Cached values are shared by all the callers
+// and must not be modified.
+//
+// The fetch is shared by the concurrent callers, so it doesn't run with the
+// context of any of them, but with a context canceled after the fetch timeout
+// of the cache. Callers stop waiting for the fetch when their ctx is done.
+func (c *TTLCache) GetOrFetch(ctx context.Context, key interface{}, fetch func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	return c.GetOrFetchWithTTL(ctx, key, c.ttl, fetch)
+}
+
+// GetOrFetchWithTTL is like GetOrFetch, but caches the fetched value for ttl
+// instead of the TTL of the cache.
+func (c *TTLCache) GetOrFetchWithTTL(ctx context.Context, key interface{}, ttl time.Duration, fetch func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	c.mutex.Lock()
+	now := c.now()
+	e, found := c.entries[key]
+	if !found || e.isExpired(now) {
+		c.removeExpired(now)
+		e = &entry{ready: make(chan struct{}), err: errFetchFailed}
+		c.entries[key] = e
+		go c.fetch(key, e, ttl, fetch)
+	}
+	c.mutex.Unlock()
+
+	select {
+	case <-e.ready:
+		return e.value, e.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// fetch fetches the value of the entry of key, and removes the entry if the
+// fetch fails.
+func (c *TTLCache) fetch(key interface{}, e *entry, ttl time.Duration, fetch func(ctx context.Context) (interface{}, error)) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.fetchTimeout)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			e.err = fmt.Errorf("%w: %v", errFetchFailed, r)
+		}
+		if e.err != nil {
+			c.mutex.Lock()
+			if c.entries[key] == e {
+				delete(c.entries, key)
+			}
+			c.mutex.Unlock()
+		}
+		close(e.ready)
+	}()
+	e.value, e.err = fetch(ctx)
+	e.expiration = c.now().Add(ttl)
+}
+
+// Delete removes the value of key from the cache, so it is fetched again on
+// the next get.
+func (c *TTLCache) Delete(key interface{}) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.entries, key)
+}
+
+// removeExpired removes the expired values from the cache. It must be called
+// with the lock held.
+func (c *TTLCache) removeExpired(now time.Time) {
+	for key, e := range c.entries {
+		if e.isExpired(now) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// isExpired returns true if the value was fetched and it expired. Values being
+// fetched never expire.
+func (e *entry) isExpired(now time.Time) bool {
+	select {
+	case <-e.ready:
+		return !now.Before(e.expiration)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,175 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTTLCache(t *testing.T) {
+	now := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	c := NewTTLCache(time.Minute)
+	c.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	calls := 0
+	fetch := func(context.Context) (interface{}, error) {
+		calls++
+		return calls, nil
+	}
+
+	value, err := c.GetOrFetch(ctx, "a", fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, value)
+	value, err = c.GetOrFetch(ctx, "a", fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, value)
+	value, _ = c.GetOrFetch(ctx, "b", fetch)
+	assert.Equal(t, 2, value)
+
+	// Values expire after their TTL
+	now = now.Add(time.Minute)
+	value, _ = c.GetOrFetch(ctx, "a", fetch)
+	assert.Equal(t, 3, value)
+	assert.Len(t, c.entries, 1, "expired values should be removed")
+
+	value, _ = c.GetOrFetchWithTTL(ctx, "c", time.Hour, fetch)
+	assert.Equal(t, 4, value)
+	now = now.Add(time.Minute)
+	value, _ = c.GetOrFetch(ctx, "c", fetch)
+	assert.Equal(t, 4, value)
+
+	c.Delete("c")
+	value, _ = c.GetOrFetch(ctx, "c", fetch)
+	assert.Equal(t, 5, value)
+
+	// Errors are not cached
+	failures := 0
+	fail := func(context.Context) (interface{}, error) {
+		failures++
+		return nil, errors.New("throttled")
+	}
+	_, err = c.GetOrFetch(ctx, "d", fail)
+	assert.Error(t, err)
+	_, err = c.GetOrFetch(ctx, "d", fail)
+	assert.Error(t, err)
+	assert.Equal(t, 2, failures)
+
+	// Panics are returned as errors, and they are not cached
+	_, err = c.GetOrFetch(ctx, "e", func(context.Context) (interface{}, error) { panic("failed") })
+	assert.ErrorIs(t, err, errFetchFailed)
+	value, err = c.GetOrFetch(ctx, "e", fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, value)
+}
+
+func TestTTLCacheConcurrentFetch(t *testing.T) {
+	c := NewTTLCache(time.Minute)
+	ctx := context.Background()
+
+	var calls int
+	var callsMutex sync.Mutex
+	started := make(chan struct{})
+	release := make(chan struct{})
+	fetch := func(context.Context) (interface{}, error) {
+		callsMutex.Lock()
+		calls++
+		callsMutex.Unlock()
+		close(started)
+		<-release
+		return "value", nil
+	}
+
+	// Concurrent gets wait for the fetch in progress
+	var wg sync.WaitGroup
+	values := make([]interface{}, 3)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		values[0], _ = c.GetOrFetch(ctx, "a", fetch)
+	}()
+	<-started
+	for i := 1; i < len(values); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			values[i], _ = c.GetOrFetch(ctx, "a", fetch)
+		}(i)
+	}
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, 1, calls)
+	for _, value := range values {
+		assert.Equal(t, "value", value)
+	}
+}
+
+func TestTTLCacheCanceledCaller(t *testing.T) {
+	c := NewTTLCache(time.Minute)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	fetch := func(ctx context.Context) (interface{}, error) {
+		close(started)
+		select {
+		case <-release:
+			return "value", nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	// The caller that started the fetch stops waiting when its context is
+	// canceled, without canceling the fetch of the other callers
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	canceled := make(chan error)
+	go func() {
+		_, err := c.GetOrFetch(canceledCtx, "a", fetch)
+		canceled <- err
+	}()
+	<-started
+
+	waiting := make(chan interface{})
+	go func() {
+		value, _ := c.GetOrFetch(context.Background(), "a", fetch)
+		waiting <- value
+	}()
+
+	cancel()
+	assert.ErrorIs(t, <-canceled, context.Canceled)
+	close(release)
+	assert.Equal(t, "value", <-waiting)
+}
+
+func TestTTLCacheFetchTimeout(t *testing.T) {
+	c := NewTTLCache(time.Minute)
+	c.fetchTimeout = 10 * time.Millisecond
+
+	_, err := c.GetOrFetch(context.Background(), "a", func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -82,6 +82,9 @@ func newBaseModuleFromConfig(rawConfig *conf.C) (BaseModule, error) {
 		return baseModule, err
 	}
 
+	// Only a timeout set in the configuration cancels the fetches
+	baseModule.config.FetchTimeout = baseModule.config.Timeout
+
 	// If timeout is not set, timeout is set to the same value as period
 	if baseModule.config.Timeout == 0 {
 		baseModule.config.Timeout = baseModule.config.Period
@@ -89,6 +92,10 @@ func newBaseModuleFromConfig(rawConfig *conf.C) (BaseModule, error) {
 
 	baseModule.name = strings.ToLower(baseModule.config.Module)
 
+	if baseModule.config.Jitter > 0 && baseModule.config.Jitter >= baseModule.config.Period {
+		return baseModule, errors.Errorf("invalid jitter for module '%s': it must be shorter than the period", baseModule.name)
+	}
+
 	err = mustNotContainDuplicates(baseModule.config.Hosts)
 	if err != nil {
 		return baseModule, errors.Wrapf(err, "invalid hosts for module '%s'", baseModule.name)
@@ -196,6 +203,7 @@ func newBaseMetricSets(r *Register, m Module) ([]BaseMetricSet, error) {
 				host:    host,
 				metrics: metrics,
 				logger:  logp.NewLogger(m.Name() + "." + name),
+				period:  &adjustedPeriod{},
 			})
 		}
 	}
@@ -245,12 +253,16 @@ func mustImplementFetcher(ms MetricSet) error {
 		ifcs = append(ifcs, "PushMetricSetV2WithContext")
 	}
 
+	if _, ok := ms.(StreamingMetricSet); ok {
+		ifcs = append(ifcs, "StreamingMetricSet")
+	}
+
 	switch len(ifcs) {
 	case 0:
 		return fmt.Errorf("MetricSet '%s/%s' does not implement an event "+
 			"producing interface ("+
 			"ReportingMetricSet, ReportingMetricSetV2, ReportingMetricSetV2Error, ReportingMetricSetV2WithContext"+
-			"PushMetricSet, PushMetricSetV2, or PushMetricSetV2WithContext)",
+			"PushMetricSet, PushMetricSetV2, PushMetricSetV2WithContext, or StreamingMetricSet)",
 			ms.Module().Name(), ms.Name())
 	case 1:
 		return nil
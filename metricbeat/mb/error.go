@@ -0,0 +1,91 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package mb
+
+import "github.com/elastic/elastic-agent-libs/mapstr"
+
+// ErrorType classifies the errors reported by MetricSets, so the error events
+// can be filtered by their cause. It is added to the error.type field of the
+// events.
+type ErrorType string
+
+// Error types shared by the MetricSets.
+const (
+	// ErrorTypeThrottled is the type of the errors of requests rejected by
+	// the monitored service because of rate limits.
+	ErrorTypeThrottled ErrorType = "throttled"
+
+	// ErrorTypeAccessDenied is the type of the errors caused by missing
+	// permissions or invalid credentials.
+	ErrorTypeAccessDenied ErrorType = "access_denied"
+
+	// ErrorTypeTimeout is the type of the errors of requests or fetches that
+	// didn't complete in time.
+	ErrorTypeTimeout ErrorType = "timeout"
+)
+
+// Error is an error with structured information about its cause. When it is
+// the error of an Event, or wraps it, its fields are added to the error fields
+// of the event, next to error.message:
+//
+//	{
+//	  "error": {
+//	    "message": "operation error CloudWatch: GetMetricData, ...",
+//	    "type": "throttled",
+//	    "code": "ThrottlingException",
+//	    "id": "b4b0a1a4-7b8a-4b0c-9d4c-1b7e2b6f0a3d"
+//	  }
+//	}
+type Error struct {
+	Type ErrorType // Type of the error, like ErrorTypeThrottled.
+	Code string    // Error code of the monitored service, like ThrottlingException.
+	ID   string    // ID of the error in the monitored service, like the ID of the failed request.
+	Err  error     // Underlying error.
+}
+
+// NewError returns an Error of the given type and service error code wrapping
+// err. It returns nil if err is nil.
+func NewError(errorType ErrorType, code string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Type: errorType, Code: code, Err: err}
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// addFields adds the structured information of the error to the error fields
+// of an event.
+func (e *Error) addFields(fields mapstr.M) {
+	if e.Type != "" {
+		fields["type"] = string(e.Type)
+	}
+	if e.Code != "" {
+		fields["code"] = e.Code
+	}
+	if e.ID != "" {
+		fields["id"] = e.ID
+	}
+}
@@ -18,6 +18,7 @@
 package mb
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -102,9 +103,14 @@ func (e *Event) BeatEvent(module, metricSet string, modifiers ...EventModifier)
 	}
 
 	if e.Error != nil {
-		b.Fields["error"] = mapstr.M{
+		errorFields := mapstr.M{
 			"message": e.Error.Error(),
 		}
+		var structuredErr *Error
+		if errors.As(e.Error, &structuredErr) {
+			structuredErr.addFields(errorFields)
+		}
+		b.Fields["error"] = errorFields
 	}
 
 	return b
@@ -22,6 +22,7 @@ package mb
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -171,6 +172,25 @@ func TestEventConversionToBeatEvent(t *testing.T) {
 		}
 		assert.Equal(t, msg, errorMessage)
 	})
+
+	t.Run("structured error", func(t *testing.T) {
+		structuredErr := &Error{
+			Type: ErrorTypeThrottled,
+			Code: "ThrottlingException",
+			ID:   "1234",
+			Err:  errors.New("rate exceeded"),
+		}
+		e := (&Event{
+			Error: fmt.Errorf("fetch failed: %w", structuredErr),
+		}).BeatEvent(module, metricSet)
+
+		assert.Equal(t, mapstr.M{
+			"message": "fetch failed: rate exceeded",
+			"type":    "throttled",
+			"code":    "ThrottlingException",
+			"id":      "1234",
+		}, e.Fields["error"])
+	})
 }
 
 func TestAddMetricSetInfo(t *testing.T) {
@@ -25,6 +25,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
@@ -257,6 +258,60 @@ type PushMetricSetV2WithContext interface {
 	Run(ctx context.Context, r ReporterV2)
 }
 
+// PeriodAdjuster is implemented by MetricSets whose period can be changed
+// while they run. It is implemented by BaseMetricSet, the scheduler of the
+// periodic MetricSets uses CurrentPeriod to schedule the next fetches.
+type PeriodAdjuster interface {
+	AdjustPeriod(period time.Duration)
+	ResetPeriod()
+	CurrentPeriod() time.Duration
+}
+
+// HealthStatus is the health status of a MetricSet.
+type HealthStatus string
+
+// Health statuses of the MetricSets.
+const (
+	HealthStatusHealthy  HealthStatus = "healthy"
+	HealthStatusDegraded HealthStatus = "degraded" // Running, but with errors or partial data.
+	HealthStatusFailed   HealthStatus = "failed"   // Not collecting any data.
+)
+
+// Health is the health of a MetricSet, with the reason of its status when it
+// isn't healthy.
+type Health struct {
+	Status HealthStatus
+	Reason string
+}
+
+// HealthChecker is implemented by MetricSets that report their health. The
+// health is checked after each fetch, or every period for the MetricSets that
+// aren't fetched periodically. It is available in the monitoring metrics of
+// the MetricSet and, when Metricbeat is managed by Elastic Agent, the Beat is
+// reported as degraded while any of its MetricSets isn't healthy.
+type HealthChecker interface {
+	CheckHealth() Health
+}
+
+// StreamingMetricSet is a MetricSet that continuously pushes the events of a
+// long-running source, like a stream of metrics or a websocket. Start is
+// invoked to connect to the source, then Run pushes the events and should block
+// until the context is closed or the source fails. Stop is invoked after each
+// Run, or after Start fails, to release the connection. When Start or Run fails
+// the error is reported and the source is started again after a backoff.
+//
+// Health is invoked periodically while the MetricSet runs, concurrently with
+// Run and with the period of the module. It returns an error when the source
+// is unhealthy, for example when no events were received for too long, and
+// the MetricSet is then reported as degraded like with HealthChecker.
+type StreamingMetricSet interface {
+	MetricSet
+	Start(ctx context.Context) error
+	Run(ctx context.Context, r ReporterV2) error
+	Stop() error
+	Health() error
+}
+
 // HostData contains values parsed from the 'host' configuration. Other
 // configuration data like protocols, usernames, and passwords may also be
 // used to construct this HostData data. HostData also contains information when combined scheme are
@@ -295,6 +350,13 @@ type BaseMetricSet struct {
 	registration MetricSetRegistration
 	metrics      *monitoring.Registry
 	logger       *logp.Logger
+	period       *adjustedPeriod
+}
+
+// adjustedPeriod is the period requested by a MetricSet with AdjustPeriod. It
+// is shared by the copies of the BaseMetricSet.
+type adjustedPeriod struct {
+	value int64 // Adjusted period in nanoseconds, 0 if not adjusted.
 }
 
 func (b *BaseMetricSet) String() string {
@@ -357,6 +419,39 @@ func (b *BaseMetricSet) Registration() MetricSetRegistration {
 	return b.registration
 }
 
+// AdjustPeriod requests the scheduler to fetch the MetricSet with the given
+// period instead of the period of the module, for example to back off while the
+// service throttles the requests. The new period is used after the next fetch.
+// Non-positive periods are ignored.
+func (b *BaseMetricSet) AdjustPeriod(period time.Duration) {
+	if b.period == nil || period <= 0 {
+		return
+	}
+	atomic.StoreInt64(&b.period.value, int64(period))
+}
+
+// ResetPeriod restores the period of the module after AdjustPeriod.
+func (b *BaseMetricSet) ResetPeriod() {
+	if b.period == nil {
+		return
+	}
+	atomic.StoreInt64(&b.period.value, 0)
+}
+
+// CurrentPeriod returns the period the MetricSet is fetched with, which is the
+// period of the module unless it was changed with AdjustPeriod.
+func (b *BaseMetricSet) CurrentPeriod() time.Duration {
+	if b.period != nil {
+		if period := atomic.LoadInt64(&b.period.value); period > 0 {
+			return time.Duration(period)
+		}
+	}
+	if b.module == nil {
+		return 0
+	}
+	return b.module.Config().Period
+}
+
 // Configuration types
 
 // ModuleConfig is the base configuration data for all Modules.
@@ -368,19 +463,30 @@ type ModuleConfig struct {
 	Hosts       []string      `config:"hosts"`
 	Period      time.Duration `config:"period"     validate:"positive"`
 	Timeout     time.Duration `config:"timeout"    validate:"positive"`
+	Jitter      time.Duration `config:"jitter"     validate:"positive"`
 	Module      string        `config:"module"     validate:"required"`
 	MetricSets  []string      `config:"metricsets"`
 	Enabled     bool          `config:"enabled"`
 	Raw         bool          `config:"raw"`
 	Query       QueryParams   `config:"query"`
 	ServiceName string        `config:"service.name"`
+
+	// FetchTimeout cancels the fetches of the MetricSets supporting
+	// cancellation when they exceed it, zero doesn't limit them. It is the
+	// timeout of the module when it is set in the configuration, the timeout
+	// defaulting to the period doesn't cancel fetches.
+	FetchTimeout time.Duration `config:",ignore"`
+
+	// MaxConcurrentFetches limits the number of MetricSets of the module
+	// fetching at the same time, zero doesn't limit them.
+	MaxConcurrentFetches int `config:"max_concurrent_fetches" validate:"positive"`
 }
 
 func (c ModuleConfig) String() string {
 	return fmt.Sprintf(`{Module:"%v", MetricSets:%v, Enabled:%v, `+
-		`Hosts:[%v hosts], Period:"%v", Timeout:"%v", Raw:%v, Query:%v}`,
+		`Hosts:[%v hosts], Period:"%v", Timeout:"%v", FetchTimeout:"%v", Jitter:"%v", MaxConcurrentFetches:%v, Raw:%v, Query:%v}`,
 		c.Module, c.MetricSets, c.Enabled, len(c.Hosts), c.Period, c.Timeout,
-		c.Raw, c.Query)
+		c.FetchTimeout, c.Jitter, c.MaxConcurrentFetches, c.Raw, c.Query)
 }
 
 func (c ModuleConfig) GoString() string { return c.String() }
@@ -319,6 +319,73 @@ func TestNewBaseModuleFromModuleConfigStruct(t *testing.T) {
 	assert.Empty(t, baseModule.Config().Hosts)
 }
 
+// TestNewBaseModuleFromConfigJitter verifies that the jitter must be shorter
+// than the period.
+func TestNewBaseModuleFromConfigJitter(t *testing.T) {
+	c := newConfig(t, map[string]interface{}{
+		"module":     moduleName,
+		"metricsets": []string{metricSetName},
+		"period":     "10s",
+		"jitter":     "2s",
+	})
+	baseModule, err := newBaseModuleFromConfig(c)
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Second, baseModule.Config().Jitter)
+
+	c = newConfig(t, map[string]interface{}{
+		"module":     moduleName,
+		"metricsets": []string{metricSetName},
+		"period":     "10s",
+		"jitter":     "10s",
+	})
+	_, err = newBaseModuleFromConfig(c)
+	assert.Error(t, err)
+}
+
+// TestNewBaseModuleFromConfigFetchTimeout verifies that only a timeout set in
+// the configuration limits the fetches.
+func TestNewBaseModuleFromConfigFetchTimeout(t *testing.T) {
+	c := newConfig(t, map[string]interface{}{
+		"module":     moduleName,
+		"metricsets": []string{metricSetName},
+		"period":     "10s",
+		"timeout":    "2s",
+	})
+	baseModule, err := newBaseModuleFromConfig(c)
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Second, baseModule.Config().FetchTimeout)
+
+	c = newConfig(t, map[string]interface{}{
+		"module":     moduleName,
+		"metricsets": []string{metricSetName},
+		"period":     "10s",
+	})
+	baseModule, err = newBaseModuleFromConfig(c)
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Second, baseModule.Config().Timeout)
+	assert.Zero(t, baseModule.Config().FetchTimeout)
+}
+
+func TestBaseMetricSetAdjustPeriod(t *testing.T) {
+	ms := newTestMetricSet(t, newTestRegistry(t), map[string]interface{}{
+		"module":     moduleName,
+		"metricsets": []string{metricSetName},
+		"period":     "1m",
+	})
+	adjuster, ok := ms.(PeriodAdjuster)
+	require.True(t, ok, "PeriodAdjuster not implemented")
+	assert.Equal(t, time.Minute, adjuster.CurrentPeriod())
+
+	adjuster.AdjustPeriod(5 * time.Minute)
+	assert.Equal(t, 5*time.Minute, adjuster.CurrentPeriod())
+
+	adjuster.AdjustPeriod(0)
+	assert.Equal(t, 5*time.Minute, adjuster.CurrentPeriod())
+
+	adjuster.ResetPeriod()
+	assert.Equal(t, time.Minute, adjuster.CurrentPeriod())
+}
+
 func newTestRegistry(t testing.TB, metricSetOptions ...MetricSetOption) *Register {
 	r := NewRegister()
 
@@ -18,6 +18,8 @@
 package module
 
 import (
+	"strconv"
+
 	"github.com/elastic/beats/v7/libbeat/beat"
 	"github.com/elastic/beats/v7/libbeat/cfgfile"
 	"github.com/elastic/beats/v7/metricbeat/mb"
@@ -73,6 +75,86 @@ func (r *Factory) Create(p beat.PipelineConnector, c *conf.C) (cfgfile.Runner, e
 	return newRunnerGroup(runners), nil
 }
 
+// SplitConfig splits the module configs with independent_metricsets enabled
+// in one config per metricset, so reloading the module configs only restarts
+// the metricsets whose config changed, and the others keep running with their
+// state. The settings under the name of a metricset only apply to it, so
+// changing them doesn't restart the other metricsets. They are set in the
+// config of the metricset both under its name and at the top level, like the
+// settings of the module. The metricsets of a split config don't share a
+// module instance, so other module configs are not split.
+func (r *Factory) SplitConfig(c *conf.C) ([]cfgfile.SplitConfig, error) {
+	var config struct {
+		MetricSets            []string `config:"metricsets"`
+		IndependentMetricSets bool     `config:"independent_metricsets"`
+	}
+	if err := c.Unpack(&config); err != nil {
+		return nil, err
+	}
+	if !config.IndependentMetricSets || len(config.MetricSets) <= 1 {
+		return nil, nil
+	}
+
+	shared, err := conf.NewConfigFrom(c)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := shared.Remove("metricsets", -1); err != nil {
+		return nil, err
+	}
+
+	// Remove the settings of each metricset from the shared settings
+	metricSetSettings := map[string]*conf.C{}
+	for _, name := range config.MetricSets {
+		if !shared.HasField(name) {
+			continue
+		}
+		settings, err := shared.Child(name, -1)
+		if err != nil {
+			return nil, err
+		}
+		metricSetSettings[name] = settings
+		if _, err := shared.Remove(name, -1); err != nil {
+			return nil, err
+		}
+	}
+
+	// The parts share the fetch limiter of the module, keyed on the settings
+	// they share.
+	var sharedSettings map[string]interface{}
+	if err := shared.Unpack(&sharedSettings); err != nil {
+		return nil, err
+	}
+	sharedHash, err := hashSharedSettings(sharedSettings)
+	if err != nil {
+		return nil, err
+	}
+	if err := shared.SetString(sharedSettingsHashSetting, -1, strconv.FormatUint(sharedHash, 10)); err != nil {
+		return nil, err
+	}
+
+	parts := make([]cfgfile.SplitConfig, 0, len(config.MetricSets))
+	for _, name := range config.MetricSets {
+		metricSetConfig, err := conf.NewConfigFrom(shared)
+		if err != nil {
+			return nil, err
+		}
+		if settings, found := metricSetSettings[name]; found {
+			if err := metricSetConfig.SetChild(name, -1, settings); err != nil {
+				return nil, err
+			}
+			if err := metricSetConfig.Merge(settings); err != nil {
+				return nil, err
+			}
+		}
+		if err := metricSetConfig.SetString("metricsets", 0, name); err != nil {
+			return nil, err
+		}
+		parts = append(parts, cfgfile.SplitConfig{Name: name, Config: metricSetConfig})
+	}
+	return parts, nil
+}
+
 // CheckConfig checks if a config is valid or not
 func (r *Factory) CheckConfig(config *conf.C) error {
 	_, err := NewWrapper(config, mb.Registry, r.options...)
@@ -0,0 +1,98 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build !integration
+// +build !integration
+
+package module
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/cfgfile"
+	conf "github.com/elastic/elastic-agent-libs/config"
+)
+
+func TestFactorySplitConfig(t *testing.T) {
+	factory := NewFactory(beat.Info{})
+
+	c, err := conf.NewConfigFrom(map[string]interface{}{
+		"module":                 "fake",
+		"metricsets":             []string{"first", "second"},
+		"period":                 "1m",
+		"independent_metricsets": true,
+		"first":                  map[string]interface{}{"metrics": []string{"a"}},
+	})
+	require.NoError(t, err)
+
+	parts, err := factory.SplitConfig(c)
+	require.NoError(t, err)
+	require.Len(t, parts, 2)
+	for i, name := range []string{"first", "second"} {
+		var config struct {
+			Module     string   `config:"module"`
+			MetricSets []string `config:"metricsets"`
+			Period     string   `config:"period"`
+			Metrics    []string `config:"metrics"`
+		}
+		assert.Equal(t, name, parts[i].Name)
+		require.NoError(t, parts[i].Config.Unpack(&config))
+		assert.Equal(t, "fake", config.Module)
+		assert.Equal(t, []string{name}, config.MetricSets)
+		assert.Equal(t, "1m", config.Period)
+		assert.Equal(t, name == "first", parts[i].Config.HasField("first"))
+		assert.False(t, parts[i].Config.HasField("second"))
+		if name == "first" {
+			assert.Equal(t, []string{"a"}, config.Metrics)
+		} else {
+			assert.Empty(t, config.Metrics)
+		}
+	}
+
+	// Changing the settings of a metricset only changes its config
+	changed, err := conf.NewConfigFrom(c)
+	require.NoError(t, err)
+	require.NoError(t, changed.SetString("first.metrics", 0, "b"))
+	changedParts, err := factory.SplitConfig(changed)
+	require.NoError(t, err)
+	require.Len(t, changedParts, 2)
+	assert.NotEqual(t, hashConfig(t, parts[0].Config), hashConfig(t, changedParts[0].Config))
+	assert.Equal(t, hashConfig(t, parts[1].Config), hashConfig(t, changedParts[1].Config))
+
+	// Configs without independent_metricsets or with a single metricset are
+	// not split
+	for _, config := range []map[string]interface{}{
+		{"module": "fake", "metricsets": []string{"first", "second"}},
+		{"module": "fake", "metricsets": []string{"first"}, "independent_metricsets": true},
+	} {
+		c, err = conf.NewConfigFrom(config)
+		require.NoError(t, err)
+		parts, err = factory.SplitConfig(c)
+		require.NoError(t, err)
+		assert.Empty(t, parts)
+	}
+}
+
+func hashConfig(t *testing.T, c *conf.C) uint64 {
+	hash, err := cfgfile.HashConfig(c)
+	require.NoError(t, err)
+	return hash
+}
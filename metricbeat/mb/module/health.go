@@ -0,0 +1,143 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package module
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/elastic/beats/v7/libbeat/management"
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/elastic-agent-libs/monitoring"
+)
+
+// metricSetsHealth aggregates the health of all the running MetricSets, in the
+// state.metricsets.health metrics.
+var metricSetsHealth = newHealthAggregator(
+	monitoring.GetNamespace("state").GetRegistry().NewRegistry("metricsets").NewRegistry("health"))
+
+// SetHealthReporter sets the reporter of the aggregated health of the
+// MetricSets, like the Elastic Agent manager of the Beat. The Beat is reported
+// as degraded while any of its MetricSets isn't healthy, and as running again
+// once all of them are healthy.
+func SetHealthReporter(reporter management.StatusReporter) {
+	metricSetsHealth.setReporter(reporter)
+}
+
+// healthAggregator aggregates the health of the MetricSets.
+type healthAggregator struct {
+	sync.Mutex
+	reporter management.StatusReporter
+	health   map[string]metricSetHealth // Health of the MetricSets by ID.
+	degraded bool                       // Set while the Beat is reported as degraded.
+
+	status *monitoring.String
+	reason *monitoring.String
+}
+
+// metricSetHealth is the health of a MetricSet, with its name, like aws/cloudwatch.
+type metricSetHealth struct {
+	name string
+	mb.Health
+}
+
+func newHealthAggregator(registry *monitoring.Registry) *healthAggregator {
+	a := &healthAggregator{
+		health: map[string]metricSetHealth{},
+		status: monitoring.NewString(registry, "status"),
+		reason: monitoring.NewString(registry, "reason"),
+	}
+	a.status.Set(string(mb.HealthStatusHealthy))
+	return a
+}
+
+func (a *healthAggregator) setReporter(reporter management.StatusReporter) {
+	a.Lock()
+	defer a.Unlock()
+	a.reporter = reporter
+	a.degraded = false
+	a.report()
+}
+
+// update sets the health of a MetricSet.
+func (a *healthAggregator) update(id string, name string, health mb.Health) {
+	a.Lock()
+	defer a.Unlock()
+	a.health[id] = metricSetHealth{name: name, Health: health}
+	a.report()
+}
+
+// remove removes the health of a stopped MetricSet.
+func (a *healthAggregator) remove(id string) {
+	a.Lock()
+	defer a.Unlock()
+	if _, found := a.health[id]; !found {
+		return
+	}
+	delete(a.health, id)
+	a.report()
+}
+
+// report updates the aggregated health and reports it. The aggregated health
+// is failed when all the MetricSets failed, and degraded when some of them
+// aren't healthy. It must be called with the lock held.
+func (a *healthAggregator) report() {
+	var reasons []string
+	failed := 0
+	for _, health := range a.health {
+		if health.Status == mb.HealthStatusHealthy {
+			continue
+		}
+		if health.Status == mb.HealthStatusFailed {
+			failed++
+		}
+		reason := health.Reason
+		if reason == "" {
+			reason = string(health.Status)
+		}
+		reasons = append(reasons, fmt.Sprintf("%s: %s", health.name, reason))
+	}
+	sort.Strings(reasons)
+	reason := strings.Join(reasons, "; ")
+
+	status := mb.HealthStatusHealthy
+	switch {
+	case failed > 0 && failed == len(a.health):
+		status = mb.HealthStatusFailed
+	case len(reasons) > 0:
+		status = mb.HealthStatusDegraded
+	}
+	a.status.Set(string(status))
+	a.reason.Set(reason)
+
+	if a.reporter == nil {
+		return
+	}
+	// The Beat keeps running when its MetricSets fail, so it is only reported
+	// as degraded.
+	switch {
+	case status != mb.HealthStatusHealthy:
+		a.reporter.UpdateStatus(management.Degraded, reason)
+		a.degraded = true
+	case a.degraded:
+		a.reporter.UpdateStatus(management.Running, "Running")
+		a.degraded = false
+	}
+}
@@ -0,0 +1,79 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build !integration
+// +build !integration
+
+package module
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/beats/v7/libbeat/management"
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/elastic-agent-libs/monitoring"
+)
+
+type fakeStatusReporter struct {
+	status management.Status
+	msg    string
+}
+
+func (r *fakeStatusReporter) UpdateStatus(status management.Status, msg string) {
+	r.status = status
+	r.msg = msg
+}
+
+func TestHealthAggregator(t *testing.T) {
+	registry := monitoring.NewRegistry()
+	a := newHealthAggregator(registry)
+	reporter := &fakeStatusReporter{}
+	a.setReporter(reporter)
+
+	status := func() string { return registry.Get("status").(*monitoring.String).Get() }
+	reason := func() string { return registry.Get("reason").(*monitoring.String).Get() }
+
+	// Healthy metricsets don't modify the status of the Beat
+	a.update("1", "aws/cloudwatch", mb.Health{Status: mb.HealthStatusHealthy})
+	a.update("2", "aws/ec2", mb.Health{Status: mb.HealthStatusHealthy})
+	assert.Equal(t, "healthy", status())
+	assert.Equal(t, management.Unknown, reporter.status)
+
+	a.update("1", "aws/cloudwatch", mb.Health{Status: mb.HealthStatusDegraded, Reason: "throttled"})
+	assert.Equal(t, "degraded", status())
+	assert.Equal(t, "aws/cloudwatch: throttled", reason())
+	assert.Equal(t, management.Degraded, reporter.status)
+	assert.Equal(t, "aws/cloudwatch: throttled", reporter.msg)
+
+	a.update("2", "aws/ec2", mb.Health{Status: mb.HealthStatusFailed, Reason: "access denied"})
+	assert.Equal(t, "degraded", status())
+	assert.Equal(t, "aws/cloudwatch: throttled; aws/ec2: access denied", reason())
+
+	a.update("1", "aws/cloudwatch", mb.Health{Status: mb.HealthStatusFailed})
+	assert.Equal(t, "failed", status())
+	assert.Equal(t, "aws/cloudwatch: failed; aws/ec2: access denied", reason())
+	assert.Equal(t, management.Degraded, reporter.status)
+
+	// The Beat is running again once all the metricsets are healthy
+	a.update("1", "aws/cloudwatch", mb.Health{Status: mb.HealthStatusHealthy})
+	a.remove("2")
+	assert.Equal(t, "healthy", status())
+	assert.Equal(t, "", reason())
+	assert.Equal(t, management.Running, reporter.status)
+}
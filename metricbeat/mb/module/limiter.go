@@ -0,0 +1,146 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package module
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/mitchellh/hashstructure"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/elastic-agent-libs/logp"
+)
+
+// sharedSettingsHashSetting is set by SplitConfig in the config of each part
+// of a split module config, with the hash of the settings shared by the parts.
+const sharedSettingsHashSetting = "shared_settings_hash"
+
+var (
+	fetchLimitersLock sync.Mutex
+	fetchLimiters     = map[string]*fetchLimiter{}
+)
+
+// fetchLimiter bounds the number of MetricSets of a module fetching at the
+// same time to the max_concurrent_fetches of the module. The MetricSets due
+// to fetch wait for a free slot.
+type fetchLimiter struct {
+	key   string
+	ref   uint32
+	slots chan struct{}
+}
+
+// getFetchLimiter returns the fetch limiter of a module, or nil if the module
+// doesn't limit its fetches. The limiter is shared with the wrappers of the
+// same module config, including the configs split per MetricSet on reload,
+// and must be released with releaseFetchLimiter.
+func getFetchLimiter(module mb.Module) *fetchLimiter {
+	limit := module.Config().MaxConcurrentFetches
+	if limit <= 0 {
+		return nil
+	}
+
+	key, err := fetchLimiterKey(module)
+	if err != nil {
+		logp.Err("Failed to share the fetch limit of module %s, limiting its fetches on their own: %s", module.Name(), err)
+		return &fetchLimiter{slots: make(chan struct{}, limit)}
+	}
+
+	fetchLimitersLock.Lock()
+	defer fetchLimitersLock.Unlock()
+
+	if l := fetchLimiters[key]; l != nil {
+		l.ref++
+		return l
+	}
+
+	l := &fetchLimiter{
+		key:   key,
+		ref:   1,
+		slots: make(chan struct{}, limit),
+	}
+	fetchLimiters[key] = l
+	return l
+}
+
+// fetchLimiterKey identifies the config of a module regardless of the
+// MetricSets enabled in it. The parts of a split config are identified by the
+// settings they share, without the settings of their own MetricSet.
+func fetchLimiterKey(module mb.Module) (string, error) {
+	var config map[string]interface{}
+	if err := module.UnpackConfig(&config); err != nil {
+		return "", err
+	}
+
+	hash, ok := config[sharedSettingsHashSetting].(string)
+	if !ok {
+		sharedHash, err := hashSharedSettings(config)
+		if err != nil {
+			return "", err
+		}
+		hash = strconv.FormatUint(sharedHash, 10)
+	}
+	return fmt.Sprintf("%s-%s", module.Name(), hash), nil
+}
+
+// hashSharedSettings returns the hash of the settings of a module config
+// shared by all its MetricSets.
+func hashSharedSettings(config map[string]interface{}) (uint64, error) {
+	delete(config, "metricsets")
+	delete(config, sharedSettingsHashSetting)
+	return hashstructure.Hash(config, nil)
+}
+
+func releaseFetchLimiter(l *fetchLimiter) {
+	if l == nil || l.key == "" {
+		return
+	}
+
+	fetchLimitersLock.Lock()
+	defer fetchLimitersLock.Unlock()
+
+	l.ref--
+	if l.ref > 0 {
+		return
+	}
+	delete(fetchLimiters, l.key)
+}
+
+// acquire waits for a free fetch slot. It returns false if the done channel
+// is closed while waiting. A nil limiter doesn't limit the fetches.
+func (l *fetchLimiter) acquire(done <-chan struct{}) bool {
+	if l == nil {
+		return true
+	}
+
+	select {
+	case <-done:
+		return false
+	case l.slots <- struct{}{}:
+		return true
+	}
+}
+
+// release frees the fetch slot taken with acquire.
+func (l *fetchLimiter) release() {
+	if l == nil {
+		return
+	}
+	<-l.slots
+}
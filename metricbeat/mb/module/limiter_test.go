@@ -0,0 +1,142 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build !integration
+// +build !integration
+
+package module
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	conf "github.com/elastic/elastic-agent-libs/config"
+)
+
+type fakeLimitedModule struct {
+	raw map[string]interface{}
+}
+
+func (m fakeLimitedModule) Name() string { return "fake" }
+
+func (m fakeLimitedModule) Config() mb.ModuleConfig {
+	var config struct {
+		MaxConcurrentFetches int `config:"max_concurrent_fetches"`
+	}
+	_ = m.UnpackConfig(&config)
+	return mb.ModuleConfig{Module: "fake", MaxConcurrentFetches: config.MaxConcurrentFetches}
+}
+
+func (m fakeLimitedModule) UnpackConfig(to interface{}) error {
+	return conf.MustNewConfigFrom(m.raw).Unpack(to)
+}
+
+func TestGetFetchLimiter(t *testing.T) {
+	newModule := func(metricSets []string, hosts []string) mb.Module {
+		return fakeLimitedModule{raw: map[string]interface{}{
+			"module":                 "fake",
+			"metricsets":             metricSets,
+			"hosts":                  hosts,
+			"max_concurrent_fetches": 2,
+		}}
+	}
+
+	// Modules without limit don't have a limiter
+	assert.Nil(t, getFetchLimiter(fakeLimitedModule{raw: map[string]interface{}{"module": "fake"}}))
+
+	// The configs split per MetricSet share the limiter of the module
+	limiter := getFetchLimiter(newModule([]string{"foo"}, []string{"a"}))
+	require.NotNil(t, limiter)
+	assert.Equal(t, 2, cap(limiter.slots))
+	assert.Same(t, limiter, getFetchLimiter(newModule([]string{"bar"}, []string{"a"})))
+
+	other := getFetchLimiter(newModule([]string{"foo"}, []string{"b"}))
+	assert.NotSame(t, limiter, other)
+
+	releaseFetchLimiter(other)
+	releaseFetchLimiter(limiter)
+	assert.Contains(t, fetchLimiters, limiter.key)
+	releaseFetchLimiter(limiter)
+	assert.NotContains(t, fetchLimiters, limiter.key)
+}
+
+func TestGetFetchLimiterSplitConfig(t *testing.T) {
+	c, err := conf.NewConfigFrom(map[string]interface{}{
+		"module":                 "fake",
+		"metricsets":             []string{"first", "second"},
+		"hosts":                  []string{"a"},
+		"independent_metricsets": true,
+		"max_concurrent_fetches": 2,
+		"first":                  map[string]interface{}{"period": "1m"},
+		"second":                 map[string]interface{}{"metrics": []string{"a"}},
+	})
+	require.NoError(t, err)
+
+	parts, err := NewFactory(beat.Info{}).SplitConfig(c)
+	require.NoError(t, err)
+	require.Len(t, parts, 2)
+
+	// The parts share the limiter of the module, even with different settings
+	// of their own MetricSet
+	limiters := make([]*fetchLimiter, 0, len(parts))
+	for _, part := range parts {
+		var raw map[string]interface{}
+		require.NoError(t, part.Config.Unpack(&raw))
+		limiters = append(limiters, getFetchLimiter(fakeLimitedModule{raw: raw}))
+	}
+	require.NotNil(t, limiters[0])
+	assert.Equal(t, 2, cap(limiters[0].slots))
+	assert.Same(t, limiters[0], limiters[1])
+
+	for _, limiter := range limiters {
+		releaseFetchLimiter(limiter)
+	}
+	assert.NotContains(t, fetchLimiters, limiters[0].key)
+}
+
+func TestFetchLimiterAcquire(t *testing.T) {
+	done := make(chan struct{})
+	limiter := &fetchLimiter{slots: make(chan struct{}, 1)}
+
+	require.True(t, limiter.acquire(done))
+
+	// The next fetch waits until the slot is released
+	acquired := make(chan bool)
+	go func() {
+		acquired <- limiter.acquire(done)
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("fetch slot acquired while the limit is reached")
+	default:
+	}
+	limiter.release()
+	assert.True(t, <-acquired)
+
+	// Waiting fetches are stopped with the MetricSet
+	close(done)
+	assert.False(t, limiter.acquire(done))
+
+	// A nil limiter doesn't limit the fetches
+	var noLimiter *fetchLimiter
+	assert.True(t, noLimiter.acquire(done))
+	noLimiter.release()
+}
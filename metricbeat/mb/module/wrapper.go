@@ -19,6 +19,7 @@ package module
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
 	"sync"
@@ -26,6 +27,7 @@ import (
 
 	"github.com/elastic/beats/v7/libbeat/beat"
 	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/common/backoff"
 	"github.com/elastic/beats/v7/metricbeat/mb"
 	conf "github.com/elastic/elastic-agent-libs/config"
 	"github.com/elastic/elastic-agent-libs/logp"
@@ -39,6 +41,19 @@ const (
 	successesKey = "success"
 	failuresKey  = "failures"
 	eventsKey    = "events"
+	periodKey    = "period"
+)
+
+// Health of the MetricSets, in the metrics of the MetricSet.
+const (
+	healthKey       = "health"
+	healthReasonKey = "health_reason"
+)
+
+// Backoff of the StreamingMetricSets started again after a failure.
+const (
+	streamingInitBackoff = time.Second
+	streamingMaxBackoff  = time.Minute
 )
 
 var (
@@ -59,6 +74,8 @@ type Wrapper struct {
 	// Options
 	maxStartDelay  time.Duration
 	eventModifiers []mb.EventModifier
+
+	fetchLimiter *fetchLimiter // Limits the concurrent fetches of the MetricSets.
 }
 
 // metricSetWrapper contains the MetricSet and the private data associated with
@@ -130,6 +147,8 @@ func (mw *Wrapper) Start(done <-chan struct{}) <-chan beat.Event {
 
 	out := make(chan beat.Event, 1)
 
+	mw.fetchLimiter = getFetchLimiter(mw.Module)
+
 	// Start one worker per MetricSet + host combination.
 	var wg sync.WaitGroup
 	wg.Add(len(mw.metricSets))
@@ -139,6 +158,7 @@ func (mw *Wrapper) Start(done <-chan struct{}) <-chan beat.Event {
 			registry := monitoring.GetNamespace("dataset").GetRegistry()
 
 			defer registry.Remove(metricsPath)
+			defer metricSetsHealth.remove(msw.ID())
 			defer releaseStats(msw.stats)
 			defer wg.Done()
 			defer msw.close()
@@ -153,6 +173,7 @@ func (mw *Wrapper) Start(done <-chan struct{}) <-chan beat.Event {
 	// Close the output channel when all writers to the channel have stopped.
 	go func() {
 		wg.Wait()
+		releaseFetchLimiter(mw.fetchLimiter)
 		close(out)
 		debugf("Stopped %s", mw)
 	}()
@@ -198,6 +219,20 @@ func (msw *metricSetWrapper) run(done <-chan struct{}, out chan<- beat.Event) {
 		done: done,
 	}
 
+	// The health of the MetricSets that aren't fetched periodically is checked
+	// every period.
+	if msw.checksHealthPeriodically() {
+		ctx, cancel := context.WithCancel(&channelContext{done})
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			msw.checkHealthPeriodically(ctx)
+		}()
+		defer wg.Wait()
+		defer cancel()
+	}
+
 	switch ms := msw.MetricSet.(type) {
 	case mb.PushMetricSet:
 		ms.Run(reporter.V1())
@@ -205,6 +240,8 @@ func (msw *metricSetWrapper) run(done <-chan struct{}, out chan<- beat.Event) {
 		ms.Run(reporter.V2())
 	case mb.PushMetricSetV2WithContext:
 		ms.Run(&channelContext{done}, reporter.V2())
+	case mb.StreamingMetricSet:
+		msw.runStreaming(&channelContext{done}, ms, reporter)
 	case mb.ReportingMetricSet, mb.ReportingMetricSetV2, mb.ReportingMetricSetV2Error, mb.ReportingMetricSetV2WithContext:
 		msw.startPeriodicFetching(&channelContext{done}, reporter)
 	default:
@@ -221,22 +258,210 @@ func (msw *metricSetWrapper) startPeriodicFetching(ctx context.Context, reporter
 	// Indicate that it has been started as periodic fetcher
 	msw.periodic = true
 
+	// Offset the fetches of the MetricSet by a random delay up to the jitter
+	// of the module, drawn once so the fetches stay one period apart.
+	if !msw.waitJitter(reporter.V2().Done()) {
+		return
+	}
+
 	// Fetch immediately.
-	msw.fetch(ctx, reporter)
+	if !msw.scheduledFetch(ctx, reporter) {
+		return
+	}
+
+	periodMetric, ok := msw.Metrics().Get(periodKey).(*monitoring.String)
+	if !ok {
+		periodMetric = monitoring.NewString(msw.Metrics(), periodKey)
+	}
+	period := msw.currentPeriod()
+	periodMetric.Set(period.String())
 
 	// Start timer for future fetches.
-	t := time.NewTicker(msw.Module().Config().Period)
+	t := time.NewTicker(period)
 	defer t.Stop()
 	for {
 		select {
 		case <-reporter.V2().Done():
 			return
 		case <-t.C:
-			msw.fetch(ctx, reporter)
+			if !msw.scheduledFetch(ctx, reporter) {
+				return
+			}
+
+			// Apply the period requested by the MetricSet in this fetch.
+			if current := msw.currentPeriod(); current != period {
+				logp.Info("Period of metricset %s.%s changed from %v to %v", msw.module.Name(), msw.Name(), period, current)
+				period = current
+				periodMetric.Set(period.String())
+				t.Reset(period)
+			}
 		}
 	}
 }
 
+// scheduledFetch fetches the MetricSet when it is due, once the module allows
+// one more concurrent fetch. It returns false if the MetricSet is stopped while
+// waiting.
+func (msw *metricSetWrapper) scheduledFetch(ctx context.Context, reporter reporter) bool {
+	if !msw.limitedFetch(ctx, reporter) {
+		return false
+	}
+	msw.checkHealth()
+	return true
+}
+
+// limitedFetch fetches the MetricSet once the module allows one more
+// concurrent fetch. The fetch slot is released even if the fetch panics, so
+// the other MetricSets of the module don't wait for it forever.
+func (msw *metricSetWrapper) limitedFetch(ctx context.Context, reporter reporter) bool {
+	if !msw.module.fetchLimiter.acquire(reporter.V2().Done()) {
+		return false
+	}
+	defer msw.module.fetchLimiter.release()
+
+	msw.fetch(ctx, reporter)
+	return true
+}
+
+// currentPeriod returns the period of the MetricSet, which can be adjusted
+// by the MetricSet while it runs.
+func (msw *metricSetWrapper) currentPeriod() time.Duration {
+	if adjuster, ok := msw.MetricSet.(mb.PeriodAdjuster); ok {
+		if period := adjuster.CurrentPeriod(); period > 0 {
+			return period
+		}
+	}
+	return msw.Module().Config().Period
+}
+
+// waitJitter waits for a random delay up to the jitter of the module before the
+// first fetch, so the fetches of MetricSets with the same period don't all
+// happen at the same time. It returns false if the done channel is closed
+// while waiting.
+func (msw *metricSetWrapper) waitJitter(done <-chan struct{}) bool {
+	jitter := msw.Module().Config().Jitter
+	if jitter <= 0 {
+		return true
+	}
+
+	t := time.NewTimer(time.Duration(rand.Int63n(int64(jitter))))
+	defer t.Stop()
+	select {
+	case <-done:
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// runStreaming starts the StreamingMetricSet and runs it until the context is
+// closed. When it fails, the error is reported and it is started again after a
+// backoff.
+func (msw *metricSetWrapper) runStreaming(ctx context.Context, ms mb.StreamingMetricSet, reporter reporter) {
+	b := backoff.NewEqualJitterBackoff(ctx.Done(), streamingInitBackoff, streamingMaxBackoff)
+	for {
+		started := time.Now()
+		err := ms.Start(ctx)
+		if err == nil {
+			msw.setHealth(mb.Health{Status: mb.HealthStatusHealthy})
+			err = ms.Run(ctx, reporter.V2())
+		} else {
+			err = fmt.Errorf("failed to start: %w", err)
+		}
+		if stopErr := ms.Stop(); stopErr != nil {
+			logp.Err("Error stopping metricset %s.%s: %s", msw.module.Name(), msw.Name(), stopErr)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			msw.setHealth(mb.Health{Status: mb.HealthStatusDegraded, Reason: err.Error()})
+			reporter.V2().Error(err)
+			logp.Err("Error streaming data for metricset %s.%s, restarting it: %s", msw.module.Name(), msw.Name(), err)
+		} else {
+			debugf("%s stopped streaming, restarting it", msw)
+		}
+
+		// Only back off further when the MetricSet keeps failing
+		if time.Since(started) > streamingMaxBackoff {
+			b.Reset()
+		}
+		if !b.Wait() {
+			return
+		}
+	}
+}
+
+// checksHealthPeriodically returns true if the MetricSet reports its health
+// and isn't fetched periodically.
+func (msw *metricSetWrapper) checksHealthPeriodically() bool {
+	switch msw.MetricSet.(type) {
+	case mb.PushMetricSet, mb.PushMetricSetV2, mb.PushMetricSetV2WithContext:
+		_, ok := msw.MetricSet.(mb.HealthChecker)
+		return ok
+	case mb.StreamingMetricSet:
+		return true
+	default:
+		return false
+	}
+}
+
+// checkHealthPeriodically checks the health of the MetricSet every period
+// until the context is closed.
+func (msw *metricSetWrapper) checkHealthPeriodically(ctx context.Context) {
+	t := time.NewTicker(msw.Module().Config().Period)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			msw.checkHealth()
+		}
+	}
+}
+
+// checkHealth updates the health of the MetricSet if it reports it.
+func (msw *metricSetWrapper) checkHealth() {
+	switch ms := msw.MetricSet.(type) {
+	case mb.HealthChecker:
+		msw.setHealth(ms.CheckHealth())
+	case mb.StreamingMetricSet:
+		if err := ms.Health(); err != nil {
+			msw.setHealth(mb.Health{Status: mb.HealthStatusDegraded, Reason: err.Error()})
+		} else {
+			msw.setHealth(mb.Health{Status: mb.HealthStatusHealthy})
+		}
+	}
+}
+
+// setHealth updates the health of the MetricSet in its metrics and in the
+// aggregated health of the MetricSets.
+func (msw *metricSetWrapper) setHealth(health mb.Health) {
+	status, ok := msw.Metrics().Get(healthKey).(*monitoring.String)
+	if !ok {
+		status = monitoring.NewString(msw.Metrics(), healthKey)
+	}
+	reason, ok := msw.Metrics().Get(healthReasonKey).(*monitoring.String)
+	if !ok {
+		reason = monitoring.NewString(msw.Metrics(), healthReasonKey)
+	}
+
+	if status.Get() != string(health.Status) || reason.Get() != health.Reason {
+		if health.Status == mb.HealthStatusHealthy {
+			if status.Get() != "" {
+				logp.Info("Metricset %s.%s is healthy again", msw.module.Name(), msw.Name())
+			}
+		} else {
+			logp.Warn("Metricset %s.%s is %s: %s", msw.module.Name(), msw.Name(), health.Status, health.Reason)
+		}
+	}
+	status.Set(string(health.Status))
+	reason.Set(health.Reason)
+	metricSetsHealth.update(msw.ID(), msw.module.Name()+"/"+msw.Name(), health)
+}
+
 // fetch invokes the appropriate Fetch method for the MetricSet and publishes
 // the result using the publisher client. This method will recover from panics
 // and log a stack track if one occurs.
@@ -257,7 +482,7 @@ func (msw *metricSetWrapper) fetch(ctx context.Context, reporter reporter) {
 		}
 	case mb.ReportingMetricSetV2WithContext:
 		reporter.StartFetchTimer()
-		err := fetcher.Fetch(ctx, reporter.V2())
+		err := msw.fetchWithTimeout(ctx, fetcher, reporter.V2())
 		if err != nil {
 			reporter.V2().Error(err)
 			logp.Err("Error fetching data for metricset %s.%s: %s", msw.module.Name(), msw.Name(), err)
@@ -267,6 +492,28 @@ func (msw *metricSetWrapper) fetch(ctx context.Context, reporter reporter) {
 	}
 }
 
+// fetchWithTimeout invokes the Fetch method of the MetricSet with a context
+// that is canceled once the timeout of the module is exceeded, so a slow fetch
+// doesn't block the MetricSet until its next period. Without a timeout in the
+// module configuration the fetches have no deadline.
+func (msw *metricSetWrapper) fetchWithTimeout(ctx context.Context, fetcher mb.ReportingMetricSetV2WithContext, reporter mb.ReporterV2) error {
+	timeout := msw.Module().Config().FetchTimeout
+	if timeout <= 0 {
+		return fetcher.Fetch(ctx, reporter)
+	}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	err := fetcher.Fetch(fetchCtx, reporter)
+	if errors.Is(fetchCtx.Err(), context.DeadlineExceeded) {
+		if err == nil || errors.Is(err, context.DeadlineExceeded) {
+			return mb.NewError(mb.ErrorTypeTimeout, "", fmt.Errorf("fetch timed out after %v", timeout))
+		}
+		return mb.NewError(mb.ErrorTypeTimeout, "", fmt.Errorf("fetch timed out after %v: %w", timeout, err))
+	}
+	return err
+}
+
 // close closes the underlying MetricSet if it implements the mb.Closer
 // interface.
 func (msw *metricSetWrapper) close() error {
@@ -359,7 +606,7 @@ func (r reporterV2) Event(event mb.Event) bool {
 		event.Took = time.Since(r.start)
 	}
 	if r.msw.periodic {
-		event.Period = r.msw.Module().Config().Period
+		event.Period = r.msw.currentPeriod()
 	}
 
 	if event.Timestamp.IsZero() {
@@ -21,6 +21,9 @@
 package module_test
 
 import (
+	"context"
+	"errors"
+	"sync"
 	"testing"
 	"time"
 
@@ -38,6 +41,10 @@ const (
 	moduleName           = "fake"
 	reportingFetcherName = "ReportingFetcher"
 	pushMetricSetName    = "PushMetricSet"
+	streamingName        = "StreamingMetricSet"
+	slowFetcherName      = "SlowFetcher"
+	adjustingName        = "AdjustingFetcher"
+	panickingName        = "PanickingFetcher"
 )
 
 // fakeMetricSet
@@ -45,6 +52,10 @@ const (
 func init() {
 	mb.Registry.MustAddMetricSet(moduleName, reportingFetcherName, newFakeReportingFetcher)
 	mb.Registry.MustAddMetricSet(moduleName, pushMetricSetName, newFakePushMetricSet)
+	mb.Registry.MustAddMetricSet(moduleName, streamingName, newFakeStreamingMetricSet)
+	mb.Registry.MustAddMetricSet(moduleName, slowFetcherName, newFakeSlowFetcher)
+	mb.Registry.MustAddMetricSet(moduleName, adjustingName, newFakeAdjustingFetcher)
+	mb.Registry.MustAddMetricSet(moduleName, panickingName, newFakePanickingFetcher)
 }
 
 // ReportingFetcher
@@ -81,6 +92,105 @@ func newFakePushMetricSet(base mb.BaseMetricSet) (mb.MetricSet, error) {
 	return r, nil
 }
 
+// StreamingMetricSet
+
+// fakeStreaming counts the lifecycle calls of the fake StreamingMetricSets.
+var fakeStreaming struct {
+	sync.Mutex
+	starts, stops int
+}
+
+type fakeStreamingMetricSet struct {
+	mb.BaseMetricSet
+}
+
+func (ms *fakeStreamingMetricSet) Start(ctx context.Context) error {
+	fakeStreaming.Lock()
+	defer fakeStreaming.Unlock()
+	fakeStreaming.starts++
+	return nil
+}
+
+// Run fails the first time, and pushes an event the next times.
+func (ms *fakeStreamingMetricSet) Run(ctx context.Context, r mb.ReporterV2) error {
+	fakeStreaming.Lock()
+	starts := fakeStreaming.starts
+	fakeStreaming.Unlock()
+	if starts == 1 {
+		return errors.New("stream disconnected")
+	}
+	r.Event(mb.Event{MetricSetFields: mapstr.M{"metric": 1}})
+	<-ctx.Done()
+	return nil
+}
+
+func (ms *fakeStreamingMetricSet) Stop() error {
+	fakeStreaming.Lock()
+	defer fakeStreaming.Unlock()
+	fakeStreaming.stops++
+	return nil
+}
+
+func (ms *fakeStreamingMetricSet) Health() error { return nil }
+
+func newFakeStreamingMetricSet(base mb.BaseMetricSet) (mb.MetricSet, error) {
+	var r mb.StreamingMetricSet = &fakeStreamingMetricSet{BaseMetricSet: base}
+	return r, nil
+}
+
+// SlowFetcher
+
+type fakeSlowFetcher struct {
+	mb.BaseMetricSet
+}
+
+// Fetch blocks until its context is canceled.
+func (ms *fakeSlowFetcher) Fetch(ctx context.Context, r mb.ReporterV2) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func newFakeSlowFetcher(base mb.BaseMetricSet) (mb.MetricSet, error) {
+	var r mb.ReportingMetricSetV2WithContext = &fakeSlowFetcher{BaseMetricSet: base}
+	return r, nil
+}
+
+// AdjustingFetcher
+
+type fakeAdjustingFetcher struct {
+	mb.BaseMetricSet
+}
+
+// Fetch shortens the period of the MetricSet.
+func (ms *fakeAdjustingFetcher) Fetch(r mb.ReporterV2) {
+	ms.AdjustPeriod(10 * time.Millisecond)
+	r.Event(mb.Event{MetricSetFields: mapstr.M{"metric": 1}})
+}
+
+func newFakeAdjustingFetcher(base mb.BaseMetricSet) (mb.MetricSet, error) {
+	var r mb.ReportingMetricSetV2 = &fakeAdjustingFetcher{BaseMetricSet: base}
+	return r, nil
+}
+
+// PanickingFetcher
+
+type fakePanickingFetcher struct {
+	mb.BaseMetricSet
+}
+
+// Fetch panics for the alpha host, and reports an event for the others.
+func (ms *fakePanickingFetcher) Fetch(r mb.ReporterV2) {
+	if ms.Host() == "alpha" {
+		panic("fetch failed")
+	}
+	r.Event(mb.Event{MetricSetFields: mapstr.M{"metric": 1}})
+}
+
+func newFakePanickingFetcher(base mb.BaseMetricSet) (mb.MetricSet, error) {
+	var r mb.ReportingMetricSetV2 = &fakePanickingFetcher{BaseMetricSet: base}
+	return r, nil
+}
+
 // test utilities
 
 func newTestRegistry(t testing.TB) *mb.Register {
@@ -90,6 +200,14 @@ func newTestRegistry(t testing.TB) *mb.Register {
 	require.NoError(t, err)
 	err = r.AddMetricSet(moduleName, pushMetricSetName, newFakePushMetricSet)
 	require.NoError(t, err)
+	err = r.AddMetricSet(moduleName, streamingName, newFakeStreamingMetricSet)
+	require.NoError(t, err)
+	err = r.AddMetricSet(moduleName, slowFetcherName, newFakeSlowFetcher)
+	require.NoError(t, err)
+	err = r.AddMetricSet(moduleName, adjustingName, newFakeAdjustingFetcher)
+	require.NoError(t, err)
+	err = r.AddMetricSet(moduleName, panickingName, newFakePanickingFetcher)
+	require.NoError(t, err)
 	return r
 }
 
@@ -161,6 +279,203 @@ func TestWrapperOfPushMetricSet(t *testing.T) {
 	}
 }
 
+func TestWrapperOfStreamingMetricSet(t *testing.T) {
+	hosts := []string{"alpha"}
+	c := newConfig(t, map[string]interface{}{
+		"module":     moduleName,
+		"metricsets": []string{streamingName},
+		"hosts":      hosts,
+	})
+
+	m, err := module.NewWrapper(c, newTestRegistry(t))
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	output := m.Start(done)
+
+	// The error of the first run is reported, and the metricset is started
+	// again after a backoff.
+	event := <-output
+	hasError, _ := event.Fields.HasKey("error")
+	assert.True(t, hasError, "error event expected, got %+v", event)
+
+	select {
+	case event = <-output:
+		hasError, _ = event.Fields.HasKey("error")
+		assert.False(t, hasError, "unexpected error event %+v", event)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "metricset wasn't started again")
+	}
+	close(done)
+
+	// Validate that the channel is closed after the metricset stops.
+	for range output {
+		assert.Fail(t, "received unexpected event")
+	}
+
+	fakeStreaming.Lock()
+	defer fakeStreaming.Unlock()
+	assert.Equal(t, 2, fakeStreaming.starts)
+	assert.Equal(t, 2, fakeStreaming.stops)
+}
+
+func TestWrapperFetchTimeout(t *testing.T) {
+	c := newConfig(t, map[string]interface{}{
+		"module":     moduleName,
+		"metricsets": []string{slowFetcherName},
+		"hosts":      []string{"alpha"},
+		"period":     "1h",
+		"timeout":    "10ms",
+	})
+
+	m, err := module.NewWrapper(c, newTestRegistry(t))
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	output := m.Start(done)
+
+	// The fetch is canceled after the timeout, and the timeout is reported.
+	select {
+	case event := <-output:
+		message, err := event.Fields.GetValue("error.message")
+		require.NoError(t, err, "error event expected, got %+v", event)
+		assert.Equal(t, "fetch timed out after 10ms", message)
+		errorType, _ := event.Fields.GetValue("error.type")
+		assert.Equal(t, "timeout", errorType)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "fetch wasn't canceled")
+	}
+	close(done)
+
+	for range output {
+	}
+}
+
+func TestWrapperWithoutFetchTimeout(t *testing.T) {
+	c := newConfig(t, map[string]interface{}{
+		"module":     moduleName,
+		"metricsets": []string{slowFetcherName},
+		"hosts":      []string{"alpha"},
+		"period":     "10ms",
+	})
+
+	m, err := module.NewWrapper(c, newTestRegistry(t))
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	output := m.Start(done)
+
+	// The timeout of the module defaults to the period, but fetches are only
+	// canceled with a timeout set in the configuration.
+	select {
+	case event := <-output:
+		assert.Fail(t, "fetch was canceled", "event: %+v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+	close(done)
+
+	for range output {
+	}
+}
+
+func TestWrapperMaxConcurrentFetchesPanic(t *testing.T) {
+	c := newConfig(t, map[string]interface{}{
+		"module":                 moduleName,
+		"metricsets":             []string{panickingName},
+		"hosts":                  []string{"alpha", "beta"},
+		"period":                 "10ms",
+		"max_concurrent_fetches": 1,
+	})
+
+	m, err := module.NewWrapper(c, newTestRegistry(t))
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	output := m.Start(done)
+
+	// The fetch slot taken by the panicking fetch is released, so the other
+	// host keeps fetching.
+	for i := 0; i < 3; i++ {
+		select {
+		case <-output:
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "fetches blocked after a fetch panicked")
+		}
+	}
+	close(done)
+
+	for range output {
+	}
+}
+
+func TestWrapperAdjustedPeriod(t *testing.T) {
+	c := newConfig(t, map[string]interface{}{
+		"module":     moduleName,
+		"metricsets": []string{adjustingName},
+		"hosts":      []string{"alpha"},
+		"period":     "1h",
+	})
+
+	m, err := module.NewWrapper(c, newTestRegistry(t))
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	output := m.Start(done)
+
+	// The first fetch happens immediately, the next ones with the period
+	// requested by the metricset instead of the period of the module.
+	for i := 0; i < 3; i++ {
+		select {
+		case event := <-output:
+			hasError, _ := event.Fields.HasKey("error")
+			assert.False(t, hasError, "unexpected error event %+v", event)
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "adjusted period not applied")
+		}
+	}
+	close(done)
+
+	for range output {
+	}
+}
+
+func TestWrapperJitterKeepsPeriod(t *testing.T) {
+	const period = 200 * time.Millisecond
+	c := newConfig(t, map[string]interface{}{
+		"module":     moduleName,
+		"metricsets": []string{reportingFetcherName},
+		"hosts":      []string{"alpha"},
+		"period":     period.String(),
+		"jitter":     "150ms",
+	})
+
+	m, err := module.NewWrapper(c, newTestRegistry(t))
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	output := m.Start(done)
+
+	// The jitter only delays the first fetch, the next ones are one period
+	// apart.
+	var fetched []time.Time
+	for i := 0; i < 4; i++ {
+		select {
+		case <-output:
+			fetched = append(fetched, time.Now())
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "no event fetched")
+		}
+	}
+	close(done)
+
+	for range output {
+	}
+
+	for i := 1; i < len(fetched); i++ {
+		assert.InDelta(t, period, fetched[i].Sub(fetched[i-1]), float64(40*time.Millisecond))
+	}
+}
+
 func TestPeriodIsAddedToEvent(t *testing.T) {
 	cases := map[string]struct {
 		metricset string
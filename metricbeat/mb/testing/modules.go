@@ -436,3 +436,35 @@ func RunPushMetricSetV2WithContext(timeout time.Duration, waitEvents int, metric
 	go metricSet.Run(ctx, r)
 	return r.capture(waitEvents)
 }
+
+// NewStreamingMetricSet instantiates a new StreamingMetricSet using the given
+// configuration. The ModuleFactory and MetricSetFactory are obtained from the
+// global Registry.
+func NewStreamingMetricSet(t testing.TB, config interface{}) mb.StreamingMetricSet {
+	metricSet := NewMetricSet(t, config)
+
+	streamingMetricSet, ok := metricSet.(mb.StreamingMetricSet)
+	if !ok {
+		t.Fatal("MetricSet does not implement StreamingMetricSet")
+	}
+
+	return streamingMetricSet
+}
+
+// RunStreamingMetricSet starts the given streaming metricset, runs it for the
+// specific amount of time and returns all of the events that occur during that
+// period. The metricset is stopped before returning.
+func RunStreamingMetricSet(timeout time.Duration, waitEvents int, metricSet mb.StreamingMetricSet) ([]mb.Event, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := metricSet.Start(ctx); err != nil {
+		return nil, err
+	}
+	defer metricSet.Stop()
+
+	r := newCapturingPushReporterV2(ctx)
+
+	go metricSet.Run(ctx, r)
+	return r.capture(waitEvents), nil
+}
@@ -97,6 +97,7 @@ func (in *cloudwatchInput) Test(ctx v2.TestContext) error {
 
 func (in *cloudwatchInput) Run(inputContext v2.Context, pipeline beat.Pipeline) error {
 	var err error
+	defer awscommon.ReleaseAWSConfig(in.awsConfig)
 
 	// Wrap input Context's cancellation Done channel a context.Context. This
 	// goroutine stops with the parent closes the Done channel.
@@ -82,6 +82,7 @@ func (in *s3Input) Test(ctx v2.TestContext) error {
 
 func (in *s3Input) Run(inputContext v2.Context, pipeline beat.Pipeline) error {
 	var err error
+	defer awscommon.ReleaseAWSConfig(in.awsConfig)
 
 	persistentStore, err := in.store.Access()
 	if err != nil {
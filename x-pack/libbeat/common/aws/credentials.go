@@ -10,6 +10,9 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 
@@ -18,6 +21,9 @@ import (
 	awssdk "github.com/aws/aws-sdk-go-v2/aws"
 	awsConfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/processcreds"
+
+	"golang.org/x/net/http/httpproxy"
 
 	"github.com/elastic/elastic-agent-libs/logp"
 	"github.com/elastic/elastic-agent-libs/transport/httpcommon"
@@ -40,14 +46,75 @@ type ConfigAWS struct {
 	Endpoint             string            `config:"endpoint"`
 	RoleArn              string            `config:"role_arn"`
 	ProxyUrl             string            `config:"proxy_url"`
+	NoProxy              []string          `config:"no_proxy"`
 	FIPSEnabled          bool              `config:"fips_enabled"`
 	TLS                  *tlscommon.Config `config:"ssl" yaml:"ssl,omitempty" json:"ssl,omitempty"`
 	DefaultRegion        string            `config:"default_region"`
+	// WebIdentityTokenFile is the file of the OIDC token exchanged for the
+	// credentials of RoleArn, for example with EKS IAM roles for service accounts.
+	WebIdentityTokenFile string `config:"web_identity_token_file"`
+	// CredentialRefreshMargin is how long before they expire temporary
+	// credentials are refreshed.
+	CredentialRefreshMargin time.Duration `config:"credential_refresh_margin"`
+	// CredentialRefreshJitter is the size of the window before the refresh
+	// margin in which temporary credentials are refreshed at a random time.
+	CredentialRefreshJitter time.Duration `config:"credential_refresh_jitter"`
+	// CredentialProcess is an external command printing the credentials as
+	// JSON, like credential_process in AWS config files.
+	CredentialProcess string      `config:"credential_process"`
+	Retry             RetryConfig `config:"retry"`
+	// STSRegionalEndpoints selects the STS endpoint, regional or legacy.
+	STSRegionalEndpoints string `config:"sts_regional_endpoints"`
+	// VPCEndpoints are the DNS names of the interface VPC endpoints the AWS
+	// clients send their requests to. When set, the public endpoints of the
+	// services are never used.
+	VPCEndpoints []string `config:"vpc_endpoints"`
+	// EmulatorEndpoint is the URL of an emulator of the AWS APIs, like
+	// LocalStack, all the requests are sent to.
+	EmulatorEndpoint string `config:"emulator_endpoint"`
+}
+
+const (
+	// stsRegionalEndpoints sends the STS requests to the endpoint of the region
+	// of the AWS config, sts.<region>.amazonaws.com.
+	stsRegionalEndpoints = "regional"
+	// stsLegacyEndpoints sends the STS requests to the global endpoint,
+	// sts.amazonaws.com, in the partitions that have one.
+	stsLegacyEndpoints = "legacy"
+	// stsGlobalRegion is the region of the global STS endpoint.
+	stsGlobalRegion = "aws-global"
+)
+
+// RetryConfig is the retry policy of the AWS clients. Zero values keep the
+// policy of the AWS SDK, or of the AWS config file and environment variables.
+type RetryConfig struct {
+	// Mode is the retry mode, standard or adaptive. The adaptive mode also
+	// limits the rate of the requests when they are throttled.
+	Mode string `config:"mode"`
+	// MaxAttempts is the maximum number of attempts of a request, including
+	// the first one.
+	MaxAttempts int `config:"max_attempts" validate:"min=0"`
 }
 
-// InitializeAWSConfig function creates the awssdk.Config object from the provided config
+// InitializeAWSConfig function creates the awssdk.Config object from the provided config.
+// The HTTP client, with the proxy and TLS settings, is used by all the AWS clients, including
+// the ones retrieving credentials.
 func InitializeAWSConfig(beatsConfig ConfigAWS) (awssdk.Config, error) {
-	awsConfig, _ := GetAWSCredentials(beatsConfig)
+	httpClient, err := newHTTPClient(beatsConfig)
+	if err != nil {
+		return awssdk.Config{}, err
+	}
+
+	awsConfig, _ := getAWSCredentials(beatsConfig, httpClient)
+	awsConfig.HTTPClient = httpClient
+	if err := applyRetryConfig(beatsConfig.Retry, &awsConfig); err != nil {
+		return awsConfig, err
+	}
+	switch beatsConfig.STSRegionalEndpoints {
+	case "", stsRegionalEndpoints, stsLegacyEndpoints:
+	default:
+		return awsConfig, fmt.Errorf("sts_regional_endpoints %s is not supported, use %s or %s", beatsConfig.STSRegionalEndpoints, stsRegionalEndpoints, stsLegacyEndpoints)
+	}
 	if awsConfig.Region == "" {
 		if beatsConfig.DefaultRegion != "" {
 			awsConfig.Region = beatsConfig.DefaultRegion
@@ -56,45 +123,173 @@ func InitializeAWSConfig(beatsConfig ConfigAWS) (awssdk.Config, error) {
 		}
 	}
 
-	// Assume IAM role if iam_role config parameter is given
+	// Use the FIPS endpoints in all the clients, including the STS clients
+	// retrieving credentials
+	if beatsConfig.FIPSEnabled {
+		applyFIPSEndpoints(&awsConfig)
+	}
+
+	// Send the requests of all the clients, including the STS clients
+	// retrieving credentials, to the VPC endpoints or to the emulator
+	if err := applyVPCEndpoints(beatsConfig, &awsConfig); err != nil {
+		return awsConfig, err
+	}
+	if err := applyEmulatorEndpoint(beatsConfig, &awsConfig); err != nil {
+		return awsConfig, err
+	}
 	if beatsConfig.RoleArn != "" {
+		if err := CheckVPCEndpoints(beatsConfig.VPCEndpoints, sts.ServiceID, []string{awsConfig.Region}); err != nil {
+			return awsConfig, fmt.Errorf("role_arn requires an STS VPC endpoint: %w", err)
+		}
+	}
+
+	// Exchange the web identity token for the credentials of the role if
+	// web_identity_token_file is given, otherwise assume IAM role if
+	// role_arn config parameter is given
+	if beatsConfig.WebIdentityTokenFile != "" {
+		if beatsConfig.RoleArn == "" {
+			return awsConfig, fmt.Errorf("role_arn is required when web_identity_token_file is set")
+		}
+		addWebIdentityRoleProviderToAwsConfig(beatsConfig, &awsConfig)
+	} else if beatsConfig.RoleArn != "" {
 		addAssumeRoleProviderToAwsConfig(beatsConfig, &awsConfig)
 	}
 
-	var proxy func(*http.Request) (*url.URL, error)
-	if beatsConfig.ProxyUrl != "" {
-		proxyUrl, err := httpcommon.NewProxyURIFromString(beatsConfig.ProxyUrl)
+	// Refresh temporary credentials before they expire, and when the web
+	// identity token file is rotated
+	if awsConfig.Credentials != nil {
+		tokenFile := beatsConfig.WebIdentityTokenFile
+		if tokenFile == "" {
+			tokenFile = os.Getenv(webIdentityTokenFileEnvVar)
+		}
+		provider := awsConfig.Credentials
+		newProvider := func() *refreshingCredentialsProvider {
+			return newRefreshingCredentialsProvider(provider, tokenFile, beatsConfig.CredentialRefreshMargin, beatsConfig.CredentialRefreshJitter)
+		}
+		if beatsConfig.RoleArn == "" {
+			awsConfig.Credentials = newProvider()
+		} else {
+			shared, err := sharedRoleCredentials(beatsConfig, awsConfig.Region, newProvider)
+			if err != nil {
+				return awsConfig, err
+			}
+			awsConfig.Credentials = shared
+		}
+	}
+	return awsConfig, nil
+}
+
+// applyRetryConfig sets the retry mode and maximum attempts of the AWS clients created from awsConfig.
+func applyRetryConfig(retryConfig RetryConfig, awsConfig *awssdk.Config) error {
+	if retryConfig.Mode != "" {
+		mode, err := awssdk.ParseRetryMode(retryConfig.Mode)
 		if err != nil {
-			return awsConfig, err
+			return fmt.Errorf("invalid retry.mode: %w", err)
 		}
-		proxy = http.ProxyURL(proxyUrl.URI())
+		awsConfig.RetryMode = mode
+	}
+	if retryConfig.MaxAttempts > 0 {
+		awsConfig.RetryMaxAttempts = retryConfig.MaxAttempts
+	}
+	return nil
+}
+
+// fipsEndpointSource is a config source enabling the FIPS endpoints.
+type fipsEndpointSource struct{}
+
+func (fipsEndpointSource) GetUseFIPSEndpoint(context.Context) (awssdk.FIPSEndpointState, bool, error) {
+	return awssdk.FIPSEndpointStateEnabled, true, nil
+}
+
+// applyFIPSEndpoints makes the AWS clients created from awsConfig use the FIPS
+// endpoints of their service. The clients resolve the endpoint options from the
+// first config source setting them, so it takes precedence over the shared
+// config files and the environment.
+func applyFIPSEndpoints(awsConfig *awssdk.Config) {
+	awsConfig.ConfigSources = append([]interface{}{fipsEndpointSource{}}, awsConfig.ConfigSources...)
+}
+
+// newHTTPClient creates the HTTP client for the AWS clients with the proxy and TLS settings of the Beats config,
+// like a custom CA for TLS intercepting proxies or VPC endpoints, client certificates and the verification mode.
+func newHTTPClient(beatsConfig ConfigAWS) (*http.Client, error) {
+	proxy, err := newProxyFunc(beatsConfig)
+	if err != nil {
+		return nil, err
 	}
 	var tlsConfig *tls.Config
 	if beatsConfig.TLS != nil {
-		TLSConfig, _ := tlscommon.LoadTLSConfig(beatsConfig.TLS)
-		tlsConfig = TLSConfig.ToConfig()
+		TLSConfig, err := tlscommon.LoadTLSConfig(beatsConfig.TLS)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load ssl configuration: %w", err)
+		}
+		if TLSConfig != nil {
+			tlsConfig = TLSConfig.ToConfig()
+		}
 	}
-	awsConfig.HTTPClient = &http.Client{
-		Transport: &http.Transport{
-			Proxy:           proxy,
-			TLSClientConfig: tlsConfig,
-		},
+
+	// Keep the timeouts and connection pooling of the default transport
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxy
+	transport.TLSClientConfig = tlsConfig
+	return &http.Client{
+		Transport: transport,
+	}, nil
+}
+
+// newProxyFunc returns the proxy function of the HTTP transport, nil when proxy_url is not set. The hosts
+// of no_proxy, in the NO_PROXY environment variable format, are reached without the proxy.
+func newProxyFunc(beatsConfig ConfigAWS) (func(*http.Request) (*url.URL, error), error) {
+	if beatsConfig.ProxyUrl == "" {
+		return nil, nil
 	}
-	return awsConfig, nil
+	proxyUrl, err := httpcommon.NewProxyURIFromString(beatsConfig.ProxyUrl)
+	if err != nil {
+		return nil, err
+	}
+	if len(beatsConfig.NoProxy) == 0 {
+		return http.ProxyURL(proxyUrl.URI()), nil
+	}
+
+	proxyConfig := httpproxy.Config{
+		HTTPProxy:  proxyUrl.URI().String(),
+		HTTPSProxy: proxyUrl.URI().String(),
+		NoProxy:    strings.Join(beatsConfig.NoProxy, ","),
+	}
+	proxyFunc := proxyConfig.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}, nil
 }
 
 // GetAWSCredentials function gets aws credentials from the config.
 // If access keys given, use them as credentials.
-// If access keys are not given, then load from AWS config file. If credential_profile_name is not
+// If access keys are not given and credential_process is given, run it to get the credentials.
+// Otherwise load from AWS config file. If credential_profile_name is not
 // given, default profile will be used.
 // If role_arn is given, assume the IAM role either with access keys or default profile.
 func GetAWSCredentials(beatsConfig ConfigAWS) (awssdk.Config, error) {
+	return getAWSCredentials(beatsConfig, nil)
+}
+
+// getAWSCredentials gets aws credentials from the config like GetAWSCredentials. The credentials
+// loaded from the default credential chain are retrieved with httpClient when it is not nil.
+func getAWSCredentials(beatsConfig ConfigAWS, httpClient *http.Client) (awssdk.Config, error) {
 	// Check if accessKeyID or secretAccessKey or sessionToken is given from configuration
 	if beatsConfig.AccessKeyID != "" || beatsConfig.SecretAccessKey != "" || beatsConfig.SessionToken != "" {
 		return getConfigForKeys(beatsConfig), nil
 	}
 
-	return getConfigSharedCredentialProfile(beatsConfig)
+	if beatsConfig.CredentialProcess != "" {
+		return getConfigForCredentialProcess(beatsConfig), nil
+	}
+
+	// Emulators don't need valid credentials, use placeholders unless a
+	// credential profile is given
+	if beatsConfig.EmulatorEndpoint != "" && beatsConfig.ProfileName == "" && beatsConfig.SharedCredentialFile == "" {
+		return getConfigForEmulator(), nil
+	}
+
+	return getConfigSharedCredentialProfile(beatsConfig, httpClient)
 }
 
 // getConfigForKeys creates a default AWS config and adds a CredentialsProvider using the provided Beats config.
@@ -115,10 +310,22 @@ func getConfigForKeys(beatsConfig ConfigAWS) awssdk.Config {
 	return *config
 }
 
+// getConfigForCredentialProcess creates a default AWS config with a CredentialsProvider running the
+// credential_process command of the Beats config. The credentials are cached and the command is run
+// again before they expire.
+func getConfigForCredentialProcess(beatsConfig ConfigAWS) awssdk.Config {
+	logger := logp.NewLogger("getConfigForCredentialProcess")
+	logger.Debug("Using credential process for AWS credential")
+	config := awssdk.NewConfig()
+	config.Credentials = processcreds.NewProvider(beatsConfig.CredentialProcess)
+	return *config
+}
+
 // getConfigSharedCredentialProfile If accessKeyID, secretAccessKey or sessionToken is not given,
 // then load from default config // Please see https://docs.aws.amazon.com/cli/latest/userguide/cli-configure-profiles.html
+//
 //	with more details. If credential_profile_name is empty, then default profile is used.
-func getConfigSharedCredentialProfile(beatsConfig ConfigAWS) (awssdk.Config, error) {
+func getConfigSharedCredentialProfile(beatsConfig ConfigAWS, httpClient *http.Client) (awssdk.Config, error) {
 	logger := logp.NewLogger("WithSharedConfigProfile")
 
 	var options []func(*awsConfig.LoadOptions) error
@@ -134,6 +341,10 @@ func getConfigSharedCredentialProfile(beatsConfig ConfigAWS) (awssdk.Config, err
 		options = append(options, awsConfig.WithSharedConfigFiles([]string{beatsConfig.SharedCredentialFile}))
 	}
 
+	if httpClient != nil {
+		options = append(options, awsConfig.WithHTTPClient(httpClient))
+	}
+
 	cfg, err := awsConfig.LoadDefaultConfig(context.TODO(), options...)
 	if err != nil {
 		return cfg, fmt.Errorf("awsConfig.LoadDefaultConfig failed with shared credential profile given: [%w]", err)
@@ -147,11 +358,33 @@ func getConfigSharedCredentialProfile(beatsConfig ConfigAWS) (awssdk.Config, err
 func addAssumeRoleProviderToAwsConfig(config ConfigAWS, awsConfig *awssdk.Config) {
 	logger := logp.NewLogger("addAssumeRoleProviderToAwsConfig")
 	logger.Debug("Switching credentials provider to AssumeRoleProvider")
-	stsSvc := sts.NewFromConfig(*awsConfig)
+	stsSvc := sts.NewFromConfig(*awsConfig, STSEndpointOptions(config))
 	stsCredProvider := stscreds.NewAssumeRoleProvider(stsSvc, config.RoleArn)
 	awsConfig.Credentials = stsCredProvider
 }
 
+// STSEndpointOptions returns the options of the STS clients selecting the endpoint of sts_regional_endpoints. The
+// regional endpoint of the AWS config region is used by default, as it is required in some partitions and when STS
+// is only reachable through a VPC endpoint.
+func STSEndpointOptions(beatsConfig ConfigAWS) func(*sts.Options) {
+	return func(o *sts.Options) {
+		if beatsConfig.STSRegionalEndpoints == stsLegacyEndpoints {
+			o.Region = stsGlobalRegion
+		}
+	}
+}
+
+// addWebIdentityRoleProviderToAwsConfig adds the credentials provider to the current AWS config by exchanging the
+// token of the web identity token file stored in Beats config for the credentials of the role ARN. The token file is
+// read again every time the credentials are refreshed, so rotated tokens are picked up.
+func addWebIdentityRoleProviderToAwsConfig(config ConfigAWS, awsConfig *awssdk.Config) {
+	logger := logp.NewLogger("addWebIdentityRoleProviderToAwsConfig")
+	logger.Debug("Switching credentials provider to WebIdentityRoleProvider")
+	stsSvc := sts.NewFromConfig(*awsConfig, STSEndpointOptions(config))
+	webIdentityCredProvider := stscreds.NewWebIdentityRoleProvider(stsSvc, config.RoleArn, stscreds.IdentityTokenFile(config.WebIdentityTokenFile))
+	awsConfig.Credentials = webIdentityCredProvider
+}
+
 // addStaticCredentialsProviderToAwsConfig adds a static credentials provider to the current AWS config by using the keys stored in Beats config
 func addStaticCredentialsProviderToAwsConfig(beatsConfig ConfigAWS, awsConfig *awssdk.Config) {
 	logger := logp.NewLogger("addStaticCredentialsProviderToAwsConfig")
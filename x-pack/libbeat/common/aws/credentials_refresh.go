@@ -0,0 +1,266 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/smithy-go"
+	"github.com/mitchellh/hashstructure"
+
+	"github.com/elastic/elastic-agent-libs/logp"
+)
+
+// defaultCredentialRefreshMargin is how long before they expire temporary
+// credentials are refreshed when credential_refresh_margin is not set.
+const defaultCredentialRefreshMargin = 5 * time.Minute
+
+// webIdentityTokenFileEnvVar is the environment variable set by EKS for IAM
+// roles for service accounts (IRSA), pointing to the projected token file.
+const webIdentityTokenFileEnvVar = "AWS_WEB_IDENTITY_TOKEN_FILE"
+
+// credentialsRetries is how many times retrieving credentials is retried when
+// the credentials or the token they are obtained with are rejected as expired
+// or invalid, as the web identity token file may be replaced while it is read.
+// Other errors are retried by the retryer of the SDK.
+const credentialsRetries = 2
+
+// credentialsRetryBackoff is the wait before the first retry of retrieving
+// credentials, it is doubled on each retry.
+var credentialsRetryBackoff = 500 * time.Millisecond
+
+// expiredCredentialsErrorCodes are the error codes of STS for expired or
+// invalid credentials and tokens.
+var expiredCredentialsErrorCodes = map[string]bool{
+	"ExpiredToken":          true,
+	"ExpiredTokenException": true,
+	"InvalidClientTokenId":  true,
+	"InvalidIdentityToken":  true,
+}
+
+// refreshingCredentialsProvider caches the credentials of a provider and
+// retrieves them again before they expire, or as soon as the web identity
+// token file they were obtained with is rotated.
+type refreshingCredentialsProvider struct {
+	provider  awssdk.CredentialsProvider
+	cache     *awssdk.CredentialsCache
+	tokenFile string
+	logger    *logp.Logger
+
+	mu           sync.Mutex
+	tokenModTime time.Time
+
+	// roleKey identifies the credentials in roleCredentials when they are
+	// shared, nil otherwise.
+	roleKey *roleCredentialsKey
+}
+
+// newRefreshingCredentialsProvider wraps provider in a credentials cache that
+// refreshes the credentials between margin and margin plus jitter before they
+// expire, at a random time so Beats started together don't refresh them at the
+// same time. A zero jitter defaults to the margin. When tokenFile is not empty,
+// the cached credentials are invalidated whenever the file changes.
+func newRefreshingCredentialsProvider(provider awssdk.CredentialsProvider, tokenFile string, margin time.Duration, jitter time.Duration) *refreshingCredentialsProvider {
+	if margin <= 0 {
+		margin = defaultCredentialRefreshMargin
+	}
+	if jitter == 0 {
+		jitter = margin
+	}
+	if jitter < 0 {
+		jitter = 0
+	}
+	p := &refreshingCredentialsProvider{
+		provider:  provider,
+		tokenFile: tokenFile,
+		logger:    logp.NewLogger("aws.credentials"),
+	}
+	p.cache = awssdk.NewCredentialsCache(&keepValidCredentials{provider: provider, logger: p.logger}, func(o *awssdk.CredentialsCacheOptions) {
+		o.ExpiryWindow = margin + jitter
+		o.ExpiryWindowJitterFrac = float64(jitter) / float64(margin+jitter)
+	})
+	p.tokenModTime = p.tokenFileModTime()
+	return p
+}
+
+// Retrieve returns the cached credentials, refreshing them if they are about
+// to expire or the web identity token file was rotated. A retrieval failing
+// with expired or invalid credentials is retried with backoff, as the token
+// file may be replaced while it is being read.
+func (p *refreshingCredentialsProvider) Retrieve(ctx context.Context) (awssdk.Credentials, error) {
+	if p.tokenRotated() {
+		p.logger.Debugf("Web identity token file %s was rotated, refreshing credentials", p.tokenFile)
+		p.invalidate()
+	}
+
+	creds, err := p.cache.Retrieve(ctx)
+	backoff := credentialsRetryBackoff
+	for retry := 0; err != nil && retry < credentialsRetries && isExpiredCredentialsError(err); retry++ {
+		p.logger.Debugf("Retrieving credentials failed, retrying in %s: %v", backoff, err)
+		select {
+		case <-ctx.Done():
+			return awssdk.Credentials{}, err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		creds, err = p.cache.Retrieve(ctx)
+	}
+	return creds, err
+}
+
+// isExpiredCredentialsError returns true when err is an STS error for expired
+// or invalid credentials or tokens.
+func isExpiredCredentialsError(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && expiredCredentialsErrorCodes[apiErr.ErrorCode()]
+}
+
+// invalidate drops the cached credentials.
+func (p *refreshingCredentialsProvider) invalidate() {
+	p.cache.Invalidate()
+}
+
+// tokenRotated returns true when the modification time of the token file
+// changed since it was last checked.
+func (p *refreshingCredentialsProvider) tokenRotated() bool {
+	if p.tokenFile == "" {
+		return false
+	}
+	modTime := p.tokenFileModTime()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if modTime.IsZero() || modTime.Equal(p.tokenModTime) {
+		return false
+	}
+	p.tokenModTime = modTime
+	return true
+}
+
+func (p *refreshingCredentialsProvider) tokenFileModTime() time.Time {
+	if p.tokenFile == "" {
+		return time.Time{}
+	}
+	info, err := os.Stat(p.tokenFile)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// keepValidCredentials is the provider of the credentials cache. When
+// refreshing credentials fails within the refresh window, it keeps using the
+// previous credentials until they actually expire.
+type keepValidCredentials struct {
+	provider awssdk.CredentialsProvider
+	logger   *logp.Logger
+
+	mu sync.Mutex
+	// expires is the actual expiration of the last retrieved credentials, the
+	// credentials cache moves it forward by a random part of the window.
+	expires time.Time
+}
+
+// Retrieve retrieves new credentials from the wrapped provider. When it is
+// itself a credentials cache, like the providers of the default credential
+// chain, it is invalidated first, as it would otherwise return the same
+// credentials until they actually expire.
+func (k *keepValidCredentials) Retrieve(ctx context.Context) (awssdk.Credentials, error) {
+	if cache, ok := k.provider.(*awssdk.CredentialsCache); ok {
+		cache.Invalidate()
+	}
+	creds, err := k.provider.Retrieve(ctx)
+	if err == nil {
+		k.mu.Lock()
+		k.expires = creds.Expires
+		k.mu.Unlock()
+	}
+	return creds, err
+}
+
+// HandleFailToRefresh implements awssdk.HandleFailRefreshCredentialsCacheStrategy.
+// The previous credentials are returned with their actual expiration while they
+// are still valid.
+func (k *keepValidCredentials) HandleFailToRefresh(_ context.Context, prev awssdk.Credentials, err error) (awssdk.Credentials, error) {
+	k.mu.Lock()
+	expires := k.expires
+	k.mu.Unlock()
+	if prev.HasKeys() && prev.CanExpire && time.Now().Before(expires) {
+		k.logger.Warnf("Refreshing credentials failed, using the previous credentials until they expire: %v", err)
+		prev.Expires = expires
+		return prev, nil
+	}
+	return awssdk.Credentials{}, err
+}
+
+// roleCredentials are the credentials of the assumed roles, shared by all the
+// AWS configs of the Beat assuming the same role with the same settings, so
+// the role is assumed once for all of them instead of once per input or
+// metricset. The credentials are removed when the last AWS config using them
+// is released with ReleaseAWSConfig.
+var roleCredentials = struct {
+	sync.Mutex
+	providers map[roleCredentialsKey]*sharedCredentials
+}{providers: map[roleCredentialsKey]*sharedCredentials{}}
+
+type roleCredentialsKey struct {
+	roleArn    string
+	configHash uint64
+}
+
+// sharedCredentials are role credentials with the number of AWS configs using them.
+type sharedCredentials struct {
+	provider *refreshingCredentialsProvider
+	refs     int
+}
+
+// sharedRoleCredentials returns the credentials of the role of beatsConfig
+// assumed in region, creating them with newProvider the first time.
+func sharedRoleCredentials(beatsConfig ConfigAWS, region string, newProvider func() *refreshingCredentialsProvider) (*refreshingCredentialsProvider, error) {
+	configHash, err := hashstructure.Hash([]interface{}{beatsConfig, region}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error hashing the AWS config of role %s: %w", beatsConfig.RoleArn, err)
+	}
+	key := roleCredentialsKey{roleArn: beatsConfig.RoleArn, configHash: configHash}
+
+	roleCredentials.Lock()
+	defer roleCredentials.Unlock()
+	if shared, ok := roleCredentials.providers[key]; ok {
+		shared.refs++
+		return shared.provider, nil
+	}
+	provider := newProvider()
+	provider.roleKey = &key
+	roleCredentials.providers[key] = &sharedCredentials{provider: provider, refs: 1}
+	return provider, nil
+}
+
+// ReleaseAWSConfig releases the credentials of an AWS config created with
+// InitializeAWSConfig when it is no longer used. It must be called once per
+// AWS config, the credentials of an assumed role are removed when the last
+// AWS config using them is released.
+func ReleaseAWSConfig(awsConfig awssdk.Config) {
+	provider, ok := awsConfig.Credentials.(*refreshingCredentialsProvider)
+	if !ok || provider.roleKey == nil {
+		return
+	}
+
+	roleCredentials.Lock()
+	defer roleCredentials.Unlock()
+	shared, ok := roleCredentials.providers[*provider.roleKey]
+	if !ok || shared.provider != provider {
+		return
+	}
+	shared.refs--
+	if shared.refs <= 0 {
+		delete(roleCredentials.providers, *provider.roleKey)
+	}
+}
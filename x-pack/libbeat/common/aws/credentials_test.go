@@ -6,8 +6,17 @@ package aws
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go"
 
 	"github.com/stretchr/testify/assert"
 
@@ -35,6 +44,115 @@ func TestInitializeAWSConfig(t *testing.T) {
 	assert.NotNil(t, awsConfig.HTTPClient.(*http.Client).Transport.(*http.Transport).Proxy)
 }
 
+func TestInitializeAWSConfigNoProxy(t *testing.T) {
+	inputConfig := ConfigAWS{
+		AccessKeyID:     "123",
+		SecretAccessKey: "abc",
+		ProxyUrl:        "http://proxy:3128",
+		NoProxy:         []string{"s3.us-east-1.amazonaws.com", ".internal"},
+	}
+	awsConfig, err := InitializeAWSConfig(inputConfig)
+	assert.NoError(t, err)
+
+	proxy := awsConfig.HTTPClient.(*http.Client).Transport.(*http.Transport).Proxy
+	cases := map[string]string{
+		"https://monitoring.us-east-1.amazonaws.com/": "http://proxy:3128",
+		"https://s3.us-east-1.amazonaws.com/":         "",
+		"https://vpce.monitoring.internal/":           "",
+	}
+	for endpoint, expectedProxy := range cases {
+		req, err := http.NewRequest(http.MethodPost, endpoint, nil)
+		assert.NoError(t, err)
+		proxyURL, err := proxy(req)
+		assert.NoError(t, err)
+		if expectedProxy == "" {
+			assert.Nil(t, proxyURL, endpoint)
+		} else {
+			assert.Equal(t, expectedProxy, proxyURL.String(), endpoint)
+		}
+	}
+}
+
+func TestInitializeAWSConfigInvalidTLS(t *testing.T) {
+	inputConfig := ConfigAWS{
+		AccessKeyID:     "123",
+		SecretAccessKey: "abc",
+		TLS: &tlscommon.Config{
+			CAs: []string{filepath.Join(t.TempDir(), "missing-ca.pem")},
+		},
+	}
+	_, err := InitializeAWSConfig(inputConfig)
+	assert.Error(t, err)
+}
+
+func TestInitializeAWSConfigRetry(t *testing.T) {
+	inputConfig := ConfigAWS{
+		AccessKeyID:     "123",
+		SecretAccessKey: "abc",
+		Retry: RetryConfig{
+			Mode:        "adaptive",
+			MaxAttempts: 10,
+		},
+	}
+	awsConfig, err := InitializeAWSConfig(inputConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, awssdk.RetryModeAdaptive, awsConfig.RetryMode)
+	assert.Equal(t, 10, awsConfig.RetryMaxAttempts)
+
+	inputConfig.Retry.Mode = "exponential"
+	_, err = InitializeAWSConfig(inputConfig)
+	assert.Error(t, err)
+}
+
+func TestInitializeAWSConfigFIPS(t *testing.T) {
+	type fipsProvider interface {
+		GetUseFIPSEndpoint(context.Context) (awssdk.FIPSEndpointState, bool, error)
+	}
+	fipsEndpointState := func(awsConfig awssdk.Config) awssdk.FIPSEndpointState {
+		for _, source := range awsConfig.ConfigSources {
+			if provider, ok := source.(fipsProvider); ok {
+				if state, found, _ := provider.GetUseFIPSEndpoint(context.Background()); found {
+					return state
+				}
+			}
+		}
+		return awssdk.FIPSEndpointStateUnset
+	}
+
+	inputConfig := ConfigAWS{
+		AccessKeyID:     "123",
+		SecretAccessKey: "abc",
+	}
+	awsConfig, err := InitializeAWSConfig(inputConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, awssdk.FIPSEndpointStateUnset, fipsEndpointState(awsConfig))
+
+	inputConfig.FIPSEnabled = true
+	awsConfig, err = InitializeAWSConfig(inputConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, awssdk.FIPSEndpointStateEnabled, fipsEndpointState(awsConfig))
+}
+
+func TestSTSEndpointOptions(t *testing.T) {
+	cases := map[string]string{
+		"":         "eu-west-1",
+		"regional": "eu-west-1",
+		"legacy":   "aws-global",
+	}
+	for stsRegionalEndpoints, expectedRegion := range cases {
+		options := sts.Options{Region: "eu-west-1"}
+		STSEndpointOptions(ConfigAWS{STSRegionalEndpoints: stsRegionalEndpoints})(&options)
+		assert.Equal(t, expectedRegion, options.Region, stsRegionalEndpoints)
+	}
+
+	_, err := InitializeAWSConfig(ConfigAWS{
+		AccessKeyID:          "123",
+		SecretAccessKey:      "abc",
+		STSRegionalEndpoints: "global",
+	})
+	assert.Error(t, err)
+}
+
 func TestGetAWSCredentials(t *testing.T) {
 	inputConfig := ConfigAWS{
 		AccessKeyID:     "123",
@@ -52,6 +170,24 @@ func TestGetAWSCredentials(t *testing.T) {
 	assert.Equal(t, inputConfig.SessionToken, retrievedAWSConfig.SessionToken)
 }
 
+func TestGetAWSCredentialsFromProcess(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the credential process is run with sh")
+	}
+	inputConfig := ConfigAWS{
+		CredentialProcess: `echo '{"Version": 1, "AccessKeyId": "123", "SecretAccessKey": "abc", "SessionToken": "fake-session-token"}'`,
+	}
+	awsConfig, err := GetAWSCredentials(inputConfig)
+	assert.NoError(t, err)
+
+	retrievedAWSConfig, err := awsConfig.Credentials.Retrieve(context.Background())
+	assert.NoError(t, err)
+
+	assert.Equal(t, "123", retrievedAWSConfig.AccessKeyID)
+	assert.Equal(t, "abc", retrievedAWSConfig.SecretAccessKey)
+	assert.Equal(t, "fake-session-token", retrievedAWSConfig.SessionToken)
+}
+
 func TestDefaultRegion(t *testing.T) {
 	cases := []struct {
 		title          string
@@ -84,3 +220,234 @@ func TestDefaultRegion(t *testing.T) {
 		})
 	}
 }
+
+type countingCredentialsProvider struct {
+	calls   int
+	expires time.Time
+	err     error
+}
+
+func (c *countingCredentialsProvider) Retrieve(context.Context) (awssdk.Credentials, error) {
+	c.calls++
+	if c.err != nil {
+		return awssdk.Credentials{}, c.err
+	}
+	return awssdk.Credentials{
+		AccessKeyID:     "123",
+		SecretAccessKey: "abc",
+		CanExpire:       true,
+		Expires:         c.expires,
+	}, nil
+}
+
+// failingCredentialsProvider fails to retrieve credentials the first failures
+// times with err.
+type failingCredentialsProvider struct {
+	countingCredentialsProvider
+	failures int
+	err      error
+}
+
+func (f *failingCredentialsProvider) Retrieve(ctx context.Context) (awssdk.Credentials, error) {
+	if f.failures > 0 {
+		f.failures--
+		f.calls++
+		return awssdk.Credentials{}, f.err
+	}
+	return f.countingCredentialsProvider.Retrieve(ctx)
+}
+
+func TestRefreshingCredentialsProviderRetry(t *testing.T) {
+	backoff := credentialsRetryBackoff
+	credentialsRetryBackoff = time.Millisecond
+	defer func() { credentialsRetryBackoff = backoff }()
+
+	expiredErr := &smithy.GenericAPIError{Code: "InvalidIdentityToken", Message: "token expired"}
+
+	// Expired or invalid credentials are retried
+	provider := &failingCredentialsProvider{failures: 1, err: expiredErr}
+	provider.expires = time.Now().Add(time.Hour)
+	creds, err := newRefreshingCredentialsProvider(provider, "", time.Minute, 0).Retrieve(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "123", creds.AccessKeyID)
+	assert.Equal(t, 2, provider.calls)
+
+	// up to credentialsRetries times
+	provider = &failingCredentialsProvider{failures: credentialsRetries + 1, err: expiredErr}
+	_, err = newRefreshingCredentialsProvider(provider, "", time.Minute, 0).Retrieve(context.Background())
+	assert.ErrorIs(t, err, expiredErr)
+	assert.Equal(t, credentialsRetries+1, provider.calls)
+
+	// Other errors are left to the retryer of the SDK
+	provider = &failingCredentialsProvider{failures: 1, err: errors.New("connection refused")}
+	_, err = newRefreshingCredentialsProvider(provider, "", time.Minute, 0).Retrieve(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 1, provider.calls)
+}
+
+func TestRefreshingCredentialsProviderTokenRotation(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	assert.NoError(t, os.WriteFile(tokenFile, []byte("first"), 0o600))
+
+	provider := &countingCredentialsProvider{expires: time.Now().Add(time.Hour)}
+	refreshing := newRefreshingCredentialsProvider(provider, tokenFile, time.Minute, 0)
+
+	_, err := refreshing.Retrieve(context.Background())
+	assert.NoError(t, err)
+	_, err = refreshing.Retrieve(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, provider.calls)
+
+	assert.NoError(t, os.WriteFile(tokenFile, []byte("second"), 0o600))
+	rotated := time.Now().Add(time.Minute)
+	assert.NoError(t, os.Chtimes(tokenFile, rotated, rotated))
+
+	_, err = refreshing.Retrieve(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, provider.calls)
+}
+
+func TestRefreshingCredentialsProviderMargin(t *testing.T) {
+	provider := &countingCredentialsProvider{expires: time.Now().Add(2 * time.Minute)}
+	refreshing := newRefreshingCredentialsProvider(provider, "", 5*time.Minute, 0)
+
+	_, err := refreshing.Retrieve(context.Background())
+	assert.NoError(t, err)
+	_, err = refreshing.Retrieve(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, provider.calls)
+
+	// Credentials that are within the margin are kept when refreshing fails
+	provider.err = errors.New("token expired")
+	creds, err := refreshing.Retrieve(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "123", creds.AccessKeyID)
+
+	provider.expires = time.Now().Add(-time.Minute)
+	provider.err = nil
+	_, err = refreshing.Retrieve(context.Background())
+	assert.NoError(t, err)
+	provider.err = errors.New("token expired")
+	_, err = refreshing.Retrieve(context.Background())
+	assert.Error(t, err)
+}
+
+func TestRefreshingCredentialsProviderInnerCache(t *testing.T) {
+	// The providers of the default credential chain are credentials caches
+	// that only refresh the credentials when they are about to actually expire.
+	provider := &countingCredentialsProvider{expires: time.Now().Add(2 * time.Minute)}
+	inner := awssdk.NewCredentialsCache(provider)
+	refreshing := newRefreshingCredentialsProvider(inner, "", 5*time.Minute, 0)
+
+	_, err := refreshing.Retrieve(context.Background())
+	assert.NoError(t, err)
+	_, err = refreshing.Retrieve(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, provider.calls)
+}
+
+func TestRefreshingCredentialsProviderJitter(t *testing.T) {
+	expires := time.Now().Add(time.Hour)
+	provider := &countingCredentialsProvider{expires: expires}
+	refreshing := newRefreshingCredentialsProvider(provider, "", 5*time.Minute, 10*time.Minute)
+
+	// The credentials are refreshed between the margin and the margin plus
+	// the jitter before they expire
+	creds, err := refreshing.Retrieve(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, creds.Expires.Before(expires.Add(-15*time.Minute)))
+	assert.False(t, creds.Expires.After(expires.Add(-5*time.Minute)))
+
+	_, err = refreshing.Retrieve(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, provider.calls)
+}
+
+func TestInitializeAWSConfigSharedRoleCredentials(t *testing.T) {
+	inputConfig := ConfigAWS{
+		AccessKeyID:     "123",
+		SecretAccessKey: "abc",
+		DefaultRegion:   "us-east-1",
+		RoleArn:         "arn:aws:iam::123456789012:role/shared",
+	}
+	first, err := InitializeAWSConfig(inputConfig)
+	assert.NoError(t, err)
+	second, err := InitializeAWSConfig(inputConfig)
+	assert.NoError(t, err)
+	assert.Same(t, first.Credentials, second.Credentials)
+
+	// Other roles and settings have their own credentials
+	otherRole := inputConfig
+	otherRole.RoleArn = "arn:aws:iam::123456789012:role/other"
+	other, err := InitializeAWSConfig(otherRole)
+	assert.NoError(t, err)
+	assert.NotSame(t, first.Credentials, other.Credentials)
+
+	otherRegion := inputConfig
+	otherRegion.DefaultRegion = "eu-west-1"
+	other, err = InitializeAWSConfig(otherRegion)
+	assert.NoError(t, err)
+	assert.NotSame(t, first.Credentials, other.Credentials)
+
+	// Credentials without role are not shared
+	inputConfig.RoleArn = ""
+	first, err = InitializeAWSConfig(inputConfig)
+	assert.NoError(t, err)
+	second, err = InitializeAWSConfig(inputConfig)
+	assert.NoError(t, err)
+	assert.NotSame(t, first.Credentials, second.Credentials)
+}
+
+func TestReleaseAWSConfigRoleCredentials(t *testing.T) {
+	inputConfig := ConfigAWS{
+		AccessKeyID:     "123",
+		SecretAccessKey: "abc",
+		DefaultRegion:   "us-east-1",
+		RoleArn:         "arn:aws:iam::123456789012:role/released",
+	}
+	countProviders := func() int {
+		roleCredentials.Lock()
+		defer roleCredentials.Unlock()
+		return len(roleCredentials.providers)
+	}
+	providers := countProviders()
+
+	first, err := InitializeAWSConfig(inputConfig)
+	assert.NoError(t, err)
+	second, err := InitializeAWSConfig(inputConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, providers+1, countProviders())
+
+	// The credentials are kept while an AWS config uses them
+	ReleaseAWSConfig(first)
+	assert.Equal(t, providers+1, countProviders())
+	third, err := InitializeAWSConfig(inputConfig)
+	assert.NoError(t, err)
+	assert.Same(t, second.Credentials, third.Credentials)
+
+	ReleaseAWSConfig(second)
+	ReleaseAWSConfig(third)
+	assert.Equal(t, providers, countProviders())
+
+	// New credentials are created once the previous ones are released
+	fourth, err := InitializeAWSConfig(inputConfig)
+	assert.NoError(t, err)
+	assert.NotSame(t, first.Credentials, fourth.Credentials)
+	ReleaseAWSConfig(fourth)
+
+	// AWS configs without shared credentials are ignored
+	inputConfig.RoleArn = ""
+	unshared, err := InitializeAWSConfig(inputConfig)
+	assert.NoError(t, err)
+	ReleaseAWSConfig(unshared)
+	assert.Equal(t, providers, countProviders())
+}
+
+func TestInitializeAWSConfigWebIdentityRequiresRole(t *testing.T) {
+	_, err := InitializeAWSConfig(ConfigAWS{
+		AccessKeyID:          "123",
+		SecretAccessKey:      "abc",
+		WebIdentityTokenFile: "/var/run/secrets/eks.amazonaws.com/serviceaccount/token",
+	})
+	assert.Error(t, err)
+}
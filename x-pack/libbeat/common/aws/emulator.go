@@ -0,0 +1,70 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package aws
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+)
+
+// emulatorCredentials are the credentials used with emulator_endpoint when no
+// credentials are configured. Emulators like LocalStack accept any credentials.
+var emulatorCredentials = credentials.NewStaticCredentialsProvider("test", "test", "")
+
+// parseEmulatorEndpoint returns the URL of emulator_endpoint, like
+// http://localhost:4566.
+func parseEmulatorEndpoint(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid emulator_endpoint %s: %w", endpoint, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid emulator_endpoint %s, it must be a URL like http://localhost:4566", endpoint)
+	}
+	return strings.TrimSuffix(u.String(), "/"), nil
+}
+
+// newEmulatorEndpointResolver returns an endpoint resolver sending the requests
+// of all the AWS clients to the emulator. The host of the emulator is never
+// modified, so the requests to S3 use path-style addressing.
+func newEmulatorEndpointResolver(endpointURL string) awssdk.EndpointResolverWithOptions {
+	return awssdk.EndpointResolverWithOptionsFunc(func(service, region string, options ...interface{}) (awssdk.Endpoint, error) {
+		return awssdk.Endpoint{
+			URL:               endpointURL,
+			SigningRegion:     region,
+			HostnameImmutable: true,
+			Source:            awssdk.EndpointSourceCustom,
+		}, nil
+	})
+}
+
+// applyEmulatorEndpoint makes the AWS clients created from awsConfig send all
+// their requests to the emulator of emulator_endpoint, like LocalStack.
+func applyEmulatorEndpoint(beatsConfig ConfigAWS, awsConfig *awssdk.Config) error {
+	if beatsConfig.EmulatorEndpoint == "" {
+		return nil
+	}
+	if len(beatsConfig.VPCEndpoints) > 0 {
+		return fmt.Errorf("emulator_endpoint can't be used with vpc_endpoints")
+	}
+	endpointURL, err := parseEmulatorEndpoint(beatsConfig.EmulatorEndpoint)
+	if err != nil {
+		return err
+	}
+	awsConfig.EndpointResolverWithOptions = newEmulatorEndpointResolver(endpointURL)
+	return nil
+}
+
+// getConfigForEmulator creates a default AWS config with the placeholder
+// credentials of the emulator.
+func getConfigForEmulator() awssdk.Config {
+	config := awssdk.NewConfig()
+	config.Credentials = emulatorCredentials
+	return *config
+}
@@ -0,0 +1,69 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEmulatorEndpoint(t *testing.T) {
+	endpointURL, err := parseEmulatorEndpoint("http://localhost:4566/")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost:4566", endpointURL)
+
+	endpointURL, err = parseEmulatorEndpoint("https://localstack.example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://localstack.example.com", endpointURL)
+
+	_, err = parseEmulatorEndpoint("localhost:4566")
+	assert.Error(t, err)
+	_, err = parseEmulatorEndpoint("ftp://localhost:4566")
+	assert.Error(t, err)
+}
+
+func TestInitializeAWSConfigEmulator(t *testing.T) {
+	inputConfig := ConfigAWS{
+		EmulatorEndpoint: "http://localhost:4566",
+	}
+	awsConfig, err := InitializeAWSConfig(inputConfig)
+	require.NoError(t, err)
+	assert.Equal(t, "us-east-1", awsConfig.Region)
+
+	// All the services are sent to the emulator, without modifying its host
+	require.NotNil(t, awsConfig.EndpointResolverWithOptions)
+	for _, service := range []string{"CloudWatch", "EC2", "S3", "STS"} {
+		endpoint, err := awsConfig.EndpointResolverWithOptions.ResolveEndpoint(service, "eu-west-1")
+		require.NoError(t, err)
+		assert.Equal(t, "http://localhost:4566", endpoint.URL)
+		assert.Equal(t, "eu-west-1", endpoint.SigningRegion)
+		assert.True(t, endpoint.HostnameImmutable)
+	}
+
+	// Placeholder credentials are used when none are configured
+	credentials, err := awsConfig.Credentials.Retrieve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "test", credentials.AccessKeyID)
+
+	inputConfig.AccessKeyID = "123"
+	inputConfig.SecretAccessKey = "abc"
+	awsConfig, err = InitializeAWSConfig(inputConfig)
+	require.NoError(t, err)
+	credentials, err = awsConfig.Credentials.Retrieve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "123", credentials.AccessKeyID)
+
+	inputConfig.VPCEndpoints = []string{monitoringVPCEndpoint}
+	_, err = InitializeAWSConfig(inputConfig)
+	assert.Error(t, err)
+
+	inputConfig.VPCEndpoints = nil
+	inputConfig.EmulatorEndpoint = "localhost"
+	_, err = InitializeAWSConfig(inputConfig)
+	assert.Error(t, err)
+}
@@ -0,0 +1,74 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/ec2/imds"
+)
+
+// instanceMetadataTimeout bounds the requests to the instance metadata
+// service, which is not reachable when the Beat doesn't run on EC2.
+const instanceMetadataTimeout = 2 * time.Second
+
+// InstanceIdentity holds the region and account of the EC2 instance the Beat
+// runs on.
+type InstanceIdentity struct {
+	Region    string
+	AccountID string
+}
+
+type instanceIdentityClient interface {
+	GetInstanceIdentityDocument(ctx context.Context, params *imds.GetInstanceIdentityDocumentInput, optFns ...func(*imds.Options)) (*imds.GetInstanceIdentityDocumentOutput, error)
+}
+
+var (
+	// newInstanceIdentityClient creates the client of the instance metadata
+	// service. The client gets a session token first, using IMDSv2.
+	newInstanceIdentityClient = func() instanceIdentityClient {
+		return imds.New(imds.Options{})
+	}
+
+	instanceIdentityMutex  sync.Mutex
+	instanceIdentity       *InstanceIdentity
+	instanceIdentityErr    error
+	instanceIdentityLoaded bool
+)
+
+// GetInstanceIdentity returns the region and account of the EC2 instance the
+// Beat runs on, from the instance identity document of the instance metadata
+// service. The result is retrieved once and shared by all the callers, so
+// Beats not running on EC2 only wait for the metadata service once.
+func GetInstanceIdentity() (InstanceIdentity, error) {
+	instanceIdentityMutex.Lock()
+	defer instanceIdentityMutex.Unlock()
+
+	if !instanceIdentityLoaded {
+		instanceIdentity, instanceIdentityErr = getInstanceIdentity(newInstanceIdentityClient())
+		instanceIdentityLoaded = true
+	}
+	if instanceIdentityErr != nil {
+		return InstanceIdentity{}, instanceIdentityErr
+	}
+	return *instanceIdentity, nil
+}
+
+func getInstanceIdentity(client instanceIdentityClient) (*InstanceIdentity, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), instanceMetadataTimeout)
+	defer cancel()
+
+	output, err := client.GetInstanceIdentityDocument(ctx, &imds.GetInstanceIdentityDocumentInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the instance identity document from the instance metadata service: %w", err)
+	}
+	return &InstanceIdentity{
+		Region:    output.Region,
+		AccountID: output.AccountID,
+	}, nil
+}
@@ -0,0 +1,83 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/ec2/imds"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockInstanceIdentityClient struct {
+	document imds.InstanceIdentityDocument
+	err      error
+	calls    int
+}
+
+func (c *mockInstanceIdentityClient) GetInstanceIdentityDocument(ctx context.Context, params *imds.GetInstanceIdentityDocumentInput, optFns ...func(*imds.Options)) (*imds.GetInstanceIdentityDocumentOutput, error) {
+	c.calls++
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &imds.GetInstanceIdentityDocumentOutput{InstanceIdentityDocument: c.document}, nil
+}
+
+// withInstanceIdentityClient makes GetInstanceIdentity use client until the
+// end of the test.
+func withInstanceIdentityClient(t *testing.T, client instanceIdentityClient) {
+	newClient := newInstanceIdentityClient
+	resetInstanceIdentity()
+	newInstanceIdentityClient = func() instanceIdentityClient { return client }
+	t.Cleanup(func() {
+		newInstanceIdentityClient = newClient
+		resetInstanceIdentity()
+	})
+}
+
+func resetInstanceIdentity() {
+	instanceIdentityMutex.Lock()
+	defer instanceIdentityMutex.Unlock()
+	instanceIdentity, instanceIdentityErr, instanceIdentityLoaded = nil, nil, false
+}
+
+func TestGetInstanceIdentity(t *testing.T) {
+	client := &mockInstanceIdentityClient{
+		document: imds.InstanceIdentityDocument{Region: "eu-west-1", AccountID: "123456789012"},
+	}
+	withInstanceIdentityClient(t, client)
+
+	for i := 0; i < 2; i++ {
+		identity, err := GetInstanceIdentity()
+		assert.NoError(t, err)
+		assert.Equal(t, InstanceIdentity{Region: "eu-west-1", AccountID: "123456789012"}, identity)
+	}
+	assert.Equal(t, 1, client.calls)
+}
+
+func TestGetInstanceIdentityNotOnEC2(t *testing.T) {
+	client := &mockInstanceIdentityClient{err: errors.New("connection refused")}
+	withInstanceIdentityClient(t, client)
+
+	for i := 0; i < 2; i++ {
+		_, err := GetInstanceIdentity()
+		assert.Error(t, err)
+	}
+	assert.Equal(t, 1, client.calls)
+}
+
+func TestInitializeAWSConfigWithoutInstanceRegion(t *testing.T) {
+	client := &mockInstanceIdentityClient{
+		document: imds.InstanceIdentityDocument{Region: "eu-west-1", AccountID: "123456789012"},
+	}
+	withInstanceIdentityClient(t, client)
+
+	awsConfig, err := InitializeAWSConfig(ConfigAWS{AccessKeyID: "123", SecretAccessKey: "abc"})
+	assert.NoError(t, err)
+	assert.Equal(t, "us-east-1", awsConfig.Region)
+	assert.Equal(t, 0, client.calls)
+}
@@ -0,0 +1,155 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package aws
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// vpcEndpointPrefixes are the endpoint prefixes of the services whose prefix is
+// not their SDK service ID in lower case without spaces. The prefix is the part
+// of the DNS name of an interface VPC endpoint naming the service, like
+// monitoring in vpce-0123456789abcdef0-abcdefgh.monitoring.us-east-1.vpce.amazonaws.com.
+var vpcEndpointPrefixes = map[string]string{
+	"API Gateway":                 "apigateway",
+	"ApiGatewayV2":                "apigateway",
+	"AppSync":                     "appsync-api",
+	"CloudWatch":                  "monitoring",
+	"CloudWatch Logs":             "logs",
+	"Cognito Identity Provider":   "cognito-idp",
+	"Config Service":              "config",
+	"Cost Explorer":               "ce",
+	"DocDB":                       "rds",
+	"EFS":                         "elasticfilesystem",
+	"Elastic Load Balancing v2":   "elasticloadbalancing",
+	"EMR":                         "elasticmapreduce",
+	"EventBridge":                 "events",
+	"Kinesis":                     "kinesis-streams",
+	"Neptune":                     "rds",
+	"OpenSearch":                  "es",
+	"Resource Groups Tagging API": "tagging",
+	"SageMaker":                   "api.sagemaker",
+	"SES":                         "email",
+	"SFN":                         "states",
+}
+
+// vpcEndpointPrefix returns the endpoint prefix of a service from its SDK
+// service ID.
+func vpcEndpointPrefix(serviceID string) string {
+	if prefix, ok := vpcEndpointPrefixes[serviceID]; ok {
+		return prefix
+	}
+	return strings.ToLower(strings.ReplaceAll(serviceID, " ", ""))
+}
+
+type vpcEndpointKey struct {
+	prefix string
+	region string
+}
+
+// parseVPCEndpoint returns the endpoint prefix and region of the DNS name of an
+// interface VPC endpoint, vpce-<id>.<prefix>.<region>.vpce.<domain>.
+func parseVPCEndpoint(dnsName string) (vpcEndpointKey, string, error) {
+	host := strings.TrimSuffix(strings.TrimPrefix(dnsName, "https://"), "/")
+	labels := strings.Split(host, ".")
+	vpceLabel := -1
+	for i := 1; i < len(labels); i++ {
+		if labels[i] == "vpce" {
+			vpceLabel = i
+			break
+		}
+	}
+	if !strings.HasPrefix(labels[0], "vpce-") || vpceLabel < 3 || vpceLabel == len(labels)-1 {
+		return vpcEndpointKey{}, "", fmt.Errorf("%s is not the DNS name of an interface VPC endpoint, like vpce-0123456789abcdef0-abcdefgh.monitoring.us-east-1.vpce.amazonaws.com", dnsName)
+	}
+	key := vpcEndpointKey{
+		prefix: strings.Join(labels[1:vpceLabel-1], "."),
+		region: labels[vpceLabel-1],
+	}
+	return key, host, nil
+}
+
+// parseVPCEndpoints returns the hosts of the VPC endpoints of vpc_endpoints by
+// endpoint prefix and region.
+func parseVPCEndpoints(dnsNames []string) (map[vpcEndpointKey]string, error) {
+	endpoints := make(map[vpcEndpointKey]string, len(dnsNames))
+	for _, dnsName := range dnsNames {
+		key, host, err := parseVPCEndpoint(dnsName)
+		if err != nil {
+			return nil, err
+		}
+		if other, ok := endpoints[key]; ok && other != host {
+			return nil, fmt.Errorf("vpc_endpoints has two endpoints for %s in %s: %s and %s", key.prefix, key.region, other, host)
+		}
+		endpoints[key] = host
+	}
+	return endpoints, nil
+}
+
+// newVPCEndpointResolver returns an endpoint resolver sending the requests of
+// the AWS clients to the VPC endpoints. Resolving the endpoint of a service
+// without a VPC endpoint in the region fails, instead of falling back to the
+// public endpoint of the service.
+func newVPCEndpointResolver(endpoints map[vpcEndpointKey]string) awssdk.EndpointResolverWithOptions {
+	return awssdk.EndpointResolverWithOptionsFunc(func(service, region string, options ...interface{}) (awssdk.Endpoint, error) {
+		host, ok := endpoints[vpcEndpointKey{prefix: vpcEndpointPrefix(service), region: region}]
+		if !ok {
+			return awssdk.Endpoint{}, fmt.Errorf("no VPC endpoint for %s (%s) in region %s in vpc_endpoints", service, vpcEndpointPrefix(service), region)
+		}
+		return awssdk.Endpoint{
+			URL:               "https://" + host,
+			SigningRegion:     region,
+			HostnameImmutable: true,
+			Source:            awssdk.EndpointSourceCustom,
+		}, nil
+	})
+}
+
+// applyVPCEndpoints makes the AWS clients created from awsConfig only use the
+// VPC endpoints of vpc_endpoints.
+func applyVPCEndpoints(beatsConfig ConfigAWS, awsConfig *awssdk.Config) error {
+	if len(beatsConfig.VPCEndpoints) == 0 {
+		return nil
+	}
+	if beatsConfig.STSRegionalEndpoints == stsLegacyEndpoints {
+		return fmt.Errorf("sts_regional_endpoints %s can't be used with vpc_endpoints, STS VPC endpoints are regional", stsLegacyEndpoints)
+	}
+	endpoints, err := parseVPCEndpoints(beatsConfig.VPCEndpoints)
+	if err != nil {
+		return err
+	}
+	awsConfig.EndpointResolverWithOptions = newVPCEndpointResolver(endpoints)
+	return nil
+}
+
+// CheckVPCEndpoints returns an error if the VPC endpoints of vpc_endpoints don't
+// include an endpoint of the service, identified by its SDK service ID, in each
+// of the regions. No VPC endpoints means the public endpoints are used.
+func CheckVPCEndpoints(vpcEndpoints []string, serviceID string, regions []string) error {
+	if len(vpcEndpoints) == 0 {
+		return nil
+	}
+	endpoints, err := parseVPCEndpoints(vpcEndpoints)
+	if err != nil {
+		return err
+	}
+
+	prefix := vpcEndpointPrefix(serviceID)
+	var missing []string
+	for _, region := range regions {
+		if _, ok := endpoints[vpcEndpointKey{prefix: prefix, region: region}]; !ok {
+			missing = append(missing, region)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("vpc_endpoints has no VPC endpoint for %s (%s) in regions %s", serviceID, prefix, strings.Join(missing, ", "))
+	}
+	return nil
+}
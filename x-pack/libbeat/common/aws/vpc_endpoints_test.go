@@ -0,0 +1,142 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package aws
+
+import (
+	"errors"
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	monitoringVPCEndpoint = "vpce-0123456789abcdef0-abcdefgh.monitoring.us-east-1.vpce.amazonaws.com"
+	stsVPCEndpoint        = "vpce-0123456789abcdef0-ijklmnop.sts.us-east-1.vpce.amazonaws.com"
+)
+
+func TestParseVPCEndpoint(t *testing.T) {
+	cases := []struct {
+		dnsName      string
+		expectedKey  vpcEndpointKey
+		expectedHost string
+		expectedErr  bool
+	}{
+		{
+			dnsName:      monitoringVPCEndpoint,
+			expectedKey:  vpcEndpointKey{prefix: "monitoring", region: "us-east-1"},
+			expectedHost: monitoringVPCEndpoint,
+		},
+		{
+			dnsName:      "https://vpce-0123456789abcdef0-abcdefgh-us-west-2a.api.sagemaker.us-west-2.vpce.amazonaws.com/",
+			expectedKey:  vpcEndpointKey{prefix: "api.sagemaker", region: "us-west-2"},
+			expectedHost: "vpce-0123456789abcdef0-abcdefgh-us-west-2a.api.sagemaker.us-west-2.vpce.amazonaws.com",
+		},
+		{
+			dnsName:      "vpce-0123456789abcdef0-abcdefgh.monitoring.cn-north-1.vpce.amazonaws.com.cn",
+			expectedKey:  vpcEndpointKey{prefix: "monitoring", region: "cn-north-1"},
+			expectedHost: "vpce-0123456789abcdef0-abcdefgh.monitoring.cn-north-1.vpce.amazonaws.com.cn",
+		},
+		{
+			dnsName:     "monitoring.us-east-1.amazonaws.com",
+			expectedErr: true,
+		},
+		{
+			dnsName:     "vpce-0123456789abcdef0-abcdefgh.us-east-1.vpce.amazonaws.com",
+			expectedErr: true,
+		},
+		{
+			dnsName:     "vpce-0123456789abcdef0-abcdefgh.monitoring.us-east-1.vpce",
+			expectedErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.dnsName, func(t *testing.T) {
+			key, host, err := parseVPCEndpoint(c.dnsName)
+			if c.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.expectedKey, key)
+			assert.Equal(t, c.expectedHost, host)
+		})
+	}
+}
+
+func TestParseVPCEndpointsDuplicates(t *testing.T) {
+	_, err := parseVPCEndpoints([]string{monitoringVPCEndpoint, monitoringVPCEndpoint})
+	assert.NoError(t, err)
+
+	_, err = parseVPCEndpoints([]string{monitoringVPCEndpoint, "vpce-0fedcba9876543210-abcdefgh.monitoring.us-east-1.vpce.amazonaws.com"})
+	assert.Error(t, err)
+}
+
+func TestVPCEndpointResolver(t *testing.T) {
+	endpoints, err := parseVPCEndpoints([]string{monitoringVPCEndpoint, stsVPCEndpoint})
+	require.NoError(t, err)
+	resolver := newVPCEndpointResolver(endpoints)
+
+	endpoint, err := resolver.ResolveEndpoint("CloudWatch", "us-east-1")
+	require.NoError(t, err)
+	assert.Equal(t, "https://"+monitoringVPCEndpoint, endpoint.URL)
+	assert.Equal(t, "us-east-1", endpoint.SigningRegion)
+	assert.True(t, endpoint.HostnameImmutable)
+
+	endpoint, err = resolver.ResolveEndpoint("STS", "us-east-1")
+	require.NoError(t, err)
+	assert.Equal(t, "https://"+stsVPCEndpoint, endpoint.URL)
+
+	// No fall back to the public endpoints
+	_, err = resolver.ResolveEndpoint("CloudWatch", "us-west-2")
+	assert.Error(t, err)
+	_, err = resolver.ResolveEndpoint("EC2", "us-east-1")
+	assert.Error(t, err)
+	var notFound *awssdk.EndpointNotFoundError
+	assert.False(t, errors.As(err, &notFound))
+}
+
+func TestCheckVPCEndpoints(t *testing.T) {
+	assert.NoError(t, CheckVPCEndpoints(nil, "CloudWatch", []string{"us-east-1", "us-west-2"}))
+	assert.NoError(t, CheckVPCEndpoints([]string{monitoringVPCEndpoint}, "CloudWatch", []string{"us-east-1"}))
+
+	err := CheckVPCEndpoints([]string{monitoringVPCEndpoint}, "CloudWatch", []string{"us-west-2", "us-east-1", "eu-west-1"})
+	assert.EqualError(t, err, "vpc_endpoints has no VPC endpoint for CloudWatch (monitoring) in regions eu-west-1, us-west-2")
+}
+
+func TestInitializeAWSConfigVPCEndpoints(t *testing.T) {
+	inputConfig := ConfigAWS{
+		AccessKeyID:     "123",
+		SecretAccessKey: "abc",
+		DefaultRegion:   "us-east-1",
+		VPCEndpoints:    []string{monitoringVPCEndpoint},
+	}
+	awsConfig, err := InitializeAWSConfig(inputConfig)
+	require.NoError(t, err)
+	require.NotNil(t, awsConfig.EndpointResolverWithOptions)
+	endpoint, err := awsConfig.EndpointResolverWithOptions.ResolveEndpoint("CloudWatch", "us-east-1")
+	require.NoError(t, err)
+	assert.Equal(t, "https://"+monitoringVPCEndpoint, endpoint.URL)
+
+	// Assuming a role requires an STS endpoint in the region
+	inputConfig.RoleArn = "arn:aws:iam::123456789012:role/test"
+	_, err = InitializeAWSConfig(inputConfig)
+	assert.Error(t, err)
+
+	inputConfig.VPCEndpoints = append(inputConfig.VPCEndpoints, stsVPCEndpoint)
+	_, err = InitializeAWSConfig(inputConfig)
+	assert.NoError(t, err)
+
+	inputConfig.STSRegionalEndpoints = stsLegacyEndpoints
+	_, err = InitializeAWSConfig(inputConfig)
+	assert.Error(t, err)
+
+	inputConfig.STSRegionalEndpoints = ""
+	inputConfig.VPCEndpoints = []string{"monitoring.us-east-1.amazonaws.com"}
+	_, err = InitializeAWSConfig(inputConfig)
+	assert.Error(t, err)
+}
@@ -11,8 +11,11 @@ import (
 	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/activemq"
 	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/airflow"
 	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/backup"
 	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/billing"
 	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/health"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/servicequotas"
 	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/awsfargate"
 	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/awsfargate/task_stats"
 	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/azure"
@@ -0,0 +1,95 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+)
+
+// accountAliasTTL is how long a resolved account alias is cached.
+const accountAliasTTL = 24 * time.Hour
+
+type listAccountAliasesClient interface {
+	ListAccountAliases(ctx context.Context, params *iam.ListAccountAliasesInput, optFns ...func(*iam.Options)) (*iam.ListAccountAliasesOutput, error)
+}
+
+type describeAccountClient interface {
+	DescribeAccount(ctx context.Context, params *organizations.DescribeAccountInput, optFns ...func(*organizations.Options)) (*organizations.DescribeAccountOutput, error)
+}
+
+type accountAlias struct {
+	alias      string
+	expiration time.Time
+}
+
+// AccountAliasResolver resolves and caches the alias of AWS accounts. The alias
+// of the account the credentials belong to comes from iam:ListAccountAliases,
+// the alias of other accounts is their name in AWS Organizations.
+type AccountAliasResolver struct {
+	svcIam           listAccountAliasesClient
+	svcOrganizations describeAccountClient
+	accountID        string
+
+	mutex   sync.Mutex
+	aliases map[string]accountAlias
+}
+
+// NewAccountAliasResolver creates a resolver for the aliases of accountID, the
+// account the credentials belong to, and the accounts of its organization.
+func NewAccountAliasResolver(svcIam listAccountAliasesClient, svcOrganizations describeAccountClient, accountID string) *AccountAliasResolver {
+	return &AccountAliasResolver{
+		svcIam:           svcIam,
+		svcOrganizations: svcOrganizations,
+		accountID:        accountID,
+		aliases:          map[string]accountAlias{},
+	}
+}
+
+// Resolve returns the alias of the given account ID. Accounts without alias,
+// or whose alias cannot be resolved, return an empty alias. Failed lookups are
+// cached as well, so they are not retried on every fetch.
+func (r *AccountAliasResolver) Resolve(ctx context.Context, accountID string) (string, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	now := time.Now()
+	if cached, ok := r.aliases[accountID]; ok && now.Before(cached.expiration) {
+		return cached.alias, nil
+	}
+
+	alias, err := r.lookup(ctx, accountID)
+	r.aliases[accountID] = accountAlias{alias: alias, expiration: now.Add(accountAliasTTL)}
+	return alias, err
+}
+
+func (r *AccountAliasResolver) lookup(ctx context.Context, accountID string) (string, error) {
+	if accountID == r.accountID {
+		output, err := r.svcIam.ListAccountAliases(ctx, &iam.ListAccountAliasesInput{})
+		if err != nil {
+			return "", fmt.Errorf("failed to list account aliases of account %s: %w", accountID, err)
+		}
+		// There can be more than one aliases for each account, only the first
+		// one is used, like for cloud.account.name.
+		if len(output.AccountAliases) == 0 {
+			return "", nil
+		}
+		return output.AccountAliases[0], nil
+	}
+
+	output, err := r.svcOrganizations.DescribeAccount(ctx, &organizations.DescribeAccountInput{AccountId: &accountID})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe account %s: %w", accountID, err)
+	}
+	if output.Account == nil || output.Account.Name == nil {
+		return "", nil
+	}
+	return *output.Account.Name, nil
+}
@@ -0,0 +1,76 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+//go:build !integration
+// +build !integration
+
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	organizationstypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// MockIAMClient struct is used for unit tests.
+type MockIAMClient struct {
+	calls int
+}
+
+func (m *MockIAMClient) ListAccountAliases(ctx context.Context, params *iam.ListAccountAliasesInput, optFns ...func(*iam.Options)) (*iam.ListAccountAliasesOutput, error) {
+	m.calls++
+	return &iam.ListAccountAliasesOutput{
+		AccountAliases: []string{"monitoring", "other-alias"},
+	}, nil
+}
+
+// MockOrganizationsClient struct is used for unit tests.
+type MockOrganizationsClient struct {
+	calls int
+}
+
+func (m *MockOrganizationsClient) DescribeAccount(ctx context.Context, params *organizations.DescribeAccountInput, optFns ...func(*organizations.Options)) (*organizations.DescribeAccountOutput, error) {
+	m.calls++
+	if *params.AccountId == "999999999999" {
+		return nil, errors.New("AccountNotFoundException")
+	}
+	return &organizations.DescribeAccountOutput{
+		Account: &organizationstypes.Account{
+			Id:   params.AccountId,
+			Name: awssdk.String("source-account"),
+		},
+	}, nil
+}
+
+func TestAccountAliasResolver(t *testing.T) {
+	svcIam := &MockIAMClient{}
+	svcOrganizations := &MockOrganizationsClient{}
+	resolver := NewAccountAliasResolver(svcIam, svcOrganizations, "123456789012")
+
+	alias, err := resolver.Resolve(context.Background(), "123456789012")
+	assert.NoError(t, err)
+	assert.Equal(t, "monitoring", alias)
+
+	alias, err = resolver.Resolve(context.Background(), "111111111111")
+	assert.NoError(t, err)
+	assert.Equal(t, "source-account", alias)
+
+	alias, err = resolver.Resolve(context.Background(), "999999999999")
+	assert.Error(t, err)
+	assert.Equal(t, "", alias)
+
+	// aliases are cached, including failed lookups
+	for _, accountID := range []string{"123456789012", "111111111111", "999999999999"} {
+		_, err = resolver.Resolve(context.Background(), accountID)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 1, svcIam.calls)
+	assert.Equal(t, 2, svcOrganizations.calls)
+}
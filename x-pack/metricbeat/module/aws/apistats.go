@@ -0,0 +1,105 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package aws
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go/middleware"
+
+	"github.com/elastic/elastic-agent-libs/monitoring"
+)
+
+// apiStats holds the AWS API request counters of all the aws metricsets, by
+// metricset and service, like metricbeat.aws.api.ec2.cloudwatch.calls.
+var apiStats = newAPIStatsRegistry(monitoring.Default.NewRegistry("metricbeat.aws.api"))
+
+// isThrottle detects the errors of requests throttled by AWS.
+var isThrottle = retry.IsErrorThrottles(retry.DefaultThrottles)
+
+type apiStatsKey struct {
+	metricSet string
+	serviceID string
+}
+
+// apiServiceStats are the counters of the requests of a metricset to a service.
+type apiServiceStats struct {
+	calls     *monitoring.Uint
+	errors    *monitoring.Uint
+	throttles *monitoring.Uint
+}
+
+type apiStatsRegistry struct {
+	sync.Mutex
+	registry *monitoring.Registry
+	stats    map[apiStatsKey]*apiServiceStats
+}
+
+func newAPIStatsRegistry(registry *monitoring.Registry) *apiStatsRegistry {
+	return &apiStatsRegistry{registry: registry, stats: map[apiStatsKey]*apiServiceStats{}}
+}
+
+// get returns the counters of the requests of a metricset to a service. The
+// counters are kept when the metricset is stopped, so they keep growing when
+// the metricset is restarted with a new config.
+func (r *apiStatsRegistry) get(metricSet string, serviceID string) *apiServiceStats {
+	key := apiStatsKey{metricSet: metricSet, serviceID: apiStatsServiceName(serviceID)}
+	r.Lock()
+	defer r.Unlock()
+	if stats, ok := r.stats[key]; ok {
+		return stats
+	}
+
+	metricSetRegistry := r.registry.GetRegistry(key.metricSet)
+	if metricSetRegistry == nil {
+		metricSetRegistry = r.registry.NewRegistry(key.metricSet)
+	}
+	serviceRegistry := metricSetRegistry.NewRegistry(key.serviceID)
+	stats := &apiServiceStats{
+		calls:     monitoring.NewUint(serviceRegistry, "calls"),
+		errors:    monitoring.NewUint(serviceRegistry, "errors"),
+		throttles: monitoring.NewUint(serviceRegistry, "throttles"),
+	}
+	r.stats[key] = stats
+	return stats
+}
+
+// apiStatsServiceName returns the name of the registry of a service, its SDK
+// service ID in lower case with spaces replaced, like resource_groups_tagging_api.
+func apiStatsServiceName(serviceID string) string {
+	if serviceID == "" {
+		return "unknown"
+	}
+	return strings.ReplaceAll(strings.ToLower(serviceID), " ", "_")
+}
+
+// addAPIStats makes the AWS clients created from awsConfig count their requests
+// in the API statistics of the metricset.
+func addAPIStats(awsConfig *awssdk.Config, metricSet string) {
+	awsConfig.APIOptions = append(awsConfig.APIOptions, func(stack *middleware.Stack) error {
+		// Added after the retry middleware, so every attempt is counted
+		return stack.Finalize.Add(apiStatsMiddleware(apiStats, metricSet), middleware.After)
+	})
+}
+
+func apiStatsMiddleware(registry *apiStatsRegistry, metricSet string) middleware.FinalizeMiddleware {
+	return middleware.FinalizeMiddlewareFunc("APIStats", func(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+		stats := registry.get(metricSet, awsmiddleware.GetServiceID(ctx))
+		stats.calls.Inc()
+		out, metadata, err := next.HandleFinalize(ctx, in)
+		if err != nil {
+			stats.errors.Inc()
+			if isThrottle.IsErrorThrottle(err) == awssdk.TrueTernary {
+				stats.throttles.Inc()
+			}
+		}
+		return out, metadata, err
+	})
+}
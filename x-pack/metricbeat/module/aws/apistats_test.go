@@ -0,0 +1,51 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+//go:build !integration
+// +build !integration
+
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/elastic-agent-libs/monitoring"
+)
+
+func TestAPIStatsMiddleware(t *testing.T) {
+	registry := newAPIStatsRegistry(monitoring.NewRegistry())
+	stats := apiStatsMiddleware(registry, "ec2")
+
+	results := []error{
+		nil,
+		&smithy.GenericAPIError{Code: "ThrottlingException"},
+		errors.New("connection reset"),
+	}
+	for _, result := range results {
+		ctx := awsmiddleware.SetServiceID(context.Background(), "CloudWatch")
+		_, _, err := stats.HandleFinalize(ctx, middleware.FinalizeInput{}, middleware.FinalizeHandlerFunc(
+			func(ctx context.Context, in middleware.FinalizeInput) (middleware.FinalizeOutput, middleware.Metadata, error) {
+				return middleware.FinalizeOutput{}, middleware.Metadata{}, result
+			}))
+		assert.Equal(t, result, err)
+	}
+
+	snapshot := monitoring.CollectFlatSnapshot(registry.registry, monitoring.Full, false)
+	assert.Equal(t, int64(3), snapshot.Ints["ec2.cloudwatch.calls"])
+	assert.Equal(t, int64(2), snapshot.Ints["ec2.cloudwatch.errors"])
+	assert.Equal(t, int64(1), snapshot.Ints["ec2.cloudwatch.throttles"])
+}
+
+func TestAPIStatsServiceName(t *testing.T) {
+	assert.Equal(t, "cloudwatch", apiStatsServiceName("CloudWatch"))
+	assert.Equal(t, "resource_groups_tagging_api", apiStatsServiceName("Resource Groups Tagging API"))
+	assert.Equal(t, "unknown", apiStatsServiceName(""))
+}
@@ -0,0 +1,121 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package aws
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+// ARN is an Amazon Resource Name, with the resource split in its type and ID.
+// For example, the resource of arn:aws:sqs:us-east-1:123456789012:my-queue has
+// no type, and the resource of arn:aws:lambda:us-east-1:123456789012:function:my-function
+// has the type function and the ID my-function.
+type ARN struct {
+	Partition    string
+	Service      string
+	Region       string
+	AccountID    string
+	Resource     string
+	ResourceType string
+	ResourceID   string
+}
+
+// ParseARN parses an ARN. The resource type is the part of the resource before
+// the first colon, or before the first slash when there is no colon.
+func ParseARN(resourceARN string) (ARN, error) {
+	parsed, err := arn.Parse(resourceARN)
+	if err != nil {
+		return ARN{}, fmt.Errorf("error Parse arn: %w", err)
+	}
+
+	result := ARN{
+		Partition:  parsed.Partition,
+		Service:    parsed.Service,
+		Region:     parsed.Region,
+		AccountID:  parsed.AccountID,
+		Resource:   parsed.Resource,
+		ResourceID: parsed.Resource,
+	}
+	// S3 resources are buckets and the keys of their objects
+	if parsed.Service == "s3" {
+		return result, nil
+	}
+	separator := "/"
+	if strings.Contains(parsed.Resource, ":") {
+		separator = ":"
+	}
+	if parts := strings.SplitN(parsed.Resource, separator, 2); len(parts) == 2 && parts[0] != "" {
+		result.ResourceType = parts[0]
+		result.ResourceID = parts[1]
+	}
+	return result, nil
+}
+
+// String returns the ARN in its text form.
+func (a ARN) String() string {
+	return arn.ARN{
+		Partition: a.Partition,
+		Service:   a.Service,
+		Region:    a.Region,
+		AccountID: a.AccountID,
+		Resource:  a.Resource,
+	}.String()
+}
+
+// Fields returns the components of the ARN as the fields under aws.arn.
+func (a ARN) Fields() mapstr.M {
+	resource := mapstr.M{"id": a.ResourceID}
+	if a.ResourceType != "" {
+		resource["type"] = a.ResourceType
+	}
+	fields := mapstr.M{
+		"partition": a.Partition,
+		"service":   a.Service,
+		"resource":  resource,
+	}
+	if a.Region != "" {
+		fields["region"] = a.Region
+	}
+	if a.AccountID != "" {
+		fields["account_id"] = a.AccountID
+	}
+	return fields
+}
+
+// AddARNFields adds the components of the ARN of the resource an event is about
+// to the event as aws.arn.*. Values that are not ARNs are ignored.
+func AddARNFields(event mb.Event, resourceARN string) {
+	if !arn.IsARN(resourceARN) {
+		return
+	}
+	parsed, err := ParseARN(resourceARN)
+	if err != nil {
+		return
+	}
+	_, _ = event.RootFields.Put("aws.arn", parsed.Fields())
+}
+
+// RegionPartition returns the partition of a region, used to build the ARNs of
+// the resources of the region.
+func RegionPartition(regionName string) string {
+	switch {
+	case strings.HasPrefix(regionName, "cn-"):
+		return "aws-cn"
+	case strings.HasPrefix(regionName, "us-gov-"):
+		return "aws-us-gov"
+	case strings.HasPrefix(regionName, "us-iso-"):
+		return "aws-iso"
+	case strings.HasPrefix(regionName, "us-isob-"):
+		return "aws-iso-b"
+	default:
+		return "aws"
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+//go:build !integration
+// +build !integration
+
+package aws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+func TestParseARN(t *testing.T) {
+	cases := []struct {
+		arn      string
+		expected ARN
+	}{
+		{
+			"arn:aws:sqs:us-east-1:123456789012:my-queue",
+			ARN{Partition: "aws", Service: "sqs", Region: "us-east-1", AccountID: "123456789012", Resource: "my-queue", ResourceID: "my-queue"},
+		},
+		{
+			"arn:aws:lambda:us-east-1:123456789012:function:my-function:prod",
+			ARN{Partition: "aws", Service: "lambda", Region: "us-east-1", AccountID: "123456789012", Resource: "function:my-function:prod", ResourceType: "function", ResourceID: "my-function:prod"},
+		},
+		{
+			"arn:aws:elasticloadbalancing:eu-west-1:123456789012:targetgroup/my-targets/73e2d6bc24d8a067",
+			ARN{Partition: "aws", Service: "elasticloadbalancing", Region: "eu-west-1", AccountID: "123456789012", Resource: "targetgroup/my-targets/73e2d6bc24d8a067", ResourceType: "targetgroup", ResourceID: "my-targets/73e2d6bc24d8a067"},
+		},
+		{
+			"arn:aws-cn:s3:::my-bucket/logs/2023",
+			ARN{Partition: "aws-cn", Service: "s3", Resource: "my-bucket/logs/2023", ResourceID: "my-bucket/logs/2023"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.arn, func(t *testing.T) {
+			parsed, err := ParseARN(c.arn)
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, parsed)
+			assert.Equal(t, c.arn, parsed.String())
+		})
+	}
+
+	_, err := ParseARN("my-queue")
+	assert.Error(t, err)
+}
+
+func TestAddARNFields(t *testing.T) {
+	event := mb.Event{RootFields: mapstr.M{}}
+	AddARNFields(event, "arn:aws:states:us-east-1:123456789012:stateMachine:orders")
+	assert.Equal(t, mapstr.M{
+		"partition":  "aws",
+		"service":    "states",
+		"region":     "us-east-1",
+		"account_id": "123456789012",
+		"resource": mapstr.M{
+			"type": "stateMachine",
+			"id":   "orders",
+		},
+	}, event.RootFields["aws"].(mapstr.M)["arn"])
+
+	event = mb.Event{RootFields: mapstr.M{}}
+	AddARNFields(event, "orders")
+	assert.Empty(t, event.RootFields)
+}
+
+func TestRegionPartition(t *testing.T) {
+	assert.Equal(t, "aws", RegionPartition("eu-west-1"))
+	assert.Equal(t, "aws-cn", RegionPartition("cn-north-1"))
+	assert.Equal(t, "aws-us-gov", RegionPartition("us-gov-west-1"))
+}
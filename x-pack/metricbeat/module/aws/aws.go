@@ -7,16 +7,19 @@ package aws
 import (
 	"context"
 	"fmt"
-
+	"path"
+	"strings"
 	"time"
 
 	awssdk "github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
 	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
 	resourcegroupstaggingapitypes "github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/joeshaw/multierror"
 
 	"github.com/elastic/beats/v7/metricbeat/mb"
 	awscommon "github.com/elastic/beats/v7/x-pack/libbeat/common/aws"
@@ -29,11 +32,24 @@ type describeRegionsClient interface {
 
 // Config defines all required and optional parameters for aws metricsets
 type Config struct {
-	Period     time.Duration       `config:"period" validate:"nonzero,required"`
-	Regions    []string            `config:"regions"`
-	Latency    time.Duration       `config:"latency"`
-	AWSConfig  awscommon.ConfigAWS `config:",inline"`
-	TagsFilter []Tag               `config:"tags_filter"`
+	Period                 time.Duration       `config:"period" validate:"nonzero,required"`
+	Regions                []string            `config:"regions"`
+	ExcludeRegions         []string            `config:"exclude_regions"`
+	Latency                time.Duration       `config:"latency"`
+	AWSConfig              awscommon.ConfigAWS `config:",inline"`
+	TagsFilter             []Tag               `config:"tags_filter"`
+	TagsFilterExpression   string              `config:"tags_filter_expression"`
+	TagsCacheTTL           time.Duration       `config:"tags_cache_ttl"`
+	DiscoveryCacheTTL      time.Duration       `config:"discovery_cache_ttl"`
+	SDKDebugLogging        bool                `config:"sdk_debug_logging"`
+	CredentialProfileNames []string            `config:"credential_profile_names"`
+	RateLimit              RateLimitConfig     `config:"rate_limit"`
+	// IncludeOrganizationMetadata adds the organizational unit and tags of
+	// the account of the events from AWS Organizations.
+	IncludeOrganizationMetadata bool `config:"include_organization_metadata"`
+	// RegionCredentials are the credentials of the regions collected with
+	// other credentials than the ones of the module, by region.
+	RegionCredentials map[string]RegionCredentials `config:"region_credentials"`
 }
 
 // MetricSet is the base metricset for all aws metricsets
@@ -47,6 +63,23 @@ type MetricSet struct {
 	AccountName string
 	AccountID   string
 	TagsFilter  []Tag
+	// TagsExpression is the compiled tags_filter_expression, nil when it is not set.
+	TagsExpression *TagsExpression
+	Tags           *TagService
+	ProfileName    string
+	Profiles       []*MetricSet
+	// Discovery lists the resources of the account, sharing them with the
+	// other metricsets.
+	Discovery *DiscoveryService
+	// VPCEndpoints are the DNS names of the VPC endpoints of vpc_endpoints.
+	VPCEndpoints []string
+	// Organization resolves the organization metadata of the accounts when
+	// include_organization_metadata is set, nil otherwise.
+	Organization *OrganizationResolver
+
+	// credentialRegions are the regions of region_credentials collected by
+	// the metricset, empty for the metricsets using the module credentials.
+	credentialRegions []string
 }
 
 // Tag holds a configuration specific for ec2 and cloudwatch metricset.
@@ -72,7 +105,10 @@ func newModule(base mb.BaseModule) (mb.Module, error) {
 	return &base, nil
 }
 
-// NewMetricSet creates a base metricset for aws metricsets
+// NewMetricSet creates a base metricset for aws metricsets. When
+// credential_profile_names or region_credentials is set, the returned
+// metricset is the one of the first profile, and Profiles holds the metricsets
+// of all the profiles.
 func NewMetricSet(base mb.BaseMetricSet) (*MetricSet, error) {
 	var config Config
 	err := base.Module().UnpackConfig(&config)
@@ -80,94 +116,312 @@ func NewMetricSet(base mb.BaseMetricSet) (*MetricSet, error) {
 		return nil, err
 	}
 
-	awsConfig, err := awscommon.InitializeAWSConfig(config.AWSConfig)
+	if len(config.RegionCredentials) > 0 {
+		if len(config.CredentialProfileNames) > 0 {
+			return nil, fmt.Errorf("region_credentials can't be used with credential_profile_names")
+		}
+		return newRegionCredentialsMetricSets(base, config)
+	}
+	if len(config.CredentialProfileNames) == 0 {
+		return newMetricSet(base, config)
+	}
+
+	if config.AWSConfig.AccessKeyID != "" || config.AWSConfig.SecretAccessKey != "" || config.AWSConfig.SessionToken != "" {
+		return nil, fmt.Errorf("credential_profile_names can't be used with access_key_id, secret_access_key or session_token")
+	}
+	profiles := make([]*MetricSet, 0, len(config.CredentialProfileNames))
+	for _, profileName := range config.CredentialProfileNames {
+		profileConfig := config
+		profileConfig.AWSConfig.ProfileName = profileName
+		metricSet, err := newMetricSet(base, profileConfig)
+		if err != nil {
+			releaseAWSConfigs(profiles)
+			return nil, fmt.Errorf("failed to create metricset for credential profile %s: %w", profileName, err)
+		}
+		metricSet.ProfileName = profileName
+		profiles = append(profiles, metricSet)
+	}
+	profiles[0].Profiles = profiles
+	return profiles[0], nil
+}
+
+// ForEachProfile calls fetch with the metricset of each credential profile of
+// credential_profile_names, or only with m when it is not set. The errors of
+// the profiles are combined, a failing profile doesn't stop the others. The
+// errors of the AWS APIs are classified with ClassifyError.
+func (m *MetricSet) ForEachProfile(fetch func(profile *MetricSet) error) error {
+	if len(m.Profiles) == 0 {
+		return ClassifyError(fetch(m))
+	}
+
+	var errs multierror.Errors
+	for _, profile := range m.Profiles {
+		if err := fetch(profile); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", profile.credentialsDescription(), ClassifyError(err)))
+		}
+	}
+	return errs.Err()
+}
+
+// Close releases the AWS configs of the credential profiles of the metricset.
+func (m *MetricSet) Close() error {
+	if len(m.Profiles) == 0 {
+		releaseAWSConfigs([]*MetricSet{m})
+		return nil
+	}
+	releaseAWSConfigs(m.Profiles)
+	return nil
+}
+
+// releaseAWSConfigs releases the AWS configs of the metricsets.
+func releaseAWSConfigs(metricSets []*MetricSet) {
+	for _, metricSet := range metricSets {
+		awscommon.ReleaseAWSConfig(*metricSet.AwsConfig)
+	}
+}
+
+// credentialsDescription describes the credentials of a profile in errors.
+func (m *MetricSet) credentialsDescription() string {
+	switch {
+	case len(m.credentialRegions) > 0:
+		return "region_credentials of " + strings.Join(m.credentialRegions, ", ")
+	case m.ProfileName == "":
+		return "module credentials"
+	default:
+		return "credential profile " + m.ProfileName
+	}
+}
+
+// CheckVPCEndpoints returns an error if vpc_endpoints is set and has no VPC
+// endpoint of the service, identified by its SDK service ID, in one of the
+// regions of the metricset.
+func (m *MetricSet) CheckVPCEndpoints(serviceID string) error {
+	return awscommon.CheckVPCEndpoints(m.VPCEndpoints, serviceID, m.RegionsList)
+}
+
+// getInstanceIdentity retrieves the region and account of the EC2 instance the
+// Beat runs on.
+var getInstanceIdentity = awscommon.GetInstanceIdentity
+
+// withInstanceRegion sets the default region of the AWS config to the region of
+// the EC2 instance the Beat runs on when no region is configured, neither in
+// regions nor in default_region. The region of the AWS config file and
+// environment variables still has precedence.
+func withInstanceRegion(awsConfig awscommon.ConfigAWS, regions []string) awscommon.ConfigAWS {
+	if awsConfig.DefaultRegion != "" || awsConfig.EmulatorEndpoint != "" {
+		return awsConfig
+	}
+	// The first region of regions is used as the region of the AWS config
+	if len(regions) > 0 && !isRegionPattern(regions[0]) {
+		return awsConfig
+	}
+	if identity, err := getInstanceIdentity(); err == nil && identity.Region != "" {
+		awsConfig.DefaultRegion = identity.Region
+	}
+	return awsConfig
+}
+
+// newMetricSet creates the base metricset of the credentials of config.
+func newMetricSet(base mb.BaseMetricSet, config Config) (*MetricSet, error) {
+	var tagsExpression *TagsExpression
+	if config.TagsFilterExpression != "" {
+		if len(config.TagsFilter) > 0 {
+			return nil, fmt.Errorf("tags_filter and tags_filter_expression can't be used together")
+		}
+		var err error
+		tagsExpression, err = ParseTagsExpression(config.TagsFilterExpression)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	awsConfig, err := awscommon.InitializeAWSConfig(withInstanceRegion(config.AWSConfig, config.Regions))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get aws credentials, please check AWS credential in config: %w", err)
 	}
 
+	if config.SDKDebugLogging {
+		enableSDKLogging(&awsConfig)
+	}
+	addAPIStats(&awsConfig, base.Name())
+
 	_, err = awsConfig.Credentials.Retrieve(context.Background())
 	if err != nil {
+		awscommon.ReleaseAWSConfig(awsConfig)
 		return nil, fmt.Errorf("failed to retrieve aws credentials, please check AWS credential in config: %w", err)
 	}
 
 	base.Logger().Debug("aws config endpoint = ", config.AWSConfig.Endpoint)
 	metricSet := MetricSet{
-		BaseMetricSet: base,
-		Period:        config.Period,
-		Latency:       config.Latency,
-		AwsConfig:     &awsConfig,
-		TagsFilter:    config.TagsFilter,
-		Endpoint:      config.AWSConfig.Endpoint,
+		BaseMetricSet:  base,
+		Period:         config.Period,
+		Latency:        config.Latency,
+		AwsConfig:      &awsConfig,
+		TagsFilter:     config.TagsFilter,
+		TagsExpression: tagsExpression,
+		Endpoint:       config.AWSConfig.Endpoint,
+		VPCEndpoints:   config.AWSConfig.VPCEndpoints,
 	}
 
 	base.Logger().Debug("Metricset level config for period: ", metricSet.Period)
 	base.Logger().Debug("Metricset level config for tags filter: ", metricSet.TagsFilter)
+	base.Logger().Debug("Metricset level config for tags filter expression: ", metricSet.TagsExpression)
 	base.Logger().Warn("extra charges on AWS API requests will be generated by this metricset")
 
 	// If regions in config is not empty, then overwrite the awsConfig.Region
-	if len(config.Regions) > 0 {
+	if len(config.Regions) > 0 && !isRegionPattern(config.Regions[0]) {
 		awsConfig.Region = config.Regions[0]
 	}
 
 	// Get IAM account id
-	svcSts := sts.NewFromConfig(awsConfig, func(o *sts.Options) {
-		if config.AWSConfig.FIPSEnabled {
-			o.EndpointOptions.UseFIPSEndpoint = awssdk.FIPSEndpointStateEnabled
-		}
-	})
+	svcSts := sts.NewFromConfig(awsConfig, awscommon.STSEndpointOptions(config.AWSConfig))
 	outputIdentity, err := svcSts.GetCallerIdentity(context.TODO(), &sts.GetCallerIdentityInput{})
 	if err != nil {
 		base.Logger().Warn("failed to get caller identity, please check permission setting: ", err)
+		// Fall back to the account of the EC2 instance the Beat runs on
+		if identity, err := getInstanceIdentity(); err == nil {
+			metricSet.AccountID = identity.AccountID
+			base.Logger().Debug("Using the account ID of the EC2 instance: ", metricSet.AccountID)
+		}
 	} else {
 		metricSet.AccountID = *outputIdentity.Account
 		base.Logger().Debug("AWS Credentials belong to account ID: ", metricSet.AccountID)
 	}
-	// Get account name/alias
-	svcIam := iam.NewFromConfig(awsConfig, func(o *iam.Options) {
-		if config.AWSConfig.FIPSEnabled {
-			o.EndpointOptions.UseFIPSEndpoint = awssdk.FIPSEndpointStateEnabled
-		}
+	// Share the resource tags with the other metricsets collecting in the same
+	// period, unless tags_cache_ttl is set
+	tagsCacheTTL := config.TagsCacheTTL
+	if tagsCacheTTL == 0 {
+		tagsCacheTTL = config.Period / 2
+	}
+	metricSet.Tags = NewTagService(metricSet.AccountID, tagsCacheTTL)
+	// Share the discovered resources the same way, unless
+	// discovery_cache_ttl is set
+	discoveryCacheTTL := config.DiscoveryCacheTTL
+	if discoveryCacheTTL == 0 {
+		discoveryCacheTTL = config.Period / 2
+	}
+	metricSet.Discovery = NewDiscoveryService(metricSet.AccountID, discoveryCacheTTL)
+
+	// Limit the requests of all the aws metricsets to the account
+	if config.RateLimit.Enabled() {
+		addRateLimiter(&awsConfig, config.RateLimit, metricSet.AccountID)
+	}
 
-	})
+	// Get account name/alias
+	svcIam := iam.NewFromConfig(awsConfig)
 	metricSet.AccountName = getAccountName(svcIam, base, metricSet)
 
-	// Construct MetricSet with a full regions list
-	if config.Regions == nil {
-		svcEC2 := ec2.NewFromConfig(awsConfig, func(o *ec2.Options) {
-			if config.AWSConfig.FIPSEnabled {
-				o.EndpointOptions.UseFIPSEndpoint = awssdk.FIPSEndpointStateEnabled
-			}
-		})
+	if config.IncludeOrganizationMetadata {
+		svcOrganizations := organizations.NewFromConfig(awsConfig)
+		metricSet.Organization = NewOrganizationResolver(svcOrganizations)
+	}
+
+	// Construct MetricSet with a full regions list, or with the regions
+	// matching the patterns of the regions list from config
+	regionsList := config.Regions
+	if config.Regions == nil || hasRegionPatterns(config.Regions) {
+		svcEC2 := ec2.NewFromConfig(awsConfig)
 		completeRegionsList, err := getRegions(svcEC2)
 		if err != nil {
+			awscommon.ReleaseAWSConfig(awsConfig)
 			return nil, err
 		}
-
-		metricSet.RegionsList = completeRegionsList
-		base.Logger().Debug("Metricset level config for regions: ", metricSet.RegionsList)
-		return &metricSet, nil
+		regionsList = filterRegions(completeRegionsList, config.Regions, nil)
 	}
 
-	// Construct MetricSet with specific regions list from config
-	metricSet.RegionsList = config.Regions
+	metricSet.RegionsList = filterRegions(regionsList, nil, config.ExcludeRegions)
+	if len(metricSet.RegionsList) == 0 && (hasRegionPatterns(config.Regions) || len(config.ExcludeRegions) > 0) {
+		awscommon.ReleaseAWSConfig(awsConfig)
+		return nil, fmt.Errorf("no region left after applying regions %v and exclude_regions %v", config.Regions, config.ExcludeRegions)
+	}
 	base.Logger().Debug("Metricset level config for regions: ", metricSet.RegionsList)
 	return &metricSet, nil
 }
 
+// regionNotOptedIn is the opt-in status of the opt-in regions that are not
+// enabled in the account. API requests to these regions fail with an
+// authentication error.
+const regionNotOptedIn = "not-opted-in"
+
+// getRegions returns the regions enabled in the account, the regions that
+// don't require opt-in and the opt-in regions that were enabled.
 func getRegions(svc describeRegionsClient) ([]string, error) {
 	completeRegionsList := make([]string, 0)
-	input := &ec2.DescribeRegionsInput{}
+	input := &ec2.DescribeRegionsInput{
+		AllRegions: awssdk.Bool(true),
+	}
 	output, err := svc.DescribeRegions(context.TODO(), input)
 	if err != nil {
 		err = fmt.Errorf("failed DescribeRegions: %w", err)
 		return completeRegionsList, err
 	}
 	for _, region := range output.Regions {
+		if awssdk.ToString(region.OptInStatus) == regionNotOptedIn {
+			continue
+		}
 		completeRegionsList = append(completeRegionsList, *region.RegionName)
 	}
 	return completeRegionsList, err
 }
 
+// isRegionPattern returns true if the region from config is a pattern, like
+// us-*, instead of a region name.
+func isRegionPattern(region string) bool {
+	return strings.ContainsAny(region, "*?[")
+}
+
+func hasRegionPatterns(regions []string) bool {
+	for _, region := range regions {
+		if isRegionPattern(region) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterRegions returns the regions to collect from. Without include, all the
+// available regions are used, otherwise the region names of include and the
+// available regions matching its patterns. The regions matching a name or a
+// pattern of exclude are removed.
+func filterRegions(available []string, include []string, exclude []string) []string {
+	var regions []string
+	if include == nil {
+		regions = available
+	} else {
+		for _, region := range include {
+			if !isRegionPattern(region) {
+				regions = append(regions, region)
+				continue
+			}
+			for _, availableRegion := range available {
+				if matched, _ := path.Match(region, availableRegion); matched {
+					regions = append(regions, availableRegion)
+				}
+			}
+		}
+	}
+
+	filtered := make([]string, 0, len(regions))
+	seen := make(map[string]bool, len(regions))
+	for _, region := range regions {
+		if seen[region] || matchesRegion(region, exclude) {
+			continue
+		}
+		seen[region] = true
+		filtered = append(filtered, region)
+	}
+	return filtered
+}
+
+func matchesRegion(region string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, region); matched {
+			return true
+		}
+	}
+	return false
+}
+
 func getAccountName(svc *iam.Client, base mb.BaseMetricSet, metricSet MetricSet) string {
 	output, err := svc.ListAccountAliases(context.TODO(), &iam.ListAccountAliasesInput{})
 
@@ -9,6 +9,7 @@ package aws
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
@@ -17,6 +18,8 @@ import (
 	awssdk "github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/stretchr/testify/assert"
+
+	awscommon "github.com/elastic/beats/v7/x-pack/libbeat/common/aws"
 )
 
 // MockEC2Client struct is used for unit tests.
@@ -30,6 +33,10 @@ func (m *MockEC2Client) DescribeRegions(ctx context.Context, params *ec2.Describ
 			{
 				RegionName: awssdk.String("us-west-1"),
 			},
+			{
+				RegionName:  awssdk.String("af-south-1"),
+				OptInStatus: awssdk.String("not-opted-in"),
+			},
 		},
 	}, nil
 }
@@ -44,6 +51,46 @@ func TestGetRegions(t *testing.T) {
 	assert.Equal(t, "us-west-1", regionsList[0])
 }
 
+func TestFilterRegions(t *testing.T) {
+	available := []string{"us-east-1", "us-east-2", "us-west-1", "eu-west-1", "ap-south-1"}
+	cases := []struct {
+		title           string
+		include         []string
+		exclude         []string
+		expectedRegions []string
+	}{
+		{
+			"All regions",
+			nil,
+			nil,
+			available,
+		},
+		{
+			"Exclude regions",
+			nil,
+			[]string{"ap-*", "us-west-1"},
+			[]string{"us-east-1", "us-east-2", "eu-west-1"},
+		},
+		{
+			"Region patterns",
+			[]string{"us-*", "eu-west-1"},
+			[]string{"us-east-2"},
+			[]string{"us-east-1", "us-west-1", "eu-west-1"},
+		},
+		{
+			"Region names are kept when not available",
+			[]string{"eu-central-2", "us-east-1", "us-east-?"},
+			nil,
+			[]string{"eu-central-2", "us-east-1", "us-east-2"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			assert.Equal(t, c.expectedRegions, filterRegions(available, c.include, c.exclude))
+		})
+	}
+}
+
 func TestStringInSlice(t *testing.T) {
 	cases := []struct {
 		target         string
@@ -71,6 +118,82 @@ func TestStringInSlice(t *testing.T) {
 	}
 }
 
+func TestForEachProfile(t *testing.T) {
+	var fetched []string
+	fetch := func(profile *MetricSet) error {
+		fetched = append(fetched, profile.ProfileName)
+		if profile.ProfileName == "dev" {
+			return errors.New("access denied")
+		}
+		return nil
+	}
+
+	single := &MetricSet{}
+	assert.NoError(t, single.ForEachProfile(fetch))
+	assert.Equal(t, []string{""}, fetched)
+
+	fetched = nil
+	prod := &MetricSet{ProfileName: "prod"}
+	dev := &MetricSet{ProfileName: "dev"}
+	prod.Profiles = []*MetricSet{prod, dev}
+	err := prod.ForEachProfile(fetch)
+	assert.Equal(t, []string{"prod", "dev"}, fetched)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "credential profile dev: access denied")
+	}
+}
+
+func TestForEachProfileRegionCredentials(t *testing.T) {
+	module := &MetricSet{}
+	china := &MetricSet{ProfileName: "region_credentials:cn-north-1,cn-northwest-1", credentialRegions: []string{"cn-north-1", "cn-northwest-1"}}
+	module.Profiles = []*MetricSet{module, china}
+
+	err := module.ForEachProfile(func(profile *MetricSet) error {
+		return errors.New("access denied")
+	})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "module credentials: access denied")
+		assert.Contains(t, err.Error(), "region_credentials of cn-north-1, cn-northwest-1: access denied")
+	}
+}
+
+func TestRegionCredentialsApply(t *testing.T) {
+	moduleConfig := awscommon.ConfigAWS{
+		AccessKeyID:     "module-key",
+		SecretAccessKey: "module-secret",
+		RoleArn:         "arn:aws:iam::123456789012:role/module",
+		ProxyUrl:        "http://proxy:3128",
+	}
+
+	// Only the role is replaced
+	config := RegionCredentials{RoleArn: "arn:aws:iam::210987654321:role/region"}.apply(moduleConfig)
+	assert.Equal(t, "module-key", config.AccessKeyID)
+	assert.Equal(t, "arn:aws:iam::210987654321:role/region", config.RoleArn)
+	assert.Equal(t, "http://proxy:3128", config.ProxyUrl)
+
+	// The credential profile replaces the access keys of the module
+	config = RegionCredentials{ProfileName: "china"}.apply(moduleConfig)
+	assert.Equal(t, "", config.AccessKeyID)
+	assert.Equal(t, "", config.SecretAccessKey)
+	assert.Equal(t, "china", config.ProfileName)
+	assert.Equal(t, "arn:aws:iam::123456789012:role/module", config.RoleArn)
+	assert.Equal(t, "http://proxy:3128", config.ProxyUrl)
+}
+
+func TestGroupRegionCredentials(t *testing.T) {
+	china := RegionCredentials{ProfileName: "china"}
+	govCloud := RegionCredentials{RoleArn: "arn:aws-us-gov:iam::123456789012:role/metricbeat"}
+	groups := groupRegionCredentials(map[string]RegionCredentials{
+		"us-gov-west-1":  govCloud,
+		"cn-northwest-1": china,
+		"cn-north-1":     china,
+	})
+	assert.Equal(t, []regionCredentialsGroup{
+		{credentials: china, regions: []string{"cn-north-1", "cn-northwest-1"}},
+		{credentials: govCloud, regions: []string{"us-gov-west-1"}},
+	}, groups)
+}
+
 var (
 	tagKey1   = "Name"
 	tagValue1 = "ECS Instance"
@@ -82,6 +205,31 @@ var (
 	tagValue5 = "ElastiCache Redis"
 )
 
+func TestWithInstanceRegion(t *testing.T) {
+	calls := 0
+	getIdentity := getInstanceIdentity
+	getInstanceIdentity = func() (awscommon.InstanceIdentity, error) {
+		calls++
+		return awscommon.InstanceIdentity{Region: "eu-west-1", AccountID: "123456789012"}, nil
+	}
+	defer func() { getInstanceIdentity = getIdentity }()
+
+	assert.Equal(t, "eu-west-1", withInstanceRegion(awscommon.ConfigAWS{}, nil).DefaultRegion)
+	assert.Equal(t, "us-west-1", withInstanceRegion(awscommon.ConfigAWS{DefaultRegion: "us-west-1"}, nil).DefaultRegion)
+	assert.Equal(t, "", withInstanceRegion(awscommon.ConfigAWS{EmulatorEndpoint: "http://localhost:4566"}, nil).DefaultRegion)
+	assert.Equal(t, "", withInstanceRegion(awscommon.ConfigAWS{}, []string{"us-east-1"}).DefaultRegion)
+	assert.Equal(t, 1, calls)
+
+	// Region patterns still need a region to list the regions of the account
+	assert.Equal(t, "eu-west-1", withInstanceRegion(awscommon.ConfigAWS{}, []string{"eu-*"}).DefaultRegion)
+	assert.Equal(t, 2, calls)
+
+	getInstanceIdentity = func() (awscommon.InstanceIdentity, error) {
+		return awscommon.InstanceIdentity{}, errors.New("not running on EC2")
+	}
+	assert.Equal(t, "", withInstanceRegion(awscommon.ConfigAWS{}, nil).DefaultRegion)
+}
+
 func TestCheckTagFiltersExist(t *testing.T) {
 	cases := []struct {
 		title          string
@@ -0,0 +1,314 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+	backuptypes "github.com/aws/aws-sdk-go-v2/service/backup/types"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws"
+	"github.com/elastic/elastic-agent-libs/logp"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+var (
+	metricsetName = "backup"
+	namespace     = "AWS/Backup"
+
+	// Account level job metrics published by AWS Backup to CloudWatch.
+	jobMetricNames = []string{
+		"NumberOfBackupJobsCreated",
+		"NumberOfBackupJobsCompleted",
+		"NumberOfBackupJobsFailed",
+		"NumberOfBackupJobsExpired",
+		"NumberOfBackupJobsAborted",
+		"NumberOfCopyJobsCompleted",
+		"NumberOfCopyJobsFailed",
+		"NumberOfRestoreJobsCompleted",
+		"NumberOfRestoreJobsFailed",
+	}
+)
+
+// init registers the MetricSet with the central registry as soon as the program
+// starts. The New function will be called later to instantiate an instance of
+// the MetricSet for each host defined in the module's configuration. After the
+// MetricSet has been created then Fetch will begin to be called periodically.
+func init() {
+	mb.Registry.MustAddMetricSet(aws.ModuleName, metricsetName, New)
+}
+
+// MetricSet holds any configuration or state information. It must implement
+// the mb.MetricSet interface. And this is best achieved by embedding
+// mb.BaseMetricSet because it implements all of the required mb.MetricSet
+// interface methods except for Fetch.
+type MetricSet struct {
+	*aws.MetricSet
+	logger       *logp.Logger
+	BackupConfig Config `config:"backup_config"`
+}
+
+// Config holds a configuration specific for backup metricset.
+type Config struct {
+	// RPO is the recovery point objective, protected resources whose last
+	// backup is older than it are reported as out of compliance.
+	RPO time.Duration `config:"rpo" validate:"min=0"`
+}
+
+// New creates a new instance of the MetricSet. New is responsible for unpacking
+// any MetricSet specific configuration options if there are any.
+func New(base mb.BaseMetricSet) (mb.MetricSet, error) {
+	logger := logp.NewLogger(metricsetName)
+	metricSet, err := aws.NewMetricSet(base)
+	if err != nil {
+		return nil, fmt.Errorf("error creating aws metricset: %w", err)
+	}
+
+	config := struct {
+		BackupConfig Config `config:"backup_config"`
+	}{
+		BackupConfig: Config{RPO: 24 * time.Hour},
+	}
+
+	err = base.Module().UnpackConfig(&config)
+	if err != nil {
+		return nil, fmt.Errorf("error unpack raw module config using UnpackConfig: %w", err)
+	}
+
+	logger.Debugf("backup config = %s", config)
+
+	return &MetricSet{
+		MetricSet:    metricSet,
+		logger:       logger,
+		BackupConfig: config.BackupConfig,
+	}, nil
+}
+
+// Fetch methods implements the data gathering and data conversion to the right
+// format. It publishes the event which is then forwarded to the output. In case
+// of an error set the Error field of mb.Event or simply call report.Error().
+func (m *MetricSet) Fetch(ctx context.Context, report mb.ReporterV2) error {
+	return m.MetricSet.ForEachProfile(func(profile *aws.MetricSet) error {
+		profileMetricSet := *m
+		profileMetricSet.MetricSet = profile
+		return profileMetricSet.fetch(ctx, profile.OrganizationReporter(ctx, report))
+	})
+}
+
+// fetch collects the data with the credentials of m.MetricSet.
+func (m *MetricSet) fetch(ctx context.Context, report mb.ReporterV2) error {
+	startTime, endTime := aws.GetStartTimeEndTime(time.Now(), m.Period, m.Latency)
+
+	for _, regionName := range m.MetricSet.RegionsList {
+		awsBeatsConfig := m.MetricSet.AwsConfig.Copy()
+		awsBeatsConfig.Region = regionName
+
+		svcBackup := backup.NewFromConfig(awsBeatsConfig)
+		svcCloudwatch := cloudwatch.NewFromConfig(awsBeatsConfig)
+
+		var events []mb.Event
+		if event, ok := m.createJobMetricsEvent(ctx, svcCloudwatch, regionName, startTime, endTime); ok {
+			events = append(events, event)
+		}
+		events = append(events, m.createJobEvents(ctx, svcBackup, regionName, startTime, endTime)...)
+		events = append(events, m.createProtectedResourceEvents(ctx, svcBackup, regionName, endTime)...)
+
+		for _, event := range events {
+			if reported := report.Event(event); !reported {
+				m.Logger().Debug("Fetch interrupted, failed to emit event")
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// createJobMetricsEvent returns an event with the account level job metrics of
+// AWS Backup in CloudWatch, summed over the period.
+func (m *MetricSet) createJobMetricsEvent(ctx context.Context, svcCloudwatch cloudwatch.GetMetricDataAPIClient, regionName string, startTime time.Time, endTime time.Time) (mb.Event, bool) {
+	periodInSeconds := int32(m.Period.Seconds())
+	metricDataQueries := make([]types.MetricDataQuery, 0, len(jobMetricNames))
+	for _, metricName := range jobMetricNames {
+		metricDataQueries = append(metricDataQueries, types.MetricDataQuery{
+			Id: awssdk.String(strings.ToLower(metricName)),
+			MetricStat: &types.MetricStat{
+				Period: &periodInSeconds,
+				Stat:   awssdk.String("Sum"),
+				Metric: &types.Metric{
+					Namespace:  awssdk.String(namespace),
+					MetricName: awssdk.String(metricName),
+				},
+			},
+			Label: awssdk.String(metricName),
+		})
+	}
+
+	metricDataResults, err := aws.GetMetricDataResults(ctx, metricDataQueries, svcCloudwatch, startTime, endTime)
+	if err != nil {
+		m.logger.Warnf("aws GetMetricDataResults failed with %s, skipping job metrics of region %s", err, regionName)
+		return mb.Event{}, false
+	}
+
+	metrics := mapstr.M{}
+	for _, result := range metricDataResults {
+		if len(result.Values) == 0 {
+			continue
+		}
+		metrics[awssdk.ToString(result.Label)] = mapstr.M{"sum": result.Values[0]}
+	}
+	if len(metrics) == 0 {
+		return mb.Event{}, false
+	}
+
+	event := aws.InitEvent(regionName, m.AccountName, m.AccountID, endTime)
+	event.MetricSetFields = mapstr.M{"metrics": metrics}
+	return event, true
+}
+
+// jobsKey groups backup jobs by backup vault and resource type.
+type jobsKey struct {
+	backupVaultName string
+	resourceType    string
+}
+
+// createJobEvents returns an event for each backup vault and resource type,
+// with the number of backup jobs created in the period by state.
+func (m *MetricSet) createJobEvents(ctx context.Context, svc backup.ListBackupJobsAPIClient, regionName string, startTime time.Time, endTime time.Time) []mb.Event {
+	jobs := map[jobsKey]mapstr.M{}
+	paginator := backup.NewListBackupJobsPaginator(svc, &backup.ListBackupJobsInput{
+		ByCreatedAfter:  &startTime,
+		ByCreatedBefore: &endTime,
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			m.logger.Warnf("error ListBackupJobs in region %s: %s", regionName, err)
+			return nil
+		}
+
+		for _, job := range output.BackupJobs {
+			key := jobsKey{
+				backupVaultName: awssdk.ToString(job.BackupVaultName),
+				resourceType:    awssdk.ToString(job.ResourceType),
+			}
+			counts, ok := jobs[key]
+			if !ok {
+				counts = mapstr.M{"count": 0}
+				jobs[key] = counts
+			}
+			counts["count"] = counts["count"].(int) + 1
+
+			state := strings.ToLower(string(job.State))
+			if state == "" {
+				continue
+			}
+			count, _ := counts[state].(int)
+			counts[state] = count + 1
+			if job.State == backuptypes.BackupJobStateCompleted && job.BackupSizeInBytes != nil {
+				size, _ := counts["size.bytes"].(int64)
+				counts["size.bytes"] = size + *job.BackupSizeInBytes
+			}
+		}
+	}
+
+	keys := make([]jobsKey, 0, len(jobs))
+	for key := range jobs {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].backupVaultName != keys[j].backupVaultName {
+			return keys[i].backupVaultName < keys[j].backupVaultName
+		}
+		return keys[i].resourceType < keys[j].resourceType
+	})
+
+	events := make([]mb.Event, 0, len(keys))
+	for _, key := range keys {
+		event := aws.InitEvent(regionName, m.AccountName, m.AccountID, endTime)
+		event.MetricSetFields = mapstr.M{
+			"backup_vault": mapstr.M{"name": key.backupVaultName},
+			"resource":     mapstr.M{"type": key.resourceType},
+			"jobs":         mapstr.M{},
+		}
+		for name, value := range jobs[key] {
+			_, _ = event.MetricSetFields.Put("jobs."+name, value)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// protectedResources holds the protected resources of a resource type.
+type protectedResources struct {
+	count       int
+	rpoBreached int
+	oldest      *time.Time
+}
+
+// createProtectedResourceEvents returns an event for each resource type with
+// the number of protected resources, and the number of them whose last backup
+// is older than the configured RPO.
+func (m *MetricSet) createProtectedResourceEvents(ctx context.Context, svc backup.ListProtectedResourcesAPIClient, regionName string, now time.Time) []mb.Event {
+	resourcesByType := map[string]*protectedResources{}
+	paginator := backup.NewListProtectedResourcesPaginator(svc, &backup.ListProtectedResourcesInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			m.logger.Warnf("error ListProtectedResources in region %s: %s", regionName, err)
+			return nil
+		}
+
+		for _, resource := range output.Results {
+			resourceType := awssdk.ToString(resource.ResourceType)
+			resources, ok := resourcesByType[resourceType]
+			if !ok {
+				resources = &protectedResources{}
+				resourcesByType[resourceType] = resources
+			}
+			resources.count++
+
+			lastBackupTime := resource.LastBackupTime
+			if lastBackupTime == nil || now.Sub(*lastBackupTime) > m.BackupConfig.RPO {
+				resources.rpoBreached++
+			}
+			if lastBackupTime != nil && (resources.oldest == nil || lastBackupTime.Before(*resources.oldest)) {
+				resources.oldest = lastBackupTime
+			}
+		}
+	}
+
+	resourceTypes := make([]string, 0, len(resourcesByType))
+	for resourceType := range resourcesByType {
+		resourceTypes = append(resourceTypes, resourceType)
+	}
+	sort.Strings(resourceTypes)
+
+	events := make([]mb.Event, 0, len(resourceTypes))
+	for _, resourceType := range resourceTypes {
+		resources := resourcesByType[resourceType]
+		event := aws.InitEvent(regionName, m.AccountName, m.AccountID, now)
+		event.MetricSetFields = mapstr.M{
+			"resource": mapstr.M{"type": resourceType},
+			"protected_resources": mapstr.M{
+				"count":        resources.count,
+				"rpo_breached": resources.rpoBreached,
+			},
+		}
+		if resources.oldest != nil {
+			_, _ = event.MetricSetFields.Put("protected_resources.oldest_backup", *resources.oldest)
+		}
+		events = append(events, event)
+	}
+	return events
+}
@@ -0,0 +1,121 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+//go:build !integration
+// +build !integration
+
+package backup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+	backuptypes "github.com/aws/aws-sdk-go-v2/service/backup/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws"
+	"github.com/elastic/elastic-agent-libs/logp"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+// MockBackupClient struct is used for unit tests.
+type MockBackupClient struct {
+	now time.Time
+}
+
+// ListBackupJobs implements backup.ListBackupJobsAPIClient interface
+func (m *MockBackupClient) ListBackupJobs(_ context.Context, _ *backup.ListBackupJobsInput, _ ...func(*backup.Options)) (*backup.ListBackupJobsOutput, error) {
+	return &backup.ListBackupJobsOutput{
+		BackupJobs: []backuptypes.BackupJob{
+			{
+				BackupVaultName:   awssdk.String("Default"),
+				ResourceType:      awssdk.String("EBS"),
+				State:             backuptypes.BackupJobStateCompleted,
+				BackupSizeInBytes: awssdk.Int64(1024),
+			},
+			{
+				BackupVaultName:   awssdk.String("Default"),
+				ResourceType:      awssdk.String("EBS"),
+				State:             backuptypes.BackupJobStateCompleted,
+				BackupSizeInBytes: awssdk.Int64(2048),
+			},
+			{
+				BackupVaultName: awssdk.String("Default"),
+				ResourceType:    awssdk.String("EBS"),
+				State:           backuptypes.BackupJobStateFailed,
+			},
+			{
+				BackupVaultName: awssdk.String("Default"),
+				ResourceType:    awssdk.String("RDS"),
+				State:           backuptypes.BackupJobStateExpired,
+			},
+		},
+	}, nil
+}
+
+// ListProtectedResources implements backup.ListProtectedResourcesAPIClient interface
+func (m *MockBackupClient) ListProtectedResources(_ context.Context, _ *backup.ListProtectedResourcesInput, _ ...func(*backup.Options)) (*backup.ListProtectedResourcesOutput, error) {
+	lastDay := m.now.Add(-2 * time.Hour)
+	lastWeek := m.now.Add(-7 * 24 * time.Hour)
+	return &backup.ListProtectedResourcesOutput{
+		Results: []backuptypes.ProtectedResource{
+			{ResourceType: awssdk.String("EBS"), LastBackupTime: &lastDay},
+			{ResourceType: awssdk.String("EBS"), LastBackupTime: &lastWeek},
+			{ResourceType: awssdk.String("RDS")},
+		},
+	}, nil
+}
+
+func TestCreateJobEvents(t *testing.T) {
+	m := MetricSet{logger: logp.NewLogger("test")}
+	m.MetricSet = &aws.MetricSet{}
+
+	endTime := time.Now()
+	events := m.createJobEvents(context.Background(), &MockBackupClient{}, "us-east-1", endTime.Add(-time.Hour), endTime)
+	assert.Equal(t, 2, len(events))
+
+	assert.Equal(t, mapstr.M{
+		"backup_vault": mapstr.M{"name": "Default"},
+		"resource":     mapstr.M{"type": "EBS"},
+		"jobs": mapstr.M{
+			"count":     3,
+			"completed": 2,
+			"failed":    1,
+			"size":      mapstr.M{"bytes": int64(3072)},
+		},
+	}, events[0].MetricSetFields)
+
+	expired, _ := events[1].MetricSetFields.GetValue("jobs.expired")
+	assert.Equal(t, 1, expired)
+}
+
+func TestCreateProtectedResourceEvents(t *testing.T) {
+	m := MetricSet{
+		logger:       logp.NewLogger("test"),
+		BackupConfig: Config{RPO: 24 * time.Hour},
+	}
+	m.MetricSet = &aws.MetricSet{}
+
+	now := time.Now()
+	events := m.createProtectedResourceEvents(context.Background(), &MockBackupClient{now: now}, "us-east-1", now)
+	assert.Equal(t, 2, len(events))
+
+	assert.Equal(t, mapstr.M{
+		"resource": mapstr.M{"type": "EBS"},
+		"protected_resources": mapstr.M{
+			"count":         2,
+			"rpo_breached":  1,
+			"oldest_backup": now.Add(-7 * 24 * time.Hour),
+		},
+	}, events[0].MetricSetFields)
+
+	// resources never backed up are out of compliance
+	rpoBreached, _ := events[1].MetricSetFields.GetValue("protected_resources.rpo_breached")
+	assert.Equal(t, 1, rpoBreached)
+	_, err := events[1].MetricSetFields.GetValue("protected_resources.oldest_backup")
+	assert.Error(t, err)
+}
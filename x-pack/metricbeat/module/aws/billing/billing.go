@@ -16,6 +16,8 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 
 	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/budgets"
+	budgetstypes "github.com/aws/aws-sdk-go-v2/service/budgets/types"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
 	costexplorertypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
@@ -43,6 +45,10 @@ var (
 	}
 
 	dateLayout = "2006-01-02"
+
+	// forecastPredictionIntervalLevel is the confidence level, in percent, of
+	// the prediction interval of cost forecasts.
+	forecastPredictionIntervalLevel int32 = 80
 )
 
 // init registers the MetricSet with the central registry as soon as the program
@@ -63,12 +69,21 @@ type MetricSet struct {
 	*aws.MetricSet
 	logger             *logp.Logger
 	CostExplorerConfig CostExplorerConfig `config:"cost_explorer_config"`
+	BudgetsConfig      BudgetsConfig      `config:"budgets_config"`
 }
 
 // Config holds a configuration specific for billing metricset.
 type CostExplorerConfig struct {
 	GroupByDimensionKeys []string `config:"group_by_dimension_keys"`
 	GroupByTagKeys       []string `config:"group_by_tag_keys"`
+	// Forecast enables the collection of the forecasted cost of the current
+	// month from Cost Explorer.
+	Forecast bool `config:"forecast"`
+}
+
+// BudgetsConfig holds the configuration of the collection of AWS Budgets.
+type BudgetsConfig struct {
+	Enabled bool `config:"enabled"`
 }
 
 // New creates a new instance of the MetricSet. New is responsible for unpacking
@@ -82,6 +97,7 @@ func New(base mb.BaseMetricSet) (mb.MetricSet, error) {
 
 	config := struct {
 		CostExplorerConfig CostExplorerConfig `config:"cost_explorer_config"`
+		BudgetsConfig      BudgetsConfig      `config:"budgets_config"`
 	}{}
 
 	err = base.Module().UnpackConfig(&config)
@@ -95,6 +111,7 @@ func New(base mb.BaseMetricSet) (mb.MetricSet, error) {
 		MetricSet:          metricSet,
 		logger:             logger,
 		CostExplorerConfig: config.CostExplorerConfig,
+		BudgetsConfig:      config.BudgetsConfig,
 	}, nil
 }
 
@@ -112,12 +129,16 @@ func (c CostExplorerConfig) Validate() error {
 // Fetch methods implements the data gathering and data conversion to the right
 // format. It publishes the event which is then forwarded to the output. In case
 // of an error set the Error field of mb.Event or simply call report.Error().
-func (m *MetricSet) Fetch(report mb.ReporterV2) error {
-	var config aws.Config
-	err := m.Module().UnpackConfig(&config)
-	if err != nil {
-		return err
-	}
+func (m *MetricSet) Fetch(ctx context.Context, report mb.ReporterV2) error {
+	return m.MetricSet.ForEachProfile(func(profile *aws.MetricSet) error {
+		profileMetricSet := *m
+		profileMetricSet.MetricSet = profile
+		return profileMetricSet.fetch(ctx, profile.OrganizationReporter(ctx, report))
+	})
+}
+
+// fetch collects the data with the credentials of m.MetricSet.
+func (m *MetricSet) fetch(ctx context.Context, report mb.ReporterV2) error {
 	// Get startDate and endDate
 	startDate, endDate := getStartDateEndDate(m.Period)
 
@@ -126,19 +147,10 @@ func (m *MetricSet) Fetch(report mb.ReporterV2) error {
 
 	// get cost metrics from cost explorer
 	awsBeatsConfig := m.MetricSet.AwsConfig.Copy()
-	svcCostExplorer := costexplorer.NewFromConfig(awsBeatsConfig, func(o *costexplorer.Options) {
-		if config.AWSConfig.FIPSEnabled {
-			o.EndpointOptions.UseFIPSEndpoint = awssdk.FIPSEndpointStateEnabled
-		}
-
-	})
+	svcCostExplorer := costexplorer.NewFromConfig(awsBeatsConfig)
 
 	awsBeatsConfig.Region = regionName
-	svcCloudwatch := cloudwatch.NewFromConfig(awsBeatsConfig, func(o *cloudwatch.Options) {
-		if config.AWSConfig.FIPSEnabled {
-			o.EndpointOptions.UseFIPSEndpoint = awssdk.FIPSEndpointStateEnabled
-		}
-	})
+	svcCloudwatch := cloudwatch.NewFromConfig(awsBeatsConfig)
 
 	timePeriod := costexplorertypes.DateInterval{
 		Start: awssdk.String(startDate),
@@ -148,13 +160,26 @@ func (m *MetricSet) Fetch(report mb.ReporterV2) error {
 	var events []mb.Event
 
 	// Get estimated charges from CloudWatch
-	eventsCW := m.getCloudWatchBillingMetrics(svcCloudwatch, startTime, endTime)
+	eventsCW := m.getCloudWatchBillingMetrics(ctx, svcCloudwatch, startTime, endTime)
 	events = append(events, eventsCW...)
 
 	// Get total cost from Cost Explorer GetCostAndUsage with group by type "DIMENSION" and "TAG"
-	eventsCE := m.getCostGroupBy(svcCostExplorer, m.CostExplorerConfig.GroupByDimensionKeys, m.CostExplorerConfig.GroupByTagKeys, timePeriod, startDate, endDate)
+	eventsCE := m.getCostGroupBy(ctx, svcCostExplorer, m.CostExplorerConfig.GroupByDimensionKeys, m.CostExplorerConfig.GroupByTagKeys, timePeriod, startDate, endDate)
 	events = append(events, eventsCE...)
 
+	// Get forecasted cost of the current month from Cost Explorer GetCostForecast
+	if m.CostExplorerConfig.Forecast {
+		if event, ok := m.getCostForecast(ctx, svcCostExplorer, time.Now()); ok {
+			events = append(events, event)
+		}
+	}
+
+	// Get budgeted, actual and forecasted amounts from AWS Budgets
+	if m.BudgetsConfig.Enabled {
+		svcBudgets := budgets.NewFromConfig(awsBeatsConfig)
+		events = append(events, m.getBudgets(ctx, svcBudgets, endDate)...)
+	}
+
 	// report events
 	for _, event := range events {
 		if reported := report.Event(event); !reported {
@@ -166,12 +191,13 @@ func (m *MetricSet) Fetch(report mb.ReporterV2) error {
 }
 
 func (m *MetricSet) getCloudWatchBillingMetrics(
+	ctx context.Context,
 	svcCloudwatch *cloudwatch.Client,
 	startTime time.Time,
 	endTime time.Time) []mb.Event {
 	var events []mb.Event
 	namespace := "AWS/Billing"
-	listMetricsOutput, err := aws.GetListMetricsOutput(namespace, regionName, m.Period, svcCloudwatch)
+	listMetricsOutput, err := aws.GetListMetricsOutput(ctx, namespace, regionName, m.Period, svcCloudwatch)
 	if err != nil {
 		m.Logger().Error(err.Error())
 		return nil
@@ -182,7 +208,7 @@ func (m *MetricSet) getCloudWatchBillingMetrics(
 	}
 
 	metricDataQueriesTotal := constructMetricQueries(listMetricsOutput, m.Period)
-	metricDataOutput, err := aws.GetMetricDataResults(metricDataQueriesTotal, svcCloudwatch, startTime, endTime)
+	metricDataOutput, err := aws.GetMetricDataResults(ctx, metricDataQueriesTotal, svcCloudwatch, startTime, endTime)
 	if err != nil {
 		err = fmt.Errorf("aws GetMetricDataResults failed with %w, skipping region %s", err, regionName)
 		m.Logger().Error(err.Error())
@@ -218,25 +244,16 @@ func (m *MetricSet) getCloudWatchBillingMetrics(
 	return events
 }
 
-func (m *MetricSet) getCostGroupBy(svcCostExplorer *costexplorer.Client, groupByDimKeys []string, groupByTags []string, timePeriod costexplorertypes.DateInterval, startDate string, endDate string) []mb.Event {
+func (m *MetricSet) getCostGroupBy(ctx context.Context, svcCostExplorer *costexplorer.Client, groupByDimKeys []string, groupByTags []string, timePeriod costexplorertypes.DateInterval, startDate string, endDate string) []mb.Event {
 	var events []mb.Event
 
 	// get linked account IDs and names
 	accounts := map[string]string{}
-	var config aws.Config
-	err := m.Module().UnpackConfig(&config)
-	if err != nil {
-		return nil
-	}
 	if ok, _ := aws.StringInSlice("LINKED_ACCOUNT", groupByDimKeys); ok {
 		awsConfig := m.MetricSet.AwsConfig.Copy()
 
-		svcOrg := organizations.NewFromConfig(awsConfig, func(o *organizations.Options) {
-			if config.AWSConfig.FIPSEnabled {
-				o.EndpointOptions.UseFIPSEndpoint = awssdk.FIPSEndpointStateEnabled
-			}
-		})
-		accounts = m.getAccountName(svcOrg)
+		svcOrg := organizations.NewFromConfig(awsConfig)
+		accounts = m.getAccountName(ctx, svcOrg)
 	}
 
 	groupBys := getGroupBys(groupByTags, groupByDimKeys)
@@ -267,7 +284,7 @@ func (m *MetricSet) getCostGroupBy(svcCostExplorer *costexplorer.Client, groupBy
 			GroupBy: groupDefs,
 		}
 
-		groupByOutput, err := svcCostExplorer.GetCostAndUsage(context.Background(), &groupByCostInput)
+		groupByOutput, err := svcCostExplorer.GetCostAndUsage(ctx, &groupByCostInput)
 		if err != nil {
 			err = fmt.Errorf("costexplorer GetCostAndUsageRequest failed: %w", err)
 			m.Logger().Errorf(err.Error())
@@ -315,6 +332,179 @@ func (m *MetricSet) getCostGroupBy(svcCostExplorer *costexplorer.Client, groupBy
 	return events
 }
 
+// costForecastAPI is the subset of the Cost Explorer API used to get cost
+// forecasts.
+type costForecastAPI interface {
+	GetCostForecast(ctx context.Context, params *costexplorer.GetCostForecastInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetCostForecastOutput, error)
+}
+
+// getCostForecast returns an event with the forecasted unblended cost of the
+// rest of the current month. Forecasts can't start before the current day.
+func (m *MetricSet) getCostForecast(ctx context.Context, svc costForecastAPI, now time.Time) (mb.Event, bool) {
+	startDate := now.Format(dateLayout)
+	endDate := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, now.Location()).Format(dateLayout)
+
+	output, err := svc.GetCostForecast(ctx, &costexplorer.GetCostForecastInput{
+		Granularity: costexplorertypes.GranularityMonthly,
+		Metric:      costexplorertypes.MetricUnblendedCost,
+		TimePeriod: &costexplorertypes.DateInterval{
+			Start: awssdk.String(startDate),
+			End:   awssdk.String(endDate),
+		},
+		PredictionIntervalLevel: awssdk.Int32(forecastPredictionIntervalLevel),
+	})
+	if err != nil {
+		m.Logger().Errorf("costexplorer GetCostForecast failed: %s", err)
+		return mb.Event{}, false
+	}
+	if output.Total == nil || output.Total.Amount == nil {
+		return mb.Event{}, false
+	}
+
+	amount, err := strconv.ParseFloat(*output.Total.Amount, 64)
+	if err != nil {
+		m.Logger().Errorf("strconv ParseFloat failed: %s", err)
+		return mb.Event{}, false
+	}
+
+	event := aws.InitEvent("", m.AccountName, m.AccountID, now)
+	forecast := mapstr.M{
+		"metric":     string(costexplorertypes.MetricUnblendedCost),
+		"amount":     amount,
+		"unit":       awssdk.ToString(output.Total.Unit),
+		"start_date": startDate,
+		"end_date":   endDate,
+	}
+
+	// The prediction interval is only returned in the results by time.
+	if len(output.ForecastResultsByTime) > 0 {
+		result := output.ForecastResultsByTime[0]
+		if bound, err := strconv.ParseFloat(awssdk.ToString(result.PredictionIntervalLowerBound), 64); err == nil {
+			forecast["lower_bound"] = bound
+		}
+		if bound, err := strconv.ParseFloat(awssdk.ToString(result.PredictionIntervalUpperBound), 64); err == nil {
+			forecast["upper_bound"] = bound
+		}
+	}
+	event.MetricSetFields = mapstr.M{"forecast": forecast}
+
+	// One forecast event per day, like the Cost Explorer cost events.
+	event.ID = generateEventID(startDate + endDate + "forecast")
+	return event, true
+}
+
+// budgetsAPI is the subset of the AWS Budgets API used to get the budgets of
+// the account and their notifications.
+type budgetsAPI interface {
+	budgets.DescribeBudgetsAPIClient
+	DescribeNotificationsForBudget(ctx context.Context, params *budgets.DescribeNotificationsForBudgetInput, optFns ...func(*budgets.Options)) (*budgets.DescribeNotificationsForBudgetOutput, error)
+}
+
+// getBudgets returns an event for each budget of the account, with its budgeted,
+// actual and forecasted amounts, and the state of its notifications.
+func (m *MetricSet) getBudgets(ctx context.Context, svc budgetsAPI, endDate string) []mb.Event {
+	var events []mb.Event
+	paginator := budgets.NewDescribeBudgetsPaginator(svc, &budgets.DescribeBudgetsInput{
+		AccountId: awssdk.String(m.AccountID),
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			m.Logger().Errorf("budgets DescribeBudgets failed: %s", err)
+			return events
+		}
+
+		for _, budget := range output.Budgets {
+			event := m.createBudgetEvent(budget)
+
+			notifications, err := m.getBudgetNotifications(ctx, svc, awssdk.ToString(budget.BudgetName))
+			if err != nil {
+				m.Logger().Warnf("budgets DescribeNotificationsForBudget failed for budget %s: %s", awssdk.ToString(budget.BudgetName), err)
+			} else {
+				alarms := 0
+				for _, notification := range notifications {
+					if notification.NotificationState == budgetstypes.NotificationStateAlarm {
+						alarms++
+					}
+				}
+				_, _ = event.MetricSetFields.Put("budget.notifications.count", len(notifications))
+				_, _ = event.MetricSetFields.Put("budget.notifications.alarm", alarms)
+			}
+
+			if t, err := time.Parse(dateLayout, endDate); err == nil {
+				event.Timestamp = t
+			}
+			event.ID = generateEventID(endDate + "budget" + awssdk.ToString(budget.BudgetName))
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+func (m *MetricSet) getBudgetNotifications(ctx context.Context, svc budgetsAPI, budgetName string) ([]budgetstypes.Notification, error) {
+	var notifications []budgetstypes.Notification
+	input := &budgets.DescribeNotificationsForBudgetInput{
+		AccountId:  awssdk.String(m.AccountID),
+		BudgetName: awssdk.String(budgetName),
+	}
+	for {
+		output, err := svc.DescribeNotificationsForBudget(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, output.Notifications...)
+		if output.NextToken == nil {
+			return notifications, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+func (m *MetricSet) createBudgetEvent(budget budgetstypes.Budget) mb.Event {
+	event := aws.InitEvent("", m.AccountName, m.AccountID, time.Now())
+	event.MetricSetFields = mapstr.M{
+		"budget": mapstr.M{
+			"name":      awssdk.ToString(budget.BudgetName),
+			"type":      string(budget.BudgetType),
+			"time_unit": string(budget.TimeUnit),
+		},
+	}
+
+	limit, hasLimit := parseSpend(budget.BudgetLimit)
+	if hasLimit {
+		_, _ = event.MetricSetFields.Put("budget.limit.amount", limit)
+		_, _ = event.MetricSetFields.Put("budget.limit.unit", awssdk.ToString(budget.BudgetLimit.Unit))
+	}
+
+	if budget.CalculatedSpend == nil {
+		return event
+	}
+	if actual, ok := parseSpend(budget.CalculatedSpend.ActualSpend); ok {
+		_, _ = event.MetricSetFields.Put("budget.actual.amount", actual)
+		if hasLimit && limit > 0 {
+			_, _ = event.MetricSetFields.Put("budget.actual.pct", actual/limit)
+		}
+	}
+	if forecasted, ok := parseSpend(budget.CalculatedSpend.ForecastedSpend); ok {
+		_, _ = event.MetricSetFields.Put("budget.forecasted.amount", forecasted)
+		if hasLimit && limit > 0 {
+			_, _ = event.MetricSetFields.Put("budget.forecasted.pct", forecasted/limit)
+		}
+	}
+	return event
+}
+
+func parseSpend(spend *budgetstypes.Spend) (float64, bool) {
+	if spend == nil || spend.Amount == nil {
+		return 0, false
+	}
+	amount, err := strconv.ParseFloat(*spend.Amount, 64)
+	if err != nil {
+		return 0, false
+	}
+	return amount, true
+}
+
 func (m *MetricSet) addCostMetrics(metrics map[string]costexplorertypes.MetricValue, groupDefinition costexplorertypes.GroupDefinition, startDate string, endDate string) mb.Event {
 	event := aws.InitEvent("", m.AccountName, m.AccountID, time.Now())
 
@@ -444,14 +634,14 @@ func generateEventID(eventID string) string {
 	return prefix[:20]
 }
 
-func (m *MetricSet) getAccountName(svc *organizations.Client) map[string]string {
+func (m *MetricSet) getAccountName(ctx context.Context, svc *organizations.Client) map[string]string {
 	// construct ListAccountsInput
 	listAccountsInput := &organizations.ListAccountsInput{}
 	paginator := organizations.NewListAccountsPaginator(svc, listAccountsInput)
 
 	accounts := map[string]string{}
 	for paginator.HasMorePages() {
-		page, err := paginator.NextPage(context.Background())
+		page, err := paginator.NextPage(ctx)
 		if err != nil {
 			m.Logger().Warnf("an error occurred while listing account: %s", err.Error())
 			return accounts
@@ -8,12 +8,73 @@
 package billing
 
 import (
+	"context"
 	"testing"
 	"time"
 
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/budgets"
+	budgetstypes "github.com/aws/aws-sdk-go-v2/service/budgets/types"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	costexplorertypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws"
+	"github.com/elastic/elastic-agent-libs/mapstr"
 )
 
+// MockCostExplorerClient struct is used for unit tests.
+type MockCostExplorerClient struct{}
+
+// GetCostForecast implements costForecastAPI interface
+func (m *MockCostExplorerClient) GetCostForecast(_ context.Context, params *costexplorer.GetCostForecastInput, _ ...func(*costexplorer.Options)) (*costexplorer.GetCostForecastOutput, error) {
+	return &costexplorer.GetCostForecastOutput{
+		Total: &costexplorertypes.MetricValue{
+			Amount: awssdk.String("1234.5"),
+			Unit:   awssdk.String("USD"),
+		},
+		ForecastResultsByTime: []costexplorertypes.ForecastResult{
+			{
+				TimePeriod:                   params.TimePeriod,
+				MeanValue:                    awssdk.String("1234.5"),
+				PredictionIntervalLowerBound: awssdk.String("1100"),
+				PredictionIntervalUpperBound: awssdk.String("1400"),
+			},
+		},
+	}, nil
+}
+
+// MockBudgetsClient struct is used for unit tests.
+type MockBudgetsClient struct{}
+
+// DescribeBudgets implements budgets.DescribeBudgetsAPIClient interface
+func (m *MockBudgetsClient) DescribeBudgets(_ context.Context, _ *budgets.DescribeBudgetsInput, _ ...func(*budgets.Options)) (*budgets.DescribeBudgetsOutput, error) {
+	return &budgets.DescribeBudgetsOutput{
+		Budgets: []budgetstypes.Budget{
+			{
+				BudgetName:  awssdk.String("monthly"),
+				BudgetType:  budgetstypes.BudgetTypeCost,
+				TimeUnit:    budgetstypes.TimeUnitMonthly,
+				BudgetLimit: &budgetstypes.Spend{Amount: awssdk.String("1000"), Unit: awssdk.String("USD")},
+				CalculatedSpend: &budgetstypes.CalculatedSpend{
+					ActualSpend:     &budgetstypes.Spend{Amount: awssdk.String("500"), Unit: awssdk.String("USD")},
+					ForecastedSpend: &budgetstypes.Spend{Amount: awssdk.String("1250"), Unit: awssdk.String("USD")},
+				},
+			},
+		},
+	}, nil
+}
+
+// DescribeNotificationsForBudget implements budgetsAPI interface
+func (m *MockBudgetsClient) DescribeNotificationsForBudget(_ context.Context, _ *budgets.DescribeNotificationsForBudgetInput, _ ...func(*budgets.Options)) (*budgets.DescribeNotificationsForBudgetOutput, error) {
+	return &budgets.DescribeNotificationsForBudgetOutput{
+		Notifications: []budgetstypes.Notification{
+			{NotificationType: budgetstypes.NotificationTypeActual, NotificationState: budgetstypes.NotificationStateOk},
+			{NotificationType: budgetstypes.NotificationTypeForecasted, NotificationState: budgetstypes.NotificationStateAlarm},
+		},
+	}, nil
+}
+
 func TestGetStartDateEndDate(t *testing.T) {
 	startDate, endDate := getStartDateEndDate(time.Duration(24) * time.Hour)
 	assert.NotEmpty(t, startDate)
@@ -104,3 +165,56 @@ func TestGetGroupBys(t *testing.T) {
 		})
 	}
 }
+
+func TestGetCostForecast(t *testing.T) {
+	m := MetricSet{}
+	m.MetricSet = &aws.MetricSet{}
+
+	now := time.Date(2022, time.June, 21, 10, 0, 0, 0, time.UTC)
+	event, ok := m.getCostForecast(context.Background(), &MockCostExplorerClient{}, now)
+	assert.True(t, ok)
+	assert.Equal(t, mapstr.M{
+		"forecast": mapstr.M{
+			"metric":      "UNBLENDED_COST",
+			"amount":      1234.5,
+			"unit":        "USD",
+			"start_date":  "2022-06-21",
+			"end_date":    "2022-07-01",
+			"lower_bound": 1100.0,
+			"upper_bound": 1400.0,
+		},
+	}, event.MetricSetFields)
+	assert.NotEmpty(t, event.ID)
+}
+
+func TestGetBudgets(t *testing.T) {
+	m := MetricSet{}
+	m.MetricSet = &aws.MetricSet{AccountID: "123456789012"}
+
+	events := m.getBudgets(context.Background(), &MockBudgetsClient{}, "2022-06-22")
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, mapstr.M{
+		"budget": mapstr.M{
+			"name":      "monthly",
+			"type":      "COST",
+			"time_unit": "MONTHLY",
+			"limit": mapstr.M{
+				"amount": 1000.0,
+				"unit":   "USD",
+			},
+			"actual": mapstr.M{
+				"amount": 500.0,
+				"pct":    0.5,
+			},
+			"forecasted": mapstr.M{
+				"amount": 1250.0,
+				"pct":    1.25,
+			},
+			"notifications": mapstr.M{
+				"count": 2,
+				"alarm": 1,
+			},
+		},
+	}, events[0].MetricSetFields)
+	assert.Equal(t, time.Date(2022, time.June, 22, 0, 0, 0, 0, time.UTC), events[0].Timestamp)
+}
@@ -0,0 +1,97 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cloudwatch
+
+import (
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+const (
+	ec2Namespace   = "AWS/EC2"
+	agentNamespace = "CWAgent"
+)
+
+// instanceDimensions are the dimensions of the CloudWatch agent metrics that
+// identify an instance, the agent metrics with other dimensions, for example
+// the path of a disk, are not per instance.
+var instanceDimensions = map[string]bool{
+	"InstanceId":           true,
+	"ImageId":              true,
+	"InstanceType":         true,
+	"AutoScalingGroupName": true,
+	"host":                 true,
+}
+
+// isMergedNamespace returns true when the events of the namespace are held
+// back to merge the CloudWatch agent metrics into the EC2 events.
+func (m *MetricSet) isMergedNamespace(namespace string) bool {
+	return m.config.MergeAgentMetrics && (namespace == ec2Namespace || namespace == agentNamespace)
+}
+
+// mergeAgentMetrics merges the metrics of the CloudWatch agent events into the
+// EC2 events of the same instance, and returns the events to report. Agent
+// events that are not per instance, or whose instance has no EC2 event, are
+// returned unchanged.
+func (m *MetricSet) mergeAgentMetrics(ec2Events []mb.Event, agentEvents []mb.Event) []mb.Event {
+	byInstance := make(map[string]mb.Event, len(ec2Events))
+	for _, event := range ec2Events {
+		if instanceID, ok := instanceDimension(event); ok {
+			byInstance[instanceID] = event
+		}
+	}
+
+	events := ec2Events
+	for _, agentEvent := range agentEvents {
+		instanceID, ok := instanceDimension(agentEvent)
+		if !ok {
+			events = append(events, agentEvent)
+			continue
+		}
+		ec2Event, ok := byInstance[instanceID]
+		if !ok {
+			events = append(events, agentEvent)
+			continue
+		}
+
+		metricsField := "aws." + stripNamespace(agentNamespace) + ".metrics"
+		if m.config.GenericMetricFields {
+			metricsField = "aws." + metricsetName + ".metrics"
+		}
+		metrics, err := agentEvent.RootFields.GetValue(metricsField)
+		if err != nil {
+			continue
+		}
+		metricsMap, ok := metrics.(mapstr.M)
+		if !ok {
+			continue
+		}
+		for name, value := range metricsMap.Flatten() {
+			_, _ = ec2Event.RootFields.Put(metricsField+"."+name, value)
+		}
+		_, _ = ec2Event.RootFields.Put("aws.cloudwatch.merged_namespace", agentNamespace)
+	}
+	return events
+}
+
+// instanceDimension returns the InstanceId dimension of an event whose
+// dimensions only identify an instance.
+func instanceDimension(event mb.Event) (string, bool) {
+	dimensions, err := event.RootFields.GetValue("aws.dimensions")
+	if err != nil {
+		return "", false
+	}
+	dimensionsMap, ok := dimensions.(mapstr.M)
+	if !ok {
+		return "", false
+	}
+	for name := range dimensionsMap {
+		if !instanceDimensions[name] {
+			return "", false
+		}
+	}
+	instanceID, ok := dimensionsMap["InstanceId"].(string)
+	return instanceID, ok && instanceID != ""
+}
@@ -0,0 +1,95 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cloudwatch
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws"
+)
+
+// cardinalityKey identifies the metrics of a namespace collected from a region.
+type cardinalityKey struct {
+	regionName string
+	namespace  string
+}
+
+// namespaceCardinality holds the distinct metrics collected from a namespace.
+type namespaceCardinality struct {
+	metrics     map[string]struct{}
+	metricNames map[string]struct{}
+	dimensions  map[string]struct{}
+}
+
+// countCardinality adds the metrics collected from a region to the
+// cardinality reported every cardinality_report_interval.
+func (m *MetricSet) countCardinality(regionName string, metricsWithStats []metricsWithStatistics) {
+	if m.config.CardinalityReportInterval == 0 {
+		return
+	}
+
+	for _, metricWithStats := range metricsWithStats {
+		metric := metricWithStats.cloudwatchMetric
+		key := cardinalityKey{regionName: regionName, namespace: awssdk.ToString(metric.Namespace)}
+		cardinality, ok := m.cardinality[key]
+		if !ok {
+			cardinality = &namespaceCardinality{
+				metrics:     map[string]struct{}{},
+				metricNames: map[string]struct{}{},
+				dimensions:  map[string]struct{}{},
+			}
+			m.cardinality[key] = cardinality
+		}
+
+		metricKey := metricSortKey(metric)
+		cardinality.metrics[metricKey] = struct{}{}
+		cardinality.metricNames[awssdk.ToString(metric.MetricName)] = struct{}{}
+		cardinality.dimensions[strings.SplitN(metricKey, labelSeparator, 2)[1]] = struct{}{}
+	}
+}
+
+// reportCardinality reports, every cardinality_report_interval, an event per
+// region and namespace with the number of distinct metrics, metric names and
+// dimension combinations collected since the previous report.
+func (m *MetricSet) reportCardinality(report mb.ReporterV2, now time.Time) {
+	if m.config.CardinalityReportInterval == 0 {
+		return
+	}
+	if m.lastCardinalityReport.IsZero() {
+		m.lastCardinalityReport = now
+	}
+	if now.Sub(m.lastCardinalityReport) < m.config.CardinalityReportInterval {
+		return
+	}
+
+	keys := make([]cardinalityKey, 0, len(m.cardinality))
+	for key := range m.cardinality {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].regionName != keys[j].regionName {
+			return keys[i].regionName < keys[j].regionName
+		}
+		return keys[i].namespace < keys[j].namespace
+	})
+
+	for _, key := range keys {
+		cardinality := m.cardinality[key]
+		event := aws.InitEvent(key.regionName, m.AccountName, m.AccountID, now)
+		_, _ = event.RootFields.Put("aws.cloudwatch.namespace", key.namespace)
+		_, _ = event.RootFields.Put("aws.cloudwatch.cardinality.metrics", len(cardinality.metrics))
+		_, _ = event.RootFields.Put("aws.cloudwatch.cardinality.metric_names", len(cardinality.metricNames))
+		_, _ = event.RootFields.Put("aws.cloudwatch.cardinality.dimension_combinations", len(cardinality.dimensions))
+		report.Event(event)
+	}
+
+	m.cardinality = map[cardinalityKey]*namespaceCardinality{}
+	m.lastCardinalityReport = now
+}
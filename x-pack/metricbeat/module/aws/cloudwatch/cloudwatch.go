@@ -5,22 +5,34 @@
 package cloudwatch
 
 import (
+	"context"
 	"fmt"
 	"reflect"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	awssdk "github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/configservice"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
 	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
 	resourcegroupstaggingapitypes "github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
 
 	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/common/match"
 	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/libbeat/persistentcache"
 	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
 	"github.com/elastic/elastic-agent-libs/logp"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+	"github.com/elastic/elastic-agent-libs/monitoring"
 )
 
 var (
@@ -33,7 +45,45 @@ var (
 	defaultStatistics      = []string{"Average", "Maximum", "Minimum", "Sum", "SampleCount"}
 	labelSeparator         = "|"
 	dimensionSeparator     = ","
+	labelEscape            = "\\"
 	dimensionValueWildcard = "*"
+	namespaceWildcard      = "*"
+)
+
+// maxConcurrentTagRequests is the maximum number of resource types whose tags
+// are fetched concurrently.
+const maxConcurrentTagRequests = 5
+
+// Strategies to select the timestamp of the collected data points.
+const (
+	timestampStrategyLatestComplete = "latest-complete"
+	timestampStrategyLatest         = "latest"
+	timestampStrategyPerMetric      = "per-metric"
+)
+
+// Actions of event filters.
+const (
+	eventFilterActionKeep = "keep"
+	eventFilterActionDrop = "drop"
+)
+
+// Sources of resource tags for metrics with resource_type specified.
+const (
+	tagSourceResourceGroupsTaggingAPI = aws.TagSourceResourceGroupsTaggingAPI
+	tagSourceAWSConfig                = "aws_config"
+)
+
+// Derivatives computed from Sum and SampleCount statistics across fetches.
+const (
+	counterDerivativeRate  = "rate"
+	counterDerivativeDelta = "delta"
+)
+
+// Policies applied to the events when adding metadata fails.
+const (
+	metadataFailurePolicyKeep  = "keep"
+	metadataFailurePolicyDrop  = "drop"
+	metadataFailurePolicyRetry = "retry"
 )
 
 // init registers the MetricSet with the central registry as soon as the program
@@ -52,8 +102,85 @@ func init() {
 // interface methods except for Fetch.
 type MetricSet struct {
 	*aws.MetricSet
-	logger            *logp.Logger
-	CloudwatchConfigs []Config `config:"metrics" validate:"nonzero,required"`
+	logger                *logp.Logger
+	config                MetricSetConfig
+	CloudwatchConfigs     []Config
+	labelLocation         *time.Location
+	tagSources            map[string]string
+	lastEndTimes          map[collectionWindow]time.Time
+	previousValues        map[string]previousValue
+	seenResources         map[string]seenResource
+	accountAliasResolver  *aws.AccountAliasResolver
+	state                 *collectionState
+	observations          configObservations
+	pendingEvents         heldBackEvents
+	metadataFailures      *monitoring.Int
+	metricsFileModTime    time.Time
+	cardinality           map[cardinalityKey]*namespaceCardinality
+	lastCardinalityReport time.Time
+	profiles              map[*aws.MetricSet]*MetricSet
+	costEstimated         bool
+}
+
+// MetricSetConfig holds the configuration of the cloudwatch metricset.
+type MetricSetConfig struct {
+	CloudwatchMetrics         []Config                  `config:"metrics"`
+	MetricsPath               string                    `config:"metrics_path"`
+	GenericMetricFields       bool                      `config:"generic_metric_fields"`
+	ConfigAggregator          ConfigAggregator          `config:"config_aggregator"`
+	TimestampStrategy         string                    `config:"timestamp_strategy"`
+	EventFilters              []EventFilter             `config:"event_filters"`
+	MaxMetricsPerNamespace    int                       `config:"max_metrics_per_namespace" validate:"min=0"`
+	LabelTimezone             string                    `config:"label_timezone"`
+	Backfill                  time.Duration             `config:"backfill" validate:"min=0"`
+	CounterDerivative         string                    `config:"counter_derivative"`
+	TombstonePeriods          int                       `config:"tombstone_periods" validate:"min=0"`
+	IncludeAccountAlias       bool                      `config:"include_account_alias"`
+	DimensionAliases          map[string]string         `config:"dimension_aliases"`
+	UnobservedFetches         int                       `config:"unobserved_metrics_fetches" validate:"min=0"`
+	MetadataFailurePolicy     string                    `config:"metadata_failure_policy"`
+	MaxDatapoints             int32                     `config:"max_datapoints" validate:"min=0"`
+	QueriesPerRequest         int                       `config:"metric_data_queries_per_request" validate:"min=0,max=500"`
+	DefaultStatistics         []string                  `config:"default_statistics"`
+	CardinalityReportInterval time.Duration             `config:"cardinality_report_interval" validate:"min=0"`
+	InsightRules              []InsightRuleConfig       `config:"insight_rules"`
+	PerformanceInsights       PerformanceInsightsConfig `config:"performance_insights"`
+	CrossRegionAggregation    []string                  `config:"cross_region_aggregation"`
+	MergeAgentMetrics         bool                      `config:"merge_agent_metrics"`
+}
+
+// Validate checks if the options of the cloudwatch metricset are supported.
+func (c MetricSetConfig) Validate() error {
+	if len(c.CloudwatchMetrics) == 0 && c.MetricsPath == "" && len(c.InsightRules) == 0 && !c.PerformanceInsights.Enabled {
+		return fmt.Errorf("metrics in config is missing, set metrics, metrics_path, insight_rules or performance_insights")
+	}
+
+	switch c.TimestampStrategy {
+	case "", timestampStrategyLatestComplete, timestampStrategyLatest, timestampStrategyPerMetric:
+	default:
+		return fmt.Errorf("timestamp_strategy %s is not supported, use %s, %s or %s", c.TimestampStrategy, timestampStrategyLatestComplete, timestampStrategyLatest, timestampStrategyPerMetric)
+	}
+
+	switch c.CounterDerivative {
+	case "", counterDerivativeRate, counterDerivativeDelta:
+	default:
+		return fmt.Errorf("counter_derivative %s is not supported, use %s or %s", c.CounterDerivative, counterDerivativeRate, counterDerivativeDelta)
+	}
+
+	for _, aggregation := range c.CrossRegionAggregation {
+		switch aggregation {
+		case crossRegionAggregationSum, crossRegionAggregationAvg:
+		default:
+			return fmt.Errorf("cross_region_aggregation %s is not supported, use %s or %s", aggregation, crossRegionAggregationSum, crossRegionAggregationAvg)
+		}
+	}
+
+	switch c.MetadataFailurePolicy {
+	case "", metadataFailurePolicyKeep, metadataFailurePolicyDrop, metadataFailurePolicyRetry:
+	default:
+		return fmt.Errorf("metadata_failure_policy %s is not supported, use %s, %s or %s", c.MetadataFailurePolicy, metadataFailurePolicyKeep, metadataFailurePolicyDrop, metadataFailurePolicyRetry)
+	}
+	return nil
 }
 
 // Dimension holds name and value for cloudwatch metricset dimension config.
@@ -64,11 +191,67 @@ type Dimension struct {
 
 // Config holds a configuration specific for cloudwatch metricset.
 type Config struct {
-	Namespace    string      `config:"namespace" validate:"nonzero,required"`
-	MetricName   []string    `config:"name"`
-	Dimensions   []Dimension `config:"dimensions"`
-	ResourceType string      `config:"resource_type"`
-	Statistic    []string    `config:"statistic"`
+	Namespace    string         `config:"namespace" validate:"nonzero,required"`
+	MetricName   []string       `config:"name"`
+	Dimensions   []Dimension    `config:"dimensions"`
+	ResourceType string         `config:"resource_type"`
+	Statistic    []string       `config:"statistic"`
+	TagSource    string         `config:"tag_source"`
+	Latency      *time.Duration `config:"latency"`
+	Period       *time.Duration `config:"period"`
+}
+
+// eventBatch holds events keyed by their identifier. A backfill creates a
+// batch per timestamp, a regular collection a single batch with a zero timestamp.
+type eventBatch struct {
+	timestamp time.Time
+	events    map[string]mb.Event
+}
+
+// collectionWindow identifies the metrics configs collected over the same time range.
+type collectionWindow struct {
+	period  time.Duration
+	latency time.Duration
+}
+
+// ConfigAggregator holds the AWS Config aggregator used to collect tags for
+// metrics with tag_source set to aws_config.
+type ConfigAggregator struct {
+	Name   string `config:"name"`
+	Region string `config:"region"`
+}
+
+// EventFilter keeps or drops events based on a regular expression matched against
+// a dimension value of the events, after the metrics have been collected.
+type EventFilter struct {
+	Namespace string        `config:"namespace"`
+	Dimension string        `config:"dimension" validate:"required"`
+	Pattern   match.Matcher `config:"pattern" validate:"required"`
+	Action    string        `config:"action"`
+}
+
+// Validate checks if the action of an event filter is supported.
+func (f EventFilter) Validate() error {
+	switch f.Action {
+	case "", eventFilterActionKeep, eventFilterActionDrop:
+		return nil
+	default:
+		return fmt.Errorf("event filter action %s is not supported, use %s or %s", f.Action, eventFilterActionKeep, eventFilterActionDrop)
+	}
+}
+
+// Validate checks if the tag source of a metrics config is supported.
+func (c Config) Validate() error {
+	switch c.TagSource {
+	case "", tagSourceResourceGroupsTaggingAPI:
+	case tagSourceAWSConfig:
+		if c.ResourceType == "" {
+			return fmt.Errorf("resource_type is required when tag_source is %s", tagSourceAWSConfig)
+		}
+	default:
+		return fmt.Errorf("tag_source %s is not supported, use %s or %s", c.TagSource, tagSourceResourceGroupsTaggingAPI, tagSourceAWSConfig)
+	}
+	return nil
 }
 
 type metricsWithStatistics struct {
@@ -99,34 +282,163 @@ func New(base mb.BaseMetricSet) (mb.MetricSet, error) {
 		return nil, fmt.Errorf("error creating aws metricset: %w", err)
 	}
 
-	config := struct {
-		CloudwatchMetrics []Config `config:"metrics" validate:"nonzero,required"`
-	}{}
-
+	var config MetricSetConfig
 	err = base.Module().UnpackConfig(&config)
 	if err != nil {
 		return nil, fmt.Errorf("error unpack raw module config using UnpackConfig: %w", err)
 	}
 
 	logger.Debugf("cloudwatch config = %s", config)
-	if len(config.CloudwatchMetrics) == 0 {
-		return nil, fmt.Errorf("metrics in config is missing: %w", err)
+
+	var labelLocation *time.Location
+	if config.LabelTimezone != "" {
+		labelLocation, err = aws.ParseLabelTimezone(config.LabelTimezone)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing label_timezone: %w", err)
+		}
+	}
+
+	cloudwatchConfigs := config.CloudwatchMetrics
+	var metricsFileModTime time.Time
+	if config.MetricsPath != "" {
+		fileConfigs, modTime, err := loadMetricsFile(config.MetricsPath)
+		if err != nil {
+			return nil, err
+		}
+		cloudwatchConfigs = append(append([]Config{}, config.CloudwatchMetrics...), fileConfigs...)
+		metricsFileModTime = modTime
+		if len(cloudwatchConfigs) == 0 {
+			return nil, fmt.Errorf("no metrics configs found in metrics_path %s", config.MetricsPath)
+		}
 	}
 
-	return &MetricSet{
-		MetricSet:         metricSet,
-		logger:            logger,
-		CloudwatchConfigs: config.CloudwatchMetrics,
-	}, nil
+	tagSources, err := validateConfigs(cloudwatchConfigs, metricSet.Period, config.ConfigAggregator)
+	if err != nil {
+		return nil, err
+	}
+
+	// Continue with the state of the previous metricset when only the metrics
+	// configs of the module changed.
+	stateKey, err := collectionStateKey(base)
+	if err != nil {
+		return nil, err
+	}
+	metadataFailures := monitoring.NewInt(base.Metrics(), "metadata_failures")
+
+	// Each credential profile of the module collects with its own state.
+	newProfileMetricSet := func(profile *aws.MetricSet) (*MetricSet, error) {
+		if err := profile.CheckVPCEndpoints(cloudwatch.ServiceID); err != nil {
+			return nil, err
+		}
+		profileKey, err := profileStateKey(stateKey, profile.ProfileName)
+		if err != nil {
+			return nil, err
+		}
+		state, reloaded := collectionStates.claim(profileKey, time.Now())
+		if reloaded {
+			added, removed := diffConfigs(state.cloudwatchConfigs, cloudwatchConfigs)
+			logger.Infof("Metrics configs reloaded, %d added and %d removed, continuing with the previous collection state", len(added), len(removed))
+		}
+		state.reload(cloudwatchConfigs)
+
+		profileMetricSet := &MetricSet{
+			MetricSet:            profile,
+			logger:               logger,
+			config:               config,
+			CloudwatchConfigs:    cloudwatchConfigs,
+			labelLocation:        labelLocation,
+			tagSources:           tagSources,
+			lastEndTimes:         state.lastEndTimes,
+			previousValues:       state.previousValues,
+			seenResources:        state.seenResources,
+			accountAliasResolver: state.accountAliasResolver,
+			state:                state,
+			observations:         newConfigObservations(),
+			pendingEvents:        heldBackEvents{},
+			metadataFailures:     metadataFailures,
+			metricsFileModTime:   metricsFileModTime,
+			cardinality:          map[cardinalityKey]*namespaceCardinality{},
+		}
+		profileMetricSet.logConfiguredCostEstimate()
+		return profileMetricSet, nil
+	}
+
+	m, err := newProfileMetricSet(metricSet)
+	if err != nil {
+		return nil, err
+	}
+	m.profiles = map[*aws.MetricSet]*MetricSet{}
+	for _, profile := range metricSet.Profiles {
+		if profile == metricSet {
+			continue
+		}
+		profileMetricSet, err := newProfileMetricSet(profile)
+		if err != nil {
+			m.Close()
+			return nil, err
+		}
+		m.profiles[profile] = profileMetricSet
+	}
+	return m, nil
+}
+
+// validateConfigs checks the metrics configs against the module config, and
+// returns the tag source of each resource type.
+func validateConfigs(cloudwatchConfigs []Config, modulePeriod time.Duration, configAggregator ConfigAggregator) (map[string]string, error) {
+	tagSources := map[string]string{}
+	for _, cloudwatchConfig := range cloudwatchConfigs {
+		if cloudwatchConfig.Period != nil && *cloudwatchConfig.Period < modulePeriod {
+			return nil, fmt.Errorf("period %s of namespace %s must not be smaller than the module period %s", *cloudwatchConfig.Period, cloudwatchConfig.Namespace, modulePeriod)
+		}
+		if cloudwatchConfig.ResourceType != "" && cloudwatchConfig.TagSource != "" {
+			tagSources[cloudwatchConfig.ResourceType] = cloudwatchConfig.TagSource
+		}
+		if cloudwatchConfig.TagSource == tagSourceAWSConfig && configAggregator.Name == "" {
+			return nil, fmt.Errorf("config_aggregator.name is required when tag_source is %s", tagSourceAWSConfig)
+		}
+	}
+	return tagSources, nil
+}
+
+// Close releases the collection states of the metricset, so a metricset created
+// from the same module config continues with them, and its AWS configs.
+func (m *MetricSet) Close() error {
+	for _, profile := range m.profiles {
+		profile.releaseState()
+	}
+	m.releaseState()
+	return m.MetricSet.Close()
+}
+
+// releaseState releases the collection state of a single credential profile.
+func (m *MetricSet) releaseState() {
+	if m.state != nil {
+		m.state.accountAliasResolver = m.accountAliasResolver
+		collectionStates.release(m.state, time.Now())
+		m.state = nil
+	}
 }
 
 // Fetch methods implements the data gathering and data conversion to the right
 // format. It publishes the event which is then forwarded to the output. In case
 // of an error set the Error field of mb.Event or simply call report.Error().
-func (m *MetricSet) Fetch(report mb.ReporterV2) error {
-	// Get startTime and endTime
-	startTime, endTime := aws.GetStartTimeEndTime(time.Now(), m.Period, m.Latency)
-	m.Logger().Debugf("startTime = %s, endTime = %s", startTime, endTime)
+func (m *MetricSet) Fetch(ctx context.Context, report mb.ReporterV2) error {
+	return m.MetricSet.ForEachProfile(func(profile *aws.MetricSet) error {
+		if profile == m.MetricSet {
+			return m.fetch(ctx, profile.OrganizationReporter(ctx, report))
+		}
+		return m.profiles[profile].fetch(ctx, profile.OrganizationReporter(ctx, report))
+	})
+}
+
+// fetch collects the metrics with the credentials and state of a single
+// credential profile.
+func (m *MetricSet) fetch(ctx context.Context, report mb.ReporterV2) error {
+	if m.config.MetricsPath != "" {
+		if err := m.reloadMetricsFile(); err != nil {
+			m.logger.Warnf("Failed to reload metrics from %s, continuing with the previous metrics configs: %s", m.config.MetricsPath, err)
+		}
+	}
 
 	// Check statistic method in config
 	err := m.checkStatistics()
@@ -134,17 +446,135 @@ func (m *MetricSet) Fetch(report mb.ReporterV2) error {
 		return fmt.Errorf("checkStatistics failed: %w", err)
 	}
 
+	svcConfigAPI := m.createConfigAggregatorClient()
+	if m.config.IncludeAccountAlias && m.accountAliasResolver == nil {
+		m.accountAliasResolver = m.createAccountAliasResolver()
+	}
+
+	// Metrics configs are collected with the time range of their own period and
+	// latency, so slow publishing namespaces can be shifted further back in time
+	// and metrics with a longer period are only collected once per period.
+	now := time.Now()
+	// The API requests of the first collection are counted to estimate the
+	// monthly cost with the discovered metrics
+	var usageByPeriod map[time.Duration]*apiUsage
+	if !m.costEstimated {
+		usageByPeriod = map[time.Duration]*apiUsage{}
+	}
+	for window, cloudwatchConfigs := range m.groupConfigs() {
+		// Get startTime and endTime
+		startTime, endTime := m.getStartTimeEndTime(now, window.period, window.latency)
+		if !m.isDue(window, endTime) {
+			m.logger.Debugf("skipping metrics with period %s, already collected until %s", window.period, endTime)
+			continue
+		}
+		m.Logger().Debugf("startTime = %s, endTime = %s", startTime, endTime)
+
+		// On the first collection, emit the metrics of the backfill time range
+		// with their original timestamps. The window is only marked as collected
+		// when the backfill succeeds, so a failed backfill is retried on the next fetch.
+		if _, collected := m.lastEndTimes[window]; !collected && m.config.Backfill > 0 {
+			if backfillStartTime := m.getBackfillStartTime(startTime, endTime); backfillStartTime.Before(startTime) {
+				m.logger.Infof("Backfilling metrics with period %s over the last %s", window.period, startTime.Sub(backfillStartTime))
+				err := m.collect(ctx, report, svcConfigAPI, cloudwatchConfigs, window.period, backfillStartTime, startTime, nil, true)
+				if err != nil {
+					return fmt.Errorf("backfill of metrics between %s and %s failed: %w", backfillStartTime, startTime, err)
+				}
+			}
+		}
+
+		var usage *apiUsage
+		if usageByPeriod != nil {
+			if usage = usageByPeriod[window.period]; usage == nil {
+				usage = &apiUsage{}
+				usageByPeriod[window.period] = usage
+			}
+		}
+		err := m.collect(ctx, report, svcConfigAPI, cloudwatchConfigs, window.period, startTime, endTime, usage, false)
+		if err != nil {
+			return err
+		}
+		m.lastEndTimes[window] = endTime
+	}
+	if usageByPeriod != nil {
+		m.logCollectedCostEstimate(usageByPeriod)
+		m.costEstimated = true
+	}
+
+	m.prunePreviousValues(now)
+	m.reportGoneResources(report, now)
+	m.reportUnobservedConfigs(report, now)
+	m.reportCardinality(report, now)
+	m.collectInsightRules(ctx, report, now)
+	m.collectPerformanceInsights(ctx, report, now)
+	return nil
+}
+
+// getBackfillStartTime returns the start of the backfill time range before
+// startTime, a whole number of time ranges of the same duration as the given one.
+func (m *MetricSet) getBackfillStartTime(startTime time.Time, endTime time.Time) time.Time {
+	span := endTime.Sub(startTime)
+	if span <= 0 {
+		return startTime
+	}
+	return startTime.Add(-span * (m.config.Backfill / span))
+}
+
+// isDue checks if the metrics of a collection window have to be collected up to
+// endTime. Windows with the module period are collected on every fetch, windows
+// with a longer period only when a new period has ended since the last collection.
+func (m *MetricSet) isDue(window collectionWindow, endTime time.Time) bool {
+	if window.period == m.Period {
+		return true
+	}
+	lastEndTime, ok := m.lastEndTimes[window]
+	return !ok || endTime.After(lastEndTime)
+}
+
+// getStartTimeEndTime calculates the start and end times of the queries,
+// aligned to the period in label_timezone if it is set.
+func (m *MetricSet) getStartTimeEndTime(now time.Time, period time.Duration, latency time.Duration) (time.Time, time.Time) {
+	if m.labelLocation != nil {
+		return aws.GetStartTimeEndTimeInLocation(now, period, latency, m.labelLocation)
+	}
+	return aws.GetStartTimeEndTime(now, period, latency)
+}
+
+// groupConfigs groups the metrics configs by period and latency. Metrics configs
+// without period or latency use the ones of the module.
+func (m *MetricSet) groupConfigs() map[collectionWindow][]Config {
+	configsByWindow := map[collectionWindow][]Config{}
+	for _, cloudwatchConfig := range m.CloudwatchConfigs {
+		window := collectionWindow{period: m.Period, latency: m.Latency}
+		if cloudwatchConfig.Period != nil {
+			window.period = *cloudwatchConfig.Period
+		}
+		if cloudwatchConfig.Latency != nil {
+			window.latency = *cloudwatchConfig.Latency
+		}
+		configsByWindow[window] = append(configsByWindow[window], cloudwatchConfig)
+	}
+	return configsByWindow
+}
+
+// collect creates and reports the events of the given metrics configs between startTime and endTime.
+// The API requests of the collection are counted in usage, when it is not nil. A backfill
+// reports the events of every timestamp between startTime and endTime, oldest first.
+func (m *MetricSet) collect(ctx context.Context, report mb.ReporterV2, svcConfigAPI aws.ConfigAggregatorClient, cloudwatchConfigs []Config, period time.Duration, startTime time.Time, endTime time.Time, usage *apiUsage, backfill bool) error {
 	// Get listMetricDetailTotal and namespaceDetailTotal from configuration
-	listMetricDetailTotal, namespaceDetailTotal := m.readCloudwatchConfig()
+	listMetricDetailTotal, namespaceDetailTotal := m.readCloudwatchConfig(cloudwatchConfigs)
+	m.observations.check(cloudwatchConfigs)
+	crossRegionAggregates := crossRegionBatches{}
+	// The resources described by the enrichers are shared by all the batches
+	// of the collection, so a backfill describes them once.
+	discovery := newFetchDiscovery(m.MetricSet.Discovery)
+	// The events held back by the metadata_failure_policy in this collection
+	// are retried in the next one.
+	heldBack := heldBackEvents{}
+	defer m.pendingEvents.add(heldBack)
 	m.logger.Debugf("listMetricDetailTotal = %s", listMetricDetailTotal)
 	m.logger.Debugf("namespaceDetailTotal = %s", namespaceDetailTotal)
 
-	var config aws.Config
-	err = m.Module().UnpackConfig(&config)
-	if err != nil {
-		return err
-	}
-
 	// Create events based on listMetricDetailTotal from configuration
 	if len(listMetricDetailTotal.metricsWithStats) != 0 {
 		for _, regionName := range m.MetricSet.RegionsList {
@@ -152,20 +582,32 @@ func (m *MetricSet) Fetch(report mb.ReporterV2) error {
 			beatsConfig := m.MetricSet.AwsConfig.Copy()
 			beatsConfig.Region = regionName
 
-			svcCloudwatch, svcResourceAPI, err := m.createAwsRequiredClients(beatsConfig, regionName, config)
+			svcCloudwatch, svcResourceAPI, err := m.createAwsRequiredClients(beatsConfig, regionName)
 			if err != nil {
 				m.Logger().Warn("skipping metrics list from region '%s'", regionName)
 			}
 
-			eventsWithIdentifier, err := m.createEvents(svcCloudwatch, svcResourceAPI, listMetricDetailTotal.metricsWithStats, listMetricDetailTotal.resourceTypeFilters, regionName, startTime, endTime)
+			m.countCardinality(regionName, listMetricDetailTotal.metricsWithStats)
+			usage.addMetricData(listMetricDetailTotal.metricsWithStats, m.queriesPerRequest())
+			batches, err := m.createEventBatches(ctx, svcCloudwatch, svcResourceAPI, svcConfigAPI, listMetricDetailTotal.metricsWithStats, listMetricDetailTotal.resourceTypeFilters, regionName, period, startTime, endTime, backfill)
 			if err != nil {
 				return fmt.Errorf("createEvents failed for region %s: %w", regionName, err)
 			}
 
-			m.logger.Debugf("Collected metrics of metrics = %d", len(eventsWithIdentifier))
+			for i, batch := range batches {
+				m.logger.Debugf("Collected metrics of metrics = %d", len(batch.events))
+				eventsWithIdentifier := m.filterEvents(batch.events)
+				m.addCounterDerivatives(eventsWithIdentifier, regionName, period)
+				m.aggregateCrossRegion(crossRegionAggregates.get(batch.timestamp), regionName, eventsWithIdentifier)
+				if i == len(batches)-1 {
+					m.trackResources(eventsWithIdentifier, regionName, period)
+				}
+				m.addAccountAlias(ctx, eventsWithIdentifier)
 
-			for _, event := range eventsWithIdentifier {
-				report.Event(event)
+				for _, event := range eventsWithIdentifier {
+					m.aliasDimensions(event)
+					report.Event(event)
+				}
 			}
 		}
 	}
@@ -176,18 +618,28 @@ func (m *MetricSet) Fetch(report mb.ReporterV2) error {
 		beatsConfig := m.MetricSet.AwsConfig.Copy()
 		beatsConfig.Region = regionName
 
-		svcCloudwatch, svcResourceAPI, err := m.createAwsRequiredClients(beatsConfig, regionName, config)
+		svcCloudwatch, svcResourceAPI, err := m.createAwsRequiredClients(beatsConfig, regionName)
 		if err != nil {
 			m.Logger().Warn("skipping metrics list from region '%s'", regionName)
 		}
-		for namespace, namespaceDetails := range namespaceDetailTotal {
+
+		// Resolve namespace patterns against the namespaces present in this region
+		namespaceDetailRegion, discoveredListMetrics := m.discoverNamespaces(ctx, svcCloudwatch, regionName, period, namespaceDetailTotal)
+		mergedEvents := map[time.Time]map[string][]mb.Event{}
+
+		for namespace, namespaceDetails := range namespaceDetailRegion {
 			m.logger.Debugf("Collected metrics from namespace %s", namespace)
 
-			listMetricsOutput, err := aws.GetListMetricsOutput(namespace, regionName, m.Period, svcCloudwatch)
-			if err != nil {
-				m.logger.Info(err.Error())
-				continue
+			listMetricsOutput, ok := discoveredListMetrics[namespace]
+			if !ok {
+				listMetricsOutput, err = m.MetricSet.Discovery.ListMetrics(ctx, svcCloudwatch, namespace, regionName, period)
+				if err != nil {
+					m.logger.Info(err.Error())
+					continue
+				}
 			}
+			m.observations.observe(cloudwatchConfigs, namespace, listMetricsOutput)
+			usage.addListMetrics(len(listMetricsOutput))
 
 			if len(listMetricsOutput) == 0 {
 				continue
@@ -195,48 +647,343 @@ func (m *MetricSet) Fetch(report mb.ReporterV2) error {
 
 			// filter listMetricsOutput by detailed configuration per each namespace
 			filteredMetricWithStatsTotal := filterListMetricsOutput(listMetricsOutput, namespaceDetails)
+			// cap the number of metrics collected from this namespace
+			filteredMetricWithStatsTotal = m.limitMetrics(report, namespace, regionName, filteredMetricWithStatsTotal, endTime)
 			// get resource type filters and tags filters for each namespace
 			resourceTypeTagFilters := constructTagsFilters(namespaceDetails)
+			m.countCardinality(regionName, filteredMetricWithStatsTotal)
+			usage.addMetricData(filteredMetricWithStatsTotal, m.queriesPerRequest())
 
-			eventsWithIdentifier, err := m.createEvents(svcCloudwatch, svcResourceAPI, filteredMetricWithStatsTotal, resourceTypeTagFilters, regionName, startTime, endTime)
+			batches, err := m.createEventBatches(ctx, svcCloudwatch, svcResourceAPI, svcConfigAPI, filteredMetricWithStatsTotal, resourceTypeTagFilters, regionName, period, startTime, endTime, backfill)
 			if err != nil {
 				return fmt.Errorf("createEvents failed for region %s: %w", regionName, err)
 			}
 
-			m.logger.Debugf("Collected number of metrics = %d", len(eventsWithIdentifier))
+			for i, batch := range batches {
+				m.logger.Debugf("Collected number of metrics = %d", len(batch.events))
+				eventsWithIdentifier := m.filterEvents(batch.events)
+				m.addCounterDerivatives(eventsWithIdentifier, regionName, period)
+				m.aggregateCrossRegion(crossRegionAggregates.get(batch.timestamp), regionName, eventsWithIdentifier)
+				// Only the resources of the newest batch are seen now.
+				if i == len(batches)-1 {
+					m.trackResources(eventsWithIdentifier, regionName, period)
+				}
+				m.addAccountAlias(ctx, eventsWithIdentifier)
 
-			events, err := addMetadata(namespace, regionName, beatsConfig, config.AWSConfig.FIPSEnabled, eventsWithIdentifier)
-			if err != nil {
-				// TODO What to do if add metadata fails? I guess to continue, probably we have an 90% of reliable data
-				m.Logger().Warn("could not add metadata to events: %w", err)
+				events := m.enrichEvents(ctx, namespace, regionName, beatsConfig, discovery, eventsWithIdentifier, heldBack)
+				if m.isMergedNamespace(namespace) {
+					if mergedEvents[batch.timestamp] == nil {
+						mergedEvents[batch.timestamp] = map[string][]mb.Event{}
+					}
+					mergedEvents[batch.timestamp][namespace] = events
+					continue
+				}
+				for _, event := range events {
+					m.aliasDimensions(event)
+					report.Event(event)
+				}
 			}
+		}
 
-			for _, event := range events {
+		for _, timestamp := range mergedTimestamps(mergedEvents) {
+			for _, event := range m.mergeAgentMetrics(mergedEvents[timestamp][ec2Namespace], mergedEvents[timestamp][agentNamespace]) {
+				m.aliasDimensions(event)
 				report.Event(event)
 			}
 		}
 	}
+
+	for _, timestamp := range crossRegionAggregates.timestamps() {
+		m.reportCrossRegion(report, crossRegionAggregates[timestamp])
+	}
 	return nil
 }
 
+// mergedTimestamps returns the timestamps of the batches of merged events, oldest first.
+func mergedTimestamps(mergedEvents map[time.Time]map[string][]mb.Event) []time.Time {
+	timestamps := make([]time.Time, 0, len(mergedEvents))
+	for timestamp := range mergedEvents {
+		timestamps = append(timestamps, timestamp)
+	}
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i].Before(timestamps[j]) })
+	return timestamps
+}
+
+// heldBackEvents are the batches of events held back by the retry
+// metadata_failure_policy, by region and namespace.
+type heldBackEvents map[string][]map[string]mb.Event
+
+// add adds the batches of events held back in other to e.
+func (e heldBackEvents) add(other heldBackEvents) {
+	for key, batches := range other {
+		e[key] = append(e[key], batches...)
+	}
+}
+
+// enrichEvents adds metadata to the events of a namespace and applies the
+// metadata_failure_policy when it fails, holding back the events in heldBack.
+// It returns the events to report, including the events held back in the
+// previous fetch.
+func (m *MetricSet) enrichEvents(ctx context.Context, namespace string, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event, heldBack heldBackEvents) []mb.Event {
+	key := regionName + labelSeparator + namespace
+	var enrichedEvents []mb.Event
+
+	// Events held back in the previous fetch are retried once, and reported
+	// without metadata if it fails again. The batches of a backfill are
+	// retried separately, as they have the same identifiers.
+	if pending, ok := m.pendingEvents[key]; ok {
+		delete(m.pendingEvents, key)
+		for _, pendingBatch := range pending {
+			retriedEvents, err := addMetadata(ctx, namespace, regionName, awsConfig, discovery, pendingBatch)
+			if err != nil {
+				m.countMetadataFailure()
+				m.logger.Warnf("could not add metadata to events held back from the previous fetch, reporting them without metadata: %s", err)
+				if retriedEvents == nil {
+					retriedEvents = pendingBatch
+				}
+			}
+			for _, event := range retriedEvents {
+				enrichedEvents = append(enrichedEvents, event)
+			}
+		}
+	}
+
+	eventsWithMetadata, err := addMetadata(ctx, namespace, regionName, awsConfig, discovery, events)
+	if err != nil {
+		m.countMetadataFailure()
+		switch m.config.MetadataFailurePolicy {
+		case metadataFailurePolicyDrop:
+			m.logger.Warnf("could not add metadata to events, dropping %d events: %s", len(events), err)
+			return enrichedEvents
+		case metadataFailurePolicyRetry:
+			m.logger.Warnf("could not add metadata to events, retrying on the next fetch: %s", err)
+			heldBack[key] = append(heldBack[key], events)
+			return enrichedEvents
+		default:
+			m.logger.Warnf("could not add metadata to events: %s", err)
+			if eventsWithMetadata == nil {
+				eventsWithMetadata = events
+			}
+		}
+	}
+
+	for _, event := range eventsWithMetadata {
+		enrichedEvents = append(enrichedEvents, event)
+	}
+	return enrichedEvents
+}
+
+type fetchDiscoveryKey struct {
+	operation  string
+	regionName string
+	input      string
+}
+
+type fetchListing struct {
+	resources interface{}
+	err       error
+}
+
+// fetchDiscovery keeps the resources listed through discovery for the
+// duration of a collection, including the failed listings, so the enrichers
+// of every batch of a backfill share them. It is not safe for concurrent use.
+type fetchDiscovery struct {
+	discovery metadata.Discovery
+	listings  map[fetchDiscoveryKey]fetchListing
+}
+
+func newFetchDiscovery(discovery metadata.Discovery) *fetchDiscovery {
+	return &fetchDiscovery{
+		discovery: discovery,
+		listings:  map[fetchDiscoveryKey]fetchListing{},
+	}
+}
+
+// Get implements metadata.Discovery.
+func (d *fetchDiscovery) Get(ctx context.Context, operation string, regionName string, input string, list func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	key := fetchDiscoveryKey{operation: operation, regionName: regionName, input: input}
+	if listing, ok := d.listings[key]; ok {
+		return listing.resources, listing.err
+	}
+	resources, err := metadata.Discover(ctx, d.discovery, operation, regionName, input, list)
+	d.listings[key] = fetchListing{resources: resources, err: err}
+	return resources, err
+}
+
+func (m *MetricSet) countMetadataFailure() {
+	if m.metadataFailures != nil {
+		m.metadataFailures.Inc()
+	}
+}
+
 // createAwsRequiredClients will return the two necessary client instances to do Metric requests to the AWS API
-func (m *MetricSet) createAwsRequiredClients(beatsConfig awssdk.Config, regionName string, config aws.Config) (*cloudwatch.Client, *resourcegroupstaggingapi.Client, error) {
+func (m *MetricSet) createAwsRequiredClients(beatsConfig awssdk.Config, regionName string) (*cloudwatch.Client, *resourcegroupstaggingapi.Client, error) {
 	m.logger.Debugf("Collecting metrics from AWS region %s", regionName)
 
-	svcCloudwatchClient := cloudwatch.NewFromConfig(beatsConfig, func(o *cloudwatch.Options) {
-		if config.AWSConfig.FIPSEnabled {
-			o.EndpointOptions.UseFIPSEndpoint = awssdk.FIPSEndpointStateEnabled
+	svcCloudwatchClient := cloudwatch.NewFromConfig(beatsConfig)
+
+	svcResourceAPIClient := resourcegroupstaggingapi.NewFromConfig(beatsConfig)
+
+	return svcCloudwatchClient, svcResourceAPIClient, nil
+}
+
+// createConfigAggregatorClient returns an AWS Config client for the region of the
+// configured aggregator, or nil when no metrics config uses aws_config as tag source.
+func (m *MetricSet) createConfigAggregatorClient() aws.ConfigAggregatorClient {
+	usesConfigAggregator := false
+	for _, tagSource := range m.tagSources {
+		if tagSource == tagSourceAWSConfig {
+			usesConfigAggregator = true
+			break
 		}
+	}
+	if !usesConfigAggregator {
+		return nil
+	}
 
-	})
+	beatsConfig := m.MetricSet.AwsConfig.Copy()
+	if m.config.ConfigAggregator.Region != "" {
+		beatsConfig.Region = m.config.ConfigAggregator.Region
+	}
+	return configservice.NewFromConfig(beatsConfig)
+}
+
+// createAccountAliasResolver returns a resolver for the aliases of the collected accounts.
+func (m *MetricSet) createAccountAliasResolver() *aws.AccountAliasResolver {
+	beatsConfig := m.MetricSet.AwsConfig.Copy()
+	svcIam := iam.NewFromConfig(beatsConfig)
+	svcOrganizations := organizations.NewFromConfig(beatsConfig)
+	return aws.NewAccountAliasResolver(svcIam, svcOrganizations, m.AccountID)
+}
+
+// addAccountAlias adds aws.account.alias to the events, with the alias of the
+// account in cloud.account.id.
+func (m *MetricSet) addAccountAlias(ctx context.Context, events map[string]mb.Event) {
+	if m.accountAliasResolver == nil {
+		return
+	}
 
-	svcResourceAPIClient := resourcegroupstaggingapi.NewFromConfig(beatsConfig, func(o *resourcegroupstaggingapi.Options) {
-		if config.AWSConfig.FIPSEnabled {
-			o.EndpointOptions.UseFIPSEndpoint = awssdk.FIPSEndpointStateEnabled
+	for _, event := range events {
+		accountID, err := event.RootFields.GetValue("cloud.account.id")
+		if err != nil {
+			continue
 		}
-	})
 
-	return svcCloudwatchClient, svcResourceAPIClient, nil
+		alias, err := m.accountAliasResolver.Resolve(ctx, fmt.Sprint(accountID))
+		if err != nil {
+			m.logger.Warnf("could not resolve account alias: %s", err)
+		}
+		if alias != "" {
+			_, _ = event.RootFields.Put("aws.account.alias", alias)
+		}
+	}
+}
+
+// aliasDimensions moves the dimensions configured in dimension_aliases from
+// aws.dimensions to their alias field,
+// example DBInstanceIdentifier: aws.rds.db_instance.identifier
+// aws.dimensions.DBInstanceIdentifier -> aws.rds.db_instance.identifier
+func (m *MetricSet) aliasDimensions(event mb.Event) {
+	for dimensionName, alias := range m.config.DimensionAliases {
+		field := "aws.dimensions." + dimensionName
+		value, err := event.RootFields.GetValue(field)
+		if err != nil {
+			continue
+		}
+		_ = event.RootFields.Delete(field)
+		_, _ = event.RootFields.Put(alias, value)
+	}
+
+	// remove aws.dimensions when all dimensions were moved
+	if dimensions, err := event.RootFields.GetValue("aws.dimensions"); err == nil {
+		if dimensionsMap, ok := dimensions.(mapstr.M); ok && len(dimensionsMap) == 0 {
+			_ = event.RootFields.Delete("aws.dimensions")
+		}
+	}
+}
+
+// getResourcesTags returns the resource tag mapping of a resource type from the
+// tag source configured for it.
+func (m *MetricSet) getResourcesTags(ctx context.Context, svcResourceAPI resourcegroupstaggingapi.GetResourcesAPIClient, svcConfigAPI aws.ConfigAggregatorClient, resourceType string, regionName string) (map[string][]resourcegroupstaggingapitypes.Tag, error) {
+	if m.tagSources[resourceType] == tagSourceAWSConfig {
+		if svcConfigAPI == nil {
+			return nil, fmt.Errorf("no AWS Config aggregator client available for resource type %s", resourceType)
+		}
+		return m.MetricSet.Tags.Get(tagSourceAWSConfig, regionName, resourceType, func() (map[string][]resourcegroupstaggingapitypes.Tag, error) {
+			return aws.GetResourcesTagsFromConfigAggregator(ctx, svcConfigAPI, m.config.ConfigAggregator.Name, resourceType, m.AccountID, regionName)
+		})
+	}
+	return m.MetricSet.Tags.GetResourcesTags(ctx, svcResourceAPI, regionName, resourceType)
+}
+
+// isNamespacePattern checks if the configured namespace is a pattern such as
+// `CWAgent*` or `MyCompany/*` instead of a single namespace. A single wildcard
+// keeps collecting metrics from all namespaces without discovery.
+func isNamespacePattern(namespace string) bool {
+	return namespace != namespaceWildcard && strings.ContainsAny(namespace, "*?")
+}
+
+// compileNamespacePattern converts a namespace pattern into a regular expression
+// where `*` matches any sequence of characters, including `/`, and `?` matches
+// a single character.
+func compileNamespacePattern(pattern string) (*regexp.Regexp, error) {
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, `\*`, ".*")
+	expr = strings.ReplaceAll(expr, `\?`, ".")
+	return regexp.Compile("^" + expr + "$")
+}
+
+// discoverNamespaces replaces namespace patterns in namespaceDetailTotal with
+// the namespaces present in the region that match them. ListMetrics is called
+// once without a namespace and its results are grouped by namespace, so they
+// can be reused instead of listing each discovered namespace again.
+func (m *MetricSet) discoverNamespaces(ctx context.Context, svcCloudwatch cloudwatch.ListMetricsAPIClient, regionName string, period time.Duration, namespaceDetailTotal map[string][]namespaceDetail) (map[string][]namespaceDetail, map[string][]types.Metric) {
+	namespaceDetailRegion := map[string][]namespaceDetail{}
+	patterns := map[string]*regexp.Regexp{}
+	for namespace, namespaceDetails := range namespaceDetailTotal {
+		if !isNamespacePattern(namespace) {
+			namespaceDetailRegion[namespace] = append(namespaceDetailRegion[namespace], namespaceDetails...)
+			continue
+		}
+
+		pattern, err := compileNamespacePattern(namespace)
+		if err != nil {
+			m.logger.Warnf("invalid namespace pattern %s: %s", namespace, err)
+			continue
+		}
+		patterns[namespace] = pattern
+	}
+
+	discoveredListMetrics := map[string][]types.Metric{}
+	if len(patterns) == 0 {
+		return namespaceDetailRegion, discoveredListMetrics
+	}
+
+	listMetricsOutput, err := m.MetricSet.Discovery.ListMetrics(ctx, svcCloudwatch, namespaceWildcard, regionName, period)
+	if err != nil {
+		m.logger.Info(err.Error())
+		return namespaceDetailRegion, discoveredListMetrics
+	}
+
+	listMetricsPerNamespace := map[string][]types.Metric{}
+	for _, listMetric := range listMetricsOutput {
+		if listMetric.Namespace == nil {
+			continue
+		}
+		listMetricsPerNamespace[*listMetric.Namespace] = append(listMetricsPerNamespace[*listMetric.Namespace], listMetric)
+	}
+
+	for namespace, listMetrics := range listMetricsPerNamespace {
+		for patternString, pattern := range patterns {
+			if !pattern.MatchString(namespace) {
+				continue
+			}
+			m.logger.Debugf("Discovered namespace %s matching pattern %s in region %s", namespace, patternString, regionName)
+			namespaceDetailRegion[namespace] = append(namespaceDetailRegion[namespace], namespaceDetailTotal[patternString]...)
+			discoveredListMetrics[namespace] = listMetrics
+		}
+	}
+	return namespaceDetailRegion, discoveredListMetrics
 }
 
 // filterListMetricsOutput compares config details with listMetricsOutput and filter out the ones don't match
@@ -292,6 +1039,48 @@ func filterListMetricsOutput(listMetricsOutput []types.Metric, namespaceDetails
 	return filteredMetricWithStatsTotal
 }
 
+// limitMetrics truncates the metrics collected from a namespace to
+// max_metrics_per_namespace, so a namespace with a runaway number of metrics
+// cannot blow up API cost or memory. Metrics are sorted by name and dimensions
+// first, so the same metrics are kept on every fetch. An event with the number
+// of dropped and kept metrics is reported when metrics are dropped.
+func (m *MetricSet) limitMetrics(report mb.ReporterV2, namespace string, regionName string, metricsWithStats []metricsWithStatistics, now time.Time) []metricsWithStatistics {
+	if m.config.MaxMetricsPerNamespace <= 0 || len(metricsWithStats) <= m.config.MaxMetricsPerNamespace {
+		return metricsWithStats
+	}
+
+	sort.SliceStable(metricsWithStats, func(i, j int) bool {
+		return metricSortKey(metricsWithStats[i].cloudwatchMetric) < metricSortKey(metricsWithStats[j].cloudwatchMetric)
+	})
+
+	m.logger.Warnf("Namespace %s in region %s has %d metrics matching the configuration, only the first %d are collected, see max_metrics_per_namespace",
+		namespace, regionName, len(metricsWithStats), m.config.MaxMetricsPerNamespace)
+
+	event := aws.InitEvent(regionName, m.AccountName, m.AccountID, now)
+	_, _ = event.RootFields.Put("aws.cloudwatch.namespace", namespace)
+	_, _ = event.RootFields.Put("aws.cloudwatch.truncated.dropped_metrics", len(metricsWithStats)-m.config.MaxMetricsPerNamespace)
+	_, _ = event.RootFields.Put("aws.cloudwatch.truncated.kept_metrics", m.config.MaxMetricsPerNamespace)
+	report.Event(event)
+
+	return metricsWithStats[:m.config.MaxMetricsPerNamespace]
+}
+
+// metricSortKey returns the metric name followed by its sorted dimension names and values.
+func metricSortKey(metric types.Metric) string {
+	dimensions := make([]string, 0, len(metric.Dimensions))
+	for _, dim := range metric.Dimensions {
+		dimensions = append(dimensions, awssdk.ToString(dim.Name)+"="+awssdk.ToString(dim.Value))
+	}
+	sort.Strings(dimensions)
+	return awssdk.ToString(metric.MetricName) + labelSeparator + strings.Join(dimensions, dimensionSeparator)
+}
+
+// hasTagsFilter returns true if the resources are filtered by tags, with the
+// tags_filter of a resource type or with tags_filter_expression.
+func (m *MetricSet) hasTagsFilter(tagsFilter []aws.Tag) bool {
+	return len(tagsFilter) != 0 || m.MetricSet.TagsExpression != nil
+}
+
 // Collect resource type filters and tag filters from config for cloudwatch
 func constructTagsFilters(namespaceDetails []namespaceDetail) map[string][]aws.Tag {
 	resourceTypeTagFilters := map[string][]aws.Tag{}
@@ -308,6 +1097,11 @@ func constructTagsFilters(namespaceDetails []namespaceDetail) map[string][]aws.T
 }
 
 func (m *MetricSet) checkStatistics() error {
+	for _, stat := range m.config.DefaultStatistics {
+		if _, ok := statisticLookup(stat); !ok {
+			return fmt.Errorf("statistic method specified in default_statistics is not valid: %s", stat)
+		}
+	}
 	for _, config := range m.CloudwatchConfigs {
 		for _, stat := range config.Statistic {
 			if _, ok := statisticLookup(stat); !ok {
@@ -318,31 +1112,51 @@ func (m *MetricSet) checkStatistics() error {
 	return nil
 }
 
-func (m *MetricSet) readCloudwatchConfig() (listMetricWithDetail, map[string][]namespaceDetail) {
+// isExplicitMetricsConfig checks if a metrics config fully specifies its metrics
+// with names and dimensions without wildcard, so they are queried without ListMetrics.
+func isExplicitMetricsConfig(config Config) bool {
+	return config.MetricName != nil && config.Dimensions != nil &&
+		!configDimensionValueContainsWildcard(config.Dimensions)
+}
+
+func toCloudwatchDimensions(dimensions []Dimension) []types.Dimension {
+	var cloudwatchDimensions []types.Dimension
+	for _, dim := range dimensions {
+		name := dim.Name
+		value := dim.Value
+		cloudwatchDimensions = append(cloudwatchDimensions, types.Dimension{
+			Name:  &name,
+			Value: &value,
+		})
+	}
+	return cloudwatchDimensions
+}
+
+// defaultStatistics returns the statistics of the metrics configs without
+// statistic, from default_statistics or all the statistics if it is not set.
+func (m *MetricSet) defaultStatistics() []string {
+	if len(m.config.DefaultStatistics) > 0 {
+		return m.config.DefaultStatistics
+	}
+	return defaultStatistics
+}
+
+func (m *MetricSet) readCloudwatchConfig(cloudwatchConfigs []Config) (listMetricWithDetail, map[string][]namespaceDetail) {
 	var listMetricDetailTotal listMetricWithDetail
 	namespaceDetailTotal := map[string][]namespaceDetail{}
 	var metricsWithStatsTotal []metricsWithStatistics
 	resourceTypesWithTags := map[string][]aws.Tag{}
 
-	for _, config := range m.CloudwatchConfigs {
+	for _, config := range cloudwatchConfigs {
 		// If there is no statistic method specified, then use the default.
 		if config.Statistic == nil {
-			config.Statistic = defaultStatistics
+			config.Statistic = m.defaultStatistics()
 		}
 
-		var cloudwatchDimensions []types.Dimension
-		for _, dim := range config.Dimensions {
-			name := dim.Name
-			value := dim.Value
-			cloudwatchDimensions = append(cloudwatchDimensions, types.Dimension{
-				Name:  &name,
-				Value: &value,
-			})
-		}
+		cloudwatchDimensions := toCloudwatchDimensions(config.Dimensions)
 		// if any Dimension value contains wildcard, then compare dimensions with
 		// listMetrics result in filterListMetricsOutput
-		if config.MetricName != nil && config.Dimensions != nil &&
-			!configDimensionValueContainsWildcard(config.Dimensions) {
+		if isExplicitMetricsConfig(config) {
 			namespace := config.Namespace
 			for i := range config.MetricName {
 				metricsWithStats := metricsWithStatistics{
@@ -404,12 +1218,13 @@ func createMetricDataQueries(listMetricsTotal []metricsWithStatistics, period ti
 
 func constructLabel(metric types.Metric, statistic string) string {
 	// label = metricName + namespace + statistic + dimKeys + dimValues
-	label := *metric.MetricName + labelSeparator + *metric.Namespace + labelSeparator + statistic
+	// Separators in the parts of the label are escaped, see parseLabel.
+	label := escapeLabelPart(*metric.MetricName) + labelSeparator + escapeLabelPart(*metric.Namespace) + labelSeparator + statistic
 	dimNames := ""
 	dimValues := ""
 	for i, dim := range metric.Dimensions {
-		dimNames += *dim.Name
-		dimValues += *dim.Value
+		dimNames += escapeLabelPart(*dim.Name)
+		dimValues += escapeLabelPart(*dim.Value)
 		if i != len(metric.Dimensions)-1 {
 			dimNames += dimensionSeparator
 			dimValues += dimensionSeparator
@@ -423,6 +1238,79 @@ func constructLabel(metric types.Metric, statistic string) string {
 	return label
 }
 
+// escapeLabelPart escapes the label and dimension separators, and the escape
+// character itself, in a part of a label.
+// example a,b|c\d -> a\,b\|c\\d
+func escapeLabelPart(part string) string {
+	if !strings.ContainsAny(part, labelSeparator+dimensionSeparator+labelEscape) {
+		return part
+	}
+
+	var escaped strings.Builder
+	for i := 0; i < len(part); i++ {
+		switch part[i] {
+		case labelSeparator[0], dimensionSeparator[0], labelEscape[0]:
+			escaped.WriteByte(labelEscape[0])
+		}
+		escaped.WriteByte(part[i])
+	}
+	return escaped.String()
+}
+
+// unescapeLabelPart reverts escapeLabelPart.
+func unescapeLabelPart(part string) string {
+	if !strings.Contains(part, labelEscape) {
+		return part
+	}
+
+	var unescaped strings.Builder
+	for i := 0; i < len(part); i++ {
+		if part[i] == labelEscape[0] && i+1 < len(part) {
+			i++
+		}
+		unescaped.WriteByte(part[i])
+	}
+	return unescaped.String()
+}
+
+// splitEscaped splits s on the separator where it is not escaped. The parts
+// are not unescaped.
+func splitEscaped(s string, separator string) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case labelEscape[0]:
+			i++
+		case separator[0]:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// parseLabel splits a label created by constructLabel into its parts. Metric
+// name, namespace and statistic are unescaped. Dimension names and values are
+// kept escaped, they are the identifier of the events and are split with
+// splitDimensions.
+func parseLabel(label string) []string {
+	labels := splitEscaped(label, labelSeparator)
+	for i := metricNameIdx; i <= statisticIdx && i < len(labels); i++ {
+		labels[i] = unescapeLabelPart(labels[i])
+	}
+	return labels
+}
+
+// splitDimensions splits the escaped dimension names or values of a label.
+func splitDimensions(dimensions string) []string {
+	parts := splitEscaped(dimensions, dimensionSeparator)
+	for i := range parts {
+		parts[i] = unescapeLabelPart(parts[i])
+	}
+	return parts
+}
+
 func statisticLookup(stat string) (string, bool) {
 	statisticLookupTable := map[string]string{
 		"Average":     "avg",
@@ -448,6 +1336,15 @@ func generateFieldName(namespace string, labels []string) string {
 	return "aws." + stripNamespace(namespace) + ".metrics." + common.DeDot(labels[metricNameIdx]) + "." + statMethod
 }
 
+// generateGenericFieldName returns the field name used when generic_metric_fields
+// is enabled. Metrics from all namespaces are stored under aws.cloudwatch.metrics,
+// so a single index template covers arbitrary custom namespaces.
+// example CPUUtilization Average -> aws.cloudwatch.metrics.CPUUtilization.avg
+func generateGenericFieldName(labels []string) string {
+	statMethod, _ := statisticLookup(labels[statisticIdx])
+	return "aws." + metricsetName + ".metrics." + common.DeDot(labels[metricNameIdx]) + "." + statMethod
+}
+
 // stripNamespace converts Cloudwatch namespace into the root field we will use for metrics
 // example AWS/EC2 -> ec2
 func stripNamespace(namespace string) string {
@@ -455,45 +1352,132 @@ func stripNamespace(namespace string) string {
 	return strings.ToLower(parts[len(parts)-1])
 }
 
-func insertRootFields(event mb.Event, metricValue float64, labels []string) mb.Event {
+func (m *MetricSet) insertRootFields(event mb.Event, metricValue float64, labels []string) mb.Event {
 	namespace := labels[namespaceIdx]
-	_, _ = event.RootFields.Put(generateFieldName(namespace, labels), metricValue)
+	fieldName := generateFieldName(namespace, labels)
+	if m.config.GenericMetricFields {
+		fieldName = generateGenericFieldName(labels)
+	}
+	_, _ = event.RootFields.Put(fieldName, metricValue)
 	_, _ = event.RootFields.Put("aws.cloudwatch.namespace", namespace)
 	if len(labels) == 3 {
 		return event
 	}
 
-	dimNames := strings.Split(labels[identifierNameIdx], ",")
-	dimValues := strings.Split(labels[identifierValueIdx], ",")
-	for i := 0; i < len(dimNames); i++ {
+	dimNames := splitDimensions(labels[identifierNameIdx])
+	dimValues := splitDimensions(labels[identifierValueIdx])
+	for i := 0; i < len(dimNames) && i < len(dimValues); i++ {
 		_, _ = event.RootFields.Put("aws.dimensions."+dimNames[i], dimValues[i])
 	}
 	return event
 }
 
-func (m *MetricSet) createEvents(svcCloudwatch cloudwatch.GetMetricDataAPIClient, svcResourceAPI resourcegroupstaggingapi.GetResourcesAPIClient, listMetricWithStatsTotal []metricsWithStatistics, resourceTypeTagFilters map[string][]aws.Tag, regionName string, startTime time.Time, endTime time.Time) (map[string]mb.Event, error) {
-	// Initialize events for each identifier.
-	events := map[string]mb.Event{}
+func (m *MetricSet) createEvents(ctx context.Context, svcCloudwatch cloudwatch.GetMetricDataAPIClient, svcResourceAPI resourcegroupstaggingapi.GetResourcesAPIClient, svcConfigAPI aws.ConfigAggregatorClient, listMetricWithStatsTotal []metricsWithStatistics, resourceTypeTagFilters map[string][]aws.Tag, regionName string, period time.Duration, startTime time.Time, endTime time.Time) (map[string]mb.Event, error) {
+	metricDataResults, err := m.getMetricDataResults(ctx, svcCloudwatch, listMetricWithStatsTotal, period, startTime, endTime)
+	if err != nil || len(metricDataResults) == 0 {
+		return map[string]mb.Event{}, err
+	}
+
+	// Find a timestamp for all metrics in output
+	timestamp := m.findTimestamp(metricDataResults)
+	if timestamp.IsZero() {
+		return nil, nil
+	}
+
+	var resourceTagMaps map[string]map[string][]resourcegroupstaggingapitypes.Tag
+	if len(resourceTypeTagFilters) > 0 {
+		resourceTagMaps = m.getResourcesTagsPerResourceType(ctx, svcResourceAPI, svcConfigAPI, resourceTypeTagFilters, regionName)
+	}
+	return m.createEventsAtTimestamp(metricDataResults, resourceTypeTagFilters, resourceTagMaps, regionName, timestamp, func(timestamps []time.Time) (bool, int) {
+		return m.findTimestampIdx(timestamp, timestamps)
+	}), nil
+}
+
+// createEventBatches creates the events of the metrics between startTime and
+// endTime. A regular collection creates a single batch with the events of the
+// timestamp chosen by timestamp_strategy, a backfill creates a batch per
+// timestamp of the metric data, oldest first, from the same GetMetricData requests.
+func (m *MetricSet) createEventBatches(ctx context.Context, svcCloudwatch cloudwatch.GetMetricDataAPIClient, svcResourceAPI resourcegroupstaggingapi.GetResourcesAPIClient, svcConfigAPI aws.ConfigAggregatorClient, listMetricWithStatsTotal []metricsWithStatistics, resourceTypeTagFilters map[string][]aws.Tag, regionName string, period time.Duration, startTime time.Time, endTime time.Time, backfill bool) ([]eventBatch, error) {
+	if !backfill {
+		events, err := m.createEvents(ctx, svcCloudwatch, svcResourceAPI, svcConfigAPI, listMetricWithStatsTotal, resourceTypeTagFilters, regionName, period, startTime, endTime)
+		if err != nil {
+			return nil, err
+		}
+		return []eventBatch{{events: events}}, nil
+	}
+
+	metricDataResults, err := m.getMetricDataResults(ctx, svcCloudwatch, listMetricWithStatsTotal, period, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+	timestamps := metricDataTimestamps(metricDataResults)
+	if len(timestamps) == 0 {
+		return nil, nil
+	}
+
+	var resourceTagMaps map[string]map[string][]resourcegroupstaggingapitypes.Tag
+	if len(resourceTypeTagFilters) > 0 {
+		resourceTagMaps = m.getResourcesTagsPerResourceType(ctx, svcResourceAPI, svcConfigAPI, resourceTypeTagFilters, regionName)
+	}
+	batches := make([]eventBatch, 0, len(timestamps))
+	for _, timestamp := range timestamps {
+		timestamp := timestamp
+		events := m.createEventsAtTimestamp(metricDataResults, resourceTypeTagFilters, resourceTagMaps, regionName, timestamp, func(timestamps []time.Time) (bool, int) {
+			return aws.CheckTimestampInArray(timestamp, timestamps)
+		})
+		batches = append(batches, eventBatch{timestamp: timestamp, events: events})
+	}
+	return batches, nil
+}
 
+// getMetricDataResults queries the data points of the metrics between startTime and endTime.
+func (m *MetricSet) getMetricDataResults(ctx context.Context, svcCloudwatch cloudwatch.GetMetricDataAPIClient, listMetricWithStatsTotal []metricsWithStatistics, period time.Duration, startTime time.Time, endTime time.Time) ([]types.MetricDataResult, error) {
 	// Construct metricDataQueries
-	metricDataQueries := createMetricDataQueries(listMetricWithStatsTotal, m.Period)
+	metricDataQueries := createMetricDataQueries(listMetricWithStatsTotal, period)
 	m.logger.Debugf("Number of MetricDataQueries = %d", len(metricDataQueries))
 	if len(metricDataQueries) == 0 {
-		return events, nil
+		return nil, nil
 	}
 
 	// Use metricDataQueries to make GetMetricData API calls
-	metricDataResults, err := aws.GetMetricDataResults(metricDataQueries, svcCloudwatch, startTime, endTime)
+	options := aws.GetMetricDataOptions{
+		MaxDatapoints:     m.config.MaxDatapoints,
+		QueriesPerRequest: m.config.QueriesPerRequest,
+	}
+	if m.config.LabelTimezone != "" {
+		options.LabelOptions = &types.LabelOptions{Timezone: awssdk.String(m.config.LabelTimezone)}
+	}
+	metricDataResults, err := aws.GetMetricDataResultsWithOptions(ctx, metricDataQueries, svcCloudwatch, startTime, endTime, options)
 	m.logger.Debugf("Number of metricDataResults = %d", len(metricDataResults))
 	if err != nil {
-		return events, fmt.Errorf("getMetricDataResults failed: %w", err)
+		return nil, fmt.Errorf("getMetricDataResults failed: %w", err)
 	}
+	return metricDataResults, nil
+}
 
-	// Find a timestamp for all metrics in output
-	timestamp := aws.FindTimestamp(metricDataResults)
-	if timestamp.IsZero() {
-		return nil, nil
+// metricDataTimestamps returns the distinct timestamps of the metric data results, oldest first.
+func metricDataTimestamps(metricDataResults []types.MetricDataResult) []time.Time {
+	seen := map[time.Time]struct{}{}
+	var timestamps []time.Time
+	for _, result := range metricDataResults {
+		for _, timestamp := range result.Timestamps {
+			if _, ok := seen[timestamp]; ok {
+				continue
+			}
+			seen[timestamp] = struct{}{}
+			timestamps = append(timestamps, timestamp)
+		}
 	}
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i].Before(timestamps[j]) })
+	return timestamps
+}
+
+// createEventsAtTimestamp creates the events of the metric data results at the
+// given timestamp, findTimestampIdx returns the position of the data point to
+// collect from the timestamps of a result.
+func (m *MetricSet) createEventsAtTimestamp(metricDataResults []types.MetricDataResult, resourceTypeTagFilters map[string][]aws.Tag, resourceTagMaps map[string]map[string][]resourcegroupstaggingapitypes.Tag, regionName string, timestamp time.Time, findTimestampIdx func(timestamps []time.Time) (bool, int)) map[string]mb.Event {
+	// Initialize events for each identifier.
+	events := map[string]mb.Event{}
 
 	// Create events when there is no tags_filter or resource_type specified.
 	if len(resourceTypeTagFilters) == 0 {
@@ -502,16 +1486,16 @@ func (m *MetricSet) createEvents(svcCloudwatch cloudwatch.GetMetricDataAPIClient
 				continue
 			}
 
-			exists, timestampIdx := aws.CheckTimestampInArray(timestamp, metricDataResult.Timestamps)
+			exists, timestampIdx := findTimestampIdx(metricDataResult.Timestamps)
 			if exists {
-				labels := strings.Split(*metricDataResult.Label, labelSeparator)
+				labels := parseLabel(*metricDataResult.Label)
 				if len(labels) != 5 {
 					// when there is no identifier value in label, use region+accountID+namespace instead
 					identifier := regionName + m.AccountID + labels[namespaceIdx]
 					if _, ok := events[identifier]; !ok {
 						events[identifier] = aws.InitEvent(regionName, m.AccountName, m.AccountID, timestamp)
 					}
-					events[identifier] = insertRootFields(events[identifier], metricDataResult.Values[timestampIdx], labels)
+					events[identifier] = m.insertRootFields(events[identifier], metricDataResult.Values[timestampIdx], labels)
 					continue
 				}
 
@@ -519,47 +1503,39 @@ func (m *MetricSet) createEvents(svcCloudwatch cloudwatch.GetMetricDataAPIClient
 				if _, ok := events[identifierValue]; !ok {
 					events[identifierValue] = aws.InitEvent(regionName, m.AccountName, m.AccountID, timestamp)
 				}
-				events[identifierValue] = insertRootFields(events[identifierValue], metricDataResult.Values[timestampIdx], labels)
+				events[identifierValue] = m.insertRootFields(events[identifierValue], metricDataResult.Values[timestampIdx], labels)
 			}
 		}
-		return events, nil
+		insertARNFields(events)
+		return events
 	}
 
 	// Create events with tags
 	for resourceType, tagsFilter := range resourceTypeTagFilters {
 		m.logger.Debugf("resourceType = %s", resourceType)
 		m.logger.Debugf("tagsFilter = %s", tagsFilter)
-		resourceTagMap, err := aws.GetResourcesTags(svcResourceAPI, []string{resourceType})
-		if err != nil {
-			// If GetResourcesTags failed, continue report event just without tags.
-			m.logger.Info(fmt.Errorf("getResourcesTags failed, skipping region %s: %w", regionName, err))
-		}
+		resourceTagMap := resourceTagMaps[resourceType]
+		filterByTags := m.hasTagsFilter(tagsFilter)
 
-		if len(tagsFilter) != 0 && len(resourceTagMap) == 0 {
+		if filterByTags && len(resourceTagMap) == 0 {
 			continue
 		}
 
 		// filter resourceTagMap
-		for identifier, tags := range resourceTagMap {
-			if exists := aws.CheckTagFiltersExist(tagsFilter, tags); !exists {
-				m.logger.Debugf("In region %s, service %s tags does not match tags_filter", regionName, identifier)
-				delete(resourceTagMap, identifier)
-				continue
-			}
-			m.logger.Debugf("In region %s, service %s tags match tags_filter", regionName, identifier)
-		}
+		resourceTagMap = m.MetricSet.TagsExpression.FilterResources(aws.FilterResourcesByTags(resourceTagMap, tagsFilter))
+		m.logger.Debugf("In region %s, %d resources of type %s match tags_filter", regionName, len(resourceTagMap), resourceType)
 
 		for _, output := range metricDataResults {
 			if len(output.Values) == 0 {
 				continue
 			}
 
-			exists, timestampIdx := aws.CheckTimestampInArray(timestamp, output.Timestamps)
+			exists, timestampIdx := findTimestampIdx(output.Timestamps)
 			if exists {
-				labels := strings.Split(*output.Label, labelSeparator)
+				labels := parseLabel(*output.Label)
 				if len(labels) != 5 {
 					// if there is no tag in labels but there is a tagsFilter, then no event should be reported.
-					if len(tagsFilter) != 0 {
+					if filterByTags {
 						continue
 					}
 
@@ -568,7 +1544,7 @@ func (m *MetricSet) createEvents(svcCloudwatch cloudwatch.GetMetricDataAPIClient
 					if _, ok := events[identifier]; !ok {
 						events[identifier] = aws.InitEvent(regionName, m.AccountName, m.AccountID, timestamp)
 					}
-					events[identifier] = insertRootFields(events[identifier], output.Values[timestampIdx], labels)
+					events[identifier] = m.insertRootFields(events[identifier], output.Values[timestampIdx], labels)
 					continue
 				}
 
@@ -577,19 +1553,120 @@ func (m *MetricSet) createEvents(svcCloudwatch cloudwatch.GetMetricDataAPIClient
 					// when tagsFilter is not empty but no entry in
 					// resourceTagMap for this identifier, do not initialize
 					// an event for this identifier.
-					if len(tagsFilter) != 0 && resourceTagMap[identifierValue] == nil {
+					if filterByTags && resourceTagMap[identifierValue] == nil {
 						continue
 					}
 					events[identifierValue] = aws.InitEvent(regionName, m.AccountName, m.AccountID, timestamp)
 				}
-				events[identifierValue] = insertRootFields(events[identifierValue], output.Values[timestampIdx], labels)
+				events[identifierValue] = m.insertRootFields(events[identifierValue], output.Values[timestampIdx], labels)
 
 				// add tags to event based on identifierValue
 				insertTags(events, identifierValue, resourceTagMap)
 			}
 		}
 	}
-	return events, nil
+	insertARNFields(events)
+	return events
+}
+
+// getResourcesTagsPerResourceType fetches the resource tag mappings of all the
+// resource types concurrently, with at most maxConcurrentTagRequests requests
+// in flight.
+func (m *MetricSet) getResourcesTagsPerResourceType(ctx context.Context, svcResourceAPI resourcegroupstaggingapi.GetResourcesAPIClient, svcConfigAPI aws.ConfigAggregatorClient, resourceTypeTagFilters map[string][]aws.Tag, regionName string) map[string]map[string][]resourcegroupstaggingapitypes.Tag {
+	resourceTagMaps := make(map[string]map[string][]resourcegroupstaggingapitypes.Tag, len(resourceTypeTagFilters))
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, maxConcurrentTagRequests)
+	for resourceType := range resourceTypeTagFilters {
+		wg.Add(1)
+		go func(resourceType string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			resourceTagMap, err := m.getResourcesTags(ctx, svcResourceAPI, svcConfigAPI, resourceType, regionName)
+			if err != nil {
+				// If GetResourcesTags failed, continue report event just without tags.
+				m.logger.Info(fmt.Errorf("getResourcesTags failed, skipping region %s: %w", regionName, err))
+			}
+
+			mutex.Lock()
+			resourceTagMaps[resourceType] = resourceTagMap
+			mutex.Unlock()
+		}(resourceType)
+	}
+	wg.Wait()
+	return resourceTagMaps
+}
+
+// findTimestamp returns the timestamp of the events created from the metric data
+// results, based on the configured timestamp_strategy.
+func (m *MetricSet) findTimestamp(metricDataResults []types.MetricDataResult) time.Time {
+	switch m.config.TimestampStrategy {
+	case timestampStrategyLatestComplete:
+		return aws.FindLatestCompleteTimestamp(metricDataResults)
+	case timestampStrategyLatest, timestampStrategyPerMetric:
+		return aws.FindLatestTimestamp(metricDataResults)
+	default:
+		return aws.FindTimestamp(metricDataResults)
+	}
+}
+
+// findTimestampIdx returns the position of the data point to collect from the
+// timestamps of a metric data result. With the per-metric strategy each metric
+// uses its own latest data point, otherwise the data point at the shared
+// timestamp is used.
+func (m *MetricSet) findTimestampIdx(timestamp time.Time, timestamps []time.Time) (bool, int) {
+	if m.config.TimestampStrategy != timestampStrategyPerMetric {
+		return aws.CheckTimestampInArray(timestamp, timestamps)
+	}
+
+	latestIdx := -1
+	for i := range timestamps {
+		if latestIdx == -1 || timestamps[i].After(timestamps[latestIdx]) {
+			latestIdx = i
+		}
+	}
+	return latestIdx != -1, latestIdx
+}
+
+// filterEvents applies the configured event filters to the events. A filter only
+// applies to events from its namespace, if given, that have a value for its
+// dimension. Events are dropped when the dimension value doesn't match a keep
+// filter or matches a drop filter.
+func (m *MetricSet) filterEvents(events map[string]mb.Event) map[string]mb.Event {
+	if len(m.config.EventFilters) == 0 {
+		return events
+	}
+
+	for identifier, event := range events {
+		for _, filter := range m.config.EventFilters {
+			if filter.Namespace != "" {
+				namespace, err := event.RootFields.GetValue("aws.cloudwatch.namespace")
+				if err != nil || namespace != filter.Namespace {
+					continue
+				}
+			}
+
+			dimensionValue, err := event.RootFields.GetValue("aws.dimensions." + filter.Dimension)
+			if err != nil {
+				continue
+			}
+			value, ok := dimensionValue.(string)
+			if !ok {
+				continue
+			}
+
+			matched := filter.Pattern.MatchString(value)
+			if (filter.Action == eventFilterActionDrop && matched) || (filter.Action != eventFilterActionDrop && !matched) {
+				m.logger.Debugf("Event %s dropped by event filter on dimension %s", identifier, filter.Dimension)
+				delete(events, identifier)
+				break
+			}
+		}
+	}
+	return events
 }
 
 func configDimensionValueContainsWildcard(dim []Dimension) bool {
@@ -630,23 +1707,329 @@ func insertTags(events map[string]mb.Event, identifier string, resourceTagMap ma
 	// split the identifier and check for each sub-identifier.
 	// For example, identifier might be [storageType, s3BucketName].
 	// And tags are only store under s3BucketName in resourceTagMap.
-	subIdentifiers := strings.Split(identifier, dimensionSeparator)
+	// Commas in dimension values are escaped, so they are not split.
+	// Some metric dimension values are arn format, eg: AWS/DDOS namespace
+	// metric, aws.FindTags also looks them up with their resource ID.
+	subIdentifiers := splitDimensions(identifier)
 	for _, v := range subIdentifiers {
-		tags := resourceTagMap[v]
-		// some metric dimension values are arn format, eg: AWS/DDOS namespace metric
-		if len(tags) == 0 && strings.HasPrefix(v, "arn:") {
-			resourceID, err := aws.FindShortIdentifierFromARN(v)
-			if err == nil {
-				tags = resourceTagMap[resourceID]
+		aws.AddTags(events[identifier], aws.FindTags(resourceTagMap, v))
+	}
+}
+
+// insertARNFields adds the aws.arn.* fields to the events identified by an
+// ARN, like the events of the AWS/States namespace identified by the
+// StateMachineArn dimension. For events identified by several dimensions, the
+// first dimension value that is an ARN is used.
+func insertARNFields(events map[string]mb.Event) {
+	for identifier, event := range events {
+		for _, v := range splitDimensions(identifier) {
+			if strings.HasPrefix(v, "arn:") {
+				aws.AddARNFields(event, v)
+				break
 			}
 		}
-		if len(tags) != 0 {
-			// By default, replace dot "." using underscore "_" for tag keys.
-			// Note: tag values are not dedotted.
-			for _, tag := range tags {
-				_, _ = events[identifier].RootFields.Put("aws.tags."+common.DeDot(*tag.Key), *tag.Value)
+	}
+}
+
+// previousValue is the value of a Sum or SampleCount statistic in the previous
+// collection, used to compute counter derivatives. The value and its timestamp
+// are persisted.
+type previousValue struct {
+	Value     float64   `json:"v"`
+	Timestamp time.Time `json:"t"`
+	period    time.Duration
+	updated   time.Time
+}
+
+var (
+	previousValuesCacheOnce sync.Once
+	// previousValuesCache persists the previous values of all the cloudwatch
+	// metricsets of the Beat, so the derivatives continue after a restart. It
+	// is nil when the cache cannot be opened.
+	previousValuesCache *persistentcache.PersistentCache
+)
+
+// getPreviousValue returns the previous value of key, from memory or from the
+// values persisted before a restart.
+func (m *MetricSet) getPreviousValue(key string) (previousValue, bool) {
+	if previous, ok := m.previousValues[key]; ok {
+		return previous, true
+	}
+
+	previousValuesCacheOnce.Do(func() {
+		cache, err := persistentcache.New("aws-cloudwatch-counters", persistentcache.Options{})
+		if err != nil {
+			m.logger.Warnf("could not open the cache of the counter values, they are lost on restart: %s", err)
+			return
+		}
+		previousValuesCache = cache
+	})
+	if previousValuesCache == nil {
+		return previousValue{}, false
+	}
+	var previous previousValue
+	if err := previousValuesCache.Get(key, &previous); err != nil {
+		return previousValue{}, false
+	}
+	return previous, true
+}
+
+// setPreviousValue keeps the value of key in memory and persists it for three
+// of its periods, like prunePreviousValues.
+func (m *MetricSet) setPreviousValue(key string, value previousValue) {
+	m.previousValues[key] = value
+	if previousValuesCache == nil {
+		return
+	}
+	if err := previousValuesCache.PutWithTimeout(key, value, 3*value.period); err != nil {
+		m.logger.Debugf("could not persist the counter value of %s: %s", key, err)
+	}
+}
+
+// addCounterDerivatives adds the per-second rate or the delta of the Sum and
+// SampleCount statistics of the events, from the value collected for the same
+// identifier in the previous collection. The rate is the increase divided by
+// the seconds elapsed between the two values, it is not added when the value
+// decreased, like after a counter reset. The delta is the signed difference
+// between the two values. For example, with counter_derivative set to rate,
+// aws.sqs.metrics.NumberOfMessagesSent.sum adds
+// aws.sqs.metrics.NumberOfMessagesSent.rate.sum. No derivative is added the
+// first time a value is collected.
+func (m *MetricSet) addCounterDerivatives(events map[string]mb.Event, regionName string, period time.Duration) {
+	if m.config.CounterDerivative == "" {
+		return
+	}
+
+	now := time.Now()
+	for identifier, event := range events {
+		for field, value := range event.RootFields.Flatten() {
+			metricField, statMethod, ok := splitCounterField(field)
+			if !ok {
+				continue
 			}
+			current, ok := value.(float64)
+			if !ok {
+				continue
+			}
+
+			// The same metric collected with different periods has different values.
+			key := regionName + labelSeparator + m.AccountID + labelSeparator + identifier + labelSeparator + field + labelSeparator + period.String()
+			previous, found := m.getPreviousValue(key)
+			m.setPreviousValue(key, previousValue{Value: current, Timestamp: event.Timestamp, period: period, updated: now})
+			if !found || !event.Timestamp.After(previous.Timestamp) {
+				continue
+			}
+
+			switch m.config.CounterDerivative {
+			case counterDerivativeRate:
+				if current < previous.Value {
+					continue
+				}
+				elapsed := event.Timestamp.Sub(previous.Timestamp).Seconds()
+				_, _ = event.RootFields.Put(metricField+"."+counterDerivativeRate+"."+statMethod, (current-previous.Value)/elapsed)
+			case counterDerivativeDelta:
+				_, _ = event.RootFields.Put(metricField+"."+counterDerivativeDelta+"."+statMethod, current-previous.Value)
+			}
+		}
+	}
+}
+
+// splitCounterField splits a metric field of a Sum or SampleCount statistic into
+// the metric field and the statistic method,
+// example aws.sqs.metrics.NumberOfMessagesSent.sum -> aws.sqs.metrics.NumberOfMessagesSent, sum
+func splitCounterField(field string) (string, string, bool) {
+	if !strings.HasPrefix(field, "aws.") || !strings.Contains(field, ".metrics.") {
+		return "", "", false
+	}
+
+	idx := strings.LastIndex(field, ".")
+	statMethod := field[idx+1:]
+	if statMethod != "sum" && statMethod != "count" {
+		return "", "", false
+	}
+	return field[:idx], statMethod, true
+}
+
+// prunePreviousValues removes the previous values that have not been updated
+// for three of their periods, so identifiers that are gone do not accumulate.
+func (m *MetricSet) prunePreviousValues(now time.Time) {
+	for key, previous := range m.previousValues {
+		if now.Sub(previous.updated) > 3*previous.period {
+			delete(m.previousValues, key)
+		}
+	}
+}
+
+// seenResource is a resource identifier collected from a namespace, used to
+// report resources that stopped publishing metrics.
+type seenResource struct {
+	regionName string
+	namespace  string
+	identifier string
+	dimensions interface{}
+	period     time.Duration
+	lastSeen   time.Time
+}
+
+// trackResources records the identifiers of the events as seen now.
+func (m *MetricSet) trackResources(events map[string]mb.Event, regionName string, period time.Duration) {
+	if m.config.TombstonePeriods == 0 {
+		return
+	}
+
+	now := time.Now()
+	for identifier, event := range events {
+		namespace, err := event.RootFields.GetValue("aws.cloudwatch.namespace")
+		if err != nil {
+			continue
+		}
+		dimensions, _ := event.RootFields.GetValue("aws.dimensions")
+
+		resource := seenResource{
+			regionName: regionName,
+			namespace:  fmt.Sprint(namespace),
+			identifier: identifier,
+			dimensions: dimensions,
+			period:     period,
+			lastSeen:   now,
+		}
+		key := resource.regionName + labelSeparator + resource.namespace + labelSeparator + resource.identifier
+		m.seenResources[key] = resource
+	}
+}
+
+// reportGoneResources reports a tombstone event for each resource that has not
+// been seen for tombstone_periods periods, and stops tracking it.
+func (m *MetricSet) reportGoneResources(report mb.ReporterV2, now time.Time) {
+	if m.config.TombstonePeriods == 0 {
+		return
+	}
+
+	for key, resource := range m.seenResources {
+		if now.Sub(resource.lastSeen) < time.Duration(m.config.TombstonePeriods)*resource.period {
 			continue
 		}
+
+		event := aws.InitEvent(resource.regionName, m.AccountName, m.AccountID, now)
+		_, _ = event.RootFields.Put("aws.cloudwatch.namespace", resource.namespace)
+		_, _ = event.RootFields.Put("aws.cloudwatch.resource.identifier", resource.identifier)
+		_, _ = event.RootFields.Put("aws.cloudwatch.resource.gone", true)
+		_, _ = event.RootFields.Put("aws.cloudwatch.resource.last_seen", resource.lastSeen)
+		if resource.dimensions != nil {
+			_, _ = event.RootFields.Put("aws.dimensions", resource.dimensions)
+		}
+		report.Event(event)
+		delete(m.seenResources, key)
+	}
+}
+
+// configObservations counts, for each metrics config queried with ListMetrics,
+// the consecutive fetches in which it matched no metric.
+type configObservations struct {
+	checked  map[string]Config
+	observed map[string]bool
+	misses   map[string]int
+	fetches  int
+}
+
+func newConfigObservations() configObservations {
+	return configObservations{
+		checked:  map[string]Config{},
+		observed: map[string]bool{},
+		misses:   map[string]int{},
+	}
+}
+
+// configID identifies a metrics config by the options that select its metrics.
+func configID(config Config) string {
+	return fmt.Sprintf("%s|%v|%v|%s", config.Namespace, config.MetricName, config.Dimensions, config.ResourceType)
+}
+
+// check records the metrics configs queried with ListMetrics in this fetch.
+func (o configObservations) check(cloudwatchConfigs []Config) {
+	if o.checked == nil {
+		return
+	}
+	for _, config := range cloudwatchConfigs {
+		if !isExplicitMetricsConfig(config) {
+			o.checked[configID(config)] = config
+		}
+	}
+}
+
+// observe records the metrics configs that match at least one metric of the
+// ListMetrics output of a namespace.
+func (o configObservations) observe(cloudwatchConfigs []Config, namespace string, listMetricsOutput []types.Metric) {
+	if o.observed == nil {
+		return
+	}
+	for _, config := range cloudwatchConfigs {
+		if isExplicitMetricsConfig(config) || !configsCoverNamespace([]Config{config}, namespace) {
+			continue
+		}
+		detail := []namespaceDetail{{names: config.MetricName, dimensions: toCloudwatchDimensions(config.Dimensions)}}
+		if len(filterListMetricsOutput(listMetricsOutput, detail)) > 0 {
+			o.observed[configID(config)] = true
+		}
+	}
+}
+
+// reportUnobservedConfigs updates the number of fetches in which each checked
+// metrics config matched no metric, and every unobserved_metrics_fetches fetches
+// reports a summary event of the configs that matched no metric in all of them.
+func (m *MetricSet) reportUnobservedConfigs(report mb.ReporterV2, now time.Time) {
+	if m.config.UnobservedFetches == 0 {
+		return
+	}
+
+	for id := range m.observations.checked {
+		if m.observations.observed[id] {
+			m.observations.misses[id] = 0
+		} else {
+			m.observations.misses[id]++
+		}
+	}
+	m.observations.fetches++
+
+	var unobserved []string
+	if m.observations.fetches%m.config.UnobservedFetches == 0 {
+		for id, config := range m.observations.checked {
+			if m.observations.misses[id] >= m.config.UnobservedFetches {
+				unobserved = append(unobserved, describeConfig(config))
+			}
+		}
+	}
+
+	for id := range m.observations.checked {
+		delete(m.observations.checked, id)
+		delete(m.observations.observed, id)
+	}
+
+	if len(unobserved) == 0 {
+		return
+	}
+	sort.Strings(unobserved)
+	m.logger.Warnf("Metrics configs matched no metric in the last %d fetches: %s", m.config.UnobservedFetches, strings.Join(unobserved, "; "))
+
+	event := aws.InitEvent("", m.AccountName, m.AccountID, now)
+	_, _ = event.RootFields.Put("aws.cloudwatch.unobserved.configs", unobserved)
+	_, _ = event.RootFields.Put("aws.cloudwatch.unobserved.fetches", m.config.UnobservedFetches)
+	report.Event(event)
+}
+
+// describeConfig returns a short description of a metrics config,
+// example namespace: AWS/EC2, name: [CPUUtilization], dimensions: [InstanceId=i-1]
+func describeConfig(config Config) string {
+	description := "namespace: " + config.Namespace
+	if config.MetricName != nil {
+		description += ", name: [" + strings.Join(config.MetricName, ",") + "]"
+	}
+	if config.Dimensions != nil {
+		dimensions := make([]string, 0, len(config.Dimensions))
+		for _, dim := range config.Dimensions {
+			dimensions = append(dimensions, dim.Name+"="+dim.Value)
+		}
+		description += ", dimensions: [" + strings.Join(dimensions, ",") + "]"
 	}
+	return description
 }
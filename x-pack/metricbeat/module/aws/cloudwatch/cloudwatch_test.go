@@ -10,21 +10,34 @@ package cloudwatch
 import (
 	"context"
 	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	cloudwatchtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	pitypes "github.com/aws/aws-sdk-go-v2/service/pi/types"
 	resourcegroupstaggingapitypes "github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
 	"github.com/aws/smithy-go/middleware"
 
 	awssdk "github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/configservice"
+	"github.com/aws/aws-sdk-go-v2/service/pi"
 	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/elastic/beats/v7/libbeat/common/match"
 	"github.com/elastic/beats/v7/metricbeat/mb"
+	mbtest "github.com/elastic/beats/v7/metricbeat/mb/testing"
+	"github.com/elastic/beats/v7/x-pack/libbeat/persistentcache"
 	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
 	"github.com/elastic/elastic-agent-libs/logp"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+	"github.com/elastic/elastic-agent-libs/monitoring"
 )
 
 var (
@@ -805,7 +818,7 @@ func TestReadCloudwatchConfig(t *testing.T) {
 		t.Run(c.title, func(t *testing.T) {
 			m.CloudwatchConfigs = c.cloudwatchMetricsConfig
 			m.MetricSet.TagsFilter = c.tagsFilter
-			listMetricDetailTotal, namespaceDetailTotal := m.readCloudwatchConfig()
+			listMetricDetailTotal, namespaceDetailTotal := m.readCloudwatchConfig(m.CloudwatchConfigs)
 			assert.Equal(t, c.expectedListMetricDetailTotal, listMetricDetailTotal)
 			assert.Equal(t, c.expectedNamespaceDetailTotal, namespaceDetailTotal)
 		})
@@ -1394,7 +1407,7 @@ func TestCreateEventsWithIdentifier(t *testing.T) {
 	}
 	startTime, endTime := aws.GetStartTimeEndTime(time.Now(), m.MetricSet.Period, m.MetricSet.Latency)
 
-	events, err := m.createEvents(mockCloudwatchSvc, mockTaggingSvc, listMetricWithStatsTotal, resourceTypeTagFilters, regionName, startTime, endTime)
+	events, err := m.createEvents(context.Background(), mockCloudwatchSvc, mockTaggingSvc, nil, listMetricWithStatsTotal, resourceTypeTagFilters, regionName, m.Period, startTime, endTime)
 	assert.NoError(t, err)
 
 	metricValue, err := events["i-1"].RootFields.GetValue("aws.ec2.metrics.CPUUtilization.avg")
@@ -1434,7 +1447,7 @@ func TestCreateEventsWithoutIdentifier(t *testing.T) {
 	resourceTypeTagFilters := map[string][]aws.Tag{}
 	startTime, endTime := aws.GetStartTimeEndTime(time.Now(), m.MetricSet.Period, m.MetricSet.Latency)
 
-	events, err := m.createEvents(mockCloudwatchSvc, mockTaggingSvc, listMetricWithStatsTotal, resourceTypeTagFilters, regionName, startTime, endTime)
+	events, err := m.createEvents(context.Background(), mockCloudwatchSvc, mockTaggingSvc, nil, listMetricWithStatsTotal, resourceTypeTagFilters, regionName, m.Period, startTime, endTime)
 	assert.NoError(t, err)
 
 	expectedID := regionName + accountID + namespace
@@ -1479,7 +1492,7 @@ func TestCreateEventsWithTagsFilter(t *testing.T) {
 	}
 
 	startTime, endTime := aws.GetStartTimeEndTime(time.Now(), m.MetricSet.Period, m.MetricSet.Latency)
-	events, err := m.createEvents(mockCloudwatchSvc, mockTaggingSvc, listMetricWithStatsTotal, resourceTypeTagFilters, regionName, startTime, endTime)
+	events, err := m.createEvents(context.Background(), mockCloudwatchSvc, mockTaggingSvc, nil, listMetricWithStatsTotal, resourceTypeTagFilters, regionName, m.Period, startTime, endTime)
 	assert.NoError(t, err)
 	assert.Equal(t, 1, len(events))
 
@@ -1491,7 +1504,7 @@ func TestCreateEventsWithTagsFilter(t *testing.T) {
 		},
 	}
 
-	events, err = m.createEvents(mockCloudwatchSvc, mockTaggingSvc, listMetricWithStatsTotal, resourceTypeTagFilters, regionName, startTime, endTime)
+	events, err = m.createEvents(context.Background(), mockCloudwatchSvc, mockTaggingSvc, nil, listMetricWithStatsTotal, resourceTypeTagFilters, regionName, m.Period, startTime, endTime)
 	assert.NoError(t, err)
 	assert.Equal(t, 0, len(events))
 }
@@ -1568,6 +1581,30 @@ func TestInsertTags(t *testing.T) {
 	}
 }
 
+func TestInsertARNFields(t *testing.T) {
+	identifierWithARN := "arn:aws:ec2:ap-northeast-1:111111111111:eip-allocation/eipalloc-0123456789abcdef,SYNFlood"
+	identifierWithoutARN := "StandardStorage,test-s3-1"
+
+	events := map[string]mb.Event{}
+	events[identifierWithARN] = aws.InitEvent(regionName, accountName, accountID, timestamp)
+	events[identifierWithoutARN] = aws.InitEvent(regionName, accountName, accountID, timestamp)
+
+	insertARNFields(events)
+
+	resourceType, err := events[identifierWithARN].RootFields.GetValue("aws.arn.resource.type")
+	assert.NoError(t, err)
+	assert.Equal(t, "eip-allocation", resourceType)
+	resourceID, err := events[identifierWithARN].RootFields.GetValue("aws.arn.resource.id")
+	assert.NoError(t, err)
+	assert.Equal(t, "eipalloc-0123456789abcdef", resourceID)
+	arnAccountID, err := events[identifierWithARN].RootFields.GetValue("aws.arn.account_id")
+	assert.NoError(t, err)
+	assert.Equal(t, "111111111111", arnAccountID)
+
+	_, err = events[identifierWithoutARN].RootFields.GetValue("aws.arn")
+	assert.Error(t, err)
+}
+
 func TestConfigDimensionValueContainsWildcard(t *testing.T) {
 	cases := []struct {
 		title          string
@@ -1634,7 +1671,7 @@ func TestCreateEventsTimestamp(t *testing.T) {
 
 	cloudwatchMock := &MockCloudWatchClientWithoutDim{}
 	resGroupTaggingClientMock := &MockResourceGroupsTaggingClient{}
-	events, err := m.createEvents(cloudwatchMock, resGroupTaggingClientMock, listMetricWithStatsTotal, resourceTypeTagFilters, regionName, startTime, endTime)
+	events, err := m.createEvents(context.Background(), cloudwatchMock, resGroupTaggingClientMock, nil, listMetricWithStatsTotal, resourceTypeTagFilters, regionName, m.Period, startTime, endTime)
 	assert.NoError(t, err)
 	assert.Equal(t, timestamp, events[regionName+accountID+namespace].Timestamp)
 }
@@ -1647,3 +1684,1552 @@ func TestGetStartTimeEndTime(t *testing.T) {
 	startTime, endTime := aws.GetStartTimeEndTime(time.Now(), m.MetricSet.Period, m.MetricSet.Latency)
 	assert.Equal(t, 5*time.Minute, endTime.Sub(startTime))
 }
+
+// MockCloudWatchClientListMetrics struct is used for unit tests.
+type MockCloudWatchClientListMetrics struct{}
+
+// ListMetrics implements cloudwatch.ListMetricsAPIClient interface
+func (m *MockCloudWatchClientListMetrics) ListMetrics(context.Context, *cloudwatch.ListMetricsInput, ...func(*cloudwatch.Options)) (*cloudwatch.ListMetricsOutput, error) {
+	return &cloudwatch.ListMetricsOutput{
+		Metrics: []cloudwatchtypes.Metric{
+			{
+				MetricName: awssdk.String("mem_used_percent"),
+				Namespace:  awssdk.String("CWAgent"),
+			},
+			{
+				MetricName: awssdk.String("Requests"),
+				Namespace:  awssdk.String("MyCompany/Frontend"),
+			},
+			{
+				MetricName: awssdk.String("Errors"),
+				Namespace:  awssdk.String("MyCompany/Frontend"),
+			},
+			{
+				MetricName: awssdk.String("CPUUtilization"),
+				Namespace:  awssdk.String("AWS/EC2"),
+			},
+		},
+		ResultMetadata: middleware.Metadata{},
+	}, nil
+}
+
+func TestDiscoverNamespaces(t *testing.T) {
+	m := MetricSet{}
+	m.MetricSet = &aws.MetricSet{Period: 5 * time.Minute}
+	m.logger = logp.NewLogger("test")
+
+	namespaceDetailTotal := map[string][]namespaceDetail{
+		"AWS/EC2": {
+			{
+				names:      []string{"CPUUtilization"},
+				statistics: []string{"Average"},
+			},
+		},
+		"CWAgent*": {
+			{
+				statistics: []string{"Average"},
+			},
+		},
+		"MyCompany/*": {
+			{
+				names:      []string{"Requests"},
+				statistics: []string{"Sum"},
+			},
+		},
+	}
+
+	namespaceDetailRegion, discoveredListMetrics := m.discoverNamespaces(context.Background(), &MockCloudWatchClientListMetrics{}, regionName, m.Period, namespaceDetailTotal)
+
+	assert.Equal(t, 3, len(namespaceDetailRegion))
+	assert.Equal(t, namespaceDetailTotal["AWS/EC2"], namespaceDetailRegion["AWS/EC2"])
+	assert.Equal(t, namespaceDetailTotal["CWAgent*"], namespaceDetailRegion["CWAgent"])
+	assert.Equal(t, namespaceDetailTotal["MyCompany/*"], namespaceDetailRegion["MyCompany/Frontend"])
+
+	// AWS/EC2 is not configured as a pattern, so its metrics are listed separately
+	assert.Equal(t, 2, len(discoveredListMetrics))
+	assert.Equal(t, 1, len(discoveredListMetrics["CWAgent"]))
+	assert.Equal(t, 2, len(discoveredListMetrics["MyCompany/Frontend"]))
+}
+
+func TestIsNamespacePattern(t *testing.T) {
+	cases := []struct {
+		namespace      string
+		expectedResult bool
+	}{
+		{"AWS/EC2", false},
+		{"*", false},
+		{"CWAgent*", true},
+		{"MyCompany/*", true},
+		{"AWS/E?2", true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.namespace, func(t *testing.T) {
+			assert.Equal(t, c.expectedResult, isNamespacePattern(c.namespace))
+		})
+	}
+}
+
+func TestGenerateGenericFieldName(t *testing.T) {
+	cases := []struct {
+		title             string
+		label             []string
+		expectedFieldName string
+	}{
+		{
+			"test AWS namespace",
+			[]string{"CPUUtilization", "AWS/EC2", "Average", "InstanceId", "i-1"},
+			"aws.cloudwatch.metrics.CPUUtilization.avg",
+		},
+		{
+			"test custom namespace",
+			[]string{"mem_used_percent", "CWAgent", "Maximum"},
+			"aws.cloudwatch.metrics.mem_used_percent.max",
+		},
+		{
+			"test metric name with dot",
+			[]string{"DeliveryToS3.Records", "AWS/Firehose", "Sum", "DeliveryStreamName", "test-1"},
+			"aws.cloudwatch.metrics.DeliveryToS3_Records.sum",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			fieldName := generateGenericFieldName(c.label)
+			assert.Equal(t, c.expectedFieldName, fieldName)
+		})
+	}
+}
+
+func TestCreateEventsWithGenericMetricFields(t *testing.T) {
+	m := MetricSet{
+		logger:            logp.NewLogger("test"),
+		CloudwatchConfigs: []Config{{Statistic: []string{"Average"}}},
+		MetricSet:         &aws.MetricSet{Period: 5},
+		config:            MetricSetConfig{GenericMetricFields: true},
+	}
+
+	listMetricWithStatsTotal := []metricsWithStatistics{
+		{
+			cloudwatchtypes.Metric{
+				Dimensions: []cloudwatchtypes.Dimension{{
+					Name:  awssdk.String("InstanceId"),
+					Value: awssdk.String("i-1"),
+				}},
+				MetricName: awssdk.String("CPUUtilization"),
+				Namespace:  awssdk.String("AWS/EC2"),
+			},
+			[]string{"Average"},
+		},
+	}
+
+	resourceTypeTagFilters := map[string][]aws.Tag{}
+	startTime, endTime := aws.GetStartTimeEndTime(time.Now(), m.MetricSet.Period, m.MetricSet.Latency)
+	events, err := m.createEvents(context.Background(), &MockCloudWatchClient{}, &MockResourceGroupsTaggingClient{}, nil, listMetricWithStatsTotal, resourceTypeTagFilters, regionName, m.Period, startTime, endTime)
+	assert.NoError(t, err)
+
+	metricValue, err := events["i-1"].RootFields.GetValue("aws.cloudwatch.metrics.CPUUtilization.avg")
+	assert.NoError(t, err)
+	assert.Equal(t, value1, metricValue)
+
+	namespaceValue, err := events["i-1"].RootFields.GetValue("aws.cloudwatch.namespace")
+	assert.NoError(t, err)
+	assert.Equal(t, "AWS/EC2", namespaceValue)
+
+	_, err = events["i-1"].RootFields.GetValue("aws.ec2.metrics.CPUUtilization.avg")
+	assert.Error(t, err)
+}
+
+// MockConfigAggregatorClient is used for unit tests.
+type MockConfigAggregatorClient struct{}
+
+// SelectAggregateResourceConfig implements aws.ConfigAggregatorClient.
+func (m *MockConfigAggregatorClient) SelectAggregateResourceConfig(context.Context, *configservice.SelectAggregateResourceConfigInput, ...func(*configservice.Options)) (*configservice.SelectAggregateResourceConfigOutput, error) {
+	return &configservice.SelectAggregateResourceConfigOutput{
+		Results: []string{
+			`{"resourceId":"i-1","tags":[{"key":"name","value":"test-config"}]}`,
+		},
+	}, nil
+}
+
+func TestCreateEventsWithConfigAggregatorTags(t *testing.T) {
+	m := MetricSet{
+		logger:            logp.NewLogger("test"),
+		CloudwatchConfigs: []Config{{Statistic: []string{"Average"}}},
+		MetricSet:         &aws.MetricSet{Period: 5},
+		config:            MetricSetConfig{ConfigAggregator: ConfigAggregator{Name: "aggregator"}},
+		tagSources:        map[string]string{"AWS::EC2::Instance": tagSourceAWSConfig},
+	}
+
+	listMetricWithStatsTotal := []metricsWithStatistics{
+		{
+			cloudwatchtypes.Metric{
+				Dimensions: []cloudwatchtypes.Dimension{{
+					Name:  awssdk.String("InstanceId"),
+					Value: awssdk.String("i-1"),
+				}},
+				MetricName: awssdk.String("CPUUtilization"),
+				Namespace:  awssdk.String("AWS/EC2"),
+			},
+			[]string{"Average"},
+		},
+	}
+
+	resourceTypeTagFilters := map[string][]aws.Tag{}
+	resourceTypeTagFilters["AWS::EC2::Instance"] = []aws.Tag{
+		{
+			Key:   "name",
+			Value: []string{"test-config"},
+		},
+	}
+
+	startTime, endTime := aws.GetStartTimeEndTime(time.Now(), m.MetricSet.Period, m.MetricSet.Latency)
+	events, err := m.createEvents(context.Background(), &MockCloudWatchClient{}, &MockResourceGroupsTaggingClient{}, &MockConfigAggregatorClient{}, listMetricWithStatsTotal, resourceTypeTagFilters, regionName, m.Period, startTime, endTime)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(events))
+
+	tagValue, err := events["i-1"].RootFields.GetValue("aws.tags.name")
+	assert.NoError(t, err)
+	assert.Equal(t, "test-config", tagValue)
+}
+
+func TestConfigValidate(t *testing.T) {
+	cases := []struct {
+		title         string
+		config        Config
+		expectedError bool
+	}{
+		{
+			"test default tag source",
+			Config{Namespace: "AWS/EC2", ResourceType: "ec2:instance"},
+			false,
+		},
+		{
+			"test aws_config tag source",
+			Config{Namespace: "AWS/EC2", ResourceType: "AWS::EC2::Instance", TagSource: tagSourceAWSConfig},
+			false,
+		},
+		{
+			"test aws_config tag source without resource type",
+			Config{Namespace: "AWS/EC2", TagSource: tagSourceAWSConfig},
+			true,
+		},
+		{
+			"test unsupported tag source",
+			Config{Namespace: "AWS/EC2", ResourceType: "ec2:instance", TagSource: "foo"},
+			true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			err := c.config.Validate()
+			if c.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFindTimestampIdx(t *testing.T) {
+	timestamp1 := time.Date(2020, 10, 6, 0, 5, 0, 0, time.UTC)
+	timestamp2 := time.Date(2020, 10, 6, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		title             string
+		timestampStrategy string
+		timestamps        []time.Time
+		expectedExists    bool
+		expectedIdx       int
+	}{
+		{
+			"shared timestamp exists",
+			timestampStrategyLatestComplete,
+			[]time.Time{timestamp1, timestamp2},
+			true,
+			0,
+		},
+		{
+			"shared timestamp does not exist",
+			timestampStrategyLatest,
+			[]time.Time{timestamp2},
+			false,
+			-1,
+		},
+		{
+			"per-metric uses the latest data point",
+			timestampStrategyPerMetric,
+			[]time.Time{timestamp2, timestamp1},
+			true,
+			1,
+		},
+		{
+			"per-metric without data points",
+			timestampStrategyPerMetric,
+			nil,
+			false,
+			-1,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			m := MetricSet{config: MetricSetConfig{TimestampStrategy: c.timestampStrategy}}
+			exists, idx := m.findTimestampIdx(timestamp1, c.timestamps)
+			assert.Equal(t, c.expectedExists, exists)
+			assert.Equal(t, c.expectedIdx, idx)
+		})
+	}
+}
+
+func TestGroupConfigs(t *testing.T) {
+	billingLatency := 12 * time.Hour
+	noLatency := time.Duration(0)
+	dailyPeriod := 24 * time.Hour
+
+	m := MetricSet{}
+	m.MetricSet = &aws.MetricSet{Period: 5 * time.Minute, Latency: 5 * time.Minute}
+	m.CloudwatchConfigs = []Config{
+		{Namespace: "AWS/EC2"},
+		{Namespace: "AWS/Billing", Latency: &billingLatency},
+		{Namespace: "AWS/SQS"},
+		{Namespace: "AWS/Lambda", Latency: &noLatency},
+		{Namespace: "AWS/S3", Period: &dailyPeriod},
+	}
+
+	configsByWindow := m.groupConfigs()
+	assert.Equal(t, 4, len(configsByWindow))
+	assert.Equal(t, []Config{m.CloudwatchConfigs[0], m.CloudwatchConfigs[2]}, configsByWindow[collectionWindow{5 * time.Minute, 5 * time.Minute}])
+	assert.Equal(t, []Config{m.CloudwatchConfigs[1]}, configsByWindow[collectionWindow{5 * time.Minute, billingLatency}])
+	assert.Equal(t, []Config{m.CloudwatchConfigs[3]}, configsByWindow[collectionWindow{5 * time.Minute, noLatency}])
+	assert.Equal(t, []Config{m.CloudwatchConfigs[4]}, configsByWindow[collectionWindow{dailyPeriod, 5 * time.Minute}])
+}
+
+func TestIsDue(t *testing.T) {
+	m := MetricSet{lastEndTimes: map[collectionWindow]time.Time{}}
+	m.MetricSet = &aws.MetricSet{Period: 5 * time.Minute}
+
+	moduleWindow := collectionWindow{period: 5 * time.Minute}
+	dailyWindow := collectionWindow{period: 24 * time.Hour}
+	now := time.Date(2022, 8, 15, 13, 38, 45, 0, time.UTC)
+
+	// windows with the module period are always collected
+	_, endTime := m.getStartTimeEndTime(now, moduleWindow.period, moduleWindow.latency)
+	m.lastEndTimes[moduleWindow] = endTime
+	assert.True(t, m.isDue(moduleWindow, endTime))
+
+	// daily window is collected on the first fetch, and then once per day
+	_, endTime = m.getStartTimeEndTime(now, dailyWindow.period, dailyWindow.latency)
+	assert.True(t, m.isDue(dailyWindow, endTime))
+	m.lastEndTimes[dailyWindow] = endTime
+
+	_, endTime = m.getStartTimeEndTime(now.Add(5*time.Minute), dailyWindow.period, dailyWindow.latency)
+	assert.False(t, m.isDue(dailyWindow, endTime))
+
+	_, endTime = m.getStartTimeEndTime(now.Add(11*time.Hour), dailyWindow.period, dailyWindow.latency)
+	assert.True(t, m.isDue(dailyWindow, endTime))
+}
+
+func TestGetBackfillStartTime(t *testing.T) {
+	startTime := time.Date(2022, 8, 15, 13, 30, 0, 0, time.UTC)
+	endTime := startTime.Add(5 * time.Minute)
+
+	m := MetricSet{}
+	m.MetricSet = &aws.MetricSet{Period: 5 * time.Minute}
+
+	m.config.Backfill = 12 * time.Minute
+	assert.Equal(t, startTime.Add(-10*time.Minute), m.getBackfillStartTime(startTime, endTime))
+
+	m.config.Backfill = 24 * time.Hour
+	assert.Equal(t, startTime.Add(-24*time.Hour), m.getBackfillStartTime(startTime, endTime))
+
+	m.config.Backfill = 3 * time.Minute
+	assert.Equal(t, startTime, m.getBackfillStartTime(startTime, endTime))
+
+	assert.Equal(t, startTime, m.getBackfillStartTime(startTime, startTime))
+}
+
+// MockCloudWatchClientBackfill returns the data points of a backfill in two pages
+// and records the GetMetricData requests.
+type MockCloudWatchClientBackfill struct {
+	inputs []*cloudwatch.GetMetricDataInput
+}
+
+// GetMetricData implements cloudwatch.GetMetricDataAPIClient.
+func (m *MockCloudWatchClientBackfill) GetMetricData(_ context.Context, input *cloudwatch.GetMetricDataInput, _ ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+	m.inputs = append(m.inputs, input)
+	if input.NextToken == nil {
+		return &cloudwatch.GetMetricDataOutput{
+			MetricDataResults: []cloudwatchtypes.MetricDataResult{
+				{
+					Id:         &id1,
+					Label:      &label1,
+					Values:     []float64{3, 2},
+					Timestamps: []time.Time{timestamp.Add(10 * time.Minute), timestamp.Add(5 * time.Minute)},
+				},
+				{
+					Id:         &id2,
+					Label:      &label2,
+					Values:     []float64{20},
+					Timestamps: []time.Time{timestamp.Add(5 * time.Minute)},
+				},
+			},
+			NextToken: awssdk.String("page-2"),
+		}, nil
+	}
+	return &cloudwatch.GetMetricDataOutput{
+		MetricDataResults: []cloudwatchtypes.MetricDataResult{
+			{
+				Id:         &id1,
+				Label:      &label1,
+				Values:     []float64{1},
+				Timestamps: []time.Time{timestamp},
+			},
+		},
+	}, nil
+}
+
+func TestCreateEventBatchesBackfill(t *testing.T) {
+	m := MetricSet{}
+	m.MetricSet = &aws.MetricSet{Period: 5 * time.Minute, AccountID: accountID}
+	m.logger = logp.NewLogger("test")
+
+	listMetricWithStatsTotal := []metricsWithStatistics{
+		{listMetric1, []string{"Average"}},
+		{listMetric1, []string{"Sum"}},
+	}
+	startTime := timestamp
+	endTime := timestamp.Add(15 * time.Minute)
+
+	svcCloudwatch := &MockCloudWatchClientBackfill{}
+	batches, err := m.createEventBatches(context.Background(), svcCloudwatch, nil, nil, listMetricWithStatsTotal, nil, regionName, m.Period, startTime, endTime, true)
+	assert.NoError(t, err)
+
+	// a single paged request over the whole backfill time range
+	assert.Equal(t, 2, len(svcCloudwatch.inputs))
+	for _, input := range svcCloudwatch.inputs {
+		assert.Equal(t, startTime, *input.StartTime)
+		assert.Equal(t, endTime, *input.EndTime)
+	}
+
+	expected := []struct {
+		timestamp time.Time
+		cpu       float64
+		disk      interface{}
+	}{
+		{timestamp, 1, nil},
+		{timestamp.Add(5 * time.Minute), 2, 20.0},
+		{timestamp.Add(10 * time.Minute), 3, nil},
+	}
+	assert.Equal(t, len(expected), len(batches))
+	for i, e := range expected {
+		assert.Equal(t, e.timestamp, batches[i].timestamp)
+		event, ok := batches[i].events[instanceID1]
+		assert.True(t, ok)
+		assert.Equal(t, e.timestamp, event.Timestamp)
+
+		cpu, err := event.RootFields.GetValue("aws.ec2.metrics.CPUUtilization.avg")
+		assert.NoError(t, err)
+		assert.Equal(t, e.cpu, cpu)
+		disk, _ := event.RootFields.GetValue("aws.ec2.metrics.DiskReadOps.avg")
+		assert.Equal(t, e.disk, disk)
+	}
+
+	// a regular collection creates a single batch for the timestamp of timestamp_strategy
+	batches, err = m.createEventBatches(context.Background(), &MockCloudWatchClientBackfill{}, nil, nil, listMetricWithStatsTotal, nil, regionName, m.Period, startTime, endTime, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(batches))
+	assert.True(t, batches[0].timestamp.IsZero())
+}
+
+// usePreviousValuesCache persists the previous counter values of the test in a
+// temporary directory, and returns a function reopening it like after a restart.
+func usePreviousValuesCache(t *testing.T) func() {
+	previousValuesCacheOnce.Do(func() {})
+	rootPath := t.TempDir()
+	open := func() {
+		if previousValuesCache != nil {
+			previousValuesCache.Close()
+		}
+		cache, err := persistentcache.New("aws-cloudwatch-counters", persistentcache.Options{RootPath: rootPath})
+		require.NoError(t, err)
+		previousValuesCache = cache
+	}
+	open()
+	t.Cleanup(func() {
+		previousValuesCache.Close()
+		previousValuesCache = nil
+	})
+	return open
+}
+
+func newCounterEvents(ts time.Time, sum float64, count float64) map[string]mb.Event {
+	event := aws.InitEvent(regionName, accountName, accountID, ts)
+	_, _ = event.RootFields.Put("aws.sqs.metrics.NumberOfMessagesSent.sum", sum)
+	_, _ = event.RootFields.Put("aws.sqs.metrics.NumberOfMessagesSent.count", count)
+	_, _ = event.RootFields.Put("aws.sqs.metrics.ApproximateAgeOfOldestMessage.avg", 10.0)
+	return map[string]mb.Event{"queue-1": event}
+}
+
+func TestAddCounterDerivatives(t *testing.T) {
+	newEvents := newCounterEvents
+
+	ts := time.Date(2022, 8, 15, 13, 30, 0, 0, time.UTC)
+
+	type values struct {
+		sum   float64
+		count float64
+	}
+
+	// Sum and SampleCount of consecutive 5 minute periods
+	collections := []values{
+		{600, 300},
+		{1500, 900},
+		{300, 150},
+	}
+
+	cases := []struct {
+		title             string
+		counterDerivative string
+		expected          []*values
+	}{
+		// No rate is added after the values decreased, like after a reset.
+		{"rate", counterDerivativeRate, []*values{nil, {3, 2}, nil}},
+		{"delta", counterDerivativeDelta, []*values{nil, {900, 600}, {-1200, -750}}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			usePreviousValuesCache(t)
+			m := MetricSet{config: MetricSetConfig{CounterDerivative: c.counterDerivative}, previousValues: map[string]previousValue{}}
+			m.MetricSet = &aws.MetricSet{AccountID: accountID}
+			m.logger = logp.NewLogger("test")
+
+			for i, collection := range collections {
+				events := newEvents(ts.Add(time.Duration(i)*5*time.Minute), collection.sum, collection.count)
+				m.addCounterDerivatives(events, regionName, 5*time.Minute)
+				fields := events["queue-1"].RootFields
+
+				hasDerivative, _ := fields.HasKey("aws.sqs.metrics.ApproximateAgeOfOldestMessage." + c.counterDerivative)
+				assert.False(t, hasDerivative)
+
+				expected := c.expected[i]
+				if expected == nil {
+					hasDerivative, _ = fields.HasKey("aws.sqs.metrics.NumberOfMessagesSent." + c.counterDerivative)
+					assert.False(t, hasDerivative, "collection %d", i)
+					continue
+				}
+				sum, err := fields.GetValue("aws.sqs.metrics.NumberOfMessagesSent." + c.counterDerivative + ".sum")
+				assert.NoError(t, err)
+				assert.Equal(t, expected.sum, sum, "collection %d", i)
+				count, err := fields.GetValue("aws.sqs.metrics.NumberOfMessagesSent." + c.counterDerivative + ".count")
+				assert.NoError(t, err)
+				assert.Equal(t, expected.count, count, "collection %d", i)
+			}
+
+			m.prunePreviousValues(time.Now().Add(time.Hour))
+			assert.Empty(t, m.previousValues)
+		})
+	}
+}
+
+func TestCounterRateAcrossRestart(t *testing.T) {
+	restart := usePreviousValuesCache(t)
+	ts := time.Date(2022, 8, 15, 13, 30, 0, 0, time.UTC)
+
+	newMetricSet := func() *MetricSet {
+		m := &MetricSet{config: MetricSetConfig{CounterDerivative: counterDerivativeRate}, previousValues: map[string]previousValue{}}
+		m.MetricSet = &aws.MetricSet{AccountID: accountID}
+		m.logger = logp.NewLogger("test")
+		return m
+	}
+
+	// First fetch, there is no previous value to compute the rate.
+	events := newCounterEvents(ts, 600, 300)
+	newMetricSet().addCounterDerivatives(events, regionName, 5*time.Minute)
+	hasRate, _ := events["queue-1"].RootFields.HasKey("aws.sqs.metrics.NumberOfMessagesSent.rate")
+	assert.False(t, hasRate)
+
+	// Second fetch after a restart, the rate is computed from the persisted
+	// value over the 10 minutes elapsed since then.
+	restart()
+	events = newCounterEvents(ts.Add(10*time.Minute), 1800, 900)
+	newMetricSet().addCounterDerivatives(events, regionName, 5*time.Minute)
+	sum, err := events["queue-1"].RootFields.GetValue("aws.sqs.metrics.NumberOfMessagesSent.rate.sum")
+	assert.NoError(t, err)
+	assert.Equal(t, 2.0, sum)
+	count, err := events["queue-1"].RootFields.GetValue("aws.sqs.metrics.NumberOfMessagesSent.rate.count")
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, count)
+}
+
+func TestReportGoneResources(t *testing.T) {
+	newEvents := func(identifiers ...string) map[string]mb.Event {
+		events := map[string]mb.Event{}
+		for _, identifier := range identifiers {
+			event := aws.InitEvent(regionName, accountName, accountID, timestamp)
+			_, _ = event.RootFields.Put("aws.cloudwatch.namespace", "AWS/EC2")
+			_, _ = event.RootFields.Put("aws.dimensions.InstanceId", identifier)
+			events[identifier] = event
+		}
+		return events
+	}
+
+	m := MetricSet{config: MetricSetConfig{TombstonePeriods: 3}, seenResources: map[string]seenResource{}}
+	m.MetricSet = &aws.MetricSet{AccountID: accountID, AccountName: accountName}
+
+	m.trackResources(newEvents("i-1", "i-2"), regionName, 5*time.Minute)
+	assert.Equal(t, 2, len(m.seenResources))
+
+	reporter := &mbtest.CapturingReporterV2{}
+	m.reportGoneResources(reporter, time.Now().Add(10*time.Minute))
+	assert.Empty(t, reporter.GetEvents())
+
+	m.trackResources(newEvents("i-1"), regionName, 5*time.Minute)
+	m.seenResources[regionName+labelSeparator+"AWS/EC2"+labelSeparator+"i-2"] = seenResource{
+		regionName: regionName,
+		namespace:  "AWS/EC2",
+		identifier: "i-2",
+		dimensions: mapstr.M{"InstanceId": "i-2"},
+		period:     5 * time.Minute,
+		lastSeen:   time.Now().Add(-15 * time.Minute),
+	}
+
+	m.reportGoneResources(reporter, time.Now())
+	events := reporter.GetEvents()
+	assert.Equal(t, 1, len(events))
+	identifier, _ := events[0].RootFields.GetValue("aws.cloudwatch.resource.identifier")
+	assert.Equal(t, "i-2", identifier)
+	gone, _ := events[0].RootFields.GetValue("aws.cloudwatch.resource.gone")
+	assert.Equal(t, true, gone)
+	instanceID, _ := events[0].RootFields.GetValue("aws.dimensions.InstanceId")
+	assert.Equal(t, "i-2", instanceID)
+	assert.Equal(t, 1, len(m.seenResources))
+}
+
+func TestAliasDimensions(t *testing.T) {
+	m := MetricSet{config: MetricSetConfig{DimensionAliases: map[string]string{
+		"DBInstanceIdentifier": "aws.rds.db_instance.identifier",
+		"InstanceId":           "aws.ec2.instance.id",
+	}}}
+
+	event := aws.InitEvent(regionName, accountName, accountID, timestamp)
+	_, _ = event.RootFields.Put("aws.dimensions.DBInstanceIdentifier", "db-1")
+	_, _ = event.RootFields.Put("aws.dimensions.DatabaseClass", "db.t3.micro")
+	m.aliasDimensions(event)
+
+	identifier, err := event.RootFields.GetValue("aws.rds.db_instance.identifier")
+	assert.NoError(t, err)
+	assert.Equal(t, "db-1", identifier)
+	hasDimension, _ := event.RootFields.HasKey("aws.dimensions.DBInstanceIdentifier")
+	assert.False(t, hasDimension)
+	hasDimension, _ = event.RootFields.HasKey("aws.dimensions.DatabaseClass")
+	assert.True(t, hasDimension)
+
+	event = aws.InitEvent(regionName, accountName, accountID, timestamp)
+	_, _ = event.RootFields.Put("aws.dimensions.InstanceId", "i-1")
+	m.aliasDimensions(event)
+
+	instanceID, err := event.RootFields.GetValue("aws.ec2.instance.id")
+	assert.NoError(t, err)
+	assert.Equal(t, "i-1", instanceID)
+	hasDimensions, _ := event.RootFields.HasKey("aws.dimensions")
+	assert.False(t, hasDimensions)
+}
+
+func TestGetResourcesTagsPerResourceType(t *testing.T) {
+	m := MetricSet{}
+	m.MetricSet = &aws.MetricSet{Period: 5}
+	m.logger = logp.NewLogger("test")
+
+	resourceTypeTagFilters := map[string][]aws.Tag{}
+	for _, resourceType := range []string{"ec2:instance", "rds", "sqs", "elasticloadbalancing", "s3", "lambda", "dynamodb"} {
+		resourceTypeTagFilters[resourceType] = []aws.Tag{}
+	}
+
+	resourceTagMaps := m.getResourcesTagsPerResourceType(context.Background(), &MockResourceGroupsTaggingClient{}, nil, resourceTypeTagFilters, regionName)
+	assert.Equal(t, len(resourceTypeTagFilters), len(resourceTagMaps))
+	for resourceType := range resourceTypeTagFilters {
+		assert.Equal(t, "test-ec2", *resourceTagMaps[resourceType]["i-1"][0].Value)
+	}
+}
+
+func TestCollectionStateStore(t *testing.T) {
+	store := &collectionStateStore{states: map[uint64]*collectionState{}}
+	now := time.Now()
+
+	state, reloaded := store.claim(1, now)
+	assert.False(t, reloaded)
+	state.lastEndTimes[collectionWindow{period: 5 * time.Minute}] = now
+
+	// a metricset with the same module config running at the same time gets its own state
+	otherState, reloaded := store.claim(1, now)
+	assert.False(t, reloaded)
+	assert.Empty(t, otherState.lastEndTimes)
+
+	// after release, the next metricset continues with the state
+	store.release(state, now)
+	reloadedState, reloaded := store.claim(1, now.Add(time.Minute))
+	assert.True(t, reloaded)
+	assert.Equal(t, now, reloadedState.lastEndTimes[collectionWindow{period: 5 * time.Minute}])
+
+	// released states expire
+	store.release(reloadedState, now)
+	_, reloaded = store.claim(1, now.Add(stateReleaseTimeout+time.Minute))
+	assert.False(t, reloaded)
+}
+
+func TestEstimateConfiguredUsage(t *testing.T) {
+	m := MetricSet{}
+	m.MetricSet = &aws.MetricSet{Period: 5 * time.Minute, RegionsList: []string{"us-east-1", "us-west-2"}}
+	m.CloudwatchConfigs = []Config{
+		{
+			Namespace:  "AWS/EC2",
+			MetricName: []string{"CPUUtilization", "NetworkIn"},
+			Dimensions: []Dimension{{Name: "InstanceId", Value: "i-1"}},
+			Statistic:  []string{"Average", "Maximum"},
+		},
+		{Namespace: "AWS/SQS"},
+	}
+
+	usageByPeriod, discoveredNamespaces := m.estimateConfiguredUsage()
+	assert.Equal(t, 1, discoveredNamespaces)
+	assert.Equal(t, &apiUsage{getMetricDataCalls: 2, metricDataQueries: 8, listMetricsCalls: 2}, usageByPeriod[5*time.Minute])
+
+	// 8640 collections of 5 minutes in 30 days
+	estimate := estimateCost(usageByPeriod)
+	assert.Equal(t, float64(17280), estimate.getMetricDataCalls)
+	assert.Equal(t, float64(69120), estimate.metricDataQueries)
+	assert.Equal(t, float64(17280), estimate.listMetricsCalls)
+	assert.InDelta(t, 0.864, estimate.cost(), 0.0001)
+}
+
+func TestAPIUsage(t *testing.T) {
+	usage := &apiUsage{}
+	usage.addMetricData([]metricsWithStatistics{
+		{statistic: []string{"Average", "Maximum"}},
+		{statistic: []string{"Sum", "SampleCount"}},
+	}, 3)
+	usage.addListMetrics(1200)
+	assert.Equal(t, &apiUsage{getMetricDataCalls: 2, metricDataQueries: 4, listMetricsCalls: 3}, usage)
+
+	// backfill collections are not counted
+	var noUsage *apiUsage
+	noUsage.addMetricData([]metricsWithStatistics{{statistic: []string{"Average"}}}, 500)
+	noUsage.addListMetrics(10)
+}
+
+func TestProfileStateKey(t *testing.T) {
+	key, err := profileStateKey(1, "")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), key)
+
+	prodKey, err := profileStateKey(1, "prod")
+	assert.NoError(t, err)
+	devKey, err := profileStateKey(1, "dev")
+	assert.NoError(t, err)
+	assert.NotEqual(t, prodKey, devKey)
+	assert.NotEqual(t, uint64(1), prodKey)
+
+	sameKey, err := profileStateKey(1, "prod")
+	assert.NoError(t, err)
+	assert.Equal(t, prodKey, sameKey)
+}
+
+func TestDiffConfigs(t *testing.T) {
+	ec2Config := Config{Namespace: "AWS/EC2", Statistic: []string{"Average"}}
+	sqsConfig := Config{Namespace: "AWS/SQS", MetricName: []string{"NumberOfMessagesSent"}}
+	elbConfig := Config{Namespace: "AWS/ApplicationELB"}
+
+	added, removed := diffConfigs([]Config{ec2Config, sqsConfig}, []Config{ec2Config, elbConfig})
+	assert.Equal(t, []Config{elbConfig}, added)
+	assert.Equal(t, []Config{sqsConfig}, removed)
+
+	added, removed = diffConfigs([]Config{ec2Config}, []Config{ec2Config})
+	assert.Empty(t, added)
+	assert.Empty(t, removed)
+}
+
+func TestCollectionStateReload(t *testing.T) {
+	state := newCollectionState()
+	state.seenResources["ec2"] = seenResource{namespace: "AWS/EC2"}
+	state.seenResources["sqs"] = seenResource{namespace: "AWS/SQS"}
+	state.seenResources["custom"] = seenResource{namespace: "Custom/App"}
+
+	cloudwatchConfigs := []Config{{Namespace: "AWS/EC2"}, {Namespace: "Custom/*"}}
+	state.reload(cloudwatchConfigs)
+	assert.Equal(t, cloudwatchConfigs, state.cloudwatchConfigs)
+	assert.Equal(t, 2, len(state.seenResources))
+	assert.Contains(t, state.seenResources, "ec2")
+	assert.Contains(t, state.seenResources, "custom")
+}
+
+func TestReportUnobservedConfigs(t *testing.T) {
+	elbConfig := Config{Namespace: "AWS/ApplicationELB"}
+	typoConfig := Config{Namespace: "AWS/ApplicatoinELB"}
+	nameConfig := Config{Namespace: "AWS/ApplicationELB", MetricName: []string{"RequestCount"}}
+	explicitConfig := Config{
+		Namespace:  "AWS/ApplicationELB",
+		MetricName: []string{"RequestCount"},
+		Dimensions: []Dimension{{Name: "LoadBalancer", Value: "app/test"}},
+	}
+	cloudwatchConfigs := []Config{elbConfig, typoConfig, nameConfig, explicitConfig}
+
+	listMetricsOutput := []cloudwatchtypes.Metric{
+		{
+			MetricName: awssdk.String("ActiveConnectionCount"),
+			Namespace:  awssdk.String("AWS/ApplicationELB"),
+		},
+	}
+
+	m := MetricSet{config: MetricSetConfig{UnobservedFetches: 2}, observations: newConfigObservations()}
+	m.MetricSet = &aws.MetricSet{AccountID: accountID, AccountName: accountName}
+	m.logger = logp.NewLogger("test")
+	reporter := &mbtest.CapturingReporterV2{}
+
+	for i := 0; i < 2; i++ {
+		m.observations.check(cloudwatchConfigs)
+		m.observations.observe(cloudwatchConfigs, "AWS/ApplicationELB", listMetricsOutput)
+		m.observations.observe(cloudwatchConfigs, "AWS/ApplicatoinELB", nil)
+		m.reportUnobservedConfigs(reporter, time.Now())
+	}
+
+	events := reporter.GetEvents()
+	assert.Equal(t, 1, len(events))
+	unobserved, err := events[0].RootFields.GetValue("aws.cloudwatch.unobserved.configs")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"namespace: AWS/ApplicationELB, name: [RequestCount]",
+		"namespace: AWS/ApplicatoinELB",
+	}, unobserved)
+}
+
+func TestReportCardinality(t *testing.T) {
+	newMetric := func(name string, dimensions ...string) metricsWithStatistics {
+		metric := cloudwatchtypes.Metric{
+			MetricName: awssdk.String(name),
+			Namespace:  awssdk.String("AWS/EC2"),
+		}
+		for _, dimension := range dimensions {
+			metric.Dimensions = append(metric.Dimensions, cloudwatchtypes.Dimension{
+				Name:  awssdk.String("InstanceId"),
+				Value: awssdk.String(dimension),
+			})
+		}
+		return metricsWithStatistics{metric, []string{"Average"}}
+	}
+
+	m := MetricSet{config: MetricSetConfig{CardinalityReportInterval: 10 * time.Minute}, cardinality: map[cardinalityKey]*namespaceCardinality{}}
+	m.MetricSet = &aws.MetricSet{AccountID: accountID, AccountName: accountName}
+	reporter := &mbtest.CapturingReporterV2{}
+
+	now := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	m.countCardinality(regionName, []metricsWithStatistics{
+		newMetric("CPUUtilization", "i-1"),
+		newMetric("CPUUtilization", "i-2"),
+		newMetric("NetworkIn", "i-1"),
+	})
+	m.reportCardinality(reporter, now)
+	assert.Equal(t, 0, len(reporter.GetEvents()))
+
+	// metrics collected again in the same interval are counted once
+	m.countCardinality(regionName, []metricsWithStatistics{
+		newMetric("CPUUtilization", "i-1"),
+		newMetric("CPUUtilization"),
+	})
+	m.reportCardinality(reporter, now.Add(10*time.Minute))
+
+	events := reporter.GetEvents()
+	assert.Equal(t, 1, len(events))
+	expected := mapstr.M{
+		"namespace": "AWS/EC2",
+		"cardinality": mapstr.M{
+			"metrics":                4,
+			"metric_names":           2,
+			"dimension_combinations": 3,
+		},
+	}
+	cloudwatchFields, err := events[0].RootFields.GetValue("aws.cloudwatch")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, cloudwatchFields)
+	assert.Equal(t, 0, len(m.cardinality))
+}
+
+// failMetadata makes the enricher of the Test/Metadata namespace fail.
+var failMetadata bool
+
+func addTestMetadata(_ context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	if failMetadata {
+		return events, errors.New("metadata failure")
+	}
+	for _, event := range events {
+		_, _ = event.RootFields.Put("aws.test.enriched", true)
+	}
+	return events, nil
+}
+
+func TestEnrichEvents(t *testing.T) {
+	_ = metadata.Enrichers.Register("Test/Metadata", addTestMetadata)
+
+	newEvents := func() map[string]mb.Event {
+		event := aws.InitEvent(regionName, accountName, accountID, timestamp)
+		return map[string]mb.Event{"id-1": event}
+	}
+
+	cases := []struct {
+		title                  string
+		policy                 string
+		expectedFailedEvents   int
+		expectedRetriedEvents  int
+		expectedEnrichedEvents int
+	}{
+		{"keep", metadataFailurePolicyKeep, 1, 1, 1},
+		{"drop", metadataFailurePolicyDrop, 0, 1, 1},
+		{"retry", metadataFailurePolicyRetry, 0, 2, 2},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			m := MetricSet{
+				config:           MetricSetConfig{MetadataFailurePolicy: c.policy},
+				pendingEvents:    heldBackEvents{},
+				metadataFailures: monitoring.NewInt(monitoring.NewRegistry(), "metadata_failures"),
+			}
+			m.logger = logp.NewLogger("test")
+
+			failMetadata = true
+			heldBack := heldBackEvents{}
+			events := m.enrichEvents(context.Background(), "Test/Metadata", regionName, awssdk.Config{}, nil, newEvents(), heldBack)
+			m.pendingEvents.add(heldBack)
+			assert.Equal(t, c.expectedFailedEvents, len(events))
+			assert.Equal(t, int64(1), m.metadataFailures.Get())
+
+			failMetadata = false
+			heldBack = heldBackEvents{}
+			events = m.enrichEvents(context.Background(), "Test/Metadata", regionName, awssdk.Config{}, nil, newEvents(), heldBack)
+			m.pendingEvents.add(heldBack)
+			assert.Equal(t, c.expectedRetriedEvents, len(events))
+			enriched := 0
+			for _, event := range events {
+				if ok, _ := event.RootFields.HasKey("aws.test.enriched"); ok {
+					enriched++
+				}
+			}
+			assert.Equal(t, c.expectedEnrichedEvents, enriched)
+			assert.Empty(t, m.pendingEvents)
+		})
+	}
+}
+
+func TestEnrichEventsBackfillRetry(t *testing.T) {
+	_ = metadata.Enrichers.Register("Test/Metadata", addTestMetadata)
+
+	m := MetricSet{
+		config:           MetricSetConfig{MetadataFailurePolicy: metadataFailurePolicyRetry},
+		pendingEvents:    heldBackEvents{},
+		metadataFailures: monitoring.NewInt(monitoring.NewRegistry(), "metadata_failures"),
+	}
+	m.logger = logp.NewLogger("test")
+
+	newBatch := func(timestamp time.Time) map[string]mb.Event {
+		return map[string]mb.Event{"id-1": aws.InitEvent(regionName, accountName, accountID, timestamp)}
+	}
+	firstBatch := timestamp.Add(-5 * time.Minute)
+
+	// Both batches of a backfill fail and are held back, and the first one
+	// isn't retried by the second one in the same collection.
+	failMetadata = true
+	heldBack := heldBackEvents{}
+	assert.Empty(t, m.enrichEvents(context.Background(), "Test/Metadata", regionName, awssdk.Config{}, nil, newBatch(firstBatch), heldBack))
+	assert.Empty(t, m.enrichEvents(context.Background(), "Test/Metadata", regionName, awssdk.Config{}, nil, newBatch(timestamp), heldBack))
+	m.pendingEvents.add(heldBack)
+	assert.Len(t, m.pendingEvents[regionName+labelSeparator+"Test/Metadata"], 2)
+	assert.Equal(t, int64(2), m.metadataFailures.Get())
+
+	// Both batches are reported with metadata on the next collection.
+	failMetadata = false
+	events := m.enrichEvents(context.Background(), "Test/Metadata", regionName, awssdk.Config{}, nil, map[string]mb.Event{}, heldBackEvents{})
+	require.Len(t, events, 2)
+	timestamps := []time.Time{}
+	for _, event := range events {
+		enriched, _ := event.RootFields.HasKey("aws.test.enriched")
+		assert.True(t, enriched)
+		timestamps = append(timestamps, event.Timestamp)
+	}
+	assert.ElementsMatch(t, []time.Time{firstBatch, timestamp}, timestamps)
+	assert.Empty(t, m.pendingEvents)
+}
+
+func TestFetchDiscovery(t *testing.T) {
+	calls := map[string]int{}
+	list := func(input string, err error) func(ctx context.Context) (interface{}, error) {
+		return func(ctx context.Context) (interface{}, error) {
+			calls[input]++
+			return input, err
+		}
+	}
+
+	// The listings are shared by all the batches of a backfill, including the
+	// failed ones.
+	discovery := newFetchDiscovery(nil)
+	listErr := errors.New("throttled")
+	for i := 0; i < 3; i++ {
+		resources, err := metadata.Discover(context.Background(), discovery, "ec2:DescribeInstances", regionName, "a", list("a", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, "a", resources)
+
+		_, err = metadata.Discover(context.Background(), discovery, "ec2:DescribeInstances", regionName, "b", list("b", listErr))
+		assert.ErrorIs(t, err, listErr)
+	}
+	assert.Equal(t, map[string]int{"a": 1, "b": 1}, calls)
+
+	// The next collection lists the resources again.
+	discovery = newFetchDiscovery(nil)
+	_, err := metadata.Discover(context.Background(), discovery, "ec2:DescribeInstances", regionName, "a", list("a", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls["a"])
+}
+
+// failingHTTPClient fails all the requests of the AWS clients.
+type failingHTTPClient struct {
+	requests int
+}
+
+func (c *failingHTTPClient) Do(*http.Request) (*http.Response, error) {
+	c.requests++
+	return nil, errors.New("connection refused")
+}
+
+func TestEnrichEventsDescribeFailure(t *testing.T) {
+	newEvents := func() map[string]mb.Event {
+		event := aws.InitEvent(regionName, accountName, accountID, timestamp)
+		_, _ = event.RootFields.Put("aws.dimensions.FileSystemId", "fs-1")
+		return map[string]mb.Event{"fs-1": event}
+	}
+
+	cases := []struct {
+		title           string
+		policy          string
+		expectedPending int
+	}{
+		{"drop", metadataFailurePolicyDrop, 0},
+		{"retry", metadataFailurePolicyRetry, 1},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			m := MetricSet{
+				config:           MetricSetConfig{MetadataFailurePolicy: c.policy},
+				pendingEvents:    heldBackEvents{},
+				metadataFailures: monitoring.NewInt(monitoring.NewRegistry(), "metadata_failures"),
+			}
+			m.logger = logp.NewLogger("test")
+
+			// The EFS enricher fails to describe the file systems.
+			client := &failingHTTPClient{}
+			awsConfig := awssdk.Config{
+				Region:      regionName,
+				Credentials: awssdk.AnonymousCredentials{},
+				HTTPClient:  client,
+				Retryer:     func() awssdk.Retryer { return awssdk.NopRetryer{} },
+			}
+
+			heldBack := heldBackEvents{}
+			events := m.enrichEvents(context.Background(), "AWS/EFS", regionName, awsConfig, nil, newEvents(), heldBack)
+			m.pendingEvents.add(heldBack)
+			assert.Empty(t, events)
+			assert.NotZero(t, client.requests)
+			assert.Equal(t, int64(1), m.metadataFailures.Get())
+			assert.Len(t, m.pendingEvents[regionName+labelSeparator+"AWS/EFS"], c.expectedPending)
+
+			// Held back events are reported without metadata when the retry
+			// fails too, and the events of this fetch are held back again.
+			heldBack = heldBackEvents{}
+			events = m.enrichEvents(context.Background(), "AWS/EFS", regionName, awsConfig, nil, newEvents(), heldBack)
+			m.pendingEvents.add(heldBack)
+			assert.Len(t, events, c.expectedPending)
+			assert.Len(t, m.pendingEvents[regionName+labelSeparator+"AWS/EFS"], c.expectedPending)
+			for _, event := range events {
+				hasMetadata, _ := event.RootFields.HasKey("aws.efs")
+				assert.False(t, hasMetadata)
+			}
+		})
+	}
+}
+
+func TestLabelWithSeparatorsInDimensionValues(t *testing.T) {
+	metric := cloudwatchtypes.Metric{
+		Dimensions: []cloudwatchtypes.Dimension{
+			{
+				Name:  awssdk.String("Rule"),
+				Value: awssdk.String("allow,deny|log"),
+			},
+			{
+				Name:  awssdk.String("Path"),
+				Value: awssdk.String(`C:\data`),
+			},
+		},
+		MetricName: awssdk.String("BlockedRequests"),
+		Namespace:  awssdk.String("Custom/WAF"),
+	}
+
+	label := constructLabel(metric, "Sum")
+	assert.Equal(t, `BlockedRequests|Custom/WAF|Sum|Rule,Path|allow\,deny\|log,C:\\data`, label)
+
+	labels := parseLabel(label)
+	assert.Equal(t, 5, len(labels))
+	assert.Equal(t, "BlockedRequests", labels[metricNameIdx])
+	assert.Equal(t, "Custom/WAF", labels[namespaceIdx])
+	assert.Equal(t, "Sum", labels[statisticIdx])
+	assert.Equal(t, []string{"Rule", "Path"}, splitDimensions(labels[identifierNameIdx]))
+	assert.Equal(t, []string{"allow,deny|log", `C:\data`}, splitDimensions(labels[identifierValueIdx]))
+
+	m := MetricSet{}
+	event := m.insertRootFields(aws.InitEvent(regionName, accountName, accountID, timestamp), 1, labels)
+	rule, err := event.RootFields.GetValue("aws.dimensions.Rule")
+	assert.NoError(t, err)
+	assert.Equal(t, "allow,deny|log", rule)
+
+	// tags of a dimension value with a comma are found
+	identifier := labels[identifierValueIdx]
+	events := map[string]mb.Event{identifier: event}
+	insertTags(events, identifier, map[string][]resourcegroupstaggingapitypes.Tag{
+		"allow,deny|log": {{Key: awssdk.String("team"), Value: awssdk.String("security")}},
+	})
+	team, err := events[identifier].RootFields.GetValue("aws.tags.team")
+	assert.NoError(t, err)
+	assert.Equal(t, "security", team)
+}
+
+func TestReadCloudwatchConfigWithDefaultStatistics(t *testing.T) {
+	m := MetricSet{config: MetricSetConfig{DefaultStatistics: []string{"Average", "Maximum"}}}
+	m.MetricSet = &aws.MetricSet{}
+	cloudwatchConfigs := []Config{
+		{Namespace: "AWS/EC2"},
+		{Namespace: "AWS/SQS", Statistic: []string{"Sum"}},
+	}
+
+	_, namespaceDetailTotal := m.readCloudwatchConfig(cloudwatchConfigs)
+	assert.Equal(t, []string{"Average", "Maximum"}, namespaceDetailTotal["AWS/EC2"][0].statistics)
+	assert.Equal(t, []string{"Sum"}, namespaceDetailTotal["AWS/SQS"][0].statistics)
+
+	m.config.DefaultStatistics = nil
+	_, namespaceDetailTotal = m.readCloudwatchConfig(cloudwatchConfigs)
+	assert.Equal(t, defaultStatistics, namespaceDetailTotal["AWS/EC2"][0].statistics)
+
+	m.config.DefaultStatistics = []string{"Median"}
+	assert.Error(t, m.checkStatistics())
+}
+
+func TestFilterEvents(t *testing.T) {
+	newEvent := func(namespace string, dimensionName string, dimensionValue string) mb.Event {
+		event := aws.InitEvent(regionName, accountName, accountID, timestamp)
+		_, _ = event.RootFields.Put("aws.cloudwatch.namespace", namespace)
+		_, _ = event.RootFields.Put("aws.dimensions."+dimensionName, dimensionValue)
+		return event
+	}
+
+	cases := []struct {
+		title               string
+		eventFilters        []EventFilter
+		expectedIdentifiers []string
+	}{
+		{
+			"keep events matching pattern",
+			[]EventFilter{
+				{
+					Dimension: "QueueName",
+					Pattern:   match.MustCompile("^prod-"),
+				},
+			},
+			[]string{"prod-orders", "i-1"},
+		},
+		{
+			"drop events matching pattern",
+			[]EventFilter{
+				{
+					Dimension: "QueueName",
+					Pattern:   match.MustCompile("^prod-"),
+					Action:    eventFilterActionDrop,
+				},
+			},
+			[]string{"dev-orders", "i-1"},
+		},
+		{
+			"filter applies only to its namespace",
+			[]EventFilter{
+				{
+					Namespace: "AWS/EC2",
+					Dimension: "QueueName",
+					Pattern:   match.MustCompile("^prod-"),
+				},
+			},
+			[]string{"prod-orders", "dev-orders", "i-1"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			m := MetricSet{
+				logger: logp.NewLogger("test"),
+				config: MetricSetConfig{EventFilters: c.eventFilters},
+			}
+			events := map[string]mb.Event{
+				"prod-orders": newEvent("AWS/SQS", "QueueName", "prod-orders"),
+				"dev-orders":  newEvent("AWS/SQS", "QueueName", "dev-orders"),
+				"i-1":         newEvent("AWS/EC2", "InstanceId", "i-1"),
+			}
+
+			events = m.filterEvents(events)
+			var identifiers []string
+			for identifier := range events {
+				identifiers = append(identifiers, identifier)
+			}
+			assert.ElementsMatch(t, c.expectedIdentifiers, identifiers)
+		})
+	}
+}
+
+func TestLimitMetrics(t *testing.T) {
+	newMetric := func(name string, instanceID string) metricsWithStatistics {
+		return metricsWithStatistics{
+			cloudwatchtypes.Metric{
+				Dimensions: []cloudwatchtypes.Dimension{{
+					Name:  awssdk.String("InstanceId"),
+					Value: awssdk.String(instanceID),
+				}},
+				MetricName: awssdk.String(name),
+				Namespace:  awssdk.String("AWS/EC2"),
+			},
+			[]string{"Average"},
+		}
+	}
+
+	metricsWithStats := []metricsWithStatistics{
+		newMetric("NetworkIn", "i-2"),
+		newMetric("CPUUtilization", "i-2"),
+		newMetric("NetworkIn", "i-1"),
+		newMetric("CPUUtilization", "i-1"),
+	}
+
+	now := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	m := MetricSet{logger: logp.NewLogger("test")}
+	m.MetricSet = &aws.MetricSet{AccountID: accountID, AccountName: accountName}
+	reporter := &mbtest.CapturingReporterV2{}
+	assert.Equal(t, 4, len(m.limitMetrics(reporter, namespace, regionName, metricsWithStats, now)))
+	assert.Empty(t, reporter.GetEvents())
+
+	m.config.MaxMetricsPerNamespace = 3
+	limited := m.limitMetrics(reporter, namespace, regionName, metricsWithStats, now)
+	assert.Equal(t, []metricsWithStatistics{
+		newMetric("CPUUtilization", "i-1"),
+		newMetric("CPUUtilization", "i-2"),
+		newMetric("NetworkIn", "i-1"),
+	}, limited)
+
+	events := reporter.GetEvents()
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, now, events[0].Timestamp)
+	fields := events[0].RootFields
+	for field, expected := range map[string]interface{}{
+		"cloud.region":                             regionName,
+		"cloud.account.id":                         accountID,
+		"aws.cloudwatch.namespace":                 namespace,
+		"aws.cloudwatch.truncated.dropped_metrics": 1,
+		"aws.cloudwatch.truncated.kept_metrics":    3,
+	} {
+		value, err := fields.GetValue(field)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value, field)
+	}
+}
+
+func TestReloadMetricsFile(t *testing.T) {
+	metricsPath := filepath.Join(t.TempDir(), "cloudwatch-metrics.yml")
+	writeMetricsFile := func(content string, modTime time.Time) {
+		assert.NoError(t, os.WriteFile(metricsPath, []byte(content), 0600))
+		assert.NoError(t, os.Chtimes(metricsPath, modTime, modTime))
+	}
+
+	modTime := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	writeMetricsFile("metrics:\n  - namespace: AWS/EC2\n    resource_type: ec2:instance\n", modTime)
+
+	inlineConfigs := []Config{{Namespace: "AWS/S3"}}
+	m := MetricSet{
+		MetricSet:         &aws.MetricSet{Period: 5 * time.Minute},
+		logger:            logp.NewLogger("test"),
+		CloudwatchConfigs: inlineConfigs,
+		config:            MetricSetConfig{CloudwatchMetrics: inlineConfigs, MetricsPath: metricsPath},
+		state:             newCollectionState(),
+	}
+
+	assert.NoError(t, m.reloadMetricsFile())
+	assert.Equal(t, []Config{
+		{Namespace: "AWS/S3"},
+		{Namespace: "AWS/EC2", ResourceType: "ec2:instance"},
+	}, m.CloudwatchConfigs)
+	assert.Equal(t, m.CloudwatchConfigs, m.state.cloudwatchConfigs)
+
+	// the file is not reloaded when it did not change
+	m.CloudwatchConfigs = inlineConfigs
+	assert.NoError(t, m.reloadMetricsFile())
+	assert.Equal(t, inlineConfigs, m.CloudwatchConfigs)
+
+	modTime = modTime.Add(time.Minute)
+	writeMetricsFile(`{"metrics": [{"namespace": "AWS/ELB"}]}`, modTime)
+	assert.NoError(t, m.reloadMetricsFile())
+	assert.Equal(t, []Config{{Namespace: "AWS/S3"}, {Namespace: "AWS/ELB"}}, m.CloudwatchConfigs)
+
+	// invalid metrics configs are not applied
+	modTime = modTime.Add(time.Minute)
+	writeMetricsFile("metrics:\n  - namespace: AWS/EC2\n    period: 1m\n", modTime)
+	assert.Error(t, m.reloadMetricsFile())
+	assert.Equal(t, []Config{{Namespace: "AWS/S3"}, {Namespace: "AWS/ELB"}}, m.CloudwatchConfigs)
+
+	assert.NoError(t, os.Remove(metricsPath))
+	assert.Error(t, m.reloadMetricsFile())
+	assert.Equal(t, []Config{{Namespace: "AWS/S3"}, {Namespace: "AWS/ELB"}}, m.CloudwatchConfigs)
+}
+
+// MockCloudWatchClientInsightRules struct is used for unit tests.
+type MockCloudWatchClientInsightRules struct{}
+
+// GetInsightRuleReport implements getInsightRuleReportAPI interface
+func (m *MockCloudWatchClientInsightRules) GetInsightRuleReport(_ context.Context, params *cloudwatch.GetInsightRuleReportInput, _ ...func(*cloudwatch.Options)) (*cloudwatch.GetInsightRuleReportOutput, error) {
+	if *params.RuleName != "top-talkers" {
+		return nil, errors.New("ResourceNotFoundException")
+	}
+	return &cloudwatch.GetInsightRuleReportOutput{
+		AggregateValue:         awssdk.Float64(300),
+		AggregationStatistic:   awssdk.String("Sum"),
+		ApproximateUniqueCount: awssdk.Int64(2),
+		KeyLabels:              []string{"$.srcaddr", "$.dstaddr"},
+		Contributors: []cloudwatchtypes.InsightRuleContributor{
+			{
+				ApproximateAggregateValue: awssdk.Float64(200),
+				Keys:                      []string{"10.0.0.1", "10.0.0.2"},
+			},
+			{
+				ApproximateAggregateValue: awssdk.Float64(100),
+				Keys:                      []string{"10.0.0.3", "10.0.0.2"},
+			},
+		},
+	}, nil
+}
+
+func TestCreateInsightRuleEvents(t *testing.T) {
+	m := MetricSet{
+		config: MetricSetConfig{InsightRules: []InsightRuleConfig{{Name: "top-talkers", MaxContributors: 2}, {Name: "missing"}}},
+		logger: logp.NewLogger("test"),
+	}
+	m.MetricSet = &aws.MetricSet{Period: 5 * time.Minute, AccountID: accountID}
+
+	endTime := time.Date(2022, 6, 1, 0, 5, 0, 0, time.UTC)
+	events := m.createInsightRuleEvents(context.Background(), &MockCloudWatchClientInsightRules{}, regionName, endTime.Add(-5*time.Minute), endTime)
+	assert.Equal(t, 3, len(events))
+
+	rule, err := events[0].RootFields.GetValue("aws.cloudwatch.insight_rule")
+	assert.NoError(t, err)
+	assert.Equal(t, mapstr.M{
+		"name":                  "top-talkers",
+		"aggregation_statistic": "Sum",
+		"aggregate_value":       float64(300),
+		"unique_contributors":   int64(2),
+	}, rule)
+
+	rule, err = events[2].RootFields.GetValue("aws.cloudwatch.insight_rule")
+	assert.NoError(t, err)
+	assert.Equal(t, mapstr.M{
+		"name": "top-talkers",
+		"contributor": mapstr.M{
+			"rank":  2,
+			"keys":  mapstr.M{"$.srcaddr": "10.0.0.3", "$.dstaddr": "10.0.0.2"},
+			"value": float64(100),
+		},
+	}, rule)
+	assert.Equal(t, endTime, events[2].Timestamp)
+}
+
+// MockPIClient struct is used for unit tests.
+type MockPIClient struct{}
+
+// GetResourceMetrics implements getResourceMetricsAPI interface
+func (m *MockPIClient) GetResourceMetrics(_ context.Context, params *pi.GetResourceMetricsInput, _ ...func(*pi.Options)) (*pi.GetResourceMetricsOutput, error) {
+	if *params.Identifier != "db-ABCDEFGHIJKLMNOP" {
+		return nil, errors.New("NotAuthorizedException")
+	}
+	dataPoints := func(values ...float64) []pitypes.DataPoint {
+		var points []pitypes.DataPoint
+		for _, value := range values {
+			points = append(points, pitypes.DataPoint{Timestamp: awssdk.Time(timestamp), Value: awssdk.Float64(value)})
+		}
+		return points
+	}
+	return &pi.GetResourceMetricsOutput{
+		MetricList: []pitypes.MetricKeyDataPoints{
+			{
+				Key:        &pitypes.ResponseResourceMetricKey{Metric: awssdk.String(dbLoadMetric)},
+				DataPoints: dataPoints(2, 4),
+			},
+			{
+				Key: &pitypes.ResponseResourceMetricKey{
+					Metric:     awssdk.String(dbLoadMetric),
+					Dimensions: map[string]string{"db.wait_event.name": "CPU", "db.wait_event.type": "CPU"},
+				},
+				DataPoints: dataPoints(1.5, 2.5),
+			},
+			{
+				Key: &pitypes.ResponseResourceMetricKey{
+					Metric: awssdk.String(dbLoadMetric),
+					Dimensions: map[string]string{
+						"db.sql_tokenized.id":        "4C3A1F0E8E5B2D7A9F61",
+						"db.sql_tokenized.statement": "SELECT * FROM orders WHERE id = ?",
+					},
+				},
+				DataPoints: []pitypes.DataPoint{{Timestamp: awssdk.Time(timestamp), Value: awssdk.Float64(1)}, {Timestamp: awssdk.Time(timestamp)}},
+			},
+		},
+	}, nil
+}
+
+func TestCreatePerformanceInsightsEvents(t *testing.T) {
+	m := MetricSet{logger: logp.NewLogger("test")}
+	m.MetricSet = &aws.MetricSet{Period: 5 * time.Minute, AccountID: accountID}
+
+	instances := []performanceInsightsInstance{
+		{identifier: "orders-db", resourceID: "db-ABCDEFGHIJKLMNOP"},
+		{identifier: "denied-db", resourceID: "db-QRSTUVWXYZ012345"},
+	}
+	endTime := time.Date(2022, 6, 1, 0, 5, 0, 0, time.UTC)
+	events := m.createPerformanceInsightsEvents(context.Background(), &MockPIClient{}, regionName, instances, endTime.Add(-5*time.Minute), endTime)
+	assert.Equal(t, 3, len(events))
+
+	performanceInsights, err := events[0].RootFields.GetValue("aws.rds.performance_insights")
+	assert.NoError(t, err)
+	assert.Equal(t, mapstr.M{
+		"group":   "instance",
+		"db_load": mapstr.M{"avg": float64(3)},
+	}, performanceInsights)
+
+	performanceInsights, err = events[1].RootFields.GetValue("aws.rds.performance_insights")
+	assert.NoError(t, err)
+	assert.Equal(t, mapstr.M{
+		"group":      "wait_event",
+		"rank":       1,
+		"db_load":    mapstr.M{"avg": float64(2)},
+		"wait_event": mapstr.M{"name": "CPU", "type": "CPU"},
+	}, performanceInsights)
+
+	performanceInsights, err = events[2].RootFields.GetValue("aws.rds.performance_insights")
+	assert.NoError(t, err)
+	assert.Equal(t, mapstr.M{
+		"group":   "sql",
+		"rank":    1,
+		"db_load": mapstr.M{"avg": float64(1)},
+		"sql":     mapstr.M{"id": "4C3A1F0E8E5B2D7A9F61", "statement": "SELECT * FROM orders WHERE id = ?"},
+	}, performanceInsights)
+
+	identifier, err := events[2].RootFields.GetValue("aws.rds.db_instance.identifier")
+	assert.NoError(t, err)
+	assert.Equal(t, "orders-db", identifier)
+	assert.Equal(t, endTime, events[2].Timestamp)
+}
+
+func TestCrossRegionAggregation(t *testing.T) {
+	newEvent := func(regionName string, loadBalancer string, requestCount float64) mb.Event {
+		event := aws.InitEvent(regionName, accountName, accountID, time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC))
+		_, _ = event.RootFields.Put("aws.cloudwatch.namespace", "AWS/ApplicationELB")
+		_, _ = event.RootFields.Put("aws.dimensions.LoadBalancer", loadBalancer)
+		_, _ = event.RootFields.Put("aws.applicationelb.metrics.RequestCount.sum", requestCount)
+		return event
+	}
+
+	m := MetricSet{config: MetricSetConfig{CrossRegionAggregation: []string{crossRegionAggregationSum, crossRegionAggregationAvg}}}
+	m.MetricSet = &aws.MetricSet{AccountID: accountID, AccountName: accountName}
+	reporter := &mbtest.CapturingReporterV2{}
+
+	aggregates := map[string]*crossRegionMetrics{}
+	m.aggregateCrossRegion(aggregates, "us-east-1", map[string]mb.Event{
+		"app/web": newEvent("us-east-1", "app/web", 10),
+		"app/api": newEvent("us-east-1", "app/api", 5),
+	})
+	m.aggregateCrossRegion(aggregates, "eu-west-1", map[string]mb.Event{
+		"app/web": newEvent("eu-west-1", "app/web", 30),
+	})
+	m.reportCrossRegion(reporter, aggregates)
+
+	events := reporter.GetEvents()
+	assert.Equal(t, 4, len(events))
+
+	expected := []struct {
+		loadBalancer string
+		aggregation  string
+		regions      []string
+		requestCount float64
+	}{
+		{"app/api", "sum", []string{"us-east-1"}, 5},
+		{"app/api", "avg", []string{"us-east-1"}, 5},
+		{"app/web", "sum", []string{"eu-west-1", "us-east-1"}, 40},
+		{"app/web", "avg", []string{"eu-west-1", "us-east-1"}, 20},
+	}
+	for i, e := range expected {
+		fields := events[i].RootFields
+		loadBalancer, _ := fields.GetValue("aws.dimensions.LoadBalancer")
+		assert.Equal(t, e.loadBalancer, loadBalancer)
+		aggregation, _ := fields.GetValue("aws.cloudwatch.cross_region.aggregation")
+		assert.Equal(t, e.aggregation, aggregation)
+		regions, _ := fields.GetValue("aws.cloudwatch.cross_region.regions")
+		assert.Equal(t, e.regions, regions)
+		requestCount, _ := fields.GetValue("aws.applicationelb.metrics.RequestCount.sum")
+		assert.Equal(t, e.requestCount, requestCount)
+		_, err := fields.GetValue("cloud.region")
+		assert.Error(t, err)
+	}
+}
+
+func TestMergeAgentMetrics(t *testing.T) {
+	newEvent := func(namespace string, dimensions map[string]string, metrics mapstr.M) mb.Event {
+		event := aws.InitEvent(regionName, accountName, accountID, time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC))
+		_, _ = event.RootFields.Put("aws.cloudwatch.namespace", namespace)
+		for name, value := range dimensions {
+			_, _ = event.RootFields.Put("aws.dimensions."+name, value)
+		}
+		_, _ = event.RootFields.Put("aws."+stripNamespace(namespace)+".metrics", metrics)
+		return event
+	}
+
+	ec2Events := []mb.Event{
+		newEvent(ec2Namespace, map[string]string{"InstanceId": "i-1"}, mapstr.M{"CPUUtilization": mapstr.M{"avg": 12.5}}),
+		newEvent(ec2Namespace, map[string]string{"InstanceId": "i-2"}, mapstr.M{"CPUUtilization": mapstr.M{"avg": 3.0}}),
+	}
+	agentEvents := []mb.Event{
+		newEvent(agentNamespace, map[string]string{"InstanceId": "i-1", "ImageId": "ami-1", "InstanceType": "t3.micro"}, mapstr.M{"mem_used_percent": mapstr.M{"avg": 40.0}}),
+		newEvent(agentNamespace, map[string]string{"InstanceId": "i-1"}, mapstr.M{"disk_used_percent": mapstr.M{"avg": 55.0}}),
+		newEvent(agentNamespace, map[string]string{"InstanceId": "i-1", "path": "/data"}, mapstr.M{"disk_used_percent": mapstr.M{"avg": 80.0}}),
+		newEvent(agentNamespace, map[string]string{"InstanceId": "i-3"}, mapstr.M{"mem_used_percent": mapstr.M{"avg": 10.0}}),
+	}
+
+	m := MetricSet{config: MetricSetConfig{MergeAgentMetrics: true}}
+	events := m.mergeAgentMetrics(ec2Events, agentEvents)
+	assert.Equal(t, 4, len(events))
+
+	merged := events[0].RootFields
+	cpu, _ := merged.GetValue("aws.ec2.metrics.CPUUtilization.avg")
+	assert.Equal(t, 12.5, cpu)
+	memory, _ := merged.GetValue("aws.cwagent.metrics.mem_used_percent.avg")
+	assert.Equal(t, 40.0, memory)
+	disk, _ := merged.GetValue("aws.cwagent.metrics.disk_used_percent.avg")
+	assert.Equal(t, 55.0, disk)
+	mergedNamespace, _ := merged.GetValue("aws.cloudwatch.merged_namespace")
+	assert.Equal(t, agentNamespace, mergedNamespace)
+
+	_, err := events[1].RootFields.GetValue("aws.cloudwatch.merged_namespace")
+	assert.Error(t, err)
+
+	path, _ := events[2].RootFields.GetValue("aws.dimensions.path")
+	assert.Equal(t, "/data", path)
+	instanceID, _ := events[3].RootFields.GetValue("aws.dimensions.InstanceId")
+	assert.Equal(t, "i-3", instanceID)
+}
@@ -0,0 +1,146 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cloudwatch
+
+import (
+	"time"
+
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws"
+)
+
+// CloudWatch API prices in US dollars, in the us-east-1 region. The prices of
+// other regions can be higher, and the free tier is not taken into account.
+const (
+	// getMetricDataPricePerMetric is the price of each metric statistic
+	// requested with GetMetricData.
+	getMetricDataPricePerMetric = 0.01 / 1000
+	// listMetricsPricePerRequest is the price of a ListMetrics request.
+	listMetricsPricePerRequest = 0.01 / 1000
+)
+
+const (
+	// listMetricsPageSize is the number of metrics in a ListMetrics page.
+	listMetricsPageSize = 500
+	// costEstimateMonth is the duration the API usage is extrapolated to.
+	costEstimateMonth = 30 * 24 * time.Hour
+)
+
+// apiUsage counts the CloudWatch API requests of the collections of a period.
+type apiUsage struct {
+	getMetricDataCalls int
+	metricDataQueries  int
+	listMetricsCalls   int
+}
+
+// addMetricData counts the GetMetricData requests of the metrics, one query per
+// metric and statistic. Nothing is counted on a nil apiUsage.
+func (u *apiUsage) addMetricData(metricsWithStats []metricsWithStatistics, queriesPerRequest int) {
+	if u == nil {
+		return
+	}
+	queries := 0
+	for _, metricWithStats := range metricsWithStats {
+		queries += len(metricWithStats.statistic)
+	}
+	if queries == 0 {
+		return
+	}
+	u.metricDataQueries += queries
+	u.getMetricDataCalls += (queries + queriesPerRequest - 1) / queriesPerRequest
+}
+
+// addListMetrics counts the ListMetrics requests listing the given number of
+// metrics. Nothing is counted on a nil apiUsage.
+func (u *apiUsage) addListMetrics(metrics int) {
+	if u == nil {
+		return
+	}
+	u.listMetricsCalls += metrics/listMetricsPageSize + 1
+}
+
+// costEstimate is the estimated CloudWatch API usage of a month.
+type costEstimate struct {
+	getMetricDataCalls float64
+	metricDataQueries  float64
+	listMetricsCalls   float64
+}
+
+// estimateCost extrapolates the API usage of a collection of each period to a
+// month.
+func estimateCost(usageByPeriod map[time.Duration]*apiUsage) costEstimate {
+	var estimate costEstimate
+	for period, usage := range usageByPeriod {
+		if period <= 0 {
+			continue
+		}
+		collections := float64(costEstimateMonth) / float64(period)
+		estimate.getMetricDataCalls += collections * float64(usage.getMetricDataCalls)
+		estimate.metricDataQueries += collections * float64(usage.metricDataQueries)
+		estimate.listMetricsCalls += collections * float64(usage.listMetricsCalls)
+	}
+	return estimate
+}
+
+// cost returns the estimated cost in US dollars.
+func (e costEstimate) cost() float64 {
+	return e.metricDataQueries*getMetricDataPricePerMetric + e.listMetricsCalls*listMetricsPricePerRequest
+}
+
+// queriesPerRequest returns the number of MetricDataQueries sent in each
+// GetMetricData request.
+func (m *MetricSet) queriesPerRequest() int {
+	if m.config.QueriesPerRequest > 0 {
+		return m.config.QueriesPerRequest
+	}
+	return aws.MaxMetricDataQueriesPerRequest
+}
+
+// estimateConfiguredUsage estimates the API usage of a collection from the
+// metrics configs, before any metric is discovered. The namespaces whose
+// metrics are listed with ListMetrics are counted with a single ListMetrics
+// request and no metrics, and their number is returned.
+func (m *MetricSet) estimateConfiguredUsage() (map[time.Duration]*apiUsage, int) {
+	usageByPeriod := map[time.Duration]*apiUsage{}
+	discoveredNamespaces := 0
+	for window, cloudwatchConfigs := range m.groupConfigs() {
+		usage, ok := usageByPeriod[window.period]
+		if !ok {
+			usage = &apiUsage{}
+			usageByPeriod[window.period] = usage
+		}
+
+		listMetricDetailTotal, namespaceDetailTotal := m.readCloudwatchConfig(cloudwatchConfigs)
+		for range m.MetricSet.RegionsList {
+			usage.addMetricData(listMetricDetailTotal.metricsWithStats, m.queriesPerRequest())
+			for range namespaceDetailTotal {
+				usage.addListMetrics(0)
+			}
+		}
+		discoveredNamespaces += len(namespaceDetailTotal)
+	}
+	return usageByPeriod, discoveredNamespaces
+}
+
+// logConfiguredCostEstimate logs the monthly API usage and cost estimated from
+// the metrics configs when the metricset is created.
+func (m *MetricSet) logConfiguredCostEstimate() {
+	usageByPeriod, discoveredNamespaces := m.estimateConfiguredUsage()
+	estimate := estimateCost(usageByPeriod)
+	if discoveredNamespaces > 0 {
+		m.logger.Infof("Estimated CloudWatch API usage per month before discovering the metrics of %d namespaces: %.0f GetMetricData requests of %.0f metrics and %.0f ListMetrics requests, at least $%.2f at us-east-1 prices. The estimate is updated after the first collection.",
+			discoveredNamespaces, estimate.getMetricDataCalls, estimate.metricDataQueries, estimate.listMetricsCalls, estimate.cost())
+		return
+	}
+	m.logger.Infof("Estimated CloudWatch API usage per month: %.0f GetMetricData requests of %.0f metrics, about $%.2f at us-east-1 prices",
+		estimate.getMetricDataCalls, estimate.metricDataQueries, estimate.cost())
+}
+
+// logCollectedCostEstimate logs the monthly API usage and cost estimated from
+// the requests of the first collection.
+func (m *MetricSet) logCollectedCostEstimate(usageByPeriod map[time.Duration]*apiUsage) {
+	estimate := estimateCost(usageByPeriod)
+	m.logger.Infof("Estimated CloudWatch API usage per month from the first collection: %.0f GetMetricData requests of %.0f metrics and %.0f ListMetrics requests, about $%.2f at us-east-1 prices",
+		estimate.getMetricDataCalls, estimate.metricDataQueries, estimate.listMetricsCalls, estimate.cost())
+}
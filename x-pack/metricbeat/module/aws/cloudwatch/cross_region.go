@@ -0,0 +1,170 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cloudwatch
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+const (
+	crossRegionAggregationSum = "sum"
+	crossRegionAggregationAvg = "avg"
+)
+
+// crossRegionMetrics holds the values of the same metrics, identified by their
+// namespace and dimensions, collected from different regions.
+type crossRegionMetrics struct {
+	event   mb.Event
+	regions []string
+	values  map[string][]float64
+}
+
+// crossRegionBatches holds the cross region aggregates of each batch of events,
+// keyed by the timestamp of the batch.
+type crossRegionBatches map[time.Time]map[string]*crossRegionMetrics
+
+// get returns the aggregates of the batch with the given timestamp.
+func (b crossRegionBatches) get(timestamp time.Time) map[string]*crossRegionMetrics {
+	aggregates, ok := b[timestamp]
+	if !ok {
+		aggregates = map[string]*crossRegionMetrics{}
+		b[timestamp] = aggregates
+	}
+	return aggregates
+}
+
+// timestamps returns the timestamps of the batches, oldest first.
+func (b crossRegionBatches) timestamps() []time.Time {
+	timestamps := make([]time.Time, 0, len(b))
+	for timestamp := range b {
+		timestamps = append(timestamps, timestamp)
+	}
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i].Before(timestamps[j]) })
+	return timestamps
+}
+
+// aggregateCrossRegion adds the metric values of the events collected from a
+// region to the cross region aggregates.
+func (m *MetricSet) aggregateCrossRegion(aggregates map[string]*crossRegionMetrics, regionName string, events map[string]mb.Event) {
+	if len(m.config.CrossRegionAggregation) == 0 {
+		return
+	}
+
+	for _, event := range events {
+		namespace, err := event.RootFields.GetValue("aws.cloudwatch.namespace")
+		if err != nil {
+			continue
+		}
+		metricsField := "aws." + stripNamespace(namespace.(string)) + ".metrics"
+		if m.config.GenericMetricFields {
+			metricsField = "aws." + metricsetName + ".metrics"
+		}
+		metrics, err := event.RootFields.GetValue(metricsField)
+		if err != nil {
+			continue
+		}
+		metricsMap, ok := metrics.(mapstr.M)
+		if !ok {
+			continue
+		}
+
+		dimensions, _ := event.RootFields.GetValue("aws.dimensions")
+		dimensionsMap, _ := dimensions.(mapstr.M)
+		key := crossRegionKey(namespace.(string), dimensionsMap)
+
+		aggregate, ok := aggregates[key]
+		if !ok {
+			aggregate = &crossRegionMetrics{
+				event:  aws.InitEvent("", m.AccountName, m.AccountID, event.Timestamp),
+				values: map[string][]float64{},
+			}
+			_, _ = aggregate.event.RootFields.Put("aws.cloudwatch.namespace", namespace)
+			if len(dimensionsMap) > 0 {
+				_, _ = aggregate.event.RootFields.Put("aws.dimensions", dimensionsMap.Clone())
+			}
+			aggregates[key] = aggregate
+		}
+		if event.Timestamp.After(aggregate.event.Timestamp) {
+			aggregate.event.Timestamp = event.Timestamp
+		}
+		if !containsString(aggregate.regions, regionName) {
+			aggregate.regions = append(aggregate.regions, regionName)
+		}
+
+		for field, value := range metricsMap.Flatten() {
+			if value, ok := value.(float64); ok {
+				name := metricsField + "." + field
+				aggregate.values[name] = append(aggregate.values[name], value)
+			}
+		}
+	}
+}
+
+// reportCrossRegion reports, for each configured aggregation, an event per
+// metrics aggregated across regions, with the same fields as the events of
+// each region.
+func (m *MetricSet) reportCrossRegion(report mb.ReporterV2, aggregates map[string]*crossRegionMetrics) {
+	keys := make([]string, 0, len(aggregates))
+	for key := range aggregates {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		aggregate := aggregates[key]
+		sort.Strings(aggregate.regions)
+		for _, aggregation := range m.config.CrossRegionAggregation {
+			event := mb.Event{
+				Timestamp:       aggregate.event.Timestamp,
+				MetricSetFields: mapstr.M{},
+				ModuleFields:    mapstr.M{},
+				RootFields:      aggregate.event.RootFields.Clone(),
+			}
+			_, _ = event.RootFields.Put("aws.cloudwatch.cross_region.aggregation", aggregation)
+			_, _ = event.RootFields.Put("aws.cloudwatch.cross_region.regions", aggregate.regions)
+			for field, values := range aggregate.values {
+				_, _ = event.RootFields.Put(field, aggregateValues(aggregation, values))
+			}
+			report.Event(event)
+		}
+	}
+}
+
+// crossRegionKey identifies metrics by their namespace and dimensions.
+func crossRegionKey(namespace string, dimensions mapstr.M) string {
+	pairs := make([]string, 0, len(dimensions))
+	for name, value := range dimensions.Flatten() {
+		pairs = append(pairs, escapeLabelPart(name)+"="+escapeLabelPart(fmt.Sprint(value)))
+	}
+	sort.Strings(pairs)
+	return namespace + labelSeparator + strings.Join(pairs, dimensionSeparator)
+}
+
+func aggregateValues(aggregation string, values []float64) float64 {
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+	if aggregation == crossRegionAggregationAvg {
+		return sum / float64(len(values))
+	}
+	return sum
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
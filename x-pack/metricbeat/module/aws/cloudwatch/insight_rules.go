@@ -0,0 +1,116 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cloudwatch
+
+import (
+	"context"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+// InsightRuleConfig holds the configuration of a Contributor Insights rule
+// whose report is collected.
+type InsightRuleConfig struct {
+	Name            string `config:"name" validate:"nonzero,required"`
+	MaxContributors int32  `config:"max_contributors" validate:"min=0"`
+	OrderBy         string `config:"order_by"`
+}
+
+// getInsightRuleReportAPI is the part of the cloudwatch client used to get
+// Contributor Insights rule reports.
+type getInsightRuleReportAPI interface {
+	GetInsightRuleReport(ctx context.Context, params *cloudwatch.GetInsightRuleReportInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetInsightRuleReportOutput, error)
+}
+
+// collectInsightRules reports the Contributor Insights rule reports of the last
+// period from each region.
+func (m *MetricSet) collectInsightRules(ctx context.Context, report mb.ReporterV2, now time.Time) {
+	if len(m.config.InsightRules) == 0 {
+		return
+	}
+
+	startTime, endTime := m.getStartTimeEndTime(now, m.Period, m.Latency)
+	for _, regionName := range m.MetricSet.RegionsList {
+		beatsConfig := m.MetricSet.AwsConfig.Copy()
+		beatsConfig.Region = regionName
+
+		svcCloudwatch, _, err := m.createAwsRequiredClients(beatsConfig, regionName)
+		if err != nil {
+			m.logger.Warnf("skipping insight rules from region '%s'", regionName)
+			continue
+		}
+
+		for _, event := range m.createInsightRuleEvents(ctx, svcCloudwatch, regionName, startTime, endTime) {
+			report.Event(event)
+		}
+	}
+}
+
+// createInsightRuleEvents returns, for each configured rule, an event with the
+// aggregate value of the rule and an event for each of its top contributors.
+// Rules that cannot be reported, for example because they do not exist in the
+// region, are skipped.
+func (m *MetricSet) createInsightRuleEvents(ctx context.Context, svc getInsightRuleReportAPI, regionName string, startTime time.Time, endTime time.Time) []mb.Event {
+	var events []mb.Event
+	for _, rule := range m.config.InsightRules {
+		input := &cloudwatch.GetInsightRuleReportInput{
+			RuleName:  awssdk.String(rule.Name),
+			StartTime: &startTime,
+			EndTime:   &endTime,
+			Period:    awssdk.Int32(int32(m.Period.Seconds())),
+		}
+		if rule.MaxContributors > 0 {
+			input.MaxContributorCount = awssdk.Int32(rule.MaxContributors)
+		}
+		if rule.OrderBy != "" {
+			input.OrderBy = awssdk.String(rule.OrderBy)
+		}
+
+		output, err := svc.GetInsightRuleReport(ctx, input)
+		if err != nil {
+			m.logger.Warnf("GetInsightRuleReport of rule %s failed in region %s: %s", rule.Name, regionName, err)
+			continue
+		}
+
+		event := aws.InitEvent(regionName, m.AccountName, m.AccountID, endTime)
+		_, _ = event.RootFields.Put("aws.cloudwatch.insight_rule.name", rule.Name)
+		if output.AggregationStatistic != nil {
+			_, _ = event.RootFields.Put("aws.cloudwatch.insight_rule.aggregation_statistic", *output.AggregationStatistic)
+		}
+		if output.AggregateValue != nil {
+			_, _ = event.RootFields.Put("aws.cloudwatch.insight_rule.aggregate_value", *output.AggregateValue)
+		}
+		if output.ApproximateUniqueCount != nil {
+			_, _ = event.RootFields.Put("aws.cloudwatch.insight_rule.unique_contributors", *output.ApproximateUniqueCount)
+		}
+		events = append(events, event)
+
+		for rank, contributor := range output.Contributors {
+			event := aws.InitEvent(regionName, m.AccountName, m.AccountID, endTime)
+			_, _ = event.RootFields.Put("aws.cloudwatch.insight_rule.name", rule.Name)
+			_, _ = event.RootFields.Put("aws.cloudwatch.insight_rule.contributor.rank", rank+1)
+			// Keys are in the same order as the key labels of the rule. Labels
+			// can contain dots, so they are not used as paths.
+			keys := mapstr.M{}
+			for i, key := range contributor.Keys {
+				if i < len(output.KeyLabels) {
+					keys[output.KeyLabels[i]] = key
+				}
+			}
+			_, _ = event.RootFields.Put("aws.cloudwatch.insight_rule.contributor.keys", keys)
+			if contributor.ApproximateAggregateValue != nil {
+				_, _ = event.RootFields.Put("aws.cloudwatch.insight_rule.contributor.value", *contributor.ApproximateAggregateValue)
+			}
+			events = append(events, event)
+		}
+	}
+	return events
+}
@@ -5,41 +5,63 @@
 package cloudwatch
 
 import (
+	"context"
 	"fmt"
 
 	awssdk "github.com/aws/aws-sdk-go-v2/aws"
 
 	"github.com/elastic/beats/v7/metricbeat/mb"
-	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/ec2"
-	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/rds"
-	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/sqs"
-)
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
 
-// AWS namespaces
-const (
-	namespaceEC2 = "AWS/EC2"
-	namespaceRDS = "AWS/RDS"
-	namespaceSQS = "AWS/SQS"
+	// Register the metadata enrichers of AWS namespaces
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/apigateway"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/appsync"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/athena"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/cloudfront"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/cognito"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/directconnect"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/documentdb"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/dynamodb"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/ec2"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/ecs"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/efs"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/eks"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/elasticache"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/emr"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/eventbridge"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/fsx"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/glue"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/kinesis"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/lambda"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/mq"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/msk"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/neptune"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/opensearch"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/rds"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/redshift"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/route53"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/sagemaker"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/ses"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/shield"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/sqs"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/stepfunctions"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/transitgateway"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/vpn"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/waf"
+	_ "github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata/workspaces"
 )
 
-// addMetadata adds metadata to the given events map based on namespace
-func addMetadata(namespace string, regionName string, awsConfig awssdk.Config, fipsEnabled bool, events map[string]mb.Event) (map[string]mb.Event, error) {
-	switch namespace {
-	case namespaceEC2:
-		events, err := ec2.AddMetadata(regionName, awsConfig, fipsEnabled, events)
-		if err != nil {
-			return events, fmt.Errorf("error adding metadata to ec2: %w", err)
-		}
-	case namespaceRDS:
-		events, err := rds.AddMetadata(regionName, awsConfig, fipsEnabled, events)
-		if err != nil {
-			return events, fmt.Errorf("error adding metadata to rds: %w", err)
-		}
-	case namespaceSQS:
-		events, err := sqs.AddMetadata(regionName, awsConfig, fipsEnabled, events)
-		if err != nil {
-			return events, fmt.Errorf("error adding metadata to sqs: %w", err)
-		}
+// addMetadata adds metadata to the given events map using the enricher
+// registered for the namespace, if any.
+func addMetadata(ctx context.Context, namespace string, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	addNamespaceMetadata, found := metadata.Enrichers.Lookup(namespace)
+	if !found {
+		return events, nil
+	}
+
+	events, err := addNamespaceMetadata(ctx, regionName, awsConfig, discovery, events)
+	if err != nil {
+		return events, fmt.Errorf("error adding metadata to %s: %w", namespace, err)
 	}
 	return events, nil
 }
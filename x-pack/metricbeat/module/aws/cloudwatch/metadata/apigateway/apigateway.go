@@ -0,0 +1,273 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package apigateway
+
+import (
+	"context"
+	"fmt"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/apigateway"
+	"github.com/aws/aws-sdk-go-v2/service/apigateway/types"
+	"github.com/aws/aws-sdk-go-v2/service/apigatewayv2"
+	typesv2 "github.com/aws/aws-sdk-go-v2/service/apigatewayv2/types"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
+)
+
+const metadataPrefix = "aws.apigateway."
+
+// namespace is the CloudWatch namespace enriched by this package.
+const namespace = "AWS/ApiGateway"
+
+// allMethodsSettings is the key of the stage settings of all the methods of a
+// REST API stage.
+const allMethodsSettings = "*/*"
+
+func init() {
+	metadata.Enrichers.MustRegister(namespace, AddMetadata)
+}
+
+type restAPI interface {
+	apigateway.GetRestApisAPIClient
+	GetStages(ctx context.Context, params *apigateway.GetStagesInput, optFns ...func(*apigateway.Options)) (*apigateway.GetStagesOutput, error)
+}
+
+type httpAPI interface {
+	GetApis(ctx context.Context, params *apigatewayv2.GetApisInput, optFns ...func(*apigatewayv2.Options)) (*apigatewayv2.GetApisOutput, error)
+	GetStages(ctx context.Context, params *apigatewayv2.GetStagesInput, optFns ...func(*apigatewayv2.Options)) (*apigatewayv2.GetStagesOutput, error)
+}
+
+// AddMetadata adds metadata for REST, HTTP and WebSocket APIs and their stages
+// from a specific region
+func AddMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svc := apigateway.NewFromConfig(awsConfig)
+	svcV2 := apigatewayv2.NewFromConfig(awsConfig)
+	return addMetadata(ctx, svc, svcV2, discovery, regionName, events)
+}
+
+func addMetadata(ctx context.Context, svc restAPI, svcV2 httpAPI, discovery metadata.Discovery, regionName string, events map[string]mb.Event) (map[string]mb.Event, error) {
+	// Metrics of REST APIs have an ApiName dimension, metrics of HTTP and
+	// WebSocket APIs have an ApiId dimension.
+	var restAPIs map[string]types.RestApi
+	var httpAPIs map[string]typesv2.Api
+	restStages := map[string]map[string]types.Stage{}
+	httpStages := map[string]map[string]typesv2.Stage{}
+
+	for _, event := range events {
+		stageName := getDimension(event, "Stage")
+		addMethodMetadata(event)
+
+		if apiName := getDimension(event, "ApiName"); apiName != "" {
+			if restAPIs == nil {
+				listed, err := metadata.Discover(ctx, discovery, "apigateway:GetRestApis", regionName, "", func(ctx context.Context) (interface{}, error) {
+					return getRestAPIs(ctx, svc)
+				})
+				if err != nil {
+					return events, fmt.Errorf("getRestAPIs failed in region %s: %w", regionName, err)
+				}
+				restAPIs, _ = listed.(map[string]types.RestApi)
+			}
+			api, ok := restAPIs[apiName]
+			if !ok {
+				continue
+			}
+			addRestAPIMetadata(event, api)
+			if stageName == "" {
+				continue
+			}
+
+			apiID := awssdk.ToString(api.Id)
+			stages, ok := restStages[apiID]
+			if !ok {
+				listed, err := metadata.Discover(ctx, discovery, "apigateway:GetStages", regionName, apiID, func(ctx context.Context) (interface{}, error) {
+					return getRestStages(ctx, svc, apiID)
+				})
+				if err != nil {
+					return events, fmt.Errorf("getRestStages of API %s failed in region %s: %w", apiName, regionName, err)
+				}
+				stages, _ = listed.(map[string]types.Stage)
+				restStages[apiID] = stages
+			}
+			if stage, ok := stages[stageName]; ok {
+				addRestStageMetadata(event, stage)
+			}
+			continue
+		}
+
+		if apiID := getDimension(event, "ApiId"); apiID != "" {
+			if httpAPIs == nil {
+				listed, err := metadata.Discover(ctx, discovery, "apigatewayv2:GetApis", regionName, "", func(ctx context.Context) (interface{}, error) {
+					return getHTTPAPIs(ctx, svcV2)
+				})
+				if err != nil {
+					return events, fmt.Errorf("getHTTPAPIs failed in region %s: %w", regionName, err)
+				}
+				httpAPIs, _ = listed.(map[string]typesv2.Api)
+			}
+			api, ok := httpAPIs[apiID]
+			if !ok {
+				continue
+			}
+			addHTTPAPIMetadata(event, api)
+			if stageName == "" {
+				continue
+			}
+
+			stages, ok := httpStages[apiID]
+			if !ok {
+				listed, err := metadata.Discover(ctx, discovery, "apigatewayv2:GetStages", regionName, apiID, func(ctx context.Context) (interface{}, error) {
+					return getHTTPStages(ctx, svcV2, apiID)
+				})
+				if err != nil {
+					return events, fmt.Errorf("getHTTPStages of API %s failed in region %s: %w", apiID, regionName, err)
+				}
+				stages, _ = listed.(map[string]typesv2.Stage)
+				httpStages[apiID] = stages
+			}
+			if stage, ok := stages[stageName]; ok {
+				addHTTPStageMetadata(event, stage)
+			}
+		}
+	}
+	return events, nil
+}
+
+func getDimension(event mb.Event, name string) string {
+	value, err := event.RootFields.GetValue("aws.dimensions." + name)
+	if err != nil {
+		return ""
+	}
+	dimension, _ := value.(string)
+	return dimension
+}
+
+// getRestAPIs returns the REST APIs of a region by name.
+func getRestAPIs(ctx context.Context, svc apigateway.GetRestApisAPIClient) (map[string]types.RestApi, error) {
+	apis := map[string]types.RestApi{}
+	paginator := apigateway.NewGetRestApisPaginator(svc, &apigateway.GetRestApisInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return apis, fmt.Errorf("error GetRestApis with Paginator: %w", err)
+		}
+		for _, api := range output.Items {
+			apis[awssdk.ToString(api.Name)] = api
+		}
+	}
+	return apis, nil
+}
+
+func getRestStages(ctx context.Context, svc restAPI, apiID string) (map[string]types.Stage, error) {
+	output, err := svc.GetStages(ctx, &apigateway.GetStagesInput{RestApiId: awssdk.String(apiID)})
+	if err != nil {
+		return nil, fmt.Errorf("error GetStages: %w", err)
+	}
+	stages := make(map[string]types.Stage, len(output.Item))
+	for _, stage := range output.Item {
+		stages[awssdk.ToString(stage.StageName)] = stage
+	}
+	return stages, nil
+}
+
+// getHTTPAPIs returns the HTTP and WebSocket APIs of a region by ID.
+func getHTTPAPIs(ctx context.Context, svc httpAPI) (map[string]typesv2.Api, error) {
+	apis := map[string]typesv2.Api{}
+	input := &apigatewayv2.GetApisInput{}
+	for {
+		output, err := svc.GetApis(ctx, input)
+		if err != nil {
+			return apis, fmt.Errorf("error GetApis: %w", err)
+		}
+		for _, api := range output.Items {
+			apis[awssdk.ToString(api.ApiId)] = api
+		}
+		if output.NextToken == nil {
+			return apis, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+func getHTTPStages(ctx context.Context, svc httpAPI, apiID string) (map[string]typesv2.Stage, error) {
+	stages := map[string]typesv2.Stage{}
+	input := &apigatewayv2.GetStagesInput{ApiId: awssdk.String(apiID)}
+	for {
+		output, err := svc.GetStages(ctx, input)
+		if err != nil {
+			return stages, fmt.Errorf("error GetStages: %w", err)
+		}
+		for _, stage := range output.Items {
+			stages[awssdk.ToString(stage.StageName)] = stage
+		}
+		if output.NextToken == nil {
+			return stages, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+// addMethodMetadata adds the resource, method and route of the detailed
+// metrics, that are reported per method for REST APIs and per route for HTTP
+// and WebSocket APIs.
+func addMethodMetadata(event mb.Event) {
+	if resource := getDimension(event, "Resource"); resource != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"resource", resource)
+	}
+	if method := getDimension(event, "Method"); method != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"method", method)
+	}
+	if route := getDimension(event, "Route"); route != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"route", route)
+	}
+}
+
+func addRestAPIMetadata(event mb.Event, api types.RestApi) {
+	_, _ = event.RootFields.Put(metadataPrefix+"api.id", awssdk.ToString(api.Id))
+	_, _ = event.RootFields.Put(metadataPrefix+"api.name", awssdk.ToString(api.Name))
+	_, _ = event.RootFields.Put(metadataPrefix+"api.type", "REST")
+	if api.EndpointConfiguration != nil && len(api.EndpointConfiguration.Types) > 0 {
+		_, _ = event.RootFields.Put(metadataPrefix+"api.endpoint_type", string(api.EndpointConfiguration.Types[0]))
+	}
+	if api.Description != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"api.description", *api.Description)
+	}
+}
+
+func addRestStageMetadata(event mb.Event, stage types.Stage) {
+	_, _ = event.RootFields.Put(metadataPrefix+"stage.name", awssdk.ToString(stage.StageName))
+	if stage.DeploymentId != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"stage.deployment_id", *stage.DeploymentId)
+	}
+	settings, ok := stage.MethodSettings[allMethodsSettings]
+	_, _ = event.RootFields.Put(metadataPrefix+"stage.detailed_metrics_enabled", ok && settings.MetricsEnabled)
+	_, _ = event.RootFields.Put(metadataPrefix+"stage.tracing_enabled", stage.TracingEnabled)
+	_, _ = event.RootFields.Put(metadataPrefix+"stage.cache_enabled", stage.CacheClusterEnabled)
+}
+
+func addHTTPAPIMetadata(event mb.Event, api typesv2.Api) {
+	_, _ = event.RootFields.Put(metadataPrefix+"api.id", awssdk.ToString(api.ApiId))
+	_, _ = event.RootFields.Put(metadataPrefix+"api.name", awssdk.ToString(api.Name))
+	if api.ProtocolType != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"api.type", string(api.ProtocolType))
+	}
+	if api.ApiEndpoint != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"api.endpoint", *api.ApiEndpoint)
+	}
+	if api.Description != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"api.description", *api.Description)
+	}
+}
+
+func addHTTPStageMetadata(event mb.Event, stage typesv2.Stage) {
+	_, _ = event.RootFields.Put(metadataPrefix+"stage.name", awssdk.ToString(stage.StageName))
+	if stage.DeploymentId != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"stage.deployment_id", *stage.DeploymentId)
+	}
+	detailedMetricsEnabled := stage.DefaultRouteSettings != nil && stage.DefaultRouteSettings.DetailedMetricsEnabled
+	_, _ = event.RootFields.Put(metadataPrefix+"stage.detailed_metrics_enabled", detailedMetricsEnabled)
+	_, _ = event.RootFields.Put(metadataPrefix+"stage.auto_deploy", stage.AutoDeploy)
+}
@@ -0,0 +1,121 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package appsync
+
+import (
+	"context"
+	"fmt"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/appsync"
+	"github.com/aws/aws-sdk-go-v2/service/appsync/types"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
+)
+
+const metadataPrefix = "aws.appsync."
+
+// namespace is the CloudWatch namespace enriched by this package.
+const namespace = "AWS/AppSync"
+
+// graphQLURI is the key of the GraphQL endpoint in the URIs of an API.
+const graphQLURI = "GRAPHQL"
+
+func init() {
+	metadata.Enrichers.MustRegister(namespace, AddMetadata)
+}
+
+type appsyncAPI interface {
+	ListGraphqlApis(ctx context.Context, params *appsync.ListGraphqlApisInput, optFns ...func(*appsync.Options)) (*appsync.ListGraphqlApisOutput, error)
+}
+
+// AddMetadata adds metadata for AppSync GraphQL APIs from a specific region
+func AddMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svc := appsync.NewFromConfig(awsConfig)
+	return addMetadata(ctx, svc, discovery, regionName, events)
+}
+
+func addMetadata(ctx context.Context, svc appsyncAPI, discovery metadata.Discovery, regionName string, events map[string]mb.Event) (map[string]mb.Event, error) {
+	// The APIs are shared with the other metricsets of the account
+	listed, err := metadata.Discover(ctx, discovery, "appsync:ListGraphqlApis", regionName, "", func(ctx context.Context) (interface{}, error) {
+		return getGraphqlAPIs(ctx, svc)
+	})
+	if err != nil {
+		return events, fmt.Errorf("getGraphqlAPIs failed in region %s: %w", regionName, err)
+	}
+	apis, _ := listed.(map[string]types.GraphqlApi)
+
+	for _, event := range events {
+		addResolverMetadata(event)
+
+		if api, ok := apis[getDimension(event, "GraphQLAPIId")]; ok {
+			addAPIMetadata(event, api)
+		}
+	}
+	return events, nil
+}
+
+func getDimension(event mb.Event, name string) string {
+	value, err := event.RootFields.GetValue("aws.dimensions." + name)
+	if err != nil {
+		return ""
+	}
+	dimension, _ := value.(string)
+	return dimension
+}
+
+// getGraphqlAPIs returns the GraphQL APIs of a region by ID.
+func getGraphqlAPIs(ctx context.Context, svc appsyncAPI) (map[string]types.GraphqlApi, error) {
+	apis := map[string]types.GraphqlApi{}
+	input := &appsync.ListGraphqlApisInput{}
+	for {
+		output, err := svc.ListGraphqlApis(ctx, input)
+		if err != nil {
+			return apis, fmt.Errorf("error ListGraphqlApis: %w", err)
+		}
+		for _, api := range output.GraphqlApis {
+			apis[awssdk.ToString(api.ApiId)] = api
+		}
+		if output.NextToken == nil {
+			return apis, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+// addResolverMetadata adds the resolver, data source and operation of the
+// enhanced metrics, that are reported per resolver, data source or operation
+// when enhanced metrics are enabled for the API.
+func addResolverMetadata(event mb.Event) {
+	if resolver := getDimension(event, "ResolverArn"); resolver != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"resolver.arn", resolver)
+	}
+	if dataSource := getDimension(event, "DataSourceArn"); dataSource != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"data_source.arn", dataSource)
+	}
+	if operation := getDimension(event, "Operation"); operation != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"operation", operation)
+	}
+}
+
+func addAPIMetadata(event mb.Event, api types.GraphqlApi) {
+	_, _ = event.RootFields.Put(metadataPrefix+"api.id", awssdk.ToString(api.ApiId))
+	_, _ = event.RootFields.Put(metadataPrefix+"api.name", awssdk.ToString(api.Name))
+	_, _ = event.RootFields.Put(metadataPrefix+"api.arn", awssdk.ToString(api.Arn))
+	if api.AuthenticationType != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"api.authentication_type", string(api.AuthenticationType))
+	}
+	if uri, ok := api.Uris[graphQLURI]; ok {
+		_, _ = event.RootFields.Put(metadataPrefix+"api.uri", uri)
+	}
+	_, _ = event.RootFields.Put(metadataPrefix+"api.xray_enabled", api.XrayEnabled)
+	if api.WafWebAclArn != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"api.waf_web_acl_arn", *api.WafWebAclArn)
+	}
+	if api.LogConfig != nil && api.LogConfig.FieldLogLevel != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"api.field_log_level", string(api.LogConfig.FieldLogLevel))
+	}
+}
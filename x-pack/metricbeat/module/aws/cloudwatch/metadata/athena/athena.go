@@ -0,0 +1,113 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package athena
+
+import (
+	"context"
+	"fmt"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/athena"
+	"github.com/aws/aws-sdk-go-v2/service/athena/types"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
+)
+
+const metadataPrefix = "aws.athena."
+
+// namespace is the CloudWatch namespace enriched by this package.
+const namespace = "AWS/Athena"
+
+func init() {
+	metadata.Enrichers.MustRegister(namespace, AddMetadata)
+}
+
+type athenaAPI interface {
+	GetWorkGroup(ctx context.Context, params *athena.GetWorkGroupInput, optFns ...func(*athena.Options)) (*athena.GetWorkGroupOutput, error)
+}
+
+// AddMetadata adds metadata for Athena workgroups from a specific region
+func AddMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svc := athena.NewFromConfig(awsConfig)
+	return addMetadata(ctx, svc, discovery, regionName, events)
+}
+
+func addMetadata(ctx context.Context, svc athenaAPI, discovery metadata.Discovery, regionName string, events map[string]mb.Event) (map[string]mb.Event, error) {
+	// Workgroups are only described once per fetch, even when their metrics
+	// are split in multiple events by query state and type.
+	workGroups := map[string]*types.WorkGroup{}
+	for _, event := range events {
+		if queryState := getDimension(event, "QueryState"); queryState != "" {
+			_, _ = event.RootFields.Put(metadataPrefix+"query.state", queryState)
+		}
+		if queryType := getDimension(event, "QueryType"); queryType != "" {
+			_, _ = event.RootFields.Put(metadataPrefix+"query.type", queryType)
+		}
+
+		workGroupName := getDimension(event, "WorkGroup")
+		if workGroupName == "" {
+			continue
+		}
+		workGroup, ok := workGroups[workGroupName]
+		if !ok {
+			described, err := metadata.Discover(ctx, discovery, "athena:GetWorkGroup", regionName, workGroupName, func(ctx context.Context) (interface{}, error) {
+				output, err := svc.GetWorkGroup(ctx, &athena.GetWorkGroupInput{WorkGroup: awssdk.String(workGroupName)})
+				if err != nil {
+					return nil, err
+				}
+				return output.WorkGroup, nil
+			})
+			if err != nil {
+				return events, fmt.Errorf("GetWorkGroup of workgroup %s failed in region %s: %w", workGroupName, regionName, err)
+			}
+			workGroup, _ = described.(*types.WorkGroup)
+			workGroups[workGroupName] = workGroup
+		}
+		if workGroup != nil {
+			addWorkGroupMetadata(event, workGroup)
+		}
+	}
+	return events, nil
+}
+
+func getDimension(event mb.Event, name string) string {
+	value, err := event.RootFields.GetValue("aws.dimensions." + name)
+	if err != nil {
+		return ""
+	}
+	dimension, _ := value.(string)
+	return dimension
+}
+
+func addWorkGroupMetadata(event mb.Event, workGroup *types.WorkGroup) {
+	_, _ = event.RootFields.Put(metadataPrefix+"workgroup.name", awssdk.ToString(workGroup.Name))
+	if workGroup.State != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"workgroup.state", string(workGroup.State))
+	}
+	if workGroup.Description != nil && *workGroup.Description != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"workgroup.description", *workGroup.Description)
+	}
+	if workGroup.CreationTime != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"workgroup.created_at", *workGroup.CreationTime)
+	}
+
+	config := workGroup.Configuration
+	if config == nil {
+		return
+	}
+	if config.EngineVersion != nil && config.EngineVersion.EffectiveEngineVersion != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"workgroup.engine_version", *config.EngineVersion.EffectiveEngineVersion)
+	}
+	if config.BytesScannedCutoffPerQuery != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"workgroup.bytes_scanned_cutoff_per_query", *config.BytesScannedCutoffPerQuery)
+	}
+	if config.EnforceWorkGroupConfiguration != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"workgroup.enforce_configuration", *config.EnforceWorkGroupConfiguration)
+	}
+	if config.ResultConfiguration != nil && config.ResultConfiguration.OutputLocation != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"workgroup.output_location", *config.ResultConfiguration.OutputLocation)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cloudfront
+
+import (
+	"context"
+	"fmt"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
+)
+
+const metadataPrefix = "aws.cloudfront.distribution."
+
+// namespace is the CloudWatch namespace enriched by this package.
+const namespace = "AWS/CloudFront"
+
+func init() {
+	metadata.Enrichers.MustRegister(namespace, AddMetadata)
+}
+
+// AddMetadata adds metadata for CloudFront distributions. CloudFront is a
+// global service, its metrics are only available in the us-east-1 region.
+func AddMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svc := cloudfront.NewFromConfig(awsConfig)
+
+	// The distributions are shared with the other metricsets of the account
+	listedDistributions, err := metadata.Discover(ctx, discovery, "cloudfront:ListDistributions", regionName, "", func(ctx context.Context) (interface{}, error) {
+		return getDistributions(ctx, svc)
+	})
+	if err != nil {
+		return events, fmt.Errorf("getDistributions failed, skipping region %s: %w", regionName, err)
+	}
+	distributions, _ := listedDistributions.(map[string]types.DistributionSummary)
+
+	for _, event := range events {
+		value, err := event.RootFields.GetValue("aws.dimensions.DistributionId")
+		if err != nil {
+			continue
+		}
+		distributionID, _ := value.(string)
+		if distribution, ok := distributions[distributionID]; ok {
+			addDistributionMetadata(event, distribution)
+		}
+	}
+	return events, nil
+}
+
+// getDistributions returns the CloudFront distributions of the account by ID.
+func getDistributions(ctx context.Context, svc cloudfront.ListDistributionsAPIClient) (map[string]types.DistributionSummary, error) {
+	distributions := map[string]types.DistributionSummary{}
+	paginator := cloudfront.NewListDistributionsPaginator(svc, &cloudfront.ListDistributionsInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error ListDistributions with Paginator: %w", err)
+		}
+		if output.DistributionList == nil {
+			continue
+		}
+		for _, distribution := range output.DistributionList.Items {
+			distributions[awssdk.ToString(distribution.Id)] = distribution
+		}
+	}
+	return distributions, nil
+}
+
+func addDistributionMetadata(event mb.Event, distribution types.DistributionSummary) {
+	_, _ = event.RootFields.Put(metadataPrefix+"id", awssdk.ToString(distribution.Id))
+	if distribution.ARN != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"arn", *distribution.ARN)
+	}
+	if distribution.DomainName != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"domain_name", *distribution.DomainName)
+	}
+	if distribution.Status != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"status", *distribution.Status)
+	}
+	if distribution.Enabled != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"enabled", *distribution.Enabled)
+	}
+	if distribution.PriceClass != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"price_class", string(distribution.PriceClass))
+	}
+	if distribution.HttpVersion != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"http_version", string(distribution.HttpVersion))
+	}
+	if distribution.Comment != nil && *distribution.Comment != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"comment", *distribution.Comment)
+	}
+	if distribution.Aliases != nil && len(distribution.Aliases.Items) > 0 {
+		_, _ = event.RootFields.Put(metadataPrefix+"aliases", distribution.Aliases.Items)
+	}
+	if distribution.Origins != nil {
+		origins := make([]string, 0, len(distribution.Origins.Items))
+		for _, origin := range distribution.Origins.Items {
+			if origin.DomainName != nil {
+				origins = append(origins, *origin.DomainName)
+			}
+		}
+		if len(origins) > 0 {
+			_, _ = event.RootFields.Put(metadataPrefix+"origins", origins)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cognito
+
+import (
+	"context"
+	"fmt"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider"
+	"github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider/types"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
+)
+
+const metadataPrefix = "aws.cognito."
+
+// namespace is the CloudWatch namespace enriched by this package.
+const namespace = "AWS/Cognito"
+
+func init() {
+	metadata.Enrichers.MustRegister(namespace, AddMetadata)
+}
+
+type cognitoAPI interface {
+	DescribeUserPool(ctx context.Context, params *cognitoidentityprovider.DescribeUserPoolInput, optFns ...func(*cognitoidentityprovider.Options)) (*cognitoidentityprovider.DescribeUserPoolOutput, error)
+}
+
+// AddMetadata adds metadata for Cognito user pools from a specific region
+func AddMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svc := cognitoidentityprovider.NewFromConfig(awsConfig)
+	return addMetadata(ctx, svc, discovery, regionName, events)
+}
+
+func addMetadata(ctx context.Context, svc cognitoAPI, discovery metadata.Discovery, regionName string, events map[string]mb.Event) (map[string]mb.Event, error) {
+	userPools := map[string]*types.UserPoolType{}
+	for _, event := range events {
+		if client := getDimension(event, "UserPoolClient"); client != "" {
+			_, _ = event.RootFields.Put(metadataPrefix+"user_pool_client.id", client)
+		}
+		if operation := getDimension(event, "Operation"); operation != "" {
+			_, _ = event.RootFields.Put(metadataPrefix+"operation", operation)
+		}
+		if riskLevel := getDimension(event, "RiskLevel"); riskLevel != "" {
+			_, _ = event.RootFields.Put(metadataPrefix+"risk_level", riskLevel)
+		}
+
+		// The advanced security metrics have a UserPoolId dimension instead of
+		// UserPool.
+		userPoolID := getDimension(event, "UserPool")
+		if userPoolID == "" {
+			userPoolID = getDimension(event, "UserPoolId")
+		}
+		if userPoolID == "" {
+			continue
+		}
+
+		userPool, ok := userPools[userPoolID]
+		if !ok {
+			described, err := metadata.Discover(ctx, discovery, "cognito-idp:DescribeUserPool", regionName, userPoolID, func(ctx context.Context) (interface{}, error) {
+				output, err := svc.DescribeUserPool(ctx, &cognitoidentityprovider.DescribeUserPoolInput{UserPoolId: awssdk.String(userPoolID)})
+				if err != nil {
+					return nil, err
+				}
+				return output.UserPool, nil
+			})
+			if err != nil {
+				return events, fmt.Errorf("DescribeUserPool of user pool %s failed in region %s: %w", userPoolID, regionName, err)
+			}
+			userPool, _ = described.(*types.UserPoolType)
+			userPools[userPoolID] = userPool
+		}
+		if userPool != nil {
+			addUserPoolMetadata(event, userPool)
+		}
+	}
+	return events, nil
+}
+
+func getDimension(event mb.Event, name string) string {
+	value, err := event.RootFields.GetValue("aws.dimensions." + name)
+	if err != nil {
+		return ""
+	}
+	dimension, _ := value.(string)
+	return dimension
+}
+
+func addUserPoolMetadata(event mb.Event, userPool *types.UserPoolType) {
+	_, _ = event.RootFields.Put(metadataPrefix+"user_pool.id", awssdk.ToString(userPool.Id))
+	_, _ = event.RootFields.Put(metadataPrefix+"user_pool.name", awssdk.ToString(userPool.Name))
+	_, _ = event.RootFields.Put(metadataPrefix+"user_pool.arn", awssdk.ToString(userPool.Arn))
+	_, _ = event.RootFields.Put(metadataPrefix+"user_pool.estimated_users", userPool.EstimatedNumberOfUsers)
+	if userPool.MfaConfiguration != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"user_pool.mfa_configuration", string(userPool.MfaConfiguration))
+	}
+	if userPool.UserPoolAddOns != nil && userPool.UserPoolAddOns.AdvancedSecurityMode != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"user_pool.advanced_security_mode", string(userPool.UserPoolAddOns.AdvancedSecurityMode))
+	}
+	if userPool.Domain != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"user_pool.domain", *userPool.Domain)
+	}
+	if userPool.CreationDate != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"user_pool.created_at", *userPool.CreationDate)
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package directconnect
+
+import (
+	"context"
+	"fmt"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/directconnect"
+	"github.com/aws/aws-sdk-go-v2/service/directconnect/types"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
+)
+
+const metadataPrefix = "aws.directconnect."
+
+// namespace is the CloudWatch namespace enriched by this package.
+const namespace = "AWS/DX"
+
+func init() {
+	metadata.Enrichers.MustRegister(namespace, AddMetadata)
+}
+
+type directconnectAPI interface {
+	DescribeConnections(ctx context.Context, params *directconnect.DescribeConnectionsInput, optFns ...func(*directconnect.Options)) (*directconnect.DescribeConnectionsOutput, error)
+	DescribeVirtualInterfaces(ctx context.Context, params *directconnect.DescribeVirtualInterfacesInput, optFns ...func(*directconnect.Options)) (*directconnect.DescribeVirtualInterfacesOutput, error)
+}
+
+// AddMetadata adds metadata for Direct Connect connections and virtual
+// interfaces from a specific region
+func AddMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svc := directconnect.NewFromConfig(awsConfig)
+	return addMetadata(ctx, svc, discovery, regionName, events)
+}
+
+func addMetadata(ctx context.Context, svc directconnectAPI, discovery metadata.Discovery, regionName string, events map[string]mb.Event) (map[string]mb.Event, error) {
+	listedConnections, err := metadata.Discover(ctx, discovery, "directconnect:DescribeConnections", regionName, "", func(ctx context.Context) (interface{}, error) {
+		output, err := svc.DescribeConnections(ctx, &directconnect.DescribeConnectionsInput{})
+		if err != nil {
+			return nil, err
+		}
+		connections := make(map[string]types.Connection, len(output.Connections))
+		for _, connection := range output.Connections {
+			connections[awssdk.ToString(connection.ConnectionId)] = connection
+		}
+		return connections, nil
+	})
+	if err != nil {
+		return events, fmt.Errorf("DescribeConnections failed, skipping region %s: %w", regionName, err)
+	}
+	connections, _ := listedConnections.(map[string]types.Connection)
+
+	// Virtual interfaces are only described when there are virtual interface
+	// metrics.
+	var virtualInterfaces map[string]types.VirtualInterface
+	for _, event := range events {
+		if connection, ok := connections[getDimension(event, "ConnectionId")]; ok {
+			addConnectionMetadata(event, connection)
+		}
+
+		virtualInterfaceID := getDimension(event, "VirtualInterfaceId")
+		if virtualInterfaceID == "" {
+			continue
+		}
+		if virtualInterfaces == nil {
+			listed, err := metadata.Discover(ctx, discovery, "directconnect:DescribeVirtualInterfaces", regionName, "", func(ctx context.Context) (interface{}, error) {
+				output, err := svc.DescribeVirtualInterfaces(ctx, &directconnect.DescribeVirtualInterfacesInput{})
+				if err != nil {
+					return nil, err
+				}
+				virtualInterfaces := make(map[string]types.VirtualInterface, len(output.VirtualInterfaces))
+				for _, virtualInterface := range output.VirtualInterfaces {
+					virtualInterfaces[awssdk.ToString(virtualInterface.VirtualInterfaceId)] = virtualInterface
+				}
+				return virtualInterfaces, nil
+			})
+			if err != nil {
+				return events, fmt.Errorf("DescribeVirtualInterfaces failed in region %s: %w", regionName, err)
+			}
+			virtualInterfaces, _ = listed.(map[string]types.VirtualInterface)
+		}
+		if virtualInterface, ok := virtualInterfaces[virtualInterfaceID]; ok {
+			addVirtualInterfaceMetadata(event, virtualInterface)
+		}
+	}
+	return events, nil
+}
+
+func getDimension(event mb.Event, name string) string {
+	value, err := event.RootFields.GetValue("aws.dimensions." + name)
+	if err != nil {
+		return ""
+	}
+	dimension, _ := value.(string)
+	return dimension
+}
+
+func addConnectionMetadata(event mb.Event, connection types.Connection) {
+	_, _ = event.RootFields.Put(metadataPrefix+"connection.id", awssdk.ToString(connection.ConnectionId))
+	if connection.ConnectionName != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"connection.name", *connection.ConnectionName)
+	}
+	if connection.ConnectionState != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"connection.state", string(connection.ConnectionState))
+	}
+	if connection.Bandwidth != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"connection.bandwidth", *connection.Bandwidth)
+	}
+	if connection.Location != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"connection.location", *connection.Location)
+	}
+	if connection.PartnerName != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"connection.partner_name", *connection.PartnerName)
+	}
+	if connection.AwsDeviceV2 != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"connection.aws_device", *connection.AwsDeviceV2)
+	}
+	if connection.HasLogicalRedundancy != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"connection.logical_redundancy", string(connection.HasLogicalRedundancy))
+	}
+}
+
+func addVirtualInterfaceMetadata(event mb.Event, virtualInterface types.VirtualInterface) {
+	_, _ = event.RootFields.Put(metadataPrefix+"virtual_interface.id", awssdk.ToString(virtualInterface.VirtualInterfaceId))
+	if virtualInterface.VirtualInterfaceName != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"virtual_interface.name", *virtualInterface.VirtualInterfaceName)
+	}
+	if virtualInterface.VirtualInterfaceType != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"virtual_interface.type", *virtualInterface.VirtualInterfaceType)
+	}
+	if virtualInterface.VirtualInterfaceState != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"virtual_interface.state", string(virtualInterface.VirtualInterfaceState))
+	}
+	_, _ = event.RootFields.Put(metadataPrefix+"virtual_interface.vlan", virtualInterface.Vlan)
+
+	// A virtual interface has a BGP peer per address family, IPv4 and IPv6.
+	up := 0
+	for _, peer := range virtualInterface.BgpPeers {
+		if peer.BgpStatus == types.BGPStatusUp {
+			up++
+		}
+	}
+	_, _ = event.RootFields.Put(metadataPrefix+"virtual_interface.bgp_peers.count", len(virtualInterface.BgpPeers))
+	_, _ = event.RootFields.Put(metadataPrefix+"virtual_interface.bgp_peers.up", up)
+}
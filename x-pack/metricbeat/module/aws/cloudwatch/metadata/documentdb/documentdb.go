@@ -0,0 +1,133 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package documentdb
+
+import (
+	"context"
+	"fmt"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/docdb"
+	"github.com/aws/aws-sdk-go-v2/service/docdb/types"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
+)
+
+const metadataPrefix = "aws.documentdb."
+
+// namespace is the CloudWatch namespace enriched by this package.
+const namespace = "AWS/DocDB"
+
+func init() {
+	metadata.Enrichers.MustRegister(namespace, AddMetadata)
+}
+
+// AddMetadata adds metadata for DocumentDB clusters and instances from a
+// specific region
+func AddMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svc := docdb.NewFromConfig(awsConfig)
+
+	// The clusters are shared with the other metricsets of the account
+	listedClusters, err := metadata.Discover(ctx, discovery, "docdb:DescribeDBClusters", regionName, "", func(ctx context.Context) (interface{}, error) {
+		return getClusters(ctx, svc)
+	})
+	if err != nil {
+		return events, fmt.Errorf("getClusters failed, skipping region %s: %w", regionName, err)
+	}
+	clusters, _ := listedClusters.(map[string]types.DBCluster)
+
+	// The instance metrics only have a DBInstanceIdentifier dimension, their
+	// cluster is found from the cluster members.
+	instanceClusters := map[string]types.DBCluster{}
+	for _, cluster := range clusters {
+		for _, member := range cluster.DBClusterMembers {
+			instanceClusters[awssdk.ToString(member.DBInstanceIdentifier)] = cluster
+		}
+	}
+
+	for _, event := range events {
+		if instanceID := getDimension(event, "DBInstanceIdentifier"); instanceID != "" {
+			if cluster, ok := instanceClusters[instanceID]; ok {
+				addClusterMetadata(event, cluster)
+				addInstanceMetadata(event, instanceID, cluster)
+			}
+			continue
+		}
+		if cluster, ok := clusters[getDimension(event, "DBClusterIdentifier")]; ok {
+			addClusterMetadata(event, cluster)
+		}
+	}
+	return events, nil
+}
+
+func getDimension(event mb.Event, name string) string {
+	value, err := event.RootFields.GetValue("aws.dimensions." + name)
+	if err != nil {
+		return ""
+	}
+	dimension, _ := value.(string)
+	return dimension
+}
+
+// getClusters returns the DocumentDB clusters of a region by identifier. The
+// DocumentDB API also returns RDS and Neptune clusters unless they are filtered
+// by engine.
+func getClusters(ctx context.Context, svc docdb.DescribeDBClustersAPIClient) (map[string]types.DBCluster, error) {
+	clusters := map[string]types.DBCluster{}
+	paginator := docdb.NewDescribeDBClustersPaginator(svc, &docdb.DescribeDBClustersInput{
+		Filters: []types.Filter{{Name: awssdk.String("engine"), Values: []string{"docdb"}}},
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error DescribeDBClusters with Paginator: %w", err)
+		}
+		for _, cluster := range output.DBClusters {
+			clusters[awssdk.ToString(cluster.DBClusterIdentifier)] = cluster
+		}
+	}
+	return clusters, nil
+}
+
+func addClusterMetadata(event mb.Event, cluster types.DBCluster) {
+	_, _ = event.RootFields.Put(metadataPrefix+"cluster.id", awssdk.ToString(cluster.DBClusterIdentifier))
+	if cluster.DBClusterArn != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"cluster.arn", *cluster.DBClusterArn)
+	}
+	if cluster.Status != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"cluster.status", *cluster.Status)
+	}
+	if cluster.EngineVersion != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"cluster.engine_version", *cluster.EngineVersion)
+	}
+	if cluster.Endpoint != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"cluster.endpoint", *cluster.Endpoint)
+	}
+	if cluster.ReaderEndpoint != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"cluster.reader_endpoint", *cluster.ReaderEndpoint)
+	}
+	if cluster.BackupRetentionPeriod != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"cluster.backup_retention_period.days", *cluster.BackupRetentionPeriod)
+	}
+	_, _ = event.RootFields.Put(metadataPrefix+"cluster.multi_az", cluster.MultiAZ)
+	_, _ = event.RootFields.Put(metadataPrefix+"cluster.storage_encrypted", cluster.StorageEncrypted)
+	_, _ = event.RootFields.Put(metadataPrefix+"cluster.instances.count", len(cluster.DBClusterMembers))
+}
+
+func addInstanceMetadata(event mb.Event, instanceID string, cluster types.DBCluster) {
+	_, _ = event.RootFields.Put(metadataPrefix+"instance.id", instanceID)
+	for _, member := range cluster.DBClusterMembers {
+		if awssdk.ToString(member.DBInstanceIdentifier) != instanceID {
+			continue
+		}
+		role := "reader"
+		if member.IsClusterWriter {
+			role = "writer"
+		}
+		_, _ = event.RootFields.Put(metadataPrefix+"instance.role", role)
+		return
+	}
+}
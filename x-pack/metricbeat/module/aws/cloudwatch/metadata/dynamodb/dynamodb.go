@@ -0,0 +1,131 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
+)
+
+const metadataPrefix = "aws.dynamodb."
+
+// namespace is the CloudWatch namespace enriched by this package.
+const namespace = "AWS/DynamoDB"
+
+func init() {
+	metadata.Enrichers.MustRegister(namespace, AddMetadata)
+}
+
+type dynamodbAPI interface {
+	DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)
+}
+
+// AddMetadata adds the capacity configuration of DynamoDB tables and their
+// global secondary indexes from a specific region
+func AddMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svc := dynamodb.NewFromConfig(awsConfig)
+	return addMetadata(ctx, svc, discovery, regionName, events)
+}
+
+func addMetadata(ctx context.Context, svc dynamodbAPI, discovery metadata.Discovery, regionName string, events map[string]mb.Event) (map[string]mb.Event, error) {
+	tables := map[string]*types.TableDescription{}
+	for _, event := range events {
+		tableName := getDimension(event, "TableName")
+		if tableName == "" {
+			continue
+		}
+
+		table, ok := tables[tableName]
+		if !ok {
+			described, err := metadata.Discover(ctx, discovery, "dynamodb:DescribeTable", regionName, tableName, func(ctx context.Context) (interface{}, error) {
+				output, err := svc.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: awssdk.String(tableName)})
+				if err != nil {
+					return nil, err
+				}
+				return output.Table, nil
+			})
+			if err != nil {
+				return events, fmt.Errorf("DescribeTable of table %s failed in region %s: %w", tableName, regionName, err)
+			}
+			table, _ = described.(*types.TableDescription)
+			tables[tableName] = table
+		}
+		if table == nil {
+			continue
+		}
+		addTableMetadata(event, table)
+
+		// Metrics of global secondary indexes have the name of the index in
+		// a GlobalSecondaryIndexName dimension.
+		indexName := getDimension(event, "GlobalSecondaryIndexName")
+		if indexName == "" {
+			continue
+		}
+		_, _ = event.RootFields.Put(metadataPrefix+"global_secondary_index.name", indexName)
+		for _, index := range table.GlobalSecondaryIndexes {
+			if awssdk.ToString(index.IndexName) == indexName {
+				addIndexMetadata(event, index)
+				break
+			}
+		}
+	}
+	return events, nil
+}
+
+func getDimension(event mb.Event, name string) string {
+	value, err := event.RootFields.GetValue("aws.dimensions." + name)
+	if err != nil {
+		return ""
+	}
+	dimension, _ := value.(string)
+	return dimension
+}
+
+func addTableMetadata(event mb.Event, table *types.TableDescription) {
+	_, _ = event.RootFields.Put(metadataPrefix+"table.name", awssdk.ToString(table.TableName))
+	if table.TableStatus != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"table.status", string(table.TableStatus))
+	}
+
+	// Tables created before billing modes were introduced have no billing
+	// mode summary and are provisioned.
+	billingMode := types.BillingModeProvisioned
+	if table.BillingModeSummary != nil && table.BillingModeSummary.BillingMode != "" {
+		billingMode = table.BillingModeSummary.BillingMode
+	}
+	_, _ = event.RootFields.Put(metadataPrefix+"table.billing_mode", string(billingMode))
+	_, _ = event.RootFields.Put(metadataPrefix+"table.on_demand", billingMode == types.BillingModePayPerRequest)
+
+	if table.TableClassSummary != nil && table.TableClassSummary.TableClass != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"table.table_class", string(table.TableClassSummary.TableClass))
+	}
+	_, _ = event.RootFields.Put(metadataPrefix+"table.item_count", table.ItemCount)
+	_, _ = event.RootFields.Put(metadataPrefix+"table.size.bytes", table.TableSizeBytes)
+	if billingMode == types.BillingModeProvisioned && table.ProvisionedThroughput != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"table.provisioned.read_capacity_units", awssdk.ToInt64(table.ProvisionedThroughput.ReadCapacityUnits))
+		_, _ = event.RootFields.Put(metadataPrefix+"table.provisioned.write_capacity_units", awssdk.ToInt64(table.ProvisionedThroughput.WriteCapacityUnits))
+	}
+	_, _ = event.RootFields.Put(metadataPrefix+"table.global_secondary_indexes.count", len(table.GlobalSecondaryIndexes))
+}
+
+func addIndexMetadata(event mb.Event, index types.GlobalSecondaryIndexDescription) {
+	if index.IndexStatus != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"global_secondary_index.status", string(index.IndexStatus))
+	}
+	_, _ = event.RootFields.Put(metadataPrefix+"global_secondary_index.item_count", index.ItemCount)
+	_, _ = event.RootFields.Put(metadataPrefix+"global_secondary_index.size.bytes", index.IndexSizeBytes)
+	// On-demand tables report a provisioned throughput of 0 for their indexes.
+	if index.ProvisionedThroughput != nil && awssdk.ToInt64(index.ProvisionedThroughput.ReadCapacityUnits) > 0 {
+		_, _ = event.RootFields.Put(metadataPrefix+"global_secondary_index.provisioned.read_capacity_units", awssdk.ToInt64(index.ProvisionedThroughput.ReadCapacityUnits))
+		_, _ = event.RootFields.Put(metadataPrefix+"global_secondary_index.provisioned.write_capacity_units", awssdk.ToInt64(index.ProvisionedThroughput.WriteCapacityUnits))
+	}
+}
@@ -14,24 +14,31 @@ import (
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 
 	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
 	"github.com/elastic/elastic-agent-libs/logp"
 )
 
 const metadataPrefix = "aws.ec2.instance."
 
+// namespace is the CloudWatch namespace enriched by this package.
+const namespace = "AWS/EC2"
+
+func init() {
+	metadata.Enrichers.MustRegister(namespace, AddMetadata)
+}
+
 // AddMetadata adds metadata for EC2 instances from a specific region
-func AddMetadata(regionName string, awsConfig awssdk.Config, fips_enabled bool, events map[string]mb.Event) (map[string]mb.Event, error) {
-	svcEC2 := ec2.NewFromConfig(awsConfig, func(o *ec2.Options) {
-		if fips_enabled {
-			o.EndpointOptions.UseFIPSEndpoint = awssdk.FIPSEndpointStateEnabled
-		}
+func AddMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svcEC2 := ec2.NewFromConfig(awsConfig)
 
+	// The instances are shared with the other metricsets of the account
+	instances, err := metadata.Discover(ctx, discovery, "ec2:DescribeInstances", regionName, "", func(ctx context.Context) (interface{}, error) {
+		return getInstancesPerRegion(ctx, svcEC2)
 	})
-
-	instancesOutputs, err := getInstancesPerRegion(svcEC2)
 	if err != nil {
 		return events, fmt.Errorf("getInstancesPerRegion failed, skipping region %s: %w", regionName, err)
 	}
+	instancesOutputs, _ := instances.(map[string]*ec2types.Instance)
 
 	// collect monitoring state for each instance
 	monitoringStates := map[string]string{}
@@ -103,14 +110,14 @@ func AddMetadata(regionName string, awsConfig awssdk.Config, fips_enabled bool,
 	return events, nil
 }
 
-func getInstancesPerRegion(svc *ec2.Client) (map[string]*ec2types.Instance, error) {
+func getInstancesPerRegion(ctx context.Context, svc *ec2.Client) (map[string]*ec2types.Instance, error) {
 	instancesOutputs := map[string]*ec2types.Instance{}
 	output := ec2.DescribeInstancesOutput{NextToken: nil}
 	init := true
 	for init || output.NextToken != nil {
 		init = false
 		describeInstanceInput := &ec2.DescribeInstancesInput{}
-		output, err := svc.DescribeInstances(context.Background(), describeInstanceInput)
+		output, err := svc.DescribeInstances(ctx, describeInstanceInput)
 		if err != nil {
 			err = fmt.Errorf("error DescribeInstances: %w", err)
 			return nil, err
@@ -0,0 +1,282 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
+)
+
+const metadataPrefix = "aws.ecs."
+
+// namespaces are the CloudWatch namespaces enriched by this package.
+var namespaces = []string{"AWS/ECS", "ECS/ContainerInsights"}
+
+const (
+	// maxServicesPerRequest is the maximum number of services of a DescribeServices call.
+	maxServicesPerRequest = 10
+	// maxTasksPerRequest is the maximum number of tasks of a DescribeTasks call.
+	maxTasksPerRequest = 100
+)
+
+func init() {
+	for _, namespace := range namespaces {
+		metadata.Enrichers.MustRegister(namespace, AddMetadata)
+	}
+}
+
+type ecsAPI interface {
+	DescribeClusters(ctx context.Context, params *ecs.DescribeClustersInput, optFns ...func(*ecs.Options)) (*ecs.DescribeClustersOutput, error)
+	DescribeServices(ctx context.Context, params *ecs.DescribeServicesInput, optFns ...func(*ecs.Options)) (*ecs.DescribeServicesOutput, error)
+	DescribeTasks(ctx context.Context, params *ecs.DescribeTasksInput, optFns ...func(*ecs.Options)) (*ecs.DescribeTasksOutput, error)
+	ecs.ListTasksAPIClient
+}
+
+// AddMetadata adds metadata for ECS clusters, services and task definition
+// families from a specific region
+func AddMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svc := ecs.NewFromConfig(awsConfig)
+	return addMetadata(ctx, svc, discovery, regionName, events)
+}
+
+func addMetadata(ctx context.Context, svc ecsAPI, discovery metadata.Discovery, regionName string, events map[string]mb.Event) (map[string]mb.Event, error) {
+	// Group the events by the cluster, service and task definition family of their dimensions
+	clusterEvents := map[string][]mb.Event{}
+	serviceEvents := map[string]map[string][]mb.Event{}
+	familyEvents := map[string]map[string][]mb.Event{}
+	for _, event := range events {
+		cluster := getDimension(event, "ClusterName")
+		if cluster == "" {
+			continue
+		}
+		clusterEvents[cluster] = append(clusterEvents[cluster], event)
+		if service := getDimension(event, "ServiceName"); service != "" {
+			if serviceEvents[cluster] == nil {
+				serviceEvents[cluster] = map[string][]mb.Event{}
+			}
+			serviceEvents[cluster][service] = append(serviceEvents[cluster][service], event)
+		}
+		if family := getDimension(event, "TaskDefinitionFamily"); family != "" {
+			if familyEvents[cluster] == nil {
+				familyEvents[cluster] = map[string][]mb.Event{}
+			}
+			familyEvents[cluster][family] = append(familyEvents[cluster][family], event)
+		}
+	}
+	if len(clusterEvents) == 0 {
+		return events, nil
+	}
+
+	// The clusters and services are described together, so they are shared by
+	// the fetches of the same set of names.
+	clusterNames := sortedNames(clusterEvents)
+	listedClusters, err := metadata.Discover(ctx, discovery, "ecs:DescribeClusters", regionName, strings.Join(clusterNames, ","), func(ctx context.Context) (interface{}, error) {
+		return describeClusters(ctx, svc, clusterNames)
+	})
+	if err != nil {
+		return events, fmt.Errorf("describeClusters failed, skipping region %s: %w", regionName, err)
+	}
+	clusters, _ := listedClusters.([]types.Cluster)
+	for _, cluster := range clusters {
+		for _, event := range clusterEvents[awssdk.ToString(cluster.ClusterName)] {
+			addClusterMetadata(event, cluster)
+		}
+	}
+
+	for cluster, clusterServiceEvents := range serviceEvents {
+		cluster := cluster
+		serviceNames := sortedNames(clusterServiceEvents)
+		listedServices, err := metadata.Discover(ctx, discovery, "ecs:DescribeServices", regionName, cluster+"/"+strings.Join(serviceNames, ","), func(ctx context.Context) (interface{}, error) {
+			return describeServices(ctx, svc, cluster, serviceNames)
+		})
+		if err != nil {
+			return events, fmt.Errorf("describeServices failed for cluster %s in region %s: %w", cluster, regionName, err)
+		}
+		services, _ := listedServices.([]types.Service)
+		for _, service := range services {
+			for _, event := range clusterServiceEvents[awssdk.ToString(service.ServiceName)] {
+				addServiceMetadata(event, service)
+			}
+		}
+	}
+
+	for cluster, clusterFamilyEvents := range familyEvents {
+		for family, familyEvents := range clusterFamilyEvents {
+			cluster, family := cluster, family
+			listedTasks, err := metadata.Discover(ctx, discovery, "ecs:DescribeTasks", regionName, cluster+"/"+family, func(ctx context.Context) (interface{}, error) {
+				return describeTasks(ctx, svc, cluster, family)
+			})
+			if err != nil {
+				return events, fmt.Errorf("describeTasks failed for task definition family %s of cluster %s in region %s: %w", family, cluster, regionName, err)
+			}
+			tasks, _ := listedTasks.([]types.Task)
+			for _, event := range familyEvents {
+				addTasksMetadata(event, tasks)
+			}
+		}
+	}
+	return events, nil
+}
+
+func getDimension(event mb.Event, name string) string {
+	value, err := event.RootFields.GetValue("aws.dimensions." + name)
+	if err != nil {
+		return ""
+	}
+	dimension, _ := value.(string)
+	return dimension
+}
+
+// sortedNames returns the sorted names of the groups of events.
+func sortedNames(groupedEvents map[string][]mb.Event) []string {
+	names := make([]string, 0, len(groupedEvents))
+	for name := range groupedEvents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func describeClusters(ctx context.Context, svc ecsAPI, clusterNames []string) ([]types.Cluster, error) {
+	output, err := svc.DescribeClusters(ctx, &ecs.DescribeClustersInput{Clusters: clusterNames})
+	if err != nil {
+		return nil, fmt.Errorf("error DescribeClusters: %w", err)
+	}
+	return output.Clusters, nil
+}
+
+func describeServices(ctx context.Context, svc ecsAPI, cluster string, serviceNames []string) ([]types.Service, error) {
+	var services []types.Service
+	for start := 0; start < len(serviceNames); start += maxServicesPerRequest {
+		end := start + maxServicesPerRequest
+		if end > len(serviceNames) {
+			end = len(serviceNames)
+		}
+		output, err := svc.DescribeServices(ctx, &ecs.DescribeServicesInput{
+			Cluster:  awssdk.String(cluster),
+			Services: serviceNames[start:end],
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error DescribeServices: %w", err)
+		}
+		services = append(services, output.Services...)
+	}
+	return services, nil
+}
+
+func describeTasks(ctx context.Context, svc ecsAPI, cluster string, family string) ([]types.Task, error) {
+	var taskArns []string
+	paginator := ecs.NewListTasksPaginator(svc, &ecs.ListTasksInput{
+		Cluster: awssdk.String(cluster),
+		Family:  awssdk.String(family),
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error ListTasks with Paginator: %w", err)
+		}
+		taskArns = append(taskArns, output.TaskArns...)
+	}
+
+	var tasks []types.Task
+	for start := 0; start < len(taskArns); start += maxTasksPerRequest {
+		end := start + maxTasksPerRequest
+		if end > len(taskArns) {
+			end = len(taskArns)
+		}
+		output, err := svc.DescribeTasks(ctx, &ecs.DescribeTasksInput{
+			Cluster: awssdk.String(cluster),
+			Tasks:   taskArns[start:end],
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error DescribeTasks: %w", err)
+		}
+		tasks = append(tasks, output.Tasks...)
+	}
+	return tasks, nil
+}
+
+func addClusterMetadata(event mb.Event, cluster types.Cluster) {
+	_, _ = event.RootFields.Put(metadataPrefix+"cluster.name", awssdk.ToString(cluster.ClusterName))
+	if cluster.ClusterArn != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"cluster.arn", *cluster.ClusterArn)
+	}
+	if cluster.Status != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"cluster.status", *cluster.Status)
+	}
+	_, _ = event.RootFields.Put(metadataPrefix+"cluster.services.active", cluster.ActiveServicesCount)
+	_, _ = event.RootFields.Put(metadataPrefix+"cluster.tasks.running", cluster.RunningTasksCount)
+	_, _ = event.RootFields.Put(metadataPrefix+"cluster.tasks.pending", cluster.PendingTasksCount)
+	_, _ = event.RootFields.Put(metadataPrefix+"cluster.container_instances.registered", cluster.RegisteredContainerInstancesCount)
+}
+
+func addServiceMetadata(event mb.Event, service types.Service) {
+	_, _ = event.RootFields.Put(metadataPrefix+"service.name", awssdk.ToString(service.ServiceName))
+	if service.ServiceArn != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"service.arn", *service.ServiceArn)
+	}
+	if service.Status != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"service.status", *service.Status)
+	}
+	if service.LaunchType != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"service.launch_type", string(service.LaunchType))
+	}
+	if service.TaskDefinition != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"service.task_definition", *service.TaskDefinition)
+	}
+	_, _ = event.RootFields.Put(metadataPrefix+"service.tasks.desired", service.DesiredCount)
+	_, _ = event.RootFields.Put(metadataPrefix+"service.tasks.running", service.RunningCount)
+	_, _ = event.RootFields.Put(metadataPrefix+"service.tasks.pending", service.PendingCount)
+
+	_, _ = event.RootFields.Put(metadataPrefix+"service.deployments.count", len(service.Deployments))
+	for _, deployment := range service.Deployments {
+		// The primary deployment is the most recent one, it is the only one
+		// once the deployment is completed.
+		if awssdk.ToString(deployment.Status) != "PRIMARY" {
+			continue
+		}
+		if deployment.RolloutState != "" {
+			_, _ = event.RootFields.Put(metadataPrefix+"service.deployments.primary.rollout_state", string(deployment.RolloutState))
+		}
+		_, _ = event.RootFields.Put(metadataPrefix+"service.deployments.primary.failed_tasks", deployment.FailedTasks)
+		if deployment.UpdatedAt != nil {
+			_, _ = event.RootFields.Put(metadataPrefix+"service.deployments.primary.updated_at", *deployment.UpdatedAt)
+		}
+	}
+}
+
+func addTasksMetadata(event mb.Event, tasks []types.Task) {
+	statuses := map[string]int{}
+	healthy := 0
+	unhealthy := 0
+	for _, task := range tasks {
+		statuses[strings.ToLower(awssdk.ToString(task.LastStatus))]++
+		switch task.HealthStatus {
+		case types.HealthStatusHealthy:
+			healthy++
+		case types.HealthStatusUnhealthy:
+			unhealthy++
+		}
+	}
+
+	_, _ = event.RootFields.Put(metadataPrefix+"task_definition.tasks.count", len(tasks))
+	for status, count := range statuses {
+		if status == "" {
+			continue
+		}
+		_, _ = event.RootFields.Put(metadataPrefix+"task_definition.tasks.status."+status, count)
+	}
+	_, _ = event.RootFields.Put(metadataPrefix+"task_definition.tasks.healthy", healthy)
+	_, _ = event.RootFields.Put(metadataPrefix+"task_definition.tasks.unhealthy", unhealthy)
+}
@@ -0,0 +1,146 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package efs
+
+import (
+	"context"
+	"fmt"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/efs"
+	"github.com/aws/aws-sdk-go-v2/service/efs/types"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
+)
+
+const metadataPrefix = "aws.efs.filesystem."
+
+// namespace is the CloudWatch namespace enriched by this package.
+const namespace = "AWS/EFS"
+
+func init() {
+	metadata.Enrichers.MustRegister(namespace, AddMetadata)
+}
+
+type efsAPI interface {
+	efs.DescribeFileSystemsAPIClient
+	DescribeLifecycleConfiguration(ctx context.Context, params *efs.DescribeLifecycleConfigurationInput, optFns ...func(*efs.Options)) (*efs.DescribeLifecycleConfigurationOutput, error)
+}
+
+// AddMetadata adds metadata for EFS file systems from a specific region
+func AddMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svc := efs.NewFromConfig(awsConfig)
+	return addMetadata(ctx, svc, discovery, regionName, events)
+}
+
+func addMetadata(ctx context.Context, svc efsAPI, discovery metadata.Discovery, regionName string, events map[string]mb.Event) (map[string]mb.Event, error) {
+	// The file systems are shared with the other metricsets of the account
+	listedFileSystems, err := metadata.Discover(ctx, discovery, "elasticfilesystem:DescribeFileSystems", regionName, "", func(ctx context.Context) (interface{}, error) {
+		return getFileSystems(ctx, svc)
+	})
+	if err != nil {
+		return events, fmt.Errorf("getFileSystems failed, skipping region %s: %w", regionName, err)
+	}
+	fileSystems, _ := listedFileSystems.(map[string]types.FileSystemDescription)
+
+	// Lifecycle policies are only described for the file systems with metrics.
+	lifecyclePolicies := map[string][]types.LifecyclePolicy{}
+	for _, event := range events {
+		value, err := event.RootFields.GetValue("aws.dimensions.FileSystemId")
+		if err != nil {
+			continue
+		}
+		fileSystemID, _ := value.(string)
+		fileSystem, ok := fileSystems[fileSystemID]
+		if !ok {
+			continue
+		}
+		addFileSystemMetadata(event, fileSystem)
+
+		policies, ok := lifecyclePolicies[fileSystemID]
+		if !ok {
+			described, err := metadata.Discover(ctx, discovery, "elasticfilesystem:DescribeLifecycleConfiguration", regionName, fileSystemID, func(ctx context.Context) (interface{}, error) {
+				return getLifecyclePolicies(ctx, svc, fileSystemID)
+			})
+			if err != nil {
+				return events, fmt.Errorf("getLifecyclePolicies of file system %s failed in region %s: %w", fileSystemID, regionName, err)
+			}
+			policies, _ = described.([]types.LifecyclePolicy)
+			lifecyclePolicies[fileSystemID] = policies
+		}
+		addLifecyclePoliciesMetadata(event, policies)
+	}
+	return events, nil
+}
+
+// getFileSystems returns the EFS file systems of a region by ID.
+func getFileSystems(ctx context.Context, svc efs.DescribeFileSystemsAPIClient) (map[string]types.FileSystemDescription, error) {
+	fileSystems := map[string]types.FileSystemDescription{}
+	paginator := efs.NewDescribeFileSystemsPaginator(svc, &efs.DescribeFileSystemsInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error DescribeFileSystems with Paginator: %w", err)
+		}
+		for _, fileSystem := range output.FileSystems {
+			fileSystems[awssdk.ToString(fileSystem.FileSystemId)] = fileSystem
+		}
+	}
+	return fileSystems, nil
+}
+
+func getLifecyclePolicies(ctx context.Context, svc efsAPI, fileSystemID string) ([]types.LifecyclePolicy, error) {
+	output, err := svc.DescribeLifecycleConfiguration(ctx, &efs.DescribeLifecycleConfigurationInput{
+		FileSystemId: awssdk.String(fileSystemID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error DescribeLifecycleConfiguration: %w", err)
+	}
+	return output.LifecyclePolicies, nil
+}
+
+func addFileSystemMetadata(event mb.Event, fileSystem types.FileSystemDescription) {
+	_, _ = event.RootFields.Put(metadataPrefix+"id", awssdk.ToString(fileSystem.FileSystemId))
+	if fileSystem.FileSystemArn != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"arn", *fileSystem.FileSystemArn)
+	}
+	if fileSystem.Name != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"name", *fileSystem.Name)
+	}
+	if fileSystem.LifeCycleState != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"state", string(fileSystem.LifeCycleState))
+	}
+	if fileSystem.PerformanceMode != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"performance_mode", string(fileSystem.PerformanceMode))
+	}
+	if fileSystem.ThroughputMode != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"throughput_mode", string(fileSystem.ThroughputMode))
+	}
+	if fileSystem.ProvisionedThroughputInMibps != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"provisioned_throughput.mibps", *fileSystem.ProvisionedThroughputInMibps)
+	}
+	if fileSystem.Encrypted != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"encrypted", *fileSystem.Encrypted)
+	}
+	if fileSystem.AvailabilityZoneName != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"availability_zone", *fileSystem.AvailabilityZoneName)
+	}
+	_, _ = event.RootFields.Put(metadataPrefix+"mount_targets.count", fileSystem.NumberOfMountTargets)
+	if fileSystem.SizeInBytes != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"size.bytes", fileSystem.SizeInBytes.Value)
+	}
+}
+
+func addLifecyclePoliciesMetadata(event mb.Event, policies []types.LifecyclePolicy) {
+	for _, policy := range policies {
+		if policy.TransitionToIA != "" {
+			_, _ = event.RootFields.Put(metadataPrefix+"lifecycle.transition_to_ia", string(policy.TransitionToIA))
+		}
+		if policy.TransitionToPrimaryStorageClass != "" {
+			_, _ = event.RootFields.Put(metadataPrefix+"lifecycle.transition_to_primary_storage_class", string(policy.TransitionToPrimaryStorageClass))
+		}
+	}
+}
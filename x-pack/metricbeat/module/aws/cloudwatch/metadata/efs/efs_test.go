@@ -0,0 +1,98 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+//go:build !integration
+// +build !integration
+
+package efs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/efs"
+	"github.com/aws/aws-sdk-go-v2/service/efs/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+// MockEFSClient struct is used for unit tests.
+type MockEFSClient struct {
+	describeFileSystemsErr error
+	describeLifecycleErr   error
+}
+
+// DescribeFileSystems implements efsAPI.
+func (m *MockEFSClient) DescribeFileSystems(_ context.Context, _ *efs.DescribeFileSystemsInput, _ ...func(*efs.Options)) (*efs.DescribeFileSystemsOutput, error) {
+	if m.describeFileSystemsErr != nil {
+		return nil, m.describeFileSystemsErr
+	}
+	return &efs.DescribeFileSystemsOutput{
+		FileSystems: []types.FileSystemDescription{{
+			FileSystemId:    awssdk.String("fs-1"),
+			Name:            awssdk.String("data"),
+			LifeCycleState:  types.LifeCycleStateAvailable,
+			PerformanceMode: types.PerformanceModeGeneralPurpose,
+		}},
+	}, nil
+}
+
+// DescribeLifecycleConfiguration implements efsAPI.
+func (m *MockEFSClient) DescribeLifecycleConfiguration(_ context.Context, _ *efs.DescribeLifecycleConfigurationInput, _ ...func(*efs.Options)) (*efs.DescribeLifecycleConfigurationOutput, error) {
+	if m.describeLifecycleErr != nil {
+		return nil, m.describeLifecycleErr
+	}
+	return &efs.DescribeLifecycleConfigurationOutput{
+		LifecyclePolicies: []types.LifecyclePolicy{{TransitionToIA: types.TransitionToIARulesAfter30Days}},
+	}, nil
+}
+
+func newEvents() map[string]mb.Event {
+	return map[string]mb.Event{
+		"fs-1": {RootFields: mapstr.M{"aws": mapstr.M{"dimensions": mapstr.M{"FileSystemId": "fs-1"}}}},
+	}
+}
+
+func TestAddMetadata(t *testing.T) {
+	events, err := addMetadata(context.Background(), &MockEFSClient{}, nil, "us-east-1", newEvents())
+	assert.NoError(t, err)
+
+	fields := events["fs-1"].RootFields
+	for field, expected := range map[string]interface{}{
+		"aws.efs.filesystem.id":                         "fs-1",
+		"aws.efs.filesystem.name":                       "data",
+		"aws.efs.filesystem.state":                      "available",
+		"aws.efs.filesystem.lifecycle.transition_to_ia": "AFTER_30_DAYS",
+	} {
+		value, err := fields.GetValue(field)
+		assert.NoError(t, err, field)
+		assert.Equal(t, expected, value, field)
+	}
+}
+
+func TestAddMetadataFailure(t *testing.T) {
+	apiErr := errors.New("throttled")
+
+	cases := map[string]*MockEFSClient{
+		"describe file systems": {describeFileSystemsErr: apiErr},
+		"describe lifecycle":    {describeLifecycleErr: apiErr},
+	}
+
+	for title, svc := range cases {
+		t.Run(title, func(t *testing.T) {
+			events, err := addMetadata(context.Background(), svc, nil, "us-east-1", newEvents())
+			assert.ErrorIs(t, err, apiErr)
+
+			// The events are returned so they can still be reported without
+			// lifecycle metadata.
+			assert.Len(t, events, 1)
+			_, err = events["fs-1"].RootFields.GetValue("aws.efs.filesystem.lifecycle")
+			assert.Error(t, err)
+		})
+	}
+}
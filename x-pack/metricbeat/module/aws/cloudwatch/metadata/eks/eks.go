@@ -0,0 +1,165 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package eks
+
+import (
+	"context"
+	"fmt"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
+)
+
+const metadataPrefix = "aws.eks.cluster."
+
+// namespaces are the CloudWatch namespaces enriched by this package, the EKS
+// control plane metrics and the Container Insights metrics of EKS clusters.
+var namespaces = []string{"AWS/EKS", "ContainerInsights"}
+
+func init() {
+	for _, namespace := range namespaces {
+		metadata.Enrichers.MustRegister(namespace, AddMetadata)
+	}
+}
+
+type eksAPI interface {
+	DescribeCluster(ctx context.Context, params *eks.DescribeClusterInput, optFns ...func(*eks.Options)) (*eks.DescribeClusterOutput, error)
+	DescribeNodegroup(ctx context.Context, params *eks.DescribeNodegroupInput, optFns ...func(*eks.Options)) (*eks.DescribeNodegroupOutput, error)
+	eks.ListNodegroupsAPIClient
+}
+
+// AddMetadata adds metadata and the health of EKS clusters from a specific region
+func AddMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svc := eks.NewFromConfig(awsConfig)
+	return addMetadata(ctx, svc, discovery, regionName, events)
+}
+
+func addMetadata(ctx context.Context, svc eksAPI, discovery metadata.Discovery, regionName string, events map[string]mb.Event) (map[string]mb.Event, error) {
+	clusterEvents := map[string][]mb.Event{}
+	for _, event := range events {
+		value, err := event.RootFields.GetValue("aws.dimensions.ClusterName")
+		if err != nil {
+			continue
+		}
+		if clusterName, ok := value.(string); ok && clusterName != "" {
+			clusterEvents[clusterName] = append(clusterEvents[clusterName], event)
+		}
+	}
+
+	for clusterName, eventsOfCluster := range clusterEvents {
+		clusterName := clusterName
+		described, err := metadata.Discover(ctx, discovery, "eks:DescribeCluster", regionName, clusterName, func(ctx context.Context) (interface{}, error) {
+			output, err := svc.DescribeCluster(ctx, &eks.DescribeClusterInput{Name: awssdk.String(clusterName)})
+			if err != nil {
+				return nil, err
+			}
+			return output.Cluster, nil
+		})
+		if err != nil {
+			return events, fmt.Errorf("DescribeCluster of cluster %s failed in region %s: %w", clusterName, regionName, err)
+		}
+		cluster, _ := described.(*types.Cluster)
+		if cluster == nil {
+			continue
+		}
+
+		listed, err := metadata.Discover(ctx, discovery, "eks:DescribeNodegroup", regionName, clusterName, func(ctx context.Context) (interface{}, error) {
+			return describeNodegroups(ctx, svc, clusterName)
+		})
+		if err != nil {
+			return events, fmt.Errorf("describeNodegroups of cluster %s failed in region %s: %w", clusterName, regionName, err)
+		}
+		nodegroups, _ := listed.([]types.Nodegroup)
+
+		for _, event := range eventsOfCluster {
+			addClusterMetadata(event, *cluster)
+			addNodegroupsHealth(event, cluster.Status, nodegroups)
+		}
+	}
+	return events, nil
+}
+
+func describeNodegroups(ctx context.Context, svc eksAPI, clusterName string) ([]types.Nodegroup, error) {
+	var nodegroups []types.Nodegroup
+	paginator := eks.NewListNodegroupsPaginator(svc, &eks.ListNodegroupsInput{ClusterName: awssdk.String(clusterName)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error ListNodegroups with Paginator: %w", err)
+		}
+		for _, nodegroupName := range page.Nodegroups {
+			output, err := svc.DescribeNodegroup(ctx, &eks.DescribeNodegroupInput{
+				ClusterName:   awssdk.String(clusterName),
+				NodegroupName: awssdk.String(nodegroupName),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error DescribeNodegroup: %w", err)
+			}
+			if output.Nodegroup != nil {
+				nodegroups = append(nodegroups, *output.Nodegroup)
+			}
+		}
+	}
+	return nodegroups, nil
+}
+
+func addClusterMetadata(event mb.Event, cluster types.Cluster) {
+	_, _ = event.RootFields.Put(metadataPrefix+"name", awssdk.ToString(cluster.Name))
+	if cluster.Arn != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"arn", *cluster.Arn)
+	}
+	if cluster.Status != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"status", string(cluster.Status))
+	}
+	if cluster.Version != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"version", *cluster.Version)
+	}
+	if cluster.PlatformVersion != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"platform_version", *cluster.PlatformVersion)
+	}
+	if cluster.Endpoint != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"endpoint", *cluster.Endpoint)
+	}
+	if cluster.CreatedAt != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"created_at", *cluster.CreatedAt)
+	}
+}
+
+// addNodegroupsHealth adds the health of the managed node groups of a cluster.
+// A cluster is healthy when it is active and none of its node groups is
+// degraded or reports health issues.
+func addNodegroupsHealth(event mb.Event, clusterStatus types.ClusterStatus, nodegroups []types.Nodegroup) {
+	degraded := 0
+	var desiredSize int32
+	var issues []string
+	for _, nodegroup := range nodegroups {
+		if nodegroup.ScalingConfig != nil && nodegroup.ScalingConfig.DesiredSize != nil {
+			desiredSize += *nodegroup.ScalingConfig.DesiredSize
+		}
+
+		var nodegroupIssues []types.Issue
+		if nodegroup.Health != nil {
+			nodegroupIssues = nodegroup.Health.Issues
+		}
+		if nodegroup.Status == types.NodegroupStatusDegraded || len(nodegroupIssues) > 0 {
+			degraded++
+		}
+		for _, issue := range nodegroupIssues {
+			issues = append(issues, string(issue.Code))
+		}
+	}
+
+	_, _ = event.RootFields.Put(metadataPrefix+"nodegroups.count", len(nodegroups))
+	_, _ = event.RootFields.Put(metadataPrefix+"nodegroups.degraded", degraded)
+	_, _ = event.RootFields.Put(metadataPrefix+"nodegroups.desired_size", desiredSize)
+	if len(issues) > 0 {
+		_, _ = event.RootFields.Put(metadataPrefix+"nodegroups.issues", issues)
+	}
+	_, _ = event.RootFields.Put(metadataPrefix+"healthy", clusterStatus == types.ClusterStatusActive && degraded == 0)
+}
@@ -0,0 +1,176 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package elasticache
+
+import (
+	"context"
+	"fmt"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache/types"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
+)
+
+const metadataPrefix = "aws.elasticache."
+
+// namespace is the CloudWatch namespace enriched by this package.
+const namespace = "AWS/ElastiCache"
+
+func init() {
+	metadata.Enrichers.MustRegister(namespace, AddMetadata)
+}
+
+type elasticacheAPI interface {
+	elasticache.DescribeCacheClustersAPIClient
+	elasticache.DescribeReplicationGroupsAPIClient
+}
+
+// AddMetadata adds metadata for ElastiCache clusters, nodes and replication
+// groups from a specific region
+func AddMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svc := elasticache.NewFromConfig(awsConfig)
+	return addMetadata(ctx, svc, discovery, regionName, events)
+}
+
+func addMetadata(ctx context.Context, svc elasticacheAPI, discovery metadata.Discovery, regionName string, events map[string]mb.Event) (map[string]mb.Event, error) {
+	// The clusters and replication groups are shared with the other metricsets of
+	// the account
+	listedClusters, err := metadata.Discover(ctx, discovery, "elasticache:DescribeCacheClusters", regionName, "", func(ctx context.Context) (interface{}, error) {
+		return getCacheClustersPerRegion(ctx, svc)
+	})
+	if err != nil {
+		return events, fmt.Errorf("getCacheClustersPerRegion failed, skipping region %s: %w", regionName, err)
+	}
+	clusters, _ := listedClusters.(map[string]types.CacheCluster)
+
+	listedNodeRoles, err := metadata.Discover(ctx, discovery, "elasticache:DescribeReplicationGroups", regionName, "", func(ctx context.Context) (interface{}, error) {
+		return getNodeRolesPerRegion(ctx, svc)
+	})
+	if err != nil {
+		return events, fmt.Errorf("getNodeRolesPerRegion failed in region %s: %w", regionName, err)
+	}
+	nodeRoles, _ := listedNodeRoles.(map[string]string)
+
+	for _, event := range events {
+		clusterID := getDimension(event, "CacheClusterId")
+		cluster, ok := clusters[clusterID]
+		if !ok {
+			continue
+		}
+		addClusterMetadata(event, cluster)
+
+		nodeID := getDimension(event, "CacheNodeId")
+		if nodeID == "" {
+			continue
+		}
+		_, _ = event.RootFields.Put(metadataPrefix+"node.id", nodeID)
+		for _, node := range cluster.CacheNodes {
+			if awssdk.ToString(node.CacheNodeId) == nodeID {
+				addNodeMetadata(event, node)
+				break
+			}
+		}
+		if role, ok := nodeRoles[nodeKey(clusterID, nodeID)]; ok {
+			_, _ = event.RootFields.Put(metadataPrefix+"node.role", role)
+		}
+	}
+	return events, nil
+}
+
+func getDimension(event mb.Event, name string) string {
+	value, err := event.RootFields.GetValue("aws.dimensions." + name)
+	if err != nil {
+		return ""
+	}
+	dimension, _ := value.(string)
+	return dimension
+}
+
+func nodeKey(clusterID string, nodeID string) string {
+	return clusterID + "/" + nodeID
+}
+
+// getCacheClustersPerRegion returns the cache clusters of a region, with their
+// nodes, by cluster ID.
+func getCacheClustersPerRegion(ctx context.Context, svc elasticache.DescribeCacheClustersAPIClient) (map[string]types.CacheCluster, error) {
+	clusters := map[string]types.CacheCluster{}
+	paginator := elasticache.NewDescribeCacheClustersPaginator(svc, &elasticache.DescribeCacheClustersInput{
+		ShowCacheNodeInfo: awssdk.Bool(true),
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error DescribeCacheClusters with Paginator: %w", err)
+		}
+		for _, cluster := range output.CacheClusters {
+			clusters[awssdk.ToString(cluster.CacheClusterId)] = cluster
+		}
+	}
+	return clusters, nil
+}
+
+// getNodeRolesPerRegion returns the role, primary or replica, of the nodes of
+// the replication groups of a region.
+func getNodeRolesPerRegion(ctx context.Context, svc elasticache.DescribeReplicationGroupsAPIClient) (map[string]string, error) {
+	roles := map[string]string{}
+	paginator := elasticache.NewDescribeReplicationGroupsPaginator(svc, &elasticache.DescribeReplicationGroupsInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return roles, fmt.Errorf("error DescribeReplicationGroups with Paginator: %w", err)
+		}
+		for _, replicationGroup := range output.ReplicationGroups {
+			for _, nodeGroup := range replicationGroup.NodeGroups {
+				for _, member := range nodeGroup.NodeGroupMembers {
+					if member.CurrentRole == nil {
+						continue
+					}
+					roles[nodeKey(awssdk.ToString(member.CacheClusterId), awssdk.ToString(member.CacheNodeId))] = *member.CurrentRole
+				}
+			}
+		}
+	}
+	return roles, nil
+}
+
+func addClusterMetadata(event mb.Event, cluster types.CacheCluster) {
+	_, _ = event.RootFields.Put(metadataPrefix+"cluster.id", awssdk.ToString(cluster.CacheClusterId))
+	if cluster.ARN != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"cluster.arn", *cluster.ARN)
+	}
+	if cluster.CacheClusterStatus != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"cluster.status", *cluster.CacheClusterStatus)
+	}
+	if cluster.Engine != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"cluster.engine.name", *cluster.Engine)
+	}
+	if cluster.EngineVersion != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"cluster.engine.version", *cluster.EngineVersion)
+	}
+	if cluster.CacheNodeType != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"cluster.node_type", *cluster.CacheNodeType)
+	}
+	if cluster.NumCacheNodes != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"cluster.nodes.count", *cluster.NumCacheNodes)
+	}
+	if cluster.ReplicationGroupId != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"replication_group.id", *cluster.ReplicationGroupId)
+	}
+}
+
+func addNodeMetadata(event mb.Event, node types.CacheNode) {
+	if node.CacheNodeStatus != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"node.status", *node.CacheNodeStatus)
+	}
+	if node.CustomerAvailabilityZone != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"node.availability_zone", *node.CustomerAvailabilityZone)
+	}
+	if node.Endpoint != nil && node.Endpoint.Address != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"node.endpoint", *node.Endpoint.Address)
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package emr
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/emr"
+	"github.com/aws/aws-sdk-go-v2/service/emr/types"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
+)
+
+const metadataPrefix = "aws.emr."
+
+// namespace is the CloudWatch namespace enriched by this package.
+const namespace = "AWS/ElasticMapReduce"
+
+// activeClusterStates are the states of the clusters that publish metrics.
+var activeClusterStates = []types.ClusterState{
+	types.ClusterStateStarting,
+	types.ClusterStateBootstrapping,
+	types.ClusterStateRunning,
+	types.ClusterStateWaiting,
+	types.ClusterStateTerminating,
+}
+
+func init() {
+	metadata.Enrichers.MustRegister(namespace, AddMetadata)
+}
+
+type emrAPI interface {
+	emr.ListClustersAPIClient
+	emr.ListInstanceGroupsAPIClient
+	DescribeCluster(ctx context.Context, params *emr.DescribeClusterInput, optFns ...func(*emr.Options)) (*emr.DescribeClusterOutput, error)
+}
+
+// AddMetadata adds metadata for EMR clusters and their instance groups from a
+// specific region
+func AddMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svc := emr.NewFromConfig(awsConfig)
+	return addMetadata(ctx, svc, discovery, regionName, events)
+}
+
+func addMetadata(ctx context.Context, svc emrAPI, discovery metadata.Discovery, regionName string, events map[string]mb.Event) (map[string]mb.Event, error) {
+	// The active clusters are shared with the other metricsets of the account
+	listedClusterIDs, err := metadata.Discover(ctx, discovery, "elasticmapreduce:ListClusters", regionName, "", func(ctx context.Context) (interface{}, error) {
+		return getActiveClusterIDs(ctx, svc)
+	})
+	if err != nil {
+		return events, fmt.Errorf("getActiveClusterIDs failed, skipping region %s: %w", regionName, err)
+	}
+	clusterIDs, _ := listedClusterIDs.(map[string]struct{})
+
+	// Clusters are described lazily, only the active clusters with metrics in
+	// this fetch are described.
+	clusters := map[string]*types.Cluster{}
+	instanceGroups := map[string][]types.InstanceGroup{}
+	for _, event := range events {
+		value, err := event.RootFields.GetValue("aws.dimensions.JobFlowId")
+		if err != nil {
+			continue
+		}
+		clusterID, _ := value.(string)
+		if _, ok := clusterIDs[clusterID]; !ok {
+			continue
+		}
+
+		cluster, ok := clusters[clusterID]
+		if !ok {
+			described, err := metadata.Discover(ctx, discovery, "elasticmapreduce:DescribeCluster", regionName, clusterID, func(ctx context.Context) (interface{}, error) {
+				return describeCluster(ctx, svc, clusterID)
+			})
+			if err != nil {
+				return events, fmt.Errorf("describeCluster of cluster %s failed in region %s: %w", clusterID, regionName, err)
+			}
+			cluster, _ = described.(*types.Cluster)
+			clusters[clusterID] = cluster
+
+			listed, err := metadata.Discover(ctx, discovery, "elasticmapreduce:ListInstanceGroups", regionName, clusterID, func(ctx context.Context) (interface{}, error) {
+				return getInstanceGroups(ctx, svc, clusterID)
+			})
+			if err != nil {
+				return events, fmt.Errorf("getInstanceGroups of cluster %s failed in region %s: %w", clusterID, regionName, err)
+			}
+			instanceGroups[clusterID], _ = listed.([]types.InstanceGroup)
+		}
+
+		if cluster != nil {
+			addClusterMetadata(event, cluster)
+		}
+		addInstanceGroupsMetadata(event, instanceGroups[clusterID])
+	}
+	return events, nil
+}
+
+func describeCluster(ctx context.Context, svc emrAPI, clusterID string) (*types.Cluster, error) {
+	output, err := svc.DescribeCluster(ctx, &emr.DescribeClusterInput{ClusterId: awssdk.String(clusterID)})
+	if err != nil {
+		return nil, fmt.Errorf("error DescribeCluster: %w", err)
+	}
+	return output.Cluster, nil
+}
+
+// getActiveClusterIDs returns the IDs of the active clusters of a region.
+func getActiveClusterIDs(ctx context.Context, svc emr.ListClustersAPIClient) (map[string]struct{}, error) {
+	clusterIDs := map[string]struct{}{}
+	paginator := emr.NewListClustersPaginator(svc, &emr.ListClustersInput{ClusterStates: activeClusterStates})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error ListClusters with Paginator: %w", err)
+		}
+		for _, cluster := range output.Clusters {
+			clusterIDs[awssdk.ToString(cluster.Id)] = struct{}{}
+		}
+	}
+	return clusterIDs, nil
+}
+
+func getInstanceGroups(ctx context.Context, svc emr.ListInstanceGroupsAPIClient, clusterID string) ([]types.InstanceGroup, error) {
+	var instanceGroups []types.InstanceGroup
+	paginator := emr.NewListInstanceGroupsPaginator(svc, &emr.ListInstanceGroupsInput{ClusterId: awssdk.String(clusterID)})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return instanceGroups, fmt.Errorf("error ListInstanceGroups with Paginator: %w", err)
+		}
+		instanceGroups = append(instanceGroups, output.InstanceGroups...)
+	}
+	return instanceGroups, nil
+}
+
+func addClusterMetadata(event mb.Event, cluster *types.Cluster) {
+	_, _ = event.RootFields.Put(metadataPrefix+"cluster.id", awssdk.ToString(cluster.Id))
+	_, _ = event.RootFields.Put(metadataPrefix+"cluster.name", awssdk.ToString(cluster.Name))
+	if cluster.ClusterArn != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"cluster.arn", *cluster.ClusterArn)
+	}
+	if cluster.Status != nil && cluster.Status.State != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"cluster.state", string(cluster.Status.State))
+	}
+	if cluster.ReleaseLabel != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"cluster.release_label", *cluster.ReleaseLabel)
+	}
+	if cluster.InstanceCollectionType != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"cluster.instance_collection_type", string(cluster.InstanceCollectionType))
+	}
+	if cluster.NormalizedInstanceHours != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"cluster.normalized_instance_hours", *cluster.NormalizedInstanceHours)
+	}
+	_, _ = event.RootFields.Put(metadataPrefix+"cluster.auto_terminate", cluster.AutoTerminate)
+
+	if len(cluster.Applications) > 0 {
+		applications := make([]string, 0, len(cluster.Applications))
+		for _, application := range cluster.Applications {
+			applications = append(applications, awssdk.ToString(application.Name))
+		}
+		_, _ = event.RootFields.Put(metadataPrefix+"cluster.applications", applications)
+	}
+}
+
+// addInstanceGroupsMetadata adds the instance groups of a cluster by role,
+// master, core or task. A cluster can have multiple task instance groups, their
+// instances are summed.
+func addInstanceGroupsMetadata(event mb.Event, instanceGroups []types.InstanceGroup) {
+	for _, group := range instanceGroups {
+		if group.InstanceGroupType == "" {
+			continue
+		}
+		prefix := metadataPrefix + "instance_groups." + strings.ToLower(string(group.InstanceGroupType)) + "."
+
+		count, _ := event.RootFields.GetValue(prefix + "count")
+		groupCount, _ := count.(int)
+		_, _ = event.RootFields.Put(prefix+"count", groupCount+1)
+
+		if group.RunningInstanceCount != nil {
+			running, _ := event.RootFields.GetValue(prefix + "instances.running")
+			runningCount, _ := running.(int32)
+			_, _ = event.RootFields.Put(prefix+"instances.running", runningCount+*group.RunningInstanceCount)
+		}
+		if group.RequestedInstanceCount != nil {
+			requested, _ := event.RootFields.GetValue(prefix + "instances.requested")
+			requestedCount, _ := requested.(int32)
+			_, _ = event.RootFields.Put(prefix+"instances.requested", requestedCount+*group.RequestedInstanceCount)
+		}
+		if group.InstanceType != nil {
+			instanceTypes, _ := event.RootFields.GetValue(prefix + "instance_types")
+			typeList, _ := instanceTypes.([]string)
+			_, _ = event.RootFields.Put(prefix+"instance_types", append(typeList, *group.InstanceType))
+		}
+	}
+}
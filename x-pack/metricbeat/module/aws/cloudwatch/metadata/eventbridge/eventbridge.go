@@ -0,0 +1,122 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package eventbridge
+
+import (
+	"context"
+	"fmt"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
+)
+
+const metadataPrefix = "aws.eventbridge."
+
+// namespace is the CloudWatch namespace enriched by this package.
+const namespace = "AWS/Events"
+
+// defaultEventBus is the event bus of the rules whose metrics don't have an
+// EventBusName dimension.
+const defaultEventBus = "default"
+
+func init() {
+	metadata.Enrichers.MustRegister(namespace, AddMetadata)
+}
+
+type eventbridgeAPI interface {
+	ListRules(ctx context.Context, params *eventbridge.ListRulesInput, optFns ...func(*eventbridge.Options)) (*eventbridge.ListRulesOutput, error)
+}
+
+// AddMetadata adds metadata for EventBridge rules from a specific region
+func AddMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svc := eventbridge.NewFromConfig(awsConfig)
+	return addMetadata(ctx, svc, discovery, regionName, events)
+}
+
+func addMetadata(ctx context.Context, svc eventbridgeAPI, discovery metadata.Discovery, regionName string, events map[string]mb.Event) (map[string]mb.Event, error) {
+	// Rules are listed once per event bus.
+	busRules := map[string]map[string]types.Rule{}
+	for _, event := range events {
+		busName := getDimension(event, "EventBusName")
+		if busName != "" {
+			_, _ = event.RootFields.Put(metadataPrefix+"event_bus.name", busName)
+		}
+
+		ruleName := getDimension(event, "RuleName")
+		if ruleName == "" {
+			continue
+		}
+		if busName == "" {
+			busName = defaultEventBus
+		}
+
+		rules, ok := busRules[busName]
+		if !ok {
+			listed, err := metadata.Discover(ctx, discovery, "events:ListRules", regionName, busName, func(ctx context.Context) (interface{}, error) {
+				return getRules(ctx, svc, busName)
+			})
+			if err != nil {
+				return events, fmt.Errorf("getRules of event bus %s failed in region %s: %w", busName, regionName, err)
+			}
+			rules, _ = listed.(map[string]types.Rule)
+			busRules[busName] = rules
+		}
+		if rule, ok := rules[ruleName]; ok {
+			addRuleMetadata(event, rule)
+		}
+	}
+	return events, nil
+}
+
+func getDimension(event mb.Event, name string) string {
+	value, err := event.RootFields.GetValue("aws.dimensions." + name)
+	if err != nil {
+		return ""
+	}
+	dimension, _ := value.(string)
+	return dimension
+}
+
+// getRules returns the rules of an event bus by name.
+func getRules(ctx context.Context, svc eventbridgeAPI, busName string) (map[string]types.Rule, error) {
+	rules := map[string]types.Rule{}
+	input := &eventbridge.ListRulesInput{EventBusName: awssdk.String(busName)}
+	for {
+		output, err := svc.ListRules(ctx, input)
+		if err != nil {
+			return rules, fmt.Errorf("error ListRules: %w", err)
+		}
+		for _, rule := range output.Rules {
+			rules[awssdk.ToString(rule.Name)] = rule
+		}
+		if output.NextToken == nil {
+			return rules, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+func addRuleMetadata(event mb.Event, rule types.Rule) {
+	_, _ = event.RootFields.Put(metadataPrefix+"rule.name", awssdk.ToString(rule.Name))
+	_, _ = event.RootFields.Put(metadataPrefix+"rule.arn", awssdk.ToString(rule.Arn))
+	_, _ = event.RootFields.Put(metadataPrefix+"event_bus.name", awssdk.ToString(rule.EventBusName))
+	if rule.State != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"rule.state", string(rule.State))
+	}
+	if rule.Description != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"rule.description", *rule.Description)
+	}
+	if rule.ScheduleExpression != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"rule.schedule_expression", *rule.ScheduleExpression)
+	}
+	_, _ = event.RootFields.Put(metadataPrefix+"rule.scheduled", rule.ScheduleExpression != nil)
+	if rule.ManagedBy != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"rule.managed_by", *rule.ManagedBy)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package fsx
+
+import (
+	"context"
+	"fmt"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/fsx"
+	"github.com/aws/aws-sdk-go-v2/service/fsx/types"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
+)
+
+const metadataPrefix = "aws.fsx.filesystem."
+
+// namespace is the CloudWatch namespace enriched by this package.
+const namespace = "AWS/FSx"
+
+func init() {
+	metadata.Enrichers.MustRegister(namespace, AddMetadata)
+}
+
+// AddMetadata adds metadata for FSx file systems from a specific region
+func AddMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svc := fsx.NewFromConfig(awsConfig)
+
+	// The file systems are shared with the other metricsets of the account
+	listedFileSystems, err := metadata.Discover(ctx, discovery, "fsx:DescribeFileSystems", regionName, "", func(ctx context.Context) (interface{}, error) {
+		return getFileSystems(ctx, svc)
+	})
+	if err != nil {
+		return events, fmt.Errorf("getFileSystems failed, skipping region %s: %w", regionName, err)
+	}
+	fileSystems, _ := listedFileSystems.(map[string]types.FileSystem)
+
+	for _, event := range events {
+		value, err := event.RootFields.GetValue("aws.dimensions.FileSystemId")
+		if err != nil {
+			continue
+		}
+		fileSystemID, _ := value.(string)
+		if fileSystem, ok := fileSystems[fileSystemID]; ok {
+			addFileSystemMetadata(event, fileSystem)
+		}
+	}
+	return events, nil
+}
+
+// getFileSystems returns the FSx file systems of a region by ID.
+func getFileSystems(ctx context.Context, svc fsx.DescribeFileSystemsAPIClient) (map[string]types.FileSystem, error) {
+	fileSystems := map[string]types.FileSystem{}
+	paginator := fsx.NewDescribeFileSystemsPaginator(svc, &fsx.DescribeFileSystemsInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error DescribeFileSystems with Paginator: %w", err)
+		}
+		for _, fileSystem := range output.FileSystems {
+			fileSystems[awssdk.ToString(fileSystem.FileSystemId)] = fileSystem
+		}
+	}
+	return fileSystems, nil
+}
+
+func addFileSystemMetadata(event mb.Event, fileSystem types.FileSystem) {
+	_, _ = event.RootFields.Put(metadataPrefix+"id", awssdk.ToString(fileSystem.FileSystemId))
+	if fileSystem.ResourceARN != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"arn", *fileSystem.ResourceARN)
+	}
+	if fileSystem.FileSystemType != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"type", string(fileSystem.FileSystemType))
+	}
+	if fileSystem.Lifecycle != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"lifecycle", string(fileSystem.Lifecycle))
+	}
+	if fileSystem.StorageType != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"storage.type", string(fileSystem.StorageType))
+	}
+	if fileSystem.StorageCapacity != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"storage.capacity.gib", *fileSystem.StorageCapacity)
+	}
+	if fileSystem.DNSName != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"dns_name", *fileSystem.DNSName)
+	}
+	if fileSystem.VpcId != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"vpc_id", *fileSystem.VpcId)
+	}
+
+	// The deployment type and throughput capacity are in the configuration of
+	// the type of the file system.
+	switch {
+	case fileSystem.WindowsConfiguration != nil:
+		config := fileSystem.WindowsConfiguration
+		putDeployment(event, string(config.DeploymentType), config.ThroughputCapacity)
+	case fileSystem.LustreConfiguration != nil:
+		config := fileSystem.LustreConfiguration
+		putDeployment(event, string(config.DeploymentType), nil)
+		if config.PerUnitStorageThroughput != nil {
+			_, _ = event.RootFields.Put(metadataPrefix+"per_unit_storage_throughput.mbps", *config.PerUnitStorageThroughput)
+		}
+	case fileSystem.OntapConfiguration != nil:
+		config := fileSystem.OntapConfiguration
+		putDeployment(event, string(config.DeploymentType), config.ThroughputCapacity)
+	case fileSystem.OpenZFSConfiguration != nil:
+		config := fileSystem.OpenZFSConfiguration
+		putDeployment(event, string(config.DeploymentType), config.ThroughputCapacity)
+	}
+}
+
+func putDeployment(event mb.Event, deploymentType string, throughputCapacity *int32) {
+	if deploymentType != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"deployment_type", deploymentType)
+	}
+	if throughputCapacity != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"throughput_capacity.mbps", *throughputCapacity)
+	}
+}
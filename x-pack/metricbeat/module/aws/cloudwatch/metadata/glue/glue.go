@@ -0,0 +1,190 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package glue
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/glue"
+	"github.com/aws/aws-sdk-go-v2/service/glue/types"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
+)
+
+const metadataPrefix = "aws.glue."
+
+// namespace is the CloudWatch namespace of the Glue job metrics.
+const namespace = "Glue"
+
+// allJobRuns is the value of the JobRunId dimension of the metrics aggregated
+// for all the runs of a job.
+const allJobRuns = "ALL"
+
+func init() {
+	metadata.Enrichers.MustRegister(namespace, AddMetadata)
+}
+
+type glueAPI interface {
+	GetJob(ctx context.Context, params *glue.GetJobInput, optFns ...func(*glue.Options)) (*glue.GetJobOutput, error)
+	GetJobRuns(ctx context.Context, params *glue.GetJobRunsInput, optFns ...func(*glue.Options)) (*glue.GetJobRunsOutput, error)
+}
+
+// job holds a Glue job and its most recent runs.
+type job struct {
+	job  *types.Job
+	runs []types.JobRun
+}
+
+// AddMetadata adds metadata for Glue jobs and job runs from a specific region
+func AddMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svc := glue.NewFromConfig(awsConfig)
+	return addMetadata(ctx, svc, discovery, regionName, events)
+}
+
+func addMetadata(ctx context.Context, svc glueAPI, discovery metadata.Discovery, regionName string, events map[string]mb.Event) (map[string]mb.Event, error) {
+	jobs := map[string]*job{}
+	for _, event := range events {
+		jobName := getDimension(event, "JobName")
+		if jobName == "" {
+			continue
+		}
+
+		j, ok := jobs[jobName]
+		if !ok {
+			described, err := metadata.Discover(ctx, discovery, "glue:GetJob", regionName, jobName, func(ctx context.Context) (interface{}, error) {
+				return getJob(ctx, svc, jobName)
+			})
+			if err != nil {
+				return events, fmt.Errorf("getJob of job %s failed in region %s: %w", jobName, regionName, err)
+			}
+			j, _ = described.(*job)
+			jobs[jobName] = j
+		}
+		if j.job != nil {
+			addJobMetadata(event, j.job)
+		}
+
+		jobRunID := getDimension(event, "JobRunId")
+		if jobRunID == allJobRuns {
+			addJobRunsMetadata(event, j.runs)
+			continue
+		}
+		for _, run := range j.runs {
+			if awssdk.ToString(run.Id) == jobRunID {
+				addJobRunMetadata(event, run)
+				break
+			}
+		}
+	}
+	return events, nil
+}
+
+func getDimension(event mb.Event, name string) string {
+	value, err := event.RootFields.GetValue("aws.dimensions." + name)
+	if err != nil {
+		return ""
+	}
+	dimension, _ := value.(string)
+	return dimension
+}
+
+// getJob returns a Glue job and its most recent runs. Only the first page of
+// runs is requested, as runs are returned from the most recent one.
+func getJob(ctx context.Context, svc glueAPI, jobName string) (*job, error) {
+	jobOutput, err := svc.GetJob(ctx, &glue.GetJobInput{JobName: awssdk.String(jobName)})
+	if err != nil {
+		return nil, fmt.Errorf("error GetJob: %w", err)
+	}
+
+	runsOutput, err := svc.GetJobRuns(ctx, &glue.GetJobRunsInput{JobName: awssdk.String(jobName)})
+	if err != nil {
+		return nil, fmt.Errorf("error GetJobRuns: %w", err)
+	}
+	return &job{job: jobOutput.Job, runs: runsOutput.JobRuns}, nil
+}
+
+func addJobMetadata(event mb.Event, j *types.Job) {
+	_, _ = event.RootFields.Put(metadataPrefix+"job.name", awssdk.ToString(j.Name))
+	if j.Description != nil && *j.Description != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"job.description", *j.Description)
+	}
+	if j.Command != nil && j.Command.Name != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"job.command", *j.Command.Name)
+	}
+	if j.GlueVersion != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"job.glue_version", *j.GlueVersion)
+	}
+	if j.WorkerType != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"job.worker_type", string(j.WorkerType))
+	}
+	if j.NumberOfWorkers != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"job.workers.count", *j.NumberOfWorkers)
+	}
+	if j.MaxCapacity != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"job.max_capacity", *j.MaxCapacity)
+	}
+	if j.Timeout != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"job.timeout.min", *j.Timeout)
+	}
+	_, _ = event.RootFields.Put(metadataPrefix+"job.max_retries", j.MaxRetries)
+}
+
+// addJobRunsMetadata adds the number of runs of the job by state, and their
+// DPU hours, to the metrics aggregated for all its runs. Only the runs started
+// since the timestamp of the metrics are counted.
+func addJobRunsMetadata(event mb.Event, runs []types.JobRun) {
+	counts := map[string]int{}
+	dpuHours := 0.0
+	for _, run := range runs {
+		if run.StartedOn == nil || run.StartedOn.Before(event.Timestamp) {
+			continue
+		}
+		counts[strings.ToLower(string(run.JobRunState))]++
+		dpuHours += getDPUHours(run)
+	}
+
+	total := 0
+	for state, count := range counts {
+		_, _ = event.RootFields.Put(metadataPrefix+"job.runs."+state, count)
+		total += count
+	}
+	_, _ = event.RootFields.Put(metadataPrefix+"job.runs.count", total)
+	_, _ = event.RootFields.Put(metadataPrefix+"job.runs.dpu_hours", dpuHours)
+}
+
+func addJobRunMetadata(event mb.Event, run types.JobRun) {
+	_, _ = event.RootFields.Put(metadataPrefix+"job_run.id", awssdk.ToString(run.Id))
+	_, _ = event.RootFields.Put(metadataPrefix+"job_run.state", string(run.JobRunState))
+	_, _ = event.RootFields.Put(metadataPrefix+"job_run.attempt", run.Attempt)
+	if run.StartedOn != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"job_run.started_at", *run.StartedOn)
+	}
+	if run.CompletedOn != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"job_run.completed_at", *run.CompletedOn)
+	}
+	_, _ = event.RootFields.Put(metadataPrefix+"job_run.execution_time.sec", run.ExecutionTime)
+	_, _ = event.RootFields.Put(metadataPrefix+"job_run.dpu_hours", getDPUHours(run))
+	if run.ErrorMessage != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"job_run.error_message", *run.ErrorMessage)
+	}
+}
+
+// getDPUHours returns the DPU hours consumed by a job run. The DPU seconds are
+// only reported for jobs with auto scaling and Flex jobs, for other jobs they
+// are computed from the execution time and the allocated capacity.
+func getDPUHours(run types.JobRun) float64 {
+	if run.DPUSeconds != nil {
+		return *run.DPUSeconds / time.Hour.Seconds()
+	}
+	if run.MaxCapacity != nil {
+		return float64(run.ExecutionTime) * *run.MaxCapacity / time.Hour.Seconds()
+	}
+	return 0
+}
@@ -0,0 +1,153 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package kinesis
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
+)
+
+const metadataPrefix = "aws.kinesis."
+
+// namespace is the CloudWatch namespace enriched by this package.
+const namespace = "AWS/Kinesis"
+
+// hashKeySpace is the size of the hash key space of a stream, 2^128.
+var hashKeySpace = new(big.Float).SetInt(new(big.Int).Lsh(big.NewInt(1), 128))
+
+func init() {
+	metadata.Enrichers.MustRegister(namespace, AddMetadata)
+}
+
+type listShardsAPI interface {
+	ListShards(ctx context.Context, params *kinesis.ListShardsInput, optFns ...func(*kinesis.Options)) (*kinesis.ListShardsOutput, error)
+}
+
+// AddMetadata adds metadata for the shards of Kinesis streams from a specific
+// region to the events of the shard-level metrics.
+func AddMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svc := kinesis.NewFromConfig(awsConfig)
+	return addMetadata(ctx, svc, discovery, regionName, events)
+}
+
+func addMetadata(ctx context.Context, svc listShardsAPI, discovery metadata.Discovery, regionName string, events map[string]mb.Event) (map[string]mb.Event, error) {
+	// Shards are only listed for the streams with shard-level metrics, that
+	// require enhanced monitoring to be enabled on the stream.
+	shardsByStream := map[string]map[string]types.Shard{}
+	for _, event := range events {
+		shardID := getDimension(event, "ShardId")
+		streamName := getDimension(event, "StreamName")
+		if shardID == "" || streamName == "" {
+			continue
+		}
+
+		shards, ok := shardsByStream[streamName]
+		if !ok {
+			listed, err := metadata.Discover(ctx, discovery, "kinesis:ListShards", regionName, streamName, func(ctx context.Context) (interface{}, error) {
+				return listShards(ctx, svc, streamName)
+			})
+			if err != nil {
+				return events, fmt.Errorf("listShards of stream %s failed in region %s: %w", streamName, regionName, err)
+			}
+			shards, _ = listed.(map[string]types.Shard)
+			shardsByStream[streamName] = shards
+		}
+
+		_, _ = event.RootFields.Put(metadataPrefix+"shard.id", shardID)
+		if shard, ok := shards[shardID]; ok {
+			addShardMetadata(event, shard)
+		}
+		if len(shards) > 0 {
+			_, _ = event.RootFields.Put(metadataPrefix+"stream.shards.open", countOpenShards(shards))
+		}
+	}
+	return events, nil
+}
+
+func getDimension(event mb.Event, name string) string {
+	value, err := event.RootFields.GetValue("aws.dimensions." + name)
+	if err != nil {
+		return ""
+	}
+	dimension, _ := value.(string)
+	return dimension
+}
+
+// listShards returns the shards of a stream by shard ID. The stream name
+// cannot be set together with the token of the next page.
+func listShards(ctx context.Context, svc listShardsAPI, streamName string) (map[string]types.Shard, error) {
+	shards := map[string]types.Shard{}
+	input := &kinesis.ListShardsInput{StreamName: awssdk.String(streamName)}
+	for {
+		output, err := svc.ListShards(ctx, input)
+		if err != nil {
+			return shards, fmt.Errorf("error ListShards: %w", err)
+		}
+		for _, shard := range output.Shards {
+			shards[awssdk.ToString(shard.ShardId)] = shard
+		}
+		if output.NextToken == nil {
+			return shards, nil
+		}
+		input = &kinesis.ListShardsInput{NextToken: output.NextToken}
+	}
+}
+
+// isOpen returns whether a shard still accepts records, closed shards have an
+// ending sequence number.
+func isOpen(shard types.Shard) bool {
+	return shard.SequenceNumberRange == nil || shard.SequenceNumberRange.EndingSequenceNumber == nil
+}
+
+func countOpenShards(shards map[string]types.Shard) int {
+	count := 0
+	for _, shard := range shards {
+		if isOpen(shard) {
+			count++
+		}
+	}
+	return count
+}
+
+func addShardMetadata(event mb.Event, shard types.Shard) {
+	_, _ = event.RootFields.Put(metadataPrefix+"shard.open", isOpen(shard))
+	if shard.ParentShardId != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"shard.parent_shard_id", *shard.ParentShardId)
+	}
+	if shard.HashKeyRange == nil {
+		return
+	}
+	_, _ = event.RootFields.Put(metadataPrefix+"shard.hash_key_range.starting", awssdk.ToString(shard.HashKeyRange.StartingHashKey))
+	_, _ = event.RootFields.Put(metadataPrefix+"shard.hash_key_range.ending", awssdk.ToString(shard.HashKeyRange.EndingHashKey))
+	if pct, ok := hashKeyRangePct(*shard.HashKeyRange); ok {
+		_, _ = event.RootFields.Put(metadataPrefix+"shard.hash_key_range.pct", pct)
+	}
+}
+
+// hashKeyRangePct returns the fraction of the hash key space of the stream
+// covered by a hash key range. Shards receiving a larger share of the traffic
+// than of the hash key space are hot shards.
+func hashKeyRangePct(hashKeyRange types.HashKeyRange) (float64, bool) {
+	starting, ok := new(big.Int).SetString(awssdk.ToString(hashKeyRange.StartingHashKey), 10)
+	if !ok {
+		return 0, false
+	}
+	ending, ok := new(big.Int).SetString(awssdk.ToString(hashKeyRange.EndingHashKey), 10)
+	if !ok {
+		return 0, false
+	}
+	size := new(big.Int).Sub(ending, starting)
+	size.Add(size, big.NewInt(1))
+	pct, _ := new(big.Float).Quo(new(big.Float).SetInt(size), hashKeySpace).Float64()
+	return pct, true
+}
@@ -0,0 +1,195 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package lambda
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
+	"github.com/elastic/elastic-agent-libs/logp"
+)
+
+const metadataPrefix = "aws.lambda."
+
+// namespace is the CloudWatch namespace enriched by this package.
+const namespace = "AWS/Lambda"
+
+// concurrencyCacheTTL is how long the concurrency configuration of a function
+// is cached. It is described per function and rarely changes, so it is cached
+// longer than the other discovered resources.
+const concurrencyCacheTTL = 15 * time.Minute
+
+func init() {
+	metadata.Enrichers.MustRegister(namespace, AddMetadata)
+}
+
+type lambdaAPI interface {
+	GetAccountSettings(ctx context.Context, params *lambda.GetAccountSettingsInput, optFns ...func(*lambda.Options)) (*lambda.GetAccountSettingsOutput, error)
+	GetFunctionConcurrency(ctx context.Context, params *lambda.GetFunctionConcurrencyInput, optFns ...func(*lambda.Options)) (*lambda.GetFunctionConcurrencyOutput, error)
+	lambda.ListProvisionedConcurrencyConfigsAPIClient
+}
+
+// functionConcurrency is the concurrency configuration of a function.
+type functionConcurrency struct {
+	reserved *int32
+	// provisioned is the provisioned concurrency configuration of the aliases
+	// and versions of the function by qualifier.
+	provisioned map[string]types.ProvisionedConcurrencyConfigListItem
+}
+
+// AddMetadata adds the concurrency configuration of Lambda functions and the
+// concurrency limits of the account from a specific region
+func AddMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svc := lambda.NewFromConfig(awsConfig)
+	return addMetadata(ctx, svc, discovery, regionName, events)
+}
+
+func addMetadata(ctx context.Context, svc lambdaAPI, discovery metadata.Discovery, regionName string, events map[string]mb.Event) (map[string]mb.Event, error) {
+	if len(events) == 0 {
+		return events, nil
+	}
+
+	// The account limits apply to all the functions of the region, including
+	// the region-wide metrics without dimensions.
+	listed, err := metadata.Discover(ctx, discovery, "lambda:GetAccountSettings", regionName, "", func(ctx context.Context) (interface{}, error) {
+		return svc.GetAccountSettings(ctx, &lambda.GetAccountSettingsInput{})
+	})
+	if err != nil {
+		return events, fmt.Errorf("GetAccountSettings failed in region %s: %w", regionName, err)
+	}
+	settings, _ := listed.(*lambda.GetAccountSettingsOutput)
+
+	functions := map[string]*functionConcurrency{}
+	for _, event := range events {
+		addAccountMetadata(event, settings)
+
+		functionName := getDimension(event, "FunctionName")
+		if functionName == "" {
+			continue
+		}
+		_, _ = event.RootFields.Put(metadataPrefix+"function.name", functionName)
+
+		// Metrics of aliases and versions have a Resource dimension with the
+		// function name and the qualifier, and metrics of aliases with
+		// weighted routing are also reported per ExecutedVersion.
+		qualifier := ""
+		if resource := getDimension(event, "Resource"); strings.HasPrefix(resource, functionName+":") {
+			qualifier = strings.TrimPrefix(resource, functionName+":")
+			_, _ = event.RootFields.Put(metadataPrefix+"function.qualifier", qualifier)
+		}
+		if executedVersion := getDimension(event, "ExecutedVersion"); executedVersion != "" {
+			_, _ = event.RootFields.Put(metadataPrefix+"function.executed_version", executedVersion)
+		}
+
+		concurrency, ok := functions[functionName]
+		if !ok {
+			described, err := metadata.DiscoverWithTTL(ctx, discovery, "lambda:GetFunctionConcurrency", regionName, functionName, concurrencyCacheTTL, func(ctx context.Context) (interface{}, error) {
+				return getFunctionConcurrency(ctx, svc, functionName)
+			})
+			if err != nil {
+				// A function deleted since its metrics were listed, or a
+				// throttled request, doesn't prevent the enrichment of the
+				// other functions.
+				logp.Warn("skipping concurrency of function %s in region %s: %v", functionName, regionName, err)
+			}
+			concurrency, _ = described.(*functionConcurrency)
+			functions[functionName] = concurrency
+		}
+		if concurrency != nil {
+			addConcurrencyMetadata(event, concurrency, qualifier)
+		}
+	}
+	return events, nil
+}
+
+func getDimension(event mb.Event, name string) string {
+	value, err := event.RootFields.GetValue("aws.dimensions." + name)
+	if err != nil {
+		return ""
+	}
+	dimension, _ := value.(string)
+	return dimension
+}
+
+func getFunctionConcurrency(ctx context.Context, svc lambdaAPI, functionName string) (*functionConcurrency, error) {
+	concurrency := &functionConcurrency{provisioned: map[string]types.ProvisionedConcurrencyConfigListItem{}}
+
+	output, err := svc.GetFunctionConcurrency(ctx, &lambda.GetFunctionConcurrencyInput{FunctionName: awssdk.String(functionName)})
+	if err != nil {
+		return nil, fmt.Errorf("error GetFunctionConcurrency: %w", err)
+	}
+	concurrency.reserved = output.ReservedConcurrentExecutions
+
+	paginator := lambda.NewListProvisionedConcurrencyConfigsPaginator(svc, &lambda.ListProvisionedConcurrencyConfigsInput{FunctionName: awssdk.String(functionName)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error ListProvisionedConcurrencyConfigs with Paginator: %w", err)
+		}
+		for _, config := range page.ProvisionedConcurrencyConfigs {
+			// The ARN of the configuration is qualified with the alias or the
+			// version.
+			functionARN := awssdk.ToString(config.FunctionArn)
+			qualifier := functionARN[strings.LastIndex(functionARN, ":")+1:]
+			concurrency.provisioned[qualifier] = config
+		}
+	}
+	return concurrency, nil
+}
+
+func addAccountMetadata(event mb.Event, settings *lambda.GetAccountSettingsOutput) {
+	if settings.AccountLimit != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"account.concurrent_executions_limit", settings.AccountLimit.ConcurrentExecutions)
+		if settings.AccountLimit.UnreservedConcurrentExecutions != nil {
+			_, _ = event.RootFields.Put(metadataPrefix+"account.unreserved_concurrent_executions_limit", *settings.AccountLimit.UnreservedConcurrentExecutions)
+		}
+	}
+	if settings.AccountUsage != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"account.function_count", settings.AccountUsage.FunctionCount)
+	}
+}
+
+// addConcurrencyMetadata adds the reserved concurrency of the function and,
+// for the metrics of an alias or version, its provisioned concurrency. The
+// provisioned concurrency of the function is the sum of the provisioned
+// concurrency of its aliases and versions.
+func addConcurrencyMetadata(event mb.Event, concurrency *functionConcurrency, qualifier string) {
+	if concurrency.reserved != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"concurrency.reserved", *concurrency.reserved)
+	}
+
+	if qualifier != "" {
+		config, ok := concurrency.provisioned[qualifier]
+		if !ok {
+			return
+		}
+		_, _ = event.RootFields.Put(metadataPrefix+"concurrency.provisioned.requested", awssdk.ToInt32(config.RequestedProvisionedConcurrentExecutions))
+		_, _ = event.RootFields.Put(metadataPrefix+"concurrency.provisioned.allocated", awssdk.ToInt32(config.AllocatedProvisionedConcurrentExecutions))
+		_, _ = event.RootFields.Put(metadataPrefix+"concurrency.provisioned.available", awssdk.ToInt32(config.AvailableProvisionedConcurrentExecutions))
+		if config.Status != "" {
+			_, _ = event.RootFields.Put(metadataPrefix+"concurrency.provisioned.status", string(config.Status))
+		}
+		return
+	}
+
+	if len(concurrency.provisioned) == 0 {
+		return
+	}
+	var requested, allocated int32
+	for _, config := range concurrency.provisioned {
+		requested += awssdk.ToInt32(config.RequestedProvisionedConcurrentExecutions)
+		allocated += awssdk.ToInt32(config.AllocatedProvisionedConcurrentExecutions)
+	}
+	_, _ = event.RootFields.Put(metadataPrefix+"concurrency.provisioned.requested", requested)
+	_, _ = event.RootFields.Put(metadataPrefix+"concurrency.provisioned.allocated", allocated)
+}
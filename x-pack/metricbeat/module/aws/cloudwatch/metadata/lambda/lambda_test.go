@@ -0,0 +1,75 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+//go:build !integration
+// +build !integration
+
+package lambda
+
+import (
+	"context"
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+// MockLambdaClient struct is used for unit tests.
+type MockLambdaClient struct {
+	// deleted are the functions that no longer exist.
+	deleted map[string]bool
+}
+
+// GetAccountSettings implements lambdaAPI.
+func (m *MockLambdaClient) GetAccountSettings(_ context.Context, _ *lambda.GetAccountSettingsInput, _ ...func(*lambda.Options)) (*lambda.GetAccountSettingsOutput, error) {
+	return &lambda.GetAccountSettingsOutput{
+		AccountLimit: &types.AccountLimit{ConcurrentExecutions: 1000},
+	}, nil
+}
+
+// GetFunctionConcurrency implements lambdaAPI.
+func (m *MockLambdaClient) GetFunctionConcurrency(_ context.Context, params *lambda.GetFunctionConcurrencyInput, _ ...func(*lambda.Options)) (*lambda.GetFunctionConcurrencyOutput, error) {
+	if m.deleted[awssdk.ToString(params.FunctionName)] {
+		return nil, &types.ResourceNotFoundException{Message: awssdk.String("Function not found")}
+	}
+	return &lambda.GetFunctionConcurrencyOutput{ReservedConcurrentExecutions: awssdk.Int32(10)}, nil
+}
+
+// ListProvisionedConcurrencyConfigs implements lambdaAPI.
+func (m *MockLambdaClient) ListProvisionedConcurrencyConfigs(_ context.Context, _ *lambda.ListProvisionedConcurrencyConfigsInput, _ ...func(*lambda.Options)) (*lambda.ListProvisionedConcurrencyConfigsOutput, error) {
+	return &lambda.ListProvisionedConcurrencyConfigsOutput{}, nil
+}
+
+func newEvent(functionName string) mb.Event {
+	return mb.Event{RootFields: mapstr.M{"aws": mapstr.M{"dimensions": mapstr.M{"FunctionName": functionName}}}}
+}
+
+func TestAddMetadataFunctionFailure(t *testing.T) {
+	events := map[string]mb.Event{
+		"deleted": newEvent("deleted"),
+		"active":  newEvent("active"),
+	}
+
+	// The functions that can't be described are skipped
+	events, err := addMetadata(context.Background(), &MockLambdaClient{deleted: map[string]bool{"deleted": true}}, nil, "us-east-1", events)
+	assert.NoError(t, err)
+
+	reserved, err := events["active"].RootFields.GetValue("aws.lambda.concurrency.reserved")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(10), reserved)
+
+	_, err = events["deleted"].RootFields.GetValue("aws.lambda.concurrency.reserved")
+	assert.Error(t, err)
+	name, err := events["deleted"].RootFields.GetValue("aws.lambda.function.name")
+	assert.NoError(t, err)
+	assert.Equal(t, "deleted", name)
+	limit, err := events["deleted"].RootFields.GetValue("aws.lambda.account.concurrent_executions_limit")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1000), limit)
+}
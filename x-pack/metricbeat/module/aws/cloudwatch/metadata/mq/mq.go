@@ -0,0 +1,169 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package mq
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/mq"
+	"github.com/aws/aws-sdk-go-v2/service/mq/types"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
+)
+
+const metadataPrefix = "aws.mq."
+
+// namespace is the CloudWatch namespace enriched by this package.
+const namespace = "AWS/AmazonMQ"
+
+func init() {
+	metadata.Enrichers.MustRegister(namespace, AddMetadata)
+}
+
+type mqAPI interface {
+	ListBrokers(ctx context.Context, params *mq.ListBrokersInput, optFns ...func(*mq.Options)) (*mq.ListBrokersOutput, error)
+	DescribeBroker(ctx context.Context, params *mq.DescribeBrokerInput, optFns ...func(*mq.Options)) (*mq.DescribeBrokerOutput, error)
+}
+
+// AddMetadata adds metadata for Amazon MQ brokers from a specific region
+func AddMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svc := mq.NewFromConfig(awsConfig)
+	return addMetadata(ctx, svc, discovery, regionName, events)
+}
+
+func addMetadata(ctx context.Context, svc mqAPI, discovery metadata.Discovery, regionName string, events map[string]mb.Event) (map[string]mb.Event, error) {
+	// The brokers are shared with the other metricsets of the account
+	listedBrokers, err := metadata.Discover(ctx, discovery, "mq:ListBrokers", regionName, "", func(ctx context.Context) (interface{}, error) {
+		return getBrokers(ctx, svc)
+	})
+	if err != nil {
+		return events, fmt.Errorf("getBrokers failed, skipping region %s: %w", regionName, err)
+	}
+	brokers, _ := listedBrokers.(map[string]types.BrokerSummary)
+
+	details := map[string]*mq.DescribeBrokerOutput{}
+	for _, event := range events {
+		addDestinationMetadata(event)
+
+		brokerDimension := getDimension(event, "Broker")
+		if brokerDimension == "" {
+			continue
+		}
+		broker, ok := findBroker(brokers, brokerDimension)
+		if !ok {
+			continue
+		}
+
+		brokerID := awssdk.ToString(broker.BrokerId)
+		detail, ok := details[brokerID]
+		if !ok {
+			described, err := metadata.Discover(ctx, discovery, "mq:DescribeBroker", regionName, brokerID, func(ctx context.Context) (interface{}, error) {
+				return svc.DescribeBroker(ctx, &mq.DescribeBrokerInput{BrokerId: awssdk.String(brokerID)})
+			})
+			if err != nil {
+				return events, fmt.Errorf("DescribeBroker of broker %s failed in region %s: %w", brokerID, regionName, err)
+			}
+			detail, _ = described.(*mq.DescribeBrokerOutput)
+			details[brokerID] = detail
+		}
+		addBrokerMetadata(event, broker, detail)
+	}
+	return events, nil
+}
+
+func getDimension(event mb.Event, name string) string {
+	value, err := event.RootFields.GetValue("aws.dimensions." + name)
+	if err != nil {
+		return ""
+	}
+	dimension, _ := value.(string)
+	return dimension
+}
+
+// getBrokers returns the brokers of a region by name.
+func getBrokers(ctx context.Context, svc mqAPI) (map[string]types.BrokerSummary, error) {
+	brokers := map[string]types.BrokerSummary{}
+	input := &mq.ListBrokersInput{}
+	for {
+		output, err := svc.ListBrokers(ctx, input)
+		if err != nil {
+			return brokers, fmt.Errorf("error ListBrokers: %w", err)
+		}
+		for _, broker := range output.BrokerSummaries {
+			brokers[awssdk.ToString(broker.BrokerName)] = broker
+		}
+		if output.NextToken == nil {
+			return brokers, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+// findBroker returns the broker of a Broker dimension. The metrics of the
+// instances of an active/standby ActiveMQ broker have the name of the broker
+// with a -1 or -2 suffix as Broker dimension.
+func findBroker(brokers map[string]types.BrokerSummary, brokerDimension string) (types.BrokerSummary, bool) {
+	if broker, ok := brokers[brokerDimension]; ok {
+		return broker, true
+	}
+	for _, suffix := range []string{"-1", "-2"} {
+		if name := strings.TrimSuffix(brokerDimension, suffix); name != brokerDimension {
+			broker, ok := brokers[name]
+			return broker, ok
+		}
+	}
+	return types.BrokerSummary{}, false
+}
+
+// addDestinationMetadata adds the queue, topic, virtual host and node of the
+// metrics that are reported per destination or per node.
+func addDestinationMetadata(event mb.Event) {
+	if queue := getDimension(event, "Queue"); queue != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"queue", queue)
+	}
+	if topic := getDimension(event, "Topic"); topic != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"topic", topic)
+	}
+	if virtualHost := getDimension(event, "VirtualHost"); virtualHost != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"virtual_host", virtualHost)
+	}
+	if node := getDimension(event, "Node"); node != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"node", node)
+	}
+}
+
+func addBrokerMetadata(event mb.Event, broker types.BrokerSummary, detail *mq.DescribeBrokerOutput) {
+	_, _ = event.RootFields.Put(metadataPrefix+"broker.id", awssdk.ToString(broker.BrokerId))
+	_, _ = event.RootFields.Put(metadataPrefix+"broker.name", awssdk.ToString(broker.BrokerName))
+	_, _ = event.RootFields.Put(metadataPrefix+"broker.arn", awssdk.ToString(broker.BrokerArn))
+	if broker.BrokerState != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"broker.state", string(broker.BrokerState))
+	}
+	if broker.EngineType != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"broker.engine_type", string(broker.EngineType))
+	}
+	if broker.DeploymentMode != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"broker.deployment_mode", string(broker.DeploymentMode))
+	}
+	if broker.HostInstanceType != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"broker.instance_type", *broker.HostInstanceType)
+	}
+
+	if detail == nil {
+		return
+	}
+	if detail.EngineVersion != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"broker.engine_version", *detail.EngineVersion)
+	}
+	if detail.StorageType != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"broker.storage_type", string(detail.StorageType))
+	}
+	_, _ = event.RootFields.Put(metadataPrefix+"broker.publicly_accessible", detail.PubliclyAccessible)
+	_, _ = event.RootFields.Put(metadataPrefix+"broker.auto_minor_version_upgrade", detail.AutoMinorVersionUpgrade)
+}
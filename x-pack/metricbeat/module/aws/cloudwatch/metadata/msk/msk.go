@@ -0,0 +1,174 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package msk
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kafka"
+	"github.com/aws/aws-sdk-go-v2/service/kafka/types"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
+)
+
+const metadataPrefix = "aws.msk."
+
+// namespace is the CloudWatch namespace enriched by this package.
+const namespace = "AWS/Kafka"
+
+// Dimensions of the AWS/Kafka metrics, their names contain spaces.
+const (
+	clusterNameDimension = "aws.dimensions.Cluster Name"
+	brokerIDDimension    = "aws.dimensions.Broker ID"
+	topicDimension       = "aws.dimensions.Topic"
+)
+
+func init() {
+	metadata.Enrichers.MustRegister(namespace, AddMetadata)
+}
+
+type kafkaAPI interface {
+	kafka.ListClustersAPIClient
+	kafka.ListNodesAPIClient
+}
+
+// AddMetadata adds metadata for MSK clusters and their brokers from a specific region
+func AddMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svc := kafka.NewFromConfig(awsConfig)
+	return addMetadata(ctx, svc, discovery, regionName, events)
+}
+
+func addMetadata(ctx context.Context, svc kafkaAPI, discovery metadata.Discovery, regionName string, events map[string]mb.Event) (map[string]mb.Event, error) {
+	// The clusters are shared with the other metricsets of the account
+	listedClusters, err := metadata.Discover(ctx, discovery, "kafka:ListClusters", regionName, "", func(ctx context.Context) (interface{}, error) {
+		return getClustersPerRegion(ctx, svc)
+	})
+	if err != nil {
+		return events, fmt.Errorf("getClustersPerRegion failed, skipping region %s: %w", regionName, err)
+	}
+	clusters, _ := listedClusters.(map[string]types.ClusterInfo)
+
+	// Brokers are only listed for the clusters with per broker metrics
+	brokersByCluster := map[string]map[string]types.BrokerNodeInfo{}
+	for _, event := range events {
+		clusterName := getDimension(event, clusterNameDimension)
+		cluster, ok := clusters[clusterName]
+		if !ok {
+			continue
+		}
+		addClusterMetadata(event, cluster)
+
+		if topic := getDimension(event, topicDimension); topic != "" {
+			_, _ = event.RootFields.Put(metadataPrefix+"topic.name", topic)
+		}
+
+		brokerID := getDimension(event, brokerIDDimension)
+		if brokerID == "" {
+			continue
+		}
+		_, _ = event.RootFields.Put(metadataPrefix+"broker.id", brokerID)
+
+		brokers, ok := brokersByCluster[clusterName]
+		if !ok {
+			listed, err := metadata.Discover(ctx, discovery, "kafka:ListNodes", regionName, clusterName, func(ctx context.Context) (interface{}, error) {
+				return getBrokers(ctx, svc, awssdk.ToString(cluster.ClusterArn))
+			})
+			if err != nil {
+				return events, fmt.Errorf("getBrokers of cluster %s failed in region %s: %w", clusterName, regionName, err)
+			}
+			brokers, _ = listed.(map[string]types.BrokerNodeInfo)
+			brokersByCluster[clusterName] = brokers
+		}
+		if broker, ok := brokers[brokerID]; ok {
+			addBrokerMetadata(event, broker)
+		}
+	}
+	return events, nil
+}
+
+func getDimension(event mb.Event, name string) string {
+	value, err := event.RootFields.GetValue(name)
+	if err != nil {
+		return ""
+	}
+	dimension, _ := value.(string)
+	return dimension
+}
+
+// getClustersPerRegion returns the provisioned MSK clusters of a region by name.
+func getClustersPerRegion(ctx context.Context, svc kafka.ListClustersAPIClient) (map[string]types.ClusterInfo, error) {
+	clusters := map[string]types.ClusterInfo{}
+	paginator := kafka.NewListClustersPaginator(svc, &kafka.ListClustersInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error ListClusters with Paginator: %w", err)
+		}
+		for _, cluster := range output.ClusterInfoList {
+			clusters[awssdk.ToString(cluster.ClusterName)] = cluster
+		}
+	}
+	return clusters, nil
+}
+
+// getBrokers returns the broker nodes of a MSK cluster by broker ID.
+func getBrokers(ctx context.Context, svc kafka.ListNodesAPIClient, clusterArn string) (map[string]types.BrokerNodeInfo, error) {
+	brokers := map[string]types.BrokerNodeInfo{}
+	paginator := kafka.NewListNodesPaginator(svc, &kafka.ListNodesInput{ClusterArn: awssdk.String(clusterArn)})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return brokers, fmt.Errorf("error ListNodes with Paginator: %w", err)
+		}
+		for _, node := range output.NodeInfoList {
+			if node.BrokerNodeInfo == nil {
+				continue
+			}
+			// Broker IDs are numbers in the API, and strings in the metrics dimensions
+			brokerID := strconv.FormatFloat(node.BrokerNodeInfo.BrokerId, 'f', -1, 64)
+			brokers[brokerID] = *node.BrokerNodeInfo
+		}
+	}
+	return brokers, nil
+}
+
+func addClusterMetadata(event mb.Event, cluster types.ClusterInfo) {
+	_, _ = event.RootFields.Put(metadataPrefix+"cluster.name", awssdk.ToString(cluster.ClusterName))
+	if cluster.ClusterArn != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"cluster.arn", *cluster.ClusterArn)
+	}
+	if cluster.State != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"cluster.state", string(cluster.State))
+	}
+	if cluster.CurrentBrokerSoftwareInfo != nil && cluster.CurrentBrokerSoftwareInfo.KafkaVersion != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"cluster.kafka_version", *cluster.CurrentBrokerSoftwareInfo.KafkaVersion)
+	}
+	if cluster.EnhancedMonitoring != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"cluster.enhanced_monitoring", string(cluster.EnhancedMonitoring))
+	}
+	_, _ = event.RootFields.Put(metadataPrefix+"cluster.brokers.count", cluster.NumberOfBrokerNodes)
+	if cluster.BrokerNodeGroupInfo != nil && cluster.BrokerNodeGroupInfo.InstanceType != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"cluster.brokers.instance_type", *cluster.BrokerNodeGroupInfo.InstanceType)
+	}
+}
+
+func addBrokerMetadata(event mb.Event, broker types.BrokerNodeInfo) {
+	if broker.ClientVpcIpAddress != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"broker.client_vpc_ip_address", *broker.ClientVpcIpAddress)
+	}
+	if broker.ClientSubnet != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"broker.client_subnet", *broker.ClientSubnet)
+	}
+	if broker.AttachedENIId != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"broker.eni_id", *broker.AttachedENIId)
+	}
+	if len(broker.Endpoints) > 0 {
+		_, _ = event.RootFields.Put(metadataPrefix+"broker.endpoints", broker.Endpoints)
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package opensearch
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/opensearch"
+	"github.com/aws/aws-sdk-go-v2/service/opensearch/types"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
+)
+
+const metadataPrefix = "aws.opensearch."
+
+// Namespaces enriched by this package, the metrics of provisioned domains and
+// the metrics of OpenSearch Serverless collections.
+const (
+	namespace           = "AWS/ES"
+	serverlessNamespace = "AWS/AOSS"
+)
+
+// maxDomainsPerRequest is the maximum number of domains of a DescribeDomains
+// request.
+const maxDomainsPerRequest = 5
+
+func init() {
+	metadata.Enrichers.MustRegister(namespace, AddMetadata)
+	metadata.Enrichers.MustRegister(serverlessNamespace, AddServerlessMetadata)
+}
+
+type opensearchAPI interface {
+	DescribeDomains(ctx context.Context, params *opensearch.DescribeDomainsInput, optFns ...func(*opensearch.Options)) (*opensearch.DescribeDomainsOutput, error)
+}
+
+// AddMetadata adds metadata for OpenSearch Service domains from a specific
+// region
+func AddMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svc := opensearch.NewFromConfig(awsConfig)
+	return addMetadata(ctx, svc, discovery, regionName, events)
+}
+
+// AddServerlessMetadata adds the collection and index of the metrics of
+// OpenSearch Serverless collections. Collections are only identified by the
+// dimensions of their metrics.
+func AddServerlessMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	for _, event := range events {
+		_, _ = event.RootFields.Put(metadataPrefix+"type", "serverless")
+		putDimension(event, "CollectionName", "collection.name")
+		putDimension(event, "CollectionId", "collection.id")
+		putDimension(event, "IndexName", "index.name")
+		putDimension(event, "IndexId", "index.id")
+	}
+	return events, nil
+}
+
+func addMetadata(ctx context.Context, svc opensearchAPI, discovery metadata.Discovery, regionName string, events map[string]mb.Event) (map[string]mb.Event, error) {
+	var domainNames []string
+	seen := map[string]struct{}{}
+	for _, event := range events {
+		_, _ = event.RootFields.Put(metadataPrefix+"type", "domain")
+		putDimension(event, "NodeId", "node.id")
+
+		domainName := getDimension(event, "DomainName")
+		if _, ok := seen[domainName]; domainName == "" || ok {
+			continue
+		}
+		seen[domainName] = struct{}{}
+		domainNames = append(domainNames, domainName)
+	}
+	if len(domainNames) == 0 {
+		return events, nil
+	}
+
+	// The domains are described together, so they are shared by the fetches
+	// of the same set of domains.
+	sort.Strings(domainNames)
+	listed, err := metadata.Discover(ctx, discovery, "es:DescribeDomains", regionName, strings.Join(domainNames, ","), func(ctx context.Context) (interface{}, error) {
+		return getDomains(ctx, svc, domainNames)
+	})
+	if err != nil {
+		return events, fmt.Errorf("getDomains failed in region %s: %w", regionName, err)
+	}
+	domains, _ := listed.(map[string]types.DomainStatus)
+
+	for _, event := range events {
+		if domain, ok := domains[getDimension(event, "DomainName")]; ok {
+			addDomainMetadata(event, domain)
+		}
+	}
+	return events, nil
+}
+
+func getDimension(event mb.Event, name string) string {
+	value, err := event.RootFields.GetValue("aws.dimensions." + name)
+	if err != nil {
+		return ""
+	}
+	dimension, _ := value.(string)
+	return dimension
+}
+
+// putDimension copies the value of a dimension, when present, to a field.
+func putDimension(event mb.Event, name string, field string) {
+	if value := getDimension(event, name); value != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+field, value)
+	}
+}
+
+// getDomains returns the domains with the given names by name.
+func getDomains(ctx context.Context, svc opensearchAPI, domainNames []string) (map[string]types.DomainStatus, error) {
+	domains := map[string]types.DomainStatus{}
+	for start := 0; start < len(domainNames); start += maxDomainsPerRequest {
+		end := start + maxDomainsPerRequest
+		if end > len(domainNames) {
+			end = len(domainNames)
+		}
+		output, err := svc.DescribeDomains(ctx, &opensearch.DescribeDomainsInput{DomainNames: domainNames[start:end]})
+		if err != nil {
+			return domains, fmt.Errorf("error DescribeDomains: %w", err)
+		}
+		for _, domain := range output.DomainStatusList {
+			domains[awssdk.ToString(domain.DomainName)] = domain
+		}
+	}
+	return domains, nil
+}
+
+func addDomainMetadata(event mb.Event, domain types.DomainStatus) {
+	_, _ = event.RootFields.Put(metadataPrefix+"domain.name", awssdk.ToString(domain.DomainName))
+	_, _ = event.RootFields.Put(metadataPrefix+"domain.id", awssdk.ToString(domain.DomainId))
+	_, _ = event.RootFields.Put(metadataPrefix+"domain.arn", awssdk.ToString(domain.ARN))
+	if domain.EngineVersion != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"domain.engine_version", *domain.EngineVersion)
+	}
+	if domain.Endpoint != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"domain.endpoint", *domain.Endpoint)
+	}
+	if domain.ClusterConfig != nil && domain.ClusterConfig.InstanceType != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"domain.instance_type", string(domain.ClusterConfig.InstanceType))
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+//go:build !integration
+// +build !integration
+
+package opensearch
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/opensearch"
+	"github.com/aws/aws-sdk-go-v2/service/opensearch/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+// MockOpenSearchClient struct is used for unit tests.
+type MockOpenSearchClient struct {
+	requests [][]string
+}
+
+// DescribeDomains implements opensearchAPI.
+func (m *MockOpenSearchClient) DescribeDomains(_ context.Context, params *opensearch.DescribeDomainsInput, _ ...func(*opensearch.Options)) (*opensearch.DescribeDomainsOutput, error) {
+	m.requests = append(m.requests, params.DomainNames)
+	output := &opensearch.DescribeDomainsOutput{}
+	for _, name := range params.DomainNames {
+		if name == "missing" {
+			continue
+		}
+		output.DomainStatusList = append(output.DomainStatusList, types.DomainStatus{
+			DomainName:    awssdk.String(name),
+			DomainId:      awssdk.String("123456789012/" + name),
+			ARN:           awssdk.String("arn:aws:es:us-east-1:123456789012:domain/" + name),
+			EngineVersion: awssdk.String("OpenSearch_1.3"),
+			Endpoint:      awssdk.String("search-" + name + ".us-east-1.es.amazonaws.com"),
+			ClusterConfig: &types.ClusterConfig{InstanceType: types.OpenSearchPartitionInstanceTypeR6gLargeSearch},
+		})
+	}
+	return output, nil
+}
+
+func newEvent(dimensions mapstr.M) mb.Event {
+	return mb.Event{RootFields: mapstr.M{"aws": mapstr.M{"dimensions": dimensions}}}
+}
+
+func TestAddMetadata(t *testing.T) {
+	events := map[string]mb.Event{
+		"logs-node-1": newEvent(mapstr.M{"DomainName": "logs", "ClientId": "123456789012", "NodeId": "node-1"}),
+		"logs-node-2": newEvent(mapstr.M{"DomainName": "logs", "ClientId": "123456789012", "NodeId": "node-2"}),
+		"missing":     newEvent(mapstr.M{"DomainName": "missing", "ClientId": "123456789012"}),
+	}
+	for i := 0; i < maxDomainsPerRequest; i++ {
+		name := fmt.Sprintf("domain-%d", i)
+		events[name] = newEvent(mapstr.M{"DomainName": name, "ClientId": "123456789012"})
+	}
+
+	svc := &MockOpenSearchClient{}
+	events, err := addMetadata(context.Background(), svc, nil, "us-east-1", events)
+	assert.NoError(t, err)
+
+	// Every domain is described once, in requests of at most
+	// maxDomainsPerRequest domains.
+	var requested []string
+	for _, request := range svc.requests {
+		assert.LessOrEqual(t, len(request), maxDomainsPerRequest)
+		requested = append(requested, request...)
+	}
+	assert.Len(t, svc.requests, 2)
+	assert.Len(t, requested, maxDomainsPerRequest+2)
+
+	fields := events["logs-node-2"].RootFields
+	for field, expected := range map[string]interface{}{
+		"aws.opensearch.type":                  "domain",
+		"aws.opensearch.node.id":               "node-2",
+		"aws.opensearch.domain.name":           "logs",
+		"aws.opensearch.domain.id":             "123456789012/logs",
+		"aws.opensearch.domain.arn":            "arn:aws:es:us-east-1:123456789012:domain/logs",
+		"aws.opensearch.domain.engine_version": "OpenSearch_1.3",
+		"aws.opensearch.domain.endpoint":       "search-logs.us-east-1.es.amazonaws.com",
+		"aws.opensearch.domain.instance_type":  "r6g.large.search",
+	} {
+		value, err := fields.GetValue(field)
+		assert.NoError(t, err, field)
+		assert.Equal(t, expected, value, field)
+	}
+
+	// A domain that could not be described keeps its dimensions only.
+	fields = events["missing"].RootFields
+	value, err := fields.GetValue("aws.opensearch.type")
+	assert.NoError(t, err)
+	assert.Equal(t, "domain", value)
+	_, err = fields.GetValue("aws.opensearch.domain")
+	assert.Error(t, err)
+}
+
+func TestAddServerlessMetadata(t *testing.T) {
+	events := map[string]mb.Event{
+		"collection": newEvent(mapstr.M{"CollectionName": "logs", "CollectionId": "abc123", "ClientId": "123456789012"}),
+		"index":      newEvent(mapstr.M{"CollectionName": "logs", "CollectionId": "abc123", "IndexName": "app", "IndexId": "def456", "ClientId": "123456789012"}),
+	}
+
+	events, err := AddServerlessMetadata(context.Background(), "us-east-1", awssdk.Config{}, nil, events)
+	assert.NoError(t, err)
+
+	assert.Equal(t, mapstr.M{
+		"type":       "serverless",
+		"collection": mapstr.M{"name": "logs", "id": "abc123"},
+	}, events["collection"].RootFields["aws"].(mapstr.M)["opensearch"])
+	assert.Equal(t, mapstr.M{
+		"type":       "serverless",
+		"collection": mapstr.M{"name": "logs", "id": "abc123"},
+		"index":      mapstr.M{"name": "app", "id": "def456"},
+	}, events["index"].RootFields["aws"].(mapstr.M)["opensearch"])
+}
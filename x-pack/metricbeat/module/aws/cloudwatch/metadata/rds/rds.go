@@ -14,25 +14,30 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/rds"
 
 	"github.com/elastic/beats/v7/metricbeat/mb"
-	"github.com/elastic/elastic-agent-libs/logp"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
 )
 
 const metadataPrefix = "aws.rds.db_instance."
 
+// namespace is the CloudWatch namespace enriched by this package.
+const namespace = "AWS/RDS"
+
+func init() {
+	metadata.Enrichers.MustRegister(namespace, AddMetadata)
+}
+
 // AddMetadata adds metadata for RDS instances from a specific region
-func AddMetadata(regionName string, awsConfig awssdk.Config, fips_enabled bool, events map[string]mb.Event) (map[string]mb.Event, error) {
-	svc := rds.NewFromConfig(awsConfig, func(o *rds.Options) {
-		if fips_enabled {
-			o.EndpointOptions.UseFIPSEndpoint = awssdk.FIPSEndpointStateEnabled
-		}
-	})
+func AddMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svc := rds.NewFromConfig(awsConfig)
 
-	// Get DBInstance IDs per region
-	dbDetailsMap, err := getDBInstancesPerRegion(svc)
+	// Get DBInstance IDs per region, shared with the other metricsets of the account
+	dbInstances, err := metadata.Discover(ctx, discovery, "rds:DescribeDBInstances", regionName, "", func(ctx context.Context) (interface{}, error) {
+		return getDBInstancesPerRegion(ctx, svc)
+	})
 	if err != nil {
-		logp.Error(fmt.Errorf("getInstancesPerRegion failed, skipping region %s: %w", regionName, err))
-		return events, nil
+		return events, fmt.Errorf("getInstancesPerRegion failed, skipping region %s: %w", regionName, err)
 	}
+	dbDetailsMap, _ := dbInstances.(map[string]*types.DBInstance)
 
 	for _, event := range events {
 		cpuValue, err := event.RootFields.GetValue("aws.rds.metrics.CPUUtilization.avg")
@@ -79,10 +84,10 @@ func AddMetadata(regionName string, awsConfig awssdk.Config, fips_enabled bool,
 	return events, nil
 }
 
-func getDBInstancesPerRegion(svc *rds.Client) (map[string]*types.DBInstance, error) {
+func getDBInstancesPerRegion(ctx context.Context, svc *rds.Client) (map[string]*types.DBInstance, error) {
 	describeInstanceInput := &rds.DescribeDBInstancesInput{}
 
-	output, err := svc.DescribeDBInstances(context.TODO(), describeInstanceInput)
+	output, err := svc.DescribeDBInstances(ctx, describeInstanceInput)
 	if err != nil {
 		return nil, fmt.Errorf("error DescribeDBInstancesRequest: %w", err)
 	}
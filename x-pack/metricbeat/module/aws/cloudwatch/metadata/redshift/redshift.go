@@ -0,0 +1,130 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package redshift
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshift"
+	"github.com/aws/aws-sdk-go-v2/service/redshift/types"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
+)
+
+const metadataPrefix = "aws.redshift."
+
+// namespace is the CloudWatch namespace enriched by this package.
+const namespace = "AWS/Redshift"
+
+func init() {
+	metadata.Enrichers.MustRegister(namespace, AddMetadata)
+}
+
+// AddMetadata adds metadata for Redshift clusters and their nodes from a specific region
+func AddMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svc := redshift.NewFromConfig(awsConfig)
+
+	// The clusters are shared with the other metricsets of the account
+	listedClusters, err := metadata.Discover(ctx, discovery, "redshift:DescribeClusters", regionName, "", func(ctx context.Context) (interface{}, error) {
+		return getClustersPerRegion(ctx, svc)
+	})
+	if err != nil {
+		return events, fmt.Errorf("getClustersPerRegion failed, skipping region %s: %w", regionName, err)
+	}
+	clusters, _ := listedClusters.(map[string]types.Cluster)
+
+	for _, event := range events {
+		value, err := event.RootFields.GetValue("aws.dimensions.ClusterIdentifier")
+		if err != nil {
+			continue
+		}
+		clusterIdentifier, _ := value.(string)
+		cluster, ok := clusters[clusterIdentifier]
+		if !ok {
+			continue
+		}
+		addClusterMetadata(event, cluster)
+
+		value, err = event.RootFields.GetValue("aws.dimensions.NodeID")
+		if err != nil {
+			continue
+		}
+		if nodeID, ok := value.(string); ok {
+			addNodeMetadata(event, cluster, nodeID)
+		}
+	}
+	return events, nil
+}
+
+// getClustersPerRegion returns the Redshift clusters of a region by identifier.
+func getClustersPerRegion(ctx context.Context, svc redshift.DescribeClustersAPIClient) (map[string]types.Cluster, error) {
+	clusters := map[string]types.Cluster{}
+	paginator := redshift.NewDescribeClustersPaginator(svc, &redshift.DescribeClustersInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error DescribeClusters with Paginator: %w", err)
+		}
+		for _, cluster := range output.Clusters {
+			clusters[awssdk.ToString(cluster.ClusterIdentifier)] = cluster
+		}
+	}
+	return clusters, nil
+}
+
+func addClusterMetadata(event mb.Event, cluster types.Cluster) {
+	_, _ = event.RootFields.Put(metadataPrefix+"cluster.identifier", awssdk.ToString(cluster.ClusterIdentifier))
+	if cluster.ClusterNamespaceArn != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"cluster.namespace_arn", *cluster.ClusterNamespaceArn)
+	}
+	if cluster.ClusterStatus != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"cluster.status", *cluster.ClusterStatus)
+	}
+	if cluster.ClusterAvailabilityStatus != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"cluster.availability_status", *cluster.ClusterAvailabilityStatus)
+	}
+	if cluster.ClusterVersion != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"cluster.version", *cluster.ClusterVersion)
+	}
+	if cluster.NodeType != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"cluster.node_type", *cluster.NodeType)
+	}
+	_, _ = event.RootFields.Put(metadataPrefix+"cluster.nodes.count", cluster.NumberOfNodes)
+	if cluster.PreferredMaintenanceWindow != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"cluster.maintenance_window", *cluster.PreferredMaintenanceWindow)
+	}
+	if cluster.DBName != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"cluster.db_name", *cluster.DBName)
+	}
+	_, _ = event.RootFields.Put(metadataPrefix+"cluster.encrypted", cluster.Encrypted)
+}
+
+// addNodeMetadata adds the role and IP addresses of the node of the metrics
+// reported per node. NodeID dimensions are like "Leader" or "Compute-0", while
+// node roles are like "LEADER" or "COMPUTE-0".
+func addNodeMetadata(event mb.Event, cluster types.Cluster, nodeID string) {
+	_, _ = event.RootFields.Put(metadataPrefix+"node.id", nodeID)
+	for _, node := range cluster.ClusterNodes {
+		if !strings.EqualFold(awssdk.ToString(node.NodeRole), nodeID) {
+			continue
+		}
+		role := "compute"
+		if strings.EqualFold(nodeID, "Leader") {
+			role = "leader"
+		}
+		_, _ = event.RootFields.Put(metadataPrefix+"node.role", role)
+		if node.PrivateIPAddress != nil {
+			_, _ = event.RootFields.Put(metadataPrefix+"node.private_ip", *node.PrivateIPAddress)
+		}
+		if node.PublicIPAddress != nil {
+			_, _ = event.RootFields.Put(metadataPrefix+"node.public_ip", *node.PublicIPAddress)
+		}
+		return
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+)
+
+// AddMetadataFunc adds metadata to the events collected by the cloudwatch
+// metricset for one namespace in a specific region. Events are keyed by
+// identifier, which is the dimension value(s) of the metrics. Enrichers can
+// list the resources of the region through discovery, to share them with the
+// other metricsets of the account. The API calls of the enrichers are done
+// with ctx, which is canceled when the metricset is stopped.
+type AddMetadataFunc func(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery Discovery, events map[string]mb.Event) (map[string]mb.Event, error)
+
+// Discovery lists the resources of an account and caches them for the other
+// metricsets, it is implemented by the DiscoveryService of the aws module.
+type Discovery interface {
+	Get(ctx context.Context, operation string, regionName string, input string, list func(ctx context.Context) (interface{}, error)) (interface{}, error)
+}
+
+// Discover returns the resources listed by an operation in a region through
+// discovery, or lists them with list when discovery is nil. The operation is
+// the service qualified name of the API call, like ec2:DescribeInstances, so
+// the listings of different services never collide. The listing can
+// be shared by concurrent callers, so list must do its API calls with the
+// context it is called with, not with ctx.
+func Discover(ctx context.Context, discovery Discovery, operation string, regionName string, input string, list func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	if discovery == nil {
+		return list(ctx)
+	}
+	return discovery.Get(ctx, operation, regionName, input, list)
+}
+
+// DiscoverWithTTL is like Discover, but caches the resources for ttl when the
+// discovery supports it and ttl is longer than its own TTL, for the resources
+// that change much less often than they are collected.
+func DiscoverWithTTL(ctx context.Context, discovery Discovery, operation string, regionName string, input string, ttl time.Duration, list func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	if discovery, ok := discovery.(interface {
+		GetWithTTL(ctx context.Context, operation string, regionName string, input string, ttl time.Duration, list func(ctx context.Context) (interface{}, error)) (interface{}, error)
+	}); ok {
+		return discovery.GetWithTTL(ctx, operation, regionName, input, ttl, list)
+	}
+	return Discover(ctx, discovery, operation, regionName, input, list)
+}
+
+// Registry contains the metadata enrichers of the cloudwatch metricset, keyed
+// by the CloudWatch namespace they enrich. Registries are thread safe for
+// concurrent usage.
+type Registry struct {
+	// Lock to control concurrent read/writes
+	lock sync.RWMutex
+	// A map of CloudWatch namespace to AddMetadataFunc.
+	enrichers map[string]AddMetadataFunc
+}
+
+// NewRegistry creates and returns a new Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		enrichers: map[string]AddMetadataFunc{},
+	}
+}
+
+// Enrichers is the global registry of metadata enrichers. Enrichers are
+// registered in init functions, so other metricsets or custom beats can add
+// enrichers for new namespaces by importing a package that registers them.
+var Enrichers = NewRegistry()
+
+// Register registers an enricher for the given CloudWatch namespace, for
+// example AWS/EC2. An error is returned if an enricher is already registered
+// for the namespace.
+func (r *Registry) Register(namespace string, addMetadata AddMetadataFunc) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if namespace == "" {
+		return fmt.Errorf("metadata enricher namespace is required")
+	}
+	if addMetadata == nil {
+		return fmt.Errorf("metadata enricher for namespace '%s' cannot be nil", namespace)
+	}
+	if _, exists := r.enrichers[namespace]; exists {
+		return fmt.Errorf("metadata enricher for namespace '%s' is already registered", namespace)
+	}
+
+	r.enrichers[namespace] = addMetadata
+	return nil
+}
+
+// MustRegister registers an enricher for the given CloudWatch namespace. It
+// panics if an error occurs.
+func (r *Registry) MustRegister(namespace string, addMetadata AddMetadataFunc) {
+	if err := r.Register(namespace, addMetadata); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup returns the enricher registered for the given CloudWatch namespace.
+func (r *Registry) Lookup(namespace string) (AddMetadataFunc, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	addMetadata, found := r.enrichers[namespace]
+	return addMetadata, found
+}
+
+// Namespaces returns the CloudWatch namespaces that have an enricher registered.
+func (r *Registry) Namespaces() []string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	namespaces := make([]string, 0, len(r.enrichers))
+	for namespace := range r.enrichers {
+		namespaces = append(namespaces, namespace)
+	}
+	return namespaces
+}
@@ -0,0 +1,55 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package metadata
+
+import (
+	"context"
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+func addTestMetadata(_ context.Context, regionName string, awsConfig awssdk.Config, discovery Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	for _, event := range events {
+		_, _ = event.RootFields.Put("aws.test.region", regionName)
+	}
+	return events, nil
+}
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+
+	err := registry.Register("MyCompany/App", addTestMetadata)
+	assert.NoError(t, err)
+
+	err = registry.Register("MyCompany/App", addTestMetadata)
+	assert.Error(t, err)
+
+	err = registry.Register("", addTestMetadata)
+	assert.Error(t, err)
+
+	err = registry.Register("MyCompany/Other", nil)
+	assert.Error(t, err)
+
+	_, found := registry.Lookup("AWS/EC2")
+	assert.False(t, found)
+
+	addMetadata, found := registry.Lookup("MyCompany/App")
+	assert.True(t, found)
+
+	events := map[string]mb.Event{"i-1": {RootFields: mapstr.M{}}}
+	events, err = addMetadata(context.Background(), "us-east-1", awssdk.Config{}, nil, events)
+	assert.NoError(t, err)
+
+	region, err := events["i-1"].RootFields.GetValue("aws.test.region")
+	assert.NoError(t, err)
+	assert.Equal(t, "us-east-1", region)
+
+	assert.Equal(t, []string{"MyCompany/App"}, registry.Namespaces())
+}
@@ -0,0 +1,225 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package route53
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/aws/aws-sdk-go-v2/service/route53resolver"
+	resolvertypes "github.com/aws/aws-sdk-go-v2/service/route53resolver/types"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
+)
+
+const metadataPrefix = "aws.route53."
+
+// Namespaces enriched by this package, the health check and hosted zone
+// metrics of Route 53 and the endpoint metrics of Route 53 Resolver.
+const (
+	namespace         = "AWS/Route53"
+	resolverNamespace = "AWS/Route53Resolver"
+)
+
+func init() {
+	metadata.Enrichers.MustRegister(namespace, AddMetadata)
+	metadata.Enrichers.MustRegister(resolverNamespace, AddResolverMetadata)
+}
+
+type route53API interface {
+	route53.ListHealthChecksAPIClient
+	route53.ListHostedZonesAPIClient
+}
+
+// AddMetadata adds metadata for Route 53 health checks and hosted zones. They
+// are global resources, their metrics are only available in us-east-1.
+func AddMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svc := route53.NewFromConfig(awsConfig)
+	return addMetadata(ctx, svc, discovery, regionName, events)
+}
+
+func addMetadata(ctx context.Context, svc route53API, discovery metadata.Discovery, regionName string, events map[string]mb.Event) (map[string]mb.Event, error) {
+	var healthChecks map[string]types.HealthCheck
+	var hostedZones map[string]types.HostedZone
+	for _, event := range events {
+		if healthCheckID := getDimension(event, "HealthCheckId"); healthCheckID != "" {
+			if healthChecks == nil {
+				listed, err := metadata.Discover(ctx, discovery, "route53:ListHealthChecks", regionName, "", func(ctx context.Context) (interface{}, error) {
+					return getHealthChecks(ctx, svc)
+				})
+				if err != nil {
+					return events, fmt.Errorf("getHealthChecks failed in region %s: %w", regionName, err)
+				}
+				healthChecks, _ = listed.(map[string]types.HealthCheck)
+			}
+			if healthCheck, ok := healthChecks[healthCheckID]; ok {
+				addHealthCheckMetadata(event, healthCheck)
+			}
+		}
+
+		if hostedZoneID := getDimension(event, "HostedZoneId"); hostedZoneID != "" {
+			if hostedZones == nil {
+				listed, err := metadata.Discover(ctx, discovery, "route53:ListHostedZones", regionName, "", func(ctx context.Context) (interface{}, error) {
+					return getHostedZones(ctx, svc)
+				})
+				if err != nil {
+					return events, fmt.Errorf("getHostedZones failed in region %s: %w", regionName, err)
+				}
+				hostedZones, _ = listed.(map[string]types.HostedZone)
+			}
+			if hostedZone, ok := hostedZones[hostedZoneID]; ok {
+				addHostedZoneMetadata(event, hostedZoneID, hostedZone)
+			}
+		}
+	}
+	return events, nil
+}
+
+// AddResolverMetadata adds metadata for Route 53 Resolver endpoints from a
+// specific region
+func AddResolverMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svc := route53resolver.NewFromConfig(awsConfig)
+
+	// The endpoints are shared with the other metricsets of the account
+	listedEndpoints, err := metadata.Discover(ctx, discovery, "route53resolver:ListResolverEndpoints", regionName, "", func(ctx context.Context) (interface{}, error) {
+		return getResolverEndpoints(ctx, svc)
+	})
+	if err != nil {
+		return events, fmt.Errorf("getResolverEndpoints failed, skipping region %s: %w", regionName, err)
+	}
+	endpoints, _ := listedEndpoints.(map[string]resolvertypes.ResolverEndpoint)
+
+	for _, event := range events {
+		if endpoint, ok := endpoints[getDimension(event, "EndpointId")]; ok {
+			addResolverEndpointMetadata(event, endpoint)
+		}
+	}
+	return events, nil
+}
+
+func getDimension(event mb.Event, name string) string {
+	value, err := event.RootFields.GetValue("aws.dimensions." + name)
+	if err != nil {
+		return ""
+	}
+	dimension, _ := value.(string)
+	return dimension
+}
+
+// getHealthChecks returns the health checks of the account by ID.
+func getHealthChecks(ctx context.Context, svc route53.ListHealthChecksAPIClient) (map[string]types.HealthCheck, error) {
+	healthChecks := map[string]types.HealthCheck{}
+	paginator := route53.NewListHealthChecksPaginator(svc, &route53.ListHealthChecksInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return healthChecks, fmt.Errorf("error ListHealthChecks with Paginator: %w", err)
+		}
+		for _, healthCheck := range output.HealthChecks {
+			healthChecks[awssdk.ToString(healthCheck.Id)] = healthCheck
+		}
+	}
+	return healthChecks, nil
+}
+
+// getHostedZones returns the hosted zones of the account by ID. The IDs
+// returned by the API are prefixed with /hostedzone/, unlike the ones in the
+// metrics dimensions.
+func getHostedZones(ctx context.Context, svc route53.ListHostedZonesAPIClient) (map[string]types.HostedZone, error) {
+	hostedZones := map[string]types.HostedZone{}
+	paginator := route53.NewListHostedZonesPaginator(svc, &route53.ListHostedZonesInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return hostedZones, fmt.Errorf("error ListHostedZones with Paginator: %w", err)
+		}
+		for _, hostedZone := range output.HostedZones {
+			hostedZones[strings.TrimPrefix(awssdk.ToString(hostedZone.Id), "/hostedzone/")] = hostedZone
+		}
+	}
+	return hostedZones, nil
+}
+
+// getResolverEndpoints returns the Route 53 Resolver endpoints of a region by ID.
+func getResolverEndpoints(ctx context.Context, svc route53resolver.ListResolverEndpointsAPIClient) (map[string]resolvertypes.ResolverEndpoint, error) {
+	endpoints := map[string]resolvertypes.ResolverEndpoint{}
+	paginator := route53resolver.NewListResolverEndpointsPaginator(svc, &route53resolver.ListResolverEndpointsInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error ListResolverEndpoints with Paginator: %w", err)
+		}
+		for _, endpoint := range output.ResolverEndpoints {
+			endpoints[awssdk.ToString(endpoint.Id)] = endpoint
+		}
+	}
+	return endpoints, nil
+}
+
+func addHealthCheckMetadata(event mb.Event, healthCheck types.HealthCheck) {
+	_, _ = event.RootFields.Put(metadataPrefix+"health_check.id", awssdk.ToString(healthCheck.Id))
+	config := healthCheck.HealthCheckConfig
+	if config == nil {
+		return
+	}
+	if config.Type != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"health_check.type", string(config.Type))
+	}
+	if config.FullyQualifiedDomainName != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"health_check.domain_name", *config.FullyQualifiedDomainName)
+	}
+	if config.IPAddress != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"health_check.ip_address", *config.IPAddress)
+	}
+	if config.Port != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"health_check.port", *config.Port)
+	}
+	if config.ResourcePath != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"health_check.resource_path", *config.ResourcePath)
+	}
+	if config.RequestInterval != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"health_check.request_interval.sec", *config.RequestInterval)
+	}
+	if config.FailureThreshold != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"health_check.failure_threshold", *config.FailureThreshold)
+	}
+}
+
+func addHostedZoneMetadata(event mb.Event, hostedZoneID string, hostedZone types.HostedZone) {
+	_, _ = event.RootFields.Put(metadataPrefix+"hosted_zone.id", hostedZoneID)
+	if hostedZone.Name != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"hosted_zone.name", *hostedZone.Name)
+	}
+	if hostedZone.Config != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"hosted_zone.private", hostedZone.Config.PrivateZone)
+	}
+	if hostedZone.ResourceRecordSetCount != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"hosted_zone.record_sets.count", *hostedZone.ResourceRecordSetCount)
+	}
+}
+
+func addResolverEndpointMetadata(event mb.Event, endpoint resolvertypes.ResolverEndpoint) {
+	_, _ = event.RootFields.Put(metadataPrefix+"resolver_endpoint.id", awssdk.ToString(endpoint.Id))
+	if endpoint.Name != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"resolver_endpoint.name", *endpoint.Name)
+	}
+	if endpoint.Direction != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"resolver_endpoint.direction", string(endpoint.Direction))
+	}
+	if endpoint.Status != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"resolver_endpoint.status", string(endpoint.Status))
+	}
+	if endpoint.HostVPCId != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"resolver_endpoint.vpc_id", *endpoint.HostVPCId)
+	}
+	if endpoint.IpAddressCount != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"resolver_endpoint.ip_addresses.count", *endpoint.IpAddressCount)
+	}
+}
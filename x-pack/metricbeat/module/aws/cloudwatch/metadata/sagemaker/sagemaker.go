@@ -0,0 +1,151 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package sagemaker
+
+import (
+	"context"
+	"fmt"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sagemaker"
+	"github.com/aws/aws-sdk-go-v2/service/sagemaker/types"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
+)
+
+const metadataPrefix = "aws.sagemaker."
+
+// Namespaces enriched by this package, the invocation metrics of SageMaker
+// endpoints and the utilization metrics of their instances.
+const (
+	namespace          = "AWS/SageMaker"
+	endpointsNamespace = "/aws/sagemaker/Endpoints"
+)
+
+func init() {
+	metadata.Enrichers.MustRegister(namespace, AddMetadata)
+	metadata.Enrichers.MustRegister(endpointsNamespace, AddMetadata)
+}
+
+type sagemakerAPI interface {
+	sagemaker.ListEndpointsAPIClient
+	DescribeEndpoint(ctx context.Context, params *sagemaker.DescribeEndpointInput, optFns ...func(*sagemaker.Options)) (*sagemaker.DescribeEndpointOutput, error)
+}
+
+// AddMetadata adds metadata for SageMaker endpoints and their production
+// variants from a specific region
+func AddMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svc := sagemaker.NewFromConfig(awsConfig)
+	return addMetadata(ctx, svc, discovery, regionName, events)
+}
+
+func addMetadata(ctx context.Context, svc sagemakerAPI, discovery metadata.Discovery, regionName string, events map[string]mb.Event) (map[string]mb.Event, error) {
+	// The endpoints are shared with the other metricsets of the account
+	listedEndpoints, err := metadata.Discover(ctx, discovery, "sagemaker:ListEndpoints", regionName, "", func(ctx context.Context) (interface{}, error) {
+		return getEndpoints(ctx, svc)
+	})
+	if err != nil {
+		return events, fmt.Errorf("getEndpoints failed, skipping region %s: %w", regionName, err)
+	}
+	endpoints, _ := listedEndpoints.(map[string]types.EndpointSummary)
+
+	// Endpoints are only described once per fetch to get their variants.
+	variants := map[string]map[string]types.ProductionVariantSummary{}
+	for _, event := range events {
+		endpointName := getDimension(event, "EndpointName")
+		endpoint, ok := endpoints[endpointName]
+		if !ok {
+			continue
+		}
+		addEndpointMetadata(event, endpoint)
+
+		variantName := getDimension(event, "VariantName")
+		if variantName == "" {
+			continue
+		}
+		endpointVariants, ok := variants[endpointName]
+		if !ok {
+			described, err := metadata.Discover(ctx, discovery, "sagemaker:DescribeEndpoint", regionName, endpointName, func(ctx context.Context) (interface{}, error) {
+				return getVariants(ctx, svc, endpointName)
+			})
+			if err != nil {
+				return events, fmt.Errorf("getVariants of endpoint %s failed in region %s: %w", endpointName, regionName, err)
+			}
+			endpointVariants, _ = described.(map[string]types.ProductionVariantSummary)
+			variants[endpointName] = endpointVariants
+		}
+		if variant, ok := endpointVariants[variantName]; ok {
+			addVariantMetadata(event, variant)
+		}
+	}
+	return events, nil
+}
+
+func getDimension(event mb.Event, name string) string {
+	value, err := event.RootFields.GetValue("aws.dimensions." + name)
+	if err != nil {
+		return ""
+	}
+	dimension, _ := value.(string)
+	return dimension
+}
+
+// getEndpoints returns the SageMaker endpoints of a region by name.
+func getEndpoints(ctx context.Context, svc sagemaker.ListEndpointsAPIClient) (map[string]types.EndpointSummary, error) {
+	endpoints := map[string]types.EndpointSummary{}
+	paginator := sagemaker.NewListEndpointsPaginator(svc, &sagemaker.ListEndpointsInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error ListEndpoints with Paginator: %w", err)
+		}
+		for _, endpoint := range output.Endpoints {
+			endpoints[awssdk.ToString(endpoint.EndpointName)] = endpoint
+		}
+	}
+	return endpoints, nil
+}
+
+func getVariants(ctx context.Context, svc sagemakerAPI, endpointName string) (map[string]types.ProductionVariantSummary, error) {
+	output, err := svc.DescribeEndpoint(ctx, &sagemaker.DescribeEndpointInput{EndpointName: awssdk.String(endpointName)})
+	if err != nil {
+		return nil, fmt.Errorf("error DescribeEndpoint: %w", err)
+	}
+	variants := make(map[string]types.ProductionVariantSummary, len(output.ProductionVariants))
+	for _, variant := range output.ProductionVariants {
+		variants[awssdk.ToString(variant.VariantName)] = variant
+	}
+	return variants, nil
+}
+
+func addEndpointMetadata(event mb.Event, endpoint types.EndpointSummary) {
+	_, _ = event.RootFields.Put(metadataPrefix+"endpoint.name", awssdk.ToString(endpoint.EndpointName))
+	if endpoint.EndpointArn != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"endpoint.arn", *endpoint.EndpointArn)
+	}
+	if endpoint.EndpointStatus != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"endpoint.status", string(endpoint.EndpointStatus))
+	}
+	if endpoint.CreationTime != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"endpoint.created_at", *endpoint.CreationTime)
+	}
+	if endpoint.LastModifiedTime != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"endpoint.updated_at", *endpoint.LastModifiedTime)
+	}
+}
+
+func addVariantMetadata(event mb.Event, variant types.ProductionVariantSummary) {
+	_, _ = event.RootFields.Put(metadataPrefix+"variant.name", awssdk.ToString(variant.VariantName))
+	if variant.CurrentInstanceCount != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"variant.instances.current", *variant.CurrentInstanceCount)
+	}
+	if variant.DesiredInstanceCount != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"variant.instances.desired", *variant.DesiredInstanceCount)
+	}
+	if variant.CurrentWeight != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"variant.weight", *variant.CurrentWeight)
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package ses
+
+import (
+	"context"
+	"fmt"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ses"
+	"github.com/aws/aws-sdk-go-v2/service/ses/types"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
+)
+
+const metadataPrefix = "aws.ses."
+
+// namespace is the CloudWatch namespace enriched by this package.
+const namespace = "AWS/SES"
+
+func init() {
+	metadata.Enrichers.MustRegister(namespace, AddMetadata)
+}
+
+type sesAPI interface {
+	GetSendStatistics(ctx context.Context, params *ses.GetSendStatisticsInput, optFns ...func(*ses.Options)) (*ses.GetSendStatisticsOutput, error)
+	GetSendQuota(ctx context.Context, params *ses.GetSendQuotaInput, optFns ...func(*ses.Options)) (*ses.GetSendQuotaOutput, error)
+}
+
+// AddMetadata adds the sending statistics and quota of the SES account from a
+// specific region
+func AddMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svc := ses.NewFromConfig(awsConfig)
+	return addMetadata(ctx, svc, discovery, regionName, events)
+}
+
+func addMetadata(ctx context.Context, svc sesAPI, discovery metadata.Discovery, regionName string, events map[string]mb.Event) (map[string]mb.Event, error) {
+	if len(events) == 0 {
+		return events, nil
+	}
+
+	// Sending statistics and quota are per account and region, so they are
+	// requested once and added to all the events of the region.
+	listedStatistics, err := metadata.Discover(ctx, discovery, "ses:GetSendStatistics", regionName, "", func(ctx context.Context) (interface{}, error) {
+		return svc.GetSendStatistics(ctx, &ses.GetSendStatisticsInput{})
+	})
+	if err != nil {
+		return events, fmt.Errorf("GetSendStatistics failed in region %s: %w", regionName, err)
+	}
+	listedQuota, err := metadata.Discover(ctx, discovery, "ses:GetSendQuota", regionName, "", func(ctx context.Context) (interface{}, error) {
+		return svc.GetSendQuota(ctx, &ses.GetSendQuotaInput{})
+	})
+	if err != nil {
+		return events, fmt.Errorf("GetSendQuota failed in region %s: %w", regionName, err)
+	}
+	statistics, _ := listedStatistics.(*ses.GetSendStatisticsOutput)
+	quota, _ := listedQuota.(*ses.GetSendQuotaOutput)
+
+	for _, event := range events {
+		addSendStatistics(event, statistics.SendDataPoints)
+		_, _ = event.RootFields.Put(metadataPrefix+"quota.max_24_hour_send", quota.Max24HourSend)
+		_, _ = event.RootFields.Put(metadataPrefix+"quota.max_send_rate", quota.MaxSendRate)
+		_, _ = event.RootFields.Put(metadataPrefix+"quota.sent_last_24_hours", quota.SentLast24Hours)
+	}
+	return events, nil
+}
+
+// addSendStatistics adds the latest data point of the sending statistics, that
+// GetSendStatistics returns in 15 minutes intervals for the last two weeks, and
+// the bounce and complaint rates over the two weeks.
+func addSendStatistics(event mb.Event, dataPoints []types.SendDataPoint) {
+	if len(dataPoints) == 0 {
+		return
+	}
+
+	var latest types.SendDataPoint
+	var deliveryAttempts, bounces, complaints int64
+	for _, dataPoint := range dataPoints {
+		if dataPoint.Timestamp != nil && (latest.Timestamp == nil || dataPoint.Timestamp.After(*latest.Timestamp)) {
+			latest = dataPoint
+		}
+		deliveryAttempts += dataPoint.DeliveryAttempts
+		bounces += dataPoint.Bounces
+		complaints += dataPoint.Complaints
+	}
+
+	if latest.Timestamp != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"send_statistics.timestamp", *latest.Timestamp)
+	}
+	_, _ = event.RootFields.Put(metadataPrefix+"send_statistics.delivery_attempts", latest.DeliveryAttempts)
+	_, _ = event.RootFields.Put(metadataPrefix+"send_statistics.bounces", latest.Bounces)
+	_, _ = event.RootFields.Put(metadataPrefix+"send_statistics.complaints", latest.Complaints)
+	_, _ = event.RootFields.Put(metadataPrefix+"send_statistics.rejects", latest.Rejects)
+
+	if deliveryAttempts > 0 {
+		_, _ = event.RootFields.Put(metadataPrefix+"send_statistics.bounce_rate", float64(bounces)/float64(deliveryAttempts))
+		_, _ = event.RootFields.Put(metadataPrefix+"send_statistics.complaint_rate", float64(complaints)/float64(deliveryAttempts))
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package shield
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/shield"
+	"github.com/aws/aws-sdk-go-v2/service/shield/types"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
+)
+
+const metadataPrefix = "aws.shield."
+
+// namespace is the CloudWatch namespace enriched by this package.
+const namespace = "AWS/DDoSProtection"
+
+// shieldRegion is the only region where the Shield API is available.
+const shieldRegion = "us-east-1"
+
+// attacksPeriod is how far back the attacks against a resource are listed.
+const attacksPeriod = 24 * time.Hour
+
+func init() {
+	metadata.Enrichers.MustRegister(namespace, AddMetadata)
+}
+
+type shieldAPI interface {
+	ListAttacks(ctx context.Context, params *shield.ListAttacksInput, optFns ...func(*shield.Options)) (*shield.ListAttacksOutput, error)
+}
+
+// AddMetadata adds the summary of the Shield Advanced attacks of the protected
+// resources from a specific region
+func AddMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svc := shield.NewFromConfig(awsConfig, func(o *shield.Options) {
+		o.Region = shieldRegion
+	})
+	return addMetadata(ctx, svc, discovery, regionName, time.Now(), events)
+}
+
+func addMetadata(ctx context.Context, svc shieldAPI, discovery metadata.Discovery, regionName string, now time.Time, events map[string]mb.Event) (map[string]mb.Event, error) {
+	var resourceARNs []string
+	for _, event := range events {
+		if resourceARN := getDimension(event, "ResourceArn"); resourceARN != "" {
+			resourceARNs = append(resourceARNs, resourceARN)
+		}
+	}
+	if len(resourceARNs) == 0 {
+		return events, nil
+	}
+
+	listed, err := metadata.Discover(ctx, discovery, "shield:ListAttacks", regionName, "", func(ctx context.Context) (interface{}, error) {
+		return getAttacks(ctx, svc, now)
+	})
+	if err != nil {
+		return events, fmt.Errorf("getAttacks failed for region %s: %w", regionName, err)
+	}
+	attacks, _ := listed.(map[string][]types.AttackSummary)
+
+	for _, event := range events {
+		resourceARN := getDimension(event, "ResourceArn")
+		if resourceARN == "" {
+			continue
+		}
+		addAttacksMetadata(event, attacks[resourceARN])
+	}
+	return events, nil
+}
+
+func getDimension(event mb.Event, name string) string {
+	value, err := event.RootFields.GetValue("aws.dimensions." + name)
+	if err != nil {
+		return ""
+	}
+	dimension, _ := value.(string)
+	return dimension
+}
+
+// getAttacks returns the attacks of the last attacksPeriod by the ARN of the
+// attacked resource.
+func getAttacks(ctx context.Context, svc shieldAPI, now time.Time) (map[string][]types.AttackSummary, error) {
+	attacks := map[string][]types.AttackSummary{}
+	input := &shield.ListAttacksInput{
+		StartTime: &types.TimeRange{
+			FromInclusive: awssdk.Time(now.Add(-attacksPeriod)),
+			ToExclusive:   awssdk.Time(now),
+		},
+	}
+	for {
+		output, err := svc.ListAttacks(ctx, input)
+		if err != nil {
+			return attacks, fmt.Errorf("error ListAttacks: %w", err)
+		}
+		for _, attack := range output.AttackSummaries {
+			resourceARN := awssdk.ToString(attack.ResourceArn)
+			attacks[resourceARN] = append(attacks[resourceARN], attack)
+		}
+		if output.NextToken == nil {
+			return attacks, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+func addAttacksMetadata(event mb.Event, attacks []types.AttackSummary) {
+	_, _ = event.RootFields.Put(metadataPrefix+"attacks.count", len(attacks))
+
+	ongoing := 0
+	vectors := map[string]struct{}{}
+	var latest *types.AttackSummary
+	for i, attack := range attacks {
+		if attack.EndTime == nil {
+			ongoing++
+		}
+		for _, vector := range attack.AttackVectors {
+			vectors[awssdk.ToString(vector.VectorType)] = struct{}{}
+		}
+		if attack.StartTime != nil && (latest == nil || attack.StartTime.After(*latest.StartTime)) {
+			latest = &attacks[i]
+		}
+	}
+	_, _ = event.RootFields.Put(metadataPrefix+"attacks.ongoing", ongoing)
+
+	if len(vectors) > 0 {
+		vectorTypes := make([]string, 0, len(vectors))
+		for vector := range vectors {
+			vectorTypes = append(vectorTypes, vector)
+		}
+		sort.Strings(vectorTypes)
+		_, _ = event.RootFields.Put(metadataPrefix+"attacks.vectors", vectorTypes)
+	}
+
+	if latest == nil {
+		return
+	}
+	_, _ = event.RootFields.Put(metadataPrefix+"attacks.latest.id", awssdk.ToString(latest.AttackId))
+	_, _ = event.RootFields.Put(metadataPrefix+"attacks.latest.start_time", *latest.StartTime)
+	if latest.EndTime != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"attacks.latest.end_time", *latest.EndTime)
+	}
+}
@@ -7,49 +7,82 @@ package sqs
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"strings"
 
 	awssdk "github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 
 	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
 	"github.com/elastic/elastic-agent-libs/logp"
 )
 
 const metadataPrefix = "aws.sqs.queue"
 
+// namespace is the CloudWatch namespace enriched by this package.
+const namespace = "AWS/SQS"
+
+func init() {
+	metadata.Enrichers.MustRegister(namespace, AddMetadata)
+}
+
 // AddMetadata adds metadata for SQS queues from a specific region
-func AddMetadata(regionName string, awsConfig awssdk.Config, fips_enabled bool, events map[string]mb.Event) (map[string]mb.Event, error) {
-	svc := sqs.NewFromConfig(awsConfig, func(o *sqs.Options) {
-		if fips_enabled {
-			o.EndpointOptions.UseFIPSEndpoint = awssdk.FIPSEndpointStateEnabled
-		}
+func AddMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svc := sqs.NewFromConfig(awsConfig)
 
+	// Get queueUrls for each region, shared with the other metricsets of the account
+	listedQueueURLs, err := metadata.Discover(ctx, discovery, "sqs:ListQueues", regionName, "", func(ctx context.Context) (interface{}, error) {
+		return getQueueUrls(ctx, svc)
 	})
-
-	// Get queueUrls for each region
-	queueURLs, err := getQueueUrls(svc)
 	if err != nil {
-		logp.Error(fmt.Errorf("getQueueUrls failed, skipping region %s: %w", regionName, err))
-		return events, nil
+		return events, fmt.Errorf("getQueueUrls failed, skipping region %s: %w", regionName, err)
 	}
+	queueURLs, _ := listedQueueURLs.([]string)
 
 	// collect monitoring state for each instance
 	for _, queueURL := range queueURLs {
-		queueURLParsed := strings.Split(queueURL, "/")
-		queueName := queueURLParsed[len(queueURLParsed)-1]
+		queueARN, err := queueARNFromURL(regionName, queueURL)
+		if err != nil {
+			logp.Error(fmt.Errorf("skipping queue url %s: %w", queueURL, err))
+			continue
+		}
+		queueName := queueARN.Resource
 		if _, ok := events[queueName]; !ok {
 			continue
 		}
 		_, _ = events[queueName].RootFields.Put(metadataPrefix+".name", queueName)
+		aws.AddARNFields(events[queueName], queueARN.String())
 	}
 	return events, nil
 }
 
-func getQueueUrls(svc *sqs.Client) ([]string, error) {
+// queueARNFromURL returns the ARN of a queue from its URL, of the form
+// https://sqs.<region>.amazonaws.com/<account ID>/<queue name>.
+func queueARNFromURL(regionName string, queueURL string) (aws.ARN, error) {
+	parsedURL, err := url.Parse(queueURL)
+	if err != nil {
+		return aws.ARN{}, err
+	}
+	path := strings.Split(strings.Trim(parsedURL.Path, "/"), "/")
+	if len(path) != 2 || path[0] == "" || path[1] == "" {
+		return aws.ARN{}, fmt.Errorf("unexpected queue url path %s", parsedURL.Path)
+	}
+	return aws.ARN{
+		Partition:  aws.RegionPartition(regionName),
+		Service:    "sqs",
+		Region:     regionName,
+		AccountID:  path[0],
+		Resource:   path[1],
+		ResourceID: path[1],
+	}, nil
+}
+
+func getQueueUrls(ctx context.Context, svc *sqs.Client) ([]string, error) {
 	// ListQueues
 	listQueuesInput := &sqs.ListQueuesInput{}
-	output, err := svc.ListQueues(context.TODO(), listQueuesInput)
+	output, err := svc.ListQueues(ctx, listQueuesInput)
 	if err != nil {
 		err = fmt.Errorf("error ListQueues: %w", err)
 		return nil, err
@@ -0,0 +1,91 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package stepfunctions
+
+import (
+	"context"
+	"fmt"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sfn"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
+)
+
+const metadataPrefix = "aws.stepfunctions.state_machine."
+
+// namespace is the CloudWatch namespace enriched by this package.
+const namespace = "AWS/States"
+
+func init() {
+	metadata.Enrichers.MustRegister(namespace, AddMetadata)
+}
+
+type sfnAPI interface {
+	DescribeStateMachine(ctx context.Context, params *sfn.DescribeStateMachineInput, optFns ...func(*sfn.Options)) (*sfn.DescribeStateMachineOutput, error)
+}
+
+// AddMetadata adds metadata for Step Functions state machines from a specific
+// region
+func AddMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svc := sfn.NewFromConfig(awsConfig)
+	return addMetadata(ctx, svc, discovery, regionName, events)
+}
+
+func addMetadata(ctx context.Context, svc sfnAPI, discovery metadata.Discovery, regionName string, events map[string]mb.Event) (map[string]mb.Event, error) {
+	stateMachines := map[string]*sfn.DescribeStateMachineOutput{}
+	for _, event := range events {
+		value, err := event.RootFields.GetValue("aws.dimensions.StateMachineArn")
+		if err != nil {
+			continue
+		}
+		stateMachineARN, _ := value.(string)
+		if stateMachineARN == "" {
+			continue
+		}
+
+		stateMachine, ok := stateMachines[stateMachineARN]
+		if !ok {
+			described, err := metadata.Discover(ctx, discovery, "states:DescribeStateMachine", regionName, stateMachineARN, func(ctx context.Context) (interface{}, error) {
+				return svc.DescribeStateMachine(ctx, &sfn.DescribeStateMachineInput{
+					StateMachineArn: awssdk.String(stateMachineARN),
+				})
+			})
+			if err != nil {
+				return events, fmt.Errorf("DescribeStateMachine of %s failed in region %s: %w", stateMachineARN, regionName, err)
+			}
+			stateMachine, _ = described.(*sfn.DescribeStateMachineOutput)
+			stateMachines[stateMachineARN] = stateMachine
+		}
+		if stateMachine != nil {
+			addStateMachineMetadata(event, stateMachine)
+		}
+	}
+	return events, nil
+}
+
+func addStateMachineMetadata(event mb.Event, stateMachine *sfn.DescribeStateMachineOutput) {
+	_, _ = event.RootFields.Put(metadataPrefix+"arn", awssdk.ToString(stateMachine.StateMachineArn))
+	_, _ = event.RootFields.Put(metadataPrefix+"name", awssdk.ToString(stateMachine.Name))
+	if stateMachine.Type != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"type", string(stateMachine.Type))
+	}
+	if stateMachine.Status != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"status", string(stateMachine.Status))
+	}
+	if stateMachine.CreationDate != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"created_at", *stateMachine.CreationDate)
+	}
+	if stateMachine.RoleArn != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"role_arn", *stateMachine.RoleArn)
+	}
+	if stateMachine.LoggingConfiguration != nil && stateMachine.LoggingConfiguration.Level != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"logging_level", string(stateMachine.LoggingConfiguration.Level))
+	}
+	if stateMachine.TracingConfiguration != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"tracing_enabled", stateMachine.TracingConfiguration.Enabled)
+	}
+}
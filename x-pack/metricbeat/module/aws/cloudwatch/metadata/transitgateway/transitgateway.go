@@ -0,0 +1,98 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package transitgateway
+
+import (
+	"context"
+	"fmt"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
+)
+
+const metadataPrefix = "aws.transitgateway.attachment."
+
+// namespace is the CloudWatch namespace enriched by this package.
+const namespace = "AWS/TransitGateway"
+
+func init() {
+	metadata.Enrichers.MustRegister(namespace, AddMetadata)
+}
+
+// AddMetadata adds metadata for transit gateway attachments from a specific region
+func AddMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svcEC2 := ec2.NewFromConfig(awsConfig)
+
+	// The attachments are shared with the other metricsets of the account
+	listedAttachments, err := metadata.Discover(ctx, discovery, "ec2:DescribeTransitGatewayAttachments", regionName, "", func(ctx context.Context) (interface{}, error) {
+		return getAttachmentsPerRegion(ctx, svcEC2)
+	})
+	if err != nil {
+		return events, fmt.Errorf("getAttachmentsPerRegion failed, skipping region %s: %w", regionName, err)
+	}
+	attachments, _ := listedAttachments.(map[string]ec2types.TransitGatewayAttachment)
+
+	for _, event := range events {
+		value, err := event.RootFields.GetValue("aws.dimensions.TransitGatewayAttachment")
+		if err != nil {
+			continue
+		}
+		attachmentID, _ := value.(string)
+		if attachment, ok := attachments[attachmentID]; ok {
+			addAttachmentMetadata(event, attachment)
+		}
+	}
+	return events, nil
+}
+
+// getAttachmentsPerRegion returns the transit gateway attachments of a region by ID.
+func getAttachmentsPerRegion(ctx context.Context, svc ec2.DescribeTransitGatewayAttachmentsAPIClient) (map[string]ec2types.TransitGatewayAttachment, error) {
+	attachments := map[string]ec2types.TransitGatewayAttachment{}
+	paginator := ec2.NewDescribeTransitGatewayAttachmentsPaginator(svc, &ec2.DescribeTransitGatewayAttachmentsInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error DescribeTransitGatewayAttachments with Paginator: %w", err)
+		}
+		for _, attachment := range output.TransitGatewayAttachments {
+			attachments[awssdk.ToString(attachment.TransitGatewayAttachmentId)] = attachment
+		}
+	}
+	return attachments, nil
+}
+
+func addAttachmentMetadata(event mb.Event, attachment ec2types.TransitGatewayAttachment) {
+	_, _ = event.RootFields.Put(metadataPrefix+"id", awssdk.ToString(attachment.TransitGatewayAttachmentId))
+	if attachment.State != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"state", string(attachment.State))
+	}
+	if attachment.ResourceOwnerId != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"resource.owner_id", *attachment.ResourceOwnerId)
+	}
+	if attachment.Association != nil && attachment.Association.TransitGatewayRouteTableId != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"route_table_id", *attachment.Association.TransitGatewayRouteTableId)
+	}
+
+	if attachment.ResourceType == "" {
+		return
+	}
+	_, _ = event.RootFields.Put(metadataPrefix+"type", string(attachment.ResourceType))
+	if attachment.ResourceId == nil {
+		return
+	}
+	_, _ = event.RootFields.Put(metadataPrefix+"resource.id", *attachment.ResourceId)
+	// The attached VPC and VPN connection are also added with their own
+	// fields, to be correlated with the vpn metricset and the VPC flow logs.
+	switch attachment.ResourceType {
+	case ec2types.TransitGatewayAttachmentResourceTypeVpc:
+		_, _ = event.RootFields.Put(metadataPrefix+"vpc_id", *attachment.ResourceId)
+	case ec2types.TransitGatewayAttachmentResourceTypeVpn:
+		_, _ = event.RootFields.Put(metadataPrefix+"vpn_id", *attachment.ResourceId)
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package vpn
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
+)
+
+const metadataPrefix = "aws.vpn."
+
+// namespace is the CloudWatch namespace enriched by this package.
+const namespace = "AWS/VPN"
+
+func init() {
+	metadata.Enrichers.MustRegister(namespace, AddMetadata)
+}
+
+type describeVpnConnectionsAPI interface {
+	DescribeVpnConnections(ctx context.Context, params *ec2.DescribeVpnConnectionsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpnConnectionsOutput, error)
+}
+
+// AddMetadata adds metadata for Site-to-Site VPN connections and their tunnels
+// from a specific region
+func AddMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svcEC2 := ec2.NewFromConfig(awsConfig)
+
+	// The connections are shared with the other metricsets of the account
+	listedConnections, err := metadata.Discover(ctx, discovery, "ec2:DescribeVpnConnections", regionName, "", func(ctx context.Context) (interface{}, error) {
+		return getVpnConnectionsPerRegion(ctx, svcEC2)
+	})
+	if err != nil {
+		return events, fmt.Errorf("getVpnConnectionsPerRegion failed, skipping region %s: %w", regionName, err)
+	}
+	connections, _ := listedConnections.(map[string]ec2types.VpnConnection)
+
+	for _, event := range events {
+		value, err := event.RootFields.GetValue("aws.dimensions.VpnId")
+		if err != nil {
+			continue
+		}
+		vpnID, _ := value.(string)
+		connection, ok := connections[vpnID]
+		if !ok {
+			continue
+		}
+		addConnectionMetadata(event, connection)
+
+		value, err = event.RootFields.GetValue("aws.dimensions.TunnelIpAddress")
+		if err != nil {
+			continue
+		}
+		tunnelIPAddress, _ := value.(string)
+		for _, tunnel := range connection.VgwTelemetry {
+			if awssdk.ToString(tunnel.OutsideIpAddress) == tunnelIPAddress {
+				addTunnelMetadata(event, tunnel)
+				break
+			}
+		}
+	}
+	return events, nil
+}
+
+// getVpnConnectionsPerRegion returns the Site-to-Site VPN connections of a
+// region by ID. DescribeVpnConnections is not paginated.
+func getVpnConnectionsPerRegion(ctx context.Context, svc describeVpnConnectionsAPI) (map[string]ec2types.VpnConnection, error) {
+	output, err := svc.DescribeVpnConnections(ctx, &ec2.DescribeVpnConnectionsInput{})
+	if err != nil {
+		return nil, fmt.Errorf("error DescribeVpnConnections: %w", err)
+	}
+
+	connections := make(map[string]ec2types.VpnConnection, len(output.VpnConnections))
+	for _, connection := range output.VpnConnections {
+		connections[awssdk.ToString(connection.VpnConnectionId)] = connection
+	}
+	return connections, nil
+}
+
+func addConnectionMetadata(event mb.Event, connection ec2types.VpnConnection) {
+	_, _ = event.RootFields.Put(metadataPrefix+"connection.id", awssdk.ToString(connection.VpnConnectionId))
+	if connection.State != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"connection.state", string(connection.State))
+	}
+	if connection.Type != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"connection.type", string(connection.Type))
+	}
+	if connection.CustomerGatewayId != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"connection.customer_gateway_id", *connection.CustomerGatewayId)
+	}
+	if connection.VpnGatewayId != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"connection.vpn_gateway_id", *connection.VpnGatewayId)
+	}
+	if connection.TransitGatewayId != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"connection.transit_gateway_id", *connection.TransitGatewayId)
+	}
+	if connection.Options != nil && connection.Options.StaticRoutesOnly != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"connection.static_routes_only", *connection.Options.StaticRoutesOnly)
+	}
+
+	up := 0
+	for _, tunnel := range connection.VgwTelemetry {
+		if tunnel.Status == ec2types.TelemetryStatusUp {
+			up++
+		}
+	}
+	_, _ = event.RootFields.Put(metadataPrefix+"connection.tunnels.up", up)
+	_, _ = event.RootFields.Put(metadataPrefix+"connection.tunnels.down", len(connection.VgwTelemetry)-up)
+}
+
+func addTunnelMetadata(event mb.Event, tunnel ec2types.VgwTelemetry) {
+	_, _ = event.RootFields.Put(metadataPrefix+"tunnel.outside_ip", awssdk.ToString(tunnel.OutsideIpAddress))
+	if tunnel.Status != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"tunnel.status", strings.ToLower(string(tunnel.Status)))
+	}
+	if tunnel.StatusMessage != nil && *tunnel.StatusMessage != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"tunnel.status_message", *tunnel.StatusMessage)
+	}
+	if tunnel.LastStatusChange != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"tunnel.last_status_change", *tunnel.LastStatusChange)
+	}
+	_, _ = event.RootFields.Put(metadataPrefix+"tunnel.accepted_routes", tunnel.AcceptedRouteCount)
+}
@@ -0,0 +1,208 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package waf
+
+import (
+	"context"
+	"fmt"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/wafv2"
+	"github.com/aws/aws-sdk-go-v2/service/wafv2/types"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
+)
+
+const metadataPrefix = "aws.waf."
+
+// namespace is the CloudWatch namespace enriched by this package.
+const namespace = "AWS/WAFV2"
+
+// cloudFrontRegion is the region where the metrics and the web ACLs of the
+// CloudFront scope are available.
+const cloudFrontRegion = "us-east-1"
+
+// allRules is the value of the Rule dimension of the metrics of a whole web ACL.
+const allRules = "ALL"
+
+func init() {
+	metadata.Enrichers.MustRegister(namespace, AddMetadata)
+}
+
+type wafAPI interface {
+	ListWebACLs(ctx context.Context, params *wafv2.ListWebACLsInput, optFns ...func(*wafv2.Options)) (*wafv2.ListWebACLsOutput, error)
+	GetWebACL(ctx context.Context, params *wafv2.GetWebACLInput, optFns ...func(*wafv2.Options)) (*wafv2.GetWebACLOutput, error)
+}
+
+// AddMetadata adds metadata for WAFv2 web ACLs and their rules from a specific
+// region
+func AddMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svc := wafv2.NewFromConfig(awsConfig)
+	return addMetadata(ctx, svc, discovery, regionName, events)
+}
+
+func addMetadata(ctx context.Context, svc wafAPI, discovery metadata.Discovery, regionName string, events map[string]mb.Event) (map[string]mb.Event, error) {
+	// The WebACL and Rule dimensions are the metric names of the visibility
+	// configuration of the web ACLs and rules, that can differ from their
+	// names, so web ACLs are indexed by metric name per scope.
+	webACLs := map[types.Scope]map[string]*types.WebACL{}
+	for _, event := range events {
+		webACLMetricName := getDimension(event, "WebACL")
+		if webACLMetricName == "" {
+			continue
+		}
+
+		// Metrics of regional web ACLs have a Region dimension, the ones of
+		// CloudFront web ACLs are only reported in us-east-1 without it.
+		scope := types.ScopeRegional
+		if getDimension(event, "Region") == "" && regionName == cloudFrontRegion {
+			scope = types.ScopeCloudfront
+		}
+		_, _ = event.RootFields.Put(metadataPrefix+"web_acl.scope", string(scope))
+
+		scopeWebACLs, ok := webACLs[scope]
+		if !ok {
+			listed, err := metadata.Discover(ctx, discovery, "wafv2:GetWebACL", regionName, string(scope), func(ctx context.Context) (interface{}, error) {
+				return getWebACLs(ctx, svc, scope)
+			})
+			if err != nil {
+				return events, fmt.Errorf("getWebACLs of scope %s failed in region %s: %w", scope, regionName, err)
+			}
+			scopeWebACLs, _ = listed.(map[string]*types.WebACL)
+			webACLs[scope] = scopeWebACLs
+		}
+
+		webACL, ok := scopeWebACLs[webACLMetricName]
+		if !ok {
+			continue
+		}
+		addWebACLMetadata(event, webACL)
+
+		ruleMetricName := getDimension(event, "Rule")
+		if ruleMetricName == "" || ruleMetricName == allRules {
+			continue
+		}
+		for _, rule := range webACL.Rules {
+			if rule.VisibilityConfig != nil && awssdk.ToString(rule.VisibilityConfig.MetricName) == ruleMetricName {
+				addRuleMetadata(event, rule)
+				break
+			}
+		}
+	}
+	return events, nil
+}
+
+func getDimension(event mb.Event, name string) string {
+	value, err := event.RootFields.GetValue("aws.dimensions." + name)
+	if err != nil {
+		return ""
+	}
+	dimension, _ := value.(string)
+	return dimension
+}
+
+// getWebACLs returns the web ACLs of a scope by the metric name of their
+// visibility configuration.
+func getWebACLs(ctx context.Context, svc wafAPI, scope types.Scope) (map[string]*types.WebACL, error) {
+	webACLs := map[string]*types.WebACL{}
+	input := &wafv2.ListWebACLsInput{Scope: scope}
+	for {
+		output, err := svc.ListWebACLs(ctx, input)
+		if err != nil {
+			return webACLs, fmt.Errorf("error ListWebACLs: %w", err)
+		}
+		for _, summary := range output.WebACLs {
+			webACLOutput, err := svc.GetWebACL(ctx, &wafv2.GetWebACLInput{
+				Id:    summary.Id,
+				Name:  summary.Name,
+				Scope: scope,
+			})
+			if err != nil {
+				return webACLs, fmt.Errorf("error GetWebACL of web ACL %s: %w", awssdk.ToString(summary.Name), err)
+			}
+			webACL := webACLOutput.WebACL
+			if webACL == nil || webACL.VisibilityConfig == nil {
+				continue
+			}
+			webACLs[awssdk.ToString(webACL.VisibilityConfig.MetricName)] = webACL
+		}
+		if output.NextMarker == nil {
+			return webACLs, nil
+		}
+		input.NextMarker = output.NextMarker
+	}
+}
+
+func addWebACLMetadata(event mb.Event, webACL *types.WebACL) {
+	_, _ = event.RootFields.Put(metadataPrefix+"web_acl.name", awssdk.ToString(webACL.Name))
+	_, _ = event.RootFields.Put(metadataPrefix+"web_acl.id", awssdk.ToString(webACL.Id))
+	_, _ = event.RootFields.Put(metadataPrefix+"web_acl.arn", awssdk.ToString(webACL.ARN))
+	if webACL.Description != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"web_acl.description", *webACL.Description)
+	}
+	if webACL.DefaultAction != nil {
+		if webACL.DefaultAction.Block != nil {
+			_, _ = event.RootFields.Put(metadataPrefix+"web_acl.default_action", "block")
+		} else if webACL.DefaultAction.Allow != nil {
+			_, _ = event.RootFields.Put(metadataPrefix+"web_acl.default_action", "allow")
+		}
+	}
+	_, _ = event.RootFields.Put(metadataPrefix+"web_acl.capacity", webACL.Capacity)
+	_, _ = event.RootFields.Put(metadataPrefix+"web_acl.managed_by_firewall_manager", webACL.ManagedByFirewallManager)
+	_, _ = event.RootFields.Put(metadataPrefix+"web_acl.rules.count", len(webACL.Rules))
+}
+
+func addRuleMetadata(event mb.Event, rule types.Rule) {
+	_, _ = event.RootFields.Put(metadataPrefix+"rule.name", awssdk.ToString(rule.Name))
+	_, _ = event.RootFields.Put(metadataPrefix+"rule.priority", rule.Priority)
+	if action := ruleAction(rule); action != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"rule.action", action)
+	}
+
+	if rule.Statement == nil {
+		return
+	}
+	switch {
+	case rule.Statement.ManagedRuleGroupStatement != nil:
+		statement := rule.Statement.ManagedRuleGroupStatement
+		_, _ = event.RootFields.Put(metadataPrefix+"rule.type", "managed_rule_group")
+		_, _ = event.RootFields.Put(metadataPrefix+"rule_group.name", awssdk.ToString(statement.Name))
+		_, _ = event.RootFields.Put(metadataPrefix+"rule_group.vendor", awssdk.ToString(statement.VendorName))
+		if statement.Version != nil {
+			_, _ = event.RootFields.Put(metadataPrefix+"rule_group.version", *statement.Version)
+		}
+	case rule.Statement.RuleGroupReferenceStatement != nil:
+		_, _ = event.RootFields.Put(metadataPrefix+"rule.type", "rule_group")
+		_, _ = event.RootFields.Put(metadataPrefix+"rule_group.arn", awssdk.ToString(rule.Statement.RuleGroupReferenceStatement.ARN))
+	default:
+		_, _ = event.RootFields.Put(metadataPrefix+"rule.type", "rule")
+	}
+}
+
+// ruleAction returns the action of a rule, or the override action of the rules
+// that reference a rule group.
+func ruleAction(rule types.Rule) string {
+	if rule.OverrideAction != nil {
+		if rule.OverrideAction.Count != nil {
+			return "count"
+		}
+		return "none"
+	}
+	if rule.Action == nil {
+		return ""
+	}
+	switch {
+	case rule.Action.Allow != nil:
+		return "allow"
+	case rule.Action.Block != nil:
+		return "block"
+	case rule.Action.Count != nil:
+		return "count"
+	case rule.Action.Captcha != nil:
+		return "captcha"
+	}
+	return ""
+}
@@ -0,0 +1,188 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package workspaces
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/workspaces"
+	"github.com/aws/aws-sdk-go-v2/service/workspaces/types"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws/cloudwatch/metadata"
+)
+
+const metadataPrefix = "aws.workspaces."
+
+// namespace is the CloudWatch namespace enriched by this package.
+const namespace = "AWS/WorkSpaces"
+
+// maxIDsPerRequest is the maximum number of WorkSpace or bundle IDs of a
+// DescribeWorkspaces or DescribeWorkspaceBundles request.
+const maxIDsPerRequest = 25
+
+func init() {
+	metadata.Enrichers.MustRegister(namespace, AddMetadata)
+}
+
+type workspacesAPI interface {
+	DescribeWorkspaces(ctx context.Context, params *workspaces.DescribeWorkspacesInput, optFns ...func(*workspaces.Options)) (*workspaces.DescribeWorkspacesOutput, error)
+	DescribeWorkspaceBundles(ctx context.Context, params *workspaces.DescribeWorkspaceBundlesInput, optFns ...func(*workspaces.Options)) (*workspaces.DescribeWorkspaceBundlesOutput, error)
+}
+
+// AddMetadata adds metadata for WorkSpaces and their bundles from a specific
+// region
+func AddMetadata(ctx context.Context, regionName string, awsConfig awssdk.Config, discovery metadata.Discovery, events map[string]mb.Event) (map[string]mb.Event, error) {
+	svc := workspaces.NewFromConfig(awsConfig)
+	return addMetadata(ctx, svc, discovery, regionName, events)
+}
+
+func addMetadata(ctx context.Context, svc workspacesAPI, discovery metadata.Discovery, regionName string, events map[string]mb.Event) (map[string]mb.Event, error) {
+	seen := map[string]struct{}{}
+	var workspaceIDs []string
+	for _, event := range events {
+		workspaceID := getDimension(event, "WorkspaceId")
+		if _, ok := seen[workspaceID]; workspaceID == "" || ok {
+			continue
+		}
+		seen[workspaceID] = struct{}{}
+		workspaceIDs = append(workspaceIDs, workspaceID)
+	}
+	if len(workspaceIDs) == 0 {
+		return events, nil
+	}
+
+	// The WorkSpaces and bundles are described together, so they are shared
+	// by the fetches of the same set of IDs.
+	sort.Strings(workspaceIDs)
+	listedWorkspaces, err := metadata.Discover(ctx, discovery, "workspaces:DescribeWorkspaces", regionName, strings.Join(workspaceIDs, ","), func(ctx context.Context) (interface{}, error) {
+		return getWorkspaces(ctx, svc, workspaceIDs)
+	})
+	if err != nil {
+		return events, fmt.Errorf("getWorkspaces failed in region %s: %w", regionName, err)
+	}
+	workspacesByID, _ := listedWorkspaces.(map[string]types.Workspace)
+
+	seen = map[string]struct{}{}
+	var bundleIDs []string
+	for _, workspace := range workspacesByID {
+		bundleID := awssdk.ToString(workspace.BundleId)
+		if _, ok := seen[bundleID]; bundleID == "" || ok {
+			continue
+		}
+		seen[bundleID] = struct{}{}
+		bundleIDs = append(bundleIDs, bundleID)
+	}
+	sort.Strings(bundleIDs)
+	listedBundleNames, err := metadata.Discover(ctx, discovery, "workspaces:DescribeWorkspaceBundles", regionName, strings.Join(bundleIDs, ","), func(ctx context.Context) (interface{}, error) {
+		return getBundleNames(ctx, svc, bundleIDs)
+	})
+	if err != nil {
+		return events, fmt.Errorf("getBundleNames failed in region %s: %w", regionName, err)
+	}
+	bundleNames, _ := listedBundleNames.(map[string]string)
+
+	for _, event := range events {
+		workspace, ok := workspacesByID[getDimension(event, "WorkspaceId")]
+		if !ok {
+			continue
+		}
+		addWorkspaceMetadata(event, workspace)
+		if name, ok := bundleNames[awssdk.ToString(workspace.BundleId)]; ok {
+			_, _ = event.RootFields.Put(metadataPrefix+"bundle.name", name)
+		}
+	}
+	return events, nil
+}
+
+func getDimension(event mb.Event, name string) string {
+	value, err := event.RootFields.GetValue("aws.dimensions." + name)
+	if err != nil {
+		return ""
+	}
+	dimension, _ := value.(string)
+	return dimension
+}
+
+// getWorkspaces returns the WorkSpaces with the given IDs by ID.
+func getWorkspaces(ctx context.Context, svc workspacesAPI, workspaceIDs []string) (map[string]types.Workspace, error) {
+	workspacesByID := map[string]types.Workspace{}
+	for start := 0; start < len(workspaceIDs); start += maxIDsPerRequest {
+		end := start + maxIDsPerRequest
+		if end > len(workspaceIDs) {
+			end = len(workspaceIDs)
+		}
+		output, err := svc.DescribeWorkspaces(ctx, &workspaces.DescribeWorkspacesInput{WorkspaceIds: workspaceIDs[start:end]})
+		if err != nil {
+			return workspacesByID, fmt.Errorf("error DescribeWorkspaces: %w", err)
+		}
+		for _, workspace := range output.Workspaces {
+			workspacesByID[awssdk.ToString(workspace.WorkspaceId)] = workspace
+		}
+	}
+	return workspacesByID, nil
+}
+
+// getBundleNames returns the names of the bundles with the given IDs by ID.
+func getBundleNames(ctx context.Context, svc workspacesAPI, bundleIDs []string) (map[string]string, error) {
+	names := map[string]string{}
+	for start := 0; start < len(bundleIDs); start += maxIDsPerRequest {
+		end := start + maxIDsPerRequest
+		if end > len(bundleIDs) {
+			end = len(bundleIDs)
+		}
+		output, err := svc.DescribeWorkspaceBundles(ctx, &workspaces.DescribeWorkspaceBundlesInput{BundleIds: bundleIDs[start:end]})
+		if err != nil {
+			return names, fmt.Errorf("error DescribeWorkspaceBundles: %w", err)
+		}
+		for _, bundle := range output.Bundles {
+			names[awssdk.ToString(bundle.BundleId)] = awssdk.ToString(bundle.Name)
+		}
+	}
+	return names, nil
+}
+
+func addWorkspaceMetadata(event mb.Event, workspace types.Workspace) {
+	_, _ = event.RootFields.Put(metadataPrefix+"workspace.id", awssdk.ToString(workspace.WorkspaceId))
+	if workspace.DirectoryId != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"workspace.directory_id", *workspace.DirectoryId)
+	}
+	if workspace.UserName != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"workspace.user_name", *workspace.UserName)
+	}
+	if workspace.ComputerName != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"workspace.computer_name", *workspace.ComputerName)
+	}
+	if workspace.State != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"workspace.state", string(workspace.State))
+	}
+	if workspace.BundleId != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"bundle.id", *workspace.BundleId)
+	}
+
+	properties := workspace.WorkspaceProperties
+	if properties == nil {
+		return
+	}
+	if properties.RunningMode != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"workspace.running_mode", string(properties.RunningMode))
+	}
+	if properties.RunningModeAutoStopTimeoutInMinutes != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"workspace.auto_stop_timeout.minutes", *properties.RunningModeAutoStopTimeoutInMinutes)
+	}
+	if properties.ComputeTypeName != "" {
+		_, _ = event.RootFields.Put(metadataPrefix+"workspace.compute_type", string(properties.ComputeTypeName))
+	}
+	if properties.RootVolumeSizeGib != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"workspace.root_volume_size.gib", *properties.RootVolumeSizeGib)
+	}
+	if properties.UserVolumeSizeGib != nil {
+		_, _ = event.RootFields.Put(metadataPrefix+"workspace.user_volume_size.gib", *properties.UserVolumeSizeGib)
+	}
+}
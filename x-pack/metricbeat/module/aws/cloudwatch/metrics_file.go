@@ -0,0 +1,73 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cloudwatch
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+// loadMetricsFile reads the metrics configs from the YAML or JSON file set in
+// metrics_path, and returns them with the modification time of the file.
+func loadMetricsFile(path string) ([]Config, time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("error reading metrics_path %s: %w", path, err)
+	}
+
+	cfg, err := common.LoadFile(path)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("error loading metrics_path %s: %w", path, err)
+	}
+
+	metricsFile := struct {
+		CloudwatchMetrics []Config `config:"metrics"`
+	}{}
+	if err := cfg.Unpack(&metricsFile); err != nil {
+		return nil, time.Time{}, fmt.Errorf("error unpacking metrics_path %s: %w", path, err)
+	}
+	return metricsFile.CloudwatchMetrics, info.ModTime(), nil
+}
+
+// reloadMetricsFile reloads the metrics configs from metrics_path when the file
+// changed since it was last loaded. When the file cannot be loaded or its
+// metrics configs are invalid, the current metrics configs are kept.
+func (m *MetricSet) reloadMetricsFile() error {
+	info, err := os.Stat(m.config.MetricsPath)
+	if err != nil {
+		return fmt.Errorf("error reading metrics_path %s: %w", m.config.MetricsPath, err)
+	}
+	if info.ModTime().Equal(m.metricsFileModTime) {
+		return nil
+	}
+
+	fileConfigs, modTime, err := loadMetricsFile(m.config.MetricsPath)
+	if err != nil {
+		return err
+	}
+
+	cloudwatchConfigs := append(append([]Config{}, m.config.CloudwatchMetrics...), fileConfigs...)
+	if len(cloudwatchConfigs) == 0 {
+		return fmt.Errorf("no metrics configs found in metrics_path %s", m.config.MetricsPath)
+	}
+	tagSources, err := validateConfigs(cloudwatchConfigs, m.Period, m.config.ConfigAggregator)
+	if err != nil {
+		return err
+	}
+
+	added, removed := diffConfigs(m.CloudwatchConfigs, cloudwatchConfigs)
+	m.logger.Infof("Metrics configs reloaded from %s, %d added and %d removed", m.config.MetricsPath, len(added), len(removed))
+
+	m.CloudwatchConfigs = cloudwatchConfigs
+	m.tagSources = tagSources
+	m.metricsFileModTime = modTime
+	if m.state != nil {
+		m.state.reload(cloudwatchConfigs)
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cloudwatch
+
+import (
+	"context"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/pi"
+	pitypes "github.com/aws/aws-sdk-go-v2/service/pi/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws"
+)
+
+const (
+	// dbLoadMetric is the Performance Insights metric of the database load,
+	// the average number of active sessions.
+	dbLoadMetric = "db.load.avg"
+
+	waitEventGroup = "db.wait_event"
+	sqlGroup       = "db.sql_tokenized"
+
+	// performanceInsightsPeriod is the period of the data points requested
+	// from Performance Insights, they are averaged over the collection period.
+	performanceInsightsPeriod = 60
+
+	defaultPerformanceInsightsLimit = 10
+)
+
+// PerformanceInsightsConfig holds the configuration of the collection of the
+// database load of RDS instances from Performance Insights.
+type PerformanceInsightsConfig struct {
+	Enabled       bool  `config:"enabled"`
+	TopWaitEvents int32 `config:"top_wait_events" validate:"min=0,max=25"`
+	TopSQL        int32 `config:"top_sql" validate:"min=0,max=25"`
+}
+
+// performanceInsightsInstance is an RDS instance with Performance Insights
+// enabled.
+type performanceInsightsInstance struct {
+	identifier string
+	resourceID string
+}
+
+// getResourceMetricsAPI is the part of the pi client used to get the database
+// load of an instance.
+type getResourceMetricsAPI interface {
+	GetResourceMetrics(ctx context.Context, params *pi.GetResourceMetricsInput, optFns ...func(*pi.Options)) (*pi.GetResourceMetricsOutput, error)
+}
+
+// collectPerformanceInsights reports the database load of the RDS instances
+// with Performance Insights enabled in each region, in total, by wait event and
+// by SQL statement.
+func (m *MetricSet) collectPerformanceInsights(ctx context.Context, report mb.ReporterV2, now time.Time) {
+	if !m.config.PerformanceInsights.Enabled {
+		return
+	}
+
+	startTime, endTime := m.getStartTimeEndTime(now, m.Period, m.Latency)
+	for _, regionName := range m.MetricSet.RegionsList {
+		beatsConfig := m.MetricSet.AwsConfig.Copy()
+		beatsConfig.Region = regionName
+
+		svcRDS := rds.NewFromConfig(beatsConfig)
+		instances, err := getPerformanceInsightsInstances(ctx, svcRDS)
+		if err != nil {
+			m.logger.Warnf("skipping Performance Insights from region '%s': %s", regionName, err)
+			continue
+		}
+
+		svcPI := pi.NewFromConfig(beatsConfig)
+		for _, event := range m.createPerformanceInsightsEvents(ctx, svcPI, regionName, instances, startTime, endTime) {
+			report.Event(event)
+		}
+	}
+}
+
+// getPerformanceInsightsInstances returns the RDS instances of a region with
+// Performance Insights enabled.
+func getPerformanceInsightsInstances(ctx context.Context, svc rds.DescribeDBInstancesAPIClient) ([]performanceInsightsInstance, error) {
+	var instances []performanceInsightsInstance
+	paginator := rds.NewDescribeDBInstancesPaginator(svc, &rds.DescribeDBInstancesInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return instances, err
+		}
+		for _, instance := range output.DBInstances {
+			if !awssdk.ToBool(instance.PerformanceInsightsEnabled) || instance.DbiResourceId == nil {
+				continue
+			}
+			instances = append(instances, performanceInsightsInstance{
+				identifier: awssdk.ToString(instance.DBInstanceIdentifier),
+				resourceID: *instance.DbiResourceId,
+			})
+		}
+	}
+	return instances, nil
+}
+
+// createPerformanceInsightsEvents returns, for each instance, an event with its
+// database load and an event for each of its top wait events and SQL
+// statements. Instances whose metrics cannot be retrieved are skipped.
+func (m *MetricSet) createPerformanceInsightsEvents(ctx context.Context, svc getResourceMetricsAPI, regionName string, instances []performanceInsightsInstance, startTime time.Time, endTime time.Time) []mb.Event {
+	topWaitEvents := m.config.PerformanceInsights.TopWaitEvents
+	if topWaitEvents == 0 {
+		topWaitEvents = defaultPerformanceInsightsLimit
+	}
+	topSQL := m.config.PerformanceInsights.TopSQL
+	if topSQL == 0 {
+		topSQL = defaultPerformanceInsightsLimit
+	}
+
+	var events []mb.Event
+	for _, instance := range instances {
+		output, err := svc.GetResourceMetrics(ctx, &pi.GetResourceMetricsInput{
+			ServiceType:     pitypes.ServiceTypeRds,
+			Identifier:      awssdk.String(instance.resourceID),
+			StartTime:       &startTime,
+			EndTime:         &endTime,
+			PeriodInSeconds: awssdk.Int32(performanceInsightsPeriod),
+			MetricQueries: []pitypes.MetricQuery{
+				{Metric: awssdk.String(dbLoadMetric)},
+				{
+					Metric:  awssdk.String(dbLoadMetric),
+					GroupBy: &pitypes.DimensionGroup{Group: awssdk.String(waitEventGroup), Limit: awssdk.Int32(topWaitEvents)},
+				},
+				{
+					Metric:  awssdk.String(dbLoadMetric),
+					GroupBy: &pitypes.DimensionGroup{Group: awssdk.String(sqlGroup), Limit: awssdk.Int32(topSQL)},
+				},
+			},
+		})
+		if err != nil {
+			m.logger.Warnf("GetResourceMetrics of instance %s failed in region %s: %s", instance.identifier, regionName, err)
+			continue
+		}
+
+		// The grouped metrics are returned by decreasing load, so their
+		// position is their rank.
+		waitEventRank, sqlRank := 0, 0
+		for _, metric := range output.MetricList {
+			load, ok := averageDataPoints(metric.DataPoints)
+			if !ok || metric.Key == nil {
+				continue
+			}
+
+			event := aws.InitEvent(regionName, m.AccountName, m.AccountID, endTime)
+			_, _ = event.RootFields.Put("aws.rds.db_instance.identifier", instance.identifier)
+			_, _ = event.RootFields.Put("aws.rds.db_instance.resource_id", instance.resourceID)
+			_, _ = event.RootFields.Put("aws.rds.performance_insights.db_load.avg", load)
+
+			dimensions := metric.Key.Dimensions
+			switch {
+			case dimensions[waitEventGroup+".name"] != "":
+				waitEventRank++
+				_, _ = event.RootFields.Put("aws.rds.performance_insights.group", "wait_event")
+				_, _ = event.RootFields.Put("aws.rds.performance_insights.rank", waitEventRank)
+				_, _ = event.RootFields.Put("aws.rds.performance_insights.wait_event.name", dimensions[waitEventGroup+".name"])
+				_, _ = event.RootFields.Put("aws.rds.performance_insights.wait_event.type", dimensions[waitEventGroup+".type"])
+			case dimensions[sqlGroup+".id"] != "":
+				sqlRank++
+				_, _ = event.RootFields.Put("aws.rds.performance_insights.group", "sql")
+				_, _ = event.RootFields.Put("aws.rds.performance_insights.rank", sqlRank)
+				_, _ = event.RootFields.Put("aws.rds.performance_insights.sql.id", dimensions[sqlGroup+".id"])
+				_, _ = event.RootFields.Put("aws.rds.performance_insights.sql.statement", dimensions[sqlGroup+".statement"])
+				if dbID := dimensions[sqlGroup+".db_id"]; dbID != "" {
+					_, _ = event.RootFields.Put("aws.rds.performance_insights.sql.db_id", dbID)
+				}
+			default:
+				_, _ = event.RootFields.Put("aws.rds.performance_insights.group", "instance")
+			}
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// averageDataPoints returns the average of the values of the data points, and
+// false when none of them has a value.
+func averageDataPoints(dataPoints []pitypes.DataPoint) (float64, bool) {
+	var sum float64
+	count := 0
+	for _, dataPoint := range dataPoints {
+		if dataPoint.Value == nil {
+			continue
+		}
+		sum += *dataPoint.Value
+		count++
+	}
+	if count == 0 {
+		return 0, false
+	}
+	return sum / float64(count), true
+}
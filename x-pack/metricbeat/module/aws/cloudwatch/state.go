@@ -0,0 +1,162 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cloudwatch
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/hashstructure"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws"
+)
+
+// stateReleaseTimeout is how long the state of a closed metricset is kept, so a
+// metricset recreated from the same module config can continue with it.
+const stateReleaseTimeout = 10 * time.Minute
+
+// collectionState is the state of a cloudwatch metricset across fetches. When
+// only the metrics configs of a module change, for example when Elastic Agent
+// reconfigures it, the metricset is recreated and continues with the state of
+// the previous one instead of starting over.
+type collectionState struct {
+	cloudwatchConfigs    []Config
+	lastEndTimes         map[collectionWindow]time.Time
+	previousValues       map[string]previousValue
+	seenResources        map[string]seenResource
+	accountAliasResolver *aws.AccountAliasResolver
+
+	claimed  bool
+	released time.Time
+}
+
+func newCollectionState() *collectionState {
+	return &collectionState{
+		lastEndTimes:   map[collectionWindow]time.Time{},
+		previousValues: map[string]previousValue{},
+		seenResources:  map[string]seenResource{},
+	}
+}
+
+// collectionStateStore keeps the states of the cloudwatch metricsets, keyed by
+// the hash of their module config without the metrics configs.
+type collectionStateStore struct {
+	mutex  sync.Mutex
+	states map[uint64]*collectionState
+}
+
+var collectionStates = &collectionStateStore{states: map[uint64]*collectionState{}}
+
+// claim returns the state kept for key and true, or a new state and false when
+// there is none or it is used by another metricset with the same module config.
+// States released for longer than stateReleaseTimeout are dropped.
+func (s *collectionStateStore) claim(key uint64, now time.Time) (*collectionState, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for k, state := range s.states {
+		if !state.claimed && now.Sub(state.released) > stateReleaseTimeout {
+			delete(s.states, k)
+		}
+	}
+
+	if state, ok := s.states[key]; ok && !state.claimed {
+		state.claimed = true
+		return state, true
+	}
+
+	state := newCollectionState()
+	state.claimed = true
+	if _, ok := s.states[key]; !ok {
+		s.states[key] = state
+	}
+	return state, false
+}
+
+// release makes the state available to the next metricset with the same module config.
+func (s *collectionStateStore) release(state *collectionState, now time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	state.claimed = false
+	state.released = now
+}
+
+// collectionStateKey returns the hash of the module config without the metrics configs.
+func collectionStateKey(base mb.BaseMetricSet) (uint64, error) {
+	rawConfig := map[string]interface{}{}
+	if err := base.Module().UnpackConfig(&rawConfig); err != nil {
+		return 0, fmt.Errorf("error unpacking module config: %w", err)
+	}
+	delete(rawConfig, "metrics")
+	return hashstructure.Hash(rawConfig, nil)
+}
+
+// profileStateKey returns the key of the collection state of a credential
+// profile, metricsets without credential profiles use the module state key.
+func profileStateKey(stateKey uint64, profileName string) (uint64, error) {
+	if profileName == "" {
+		return stateKey, nil
+	}
+	return hashstructure.Hash([]interface{}{stateKey, profileName}, nil)
+}
+
+// diffConfigs returns the metrics configs added to and removed from oldConfigs.
+func diffConfigs(oldConfigs []Config, newConfigs []Config) ([]Config, []Config) {
+	contains := func(configs []Config, config Config) bool {
+		for _, c := range configs {
+			if reflect.DeepEqual(c, config) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var added, removed []Config
+	for _, config := range newConfigs {
+		if !contains(oldConfigs, config) {
+			added = append(added, config)
+		}
+	}
+	for _, config := range oldConfigs {
+		if !contains(newConfigs, config) {
+			removed = append(removed, config)
+		}
+	}
+	return added, removed
+}
+
+// reload updates the state with new metrics configs, and forgets the resources
+// of namespaces that are not collected anymore, so no tombstone event is
+// reported for them.
+func (s *collectionState) reload(cloudwatchConfigs []Config) {
+	for key, resource := range s.seenResources {
+		if !configsCoverNamespace(cloudwatchConfigs, resource.namespace) {
+			delete(s.seenResources, key)
+		}
+	}
+	s.cloudwatchConfigs = cloudwatchConfigs
+}
+
+// configsCoverNamespace checks if a namespace is collected by one of the metrics
+// configs, by name or by namespace pattern.
+func configsCoverNamespace(cloudwatchConfigs []Config, namespace string) bool {
+	for _, config := range cloudwatchConfigs {
+		if config.Namespace == namespace || config.Namespace == namespaceWildcard {
+			return true
+		}
+		if !isNamespacePattern(config.Namespace) {
+			continue
+		}
+		pattern, err := compileNamespacePattern(config.Namespace)
+		if err == nil && pattern.MatchString(namespace) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package aws
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+
+	"github.com/elastic/beats/v7/metricbeat/helper/cache"
+)
+
+// discoveryCache holds the resources listed by all the aws metricsets, so
+// metricsets listing the same resources of an account and region in the same
+// period share them instead of listing them again. Each DiscoveryService caches
+// the resources with its own TTL.
+var discoveryCache = cache.NewTTLCache(0)
+
+type discoveryKey struct {
+	accountID  string
+	regionName string
+	operation  string
+	input      string
+}
+
+// DiscoveryService lists and caches the resources discovered by the aws
+// metricsets, like the metrics of ListMetrics or the instances of
+// DescribeInstances. A nil DiscoveryService lists the resources without
+// caching them.
+type DiscoveryService struct {
+	accountID string
+	ttl       time.Duration
+	cache     *cache.TTLCache
+}
+
+// NewDiscoveryService returns a DiscoveryService for the resources of an
+// account, caching them for ttl. The resources are not cached when the account
+// is unknown or ttl is not positive.
+func NewDiscoveryService(accountID string, ttl time.Duration) *DiscoveryService {
+	if accountID == "" || ttl <= 0 {
+		return nil
+	}
+	return &DiscoveryService{
+		accountID: accountID,
+		ttl:       ttl,
+		cache:     discoveryCache,
+	}
+}
+
+// Get returns the resources listed by an operation in a region, for the given
+// input of the operation. The resources are listed with list when they are not
+// cached or expired, failed listings are not cached. The returned resources
+// are shared with the other metricsets and must not be modified.
+func (s *DiscoveryService) Get(ctx context.Context, operation string, regionName string, input string, list func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	if s == nil {
+		return list(ctx)
+	}
+
+	key := discoveryKey{accountID: s.accountID, regionName: regionName, operation: operation, input: input}
+	return s.cache.GetOrFetchWithTTL(ctx, key, s.ttl, list)
+}
+
+// GetWithTTL is like Get, but caches the resources for ttl when it is longer
+// than the TTL of the service, for the resources that change much less often
+// than they are collected.
+func (s *DiscoveryService) GetWithTTL(ctx context.Context, operation string, regionName string, input string, ttl time.Duration, list func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	if s == nil {
+		return list(ctx)
+	}
+	if ttl < s.ttl {
+		ttl = s.ttl
+	}
+
+	key := discoveryKey{accountID: s.accountID, regionName: regionName, operation: operation, input: input}
+	return s.cache.GetOrFetchWithTTL(ctx, key, ttl, list)
+}
+
+// ListMetrics returns the metrics of a namespace in a region, as listed by
+// GetListMetricsOutput.
+func (s *DiscoveryService) ListMetrics(ctx context.Context, svc cloudwatch.ListMetricsAPIClient, namespace string, regionName string, period time.Duration) ([]types.Metric, error) {
+	// The metrics listed depend on the period, only the recently active
+	// metrics are listed for periods up to three hours
+	input := namespace
+	if period <= time.Hour*3 {
+		input += "|recently_active"
+	}
+	metrics, err := s.Get(ctx, "cloudwatch:ListMetrics", regionName, input, func(ctx context.Context) (interface{}, error) {
+		return GetListMetricsOutput(ctx, namespace, regionName, period, svc)
+	})
+	listMetricsOutput, _ := metrics.([]types.Metric)
+	return listMetricsOutput, err
+}
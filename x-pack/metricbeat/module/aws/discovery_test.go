@@ -0,0 +1,152 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+//go:build !integration
+// +build !integration
+
+package aws
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/beats/v7/metricbeat/helper/cache"
+)
+
+func TestDiscoveryServiceCache(t *testing.T) {
+	sharedCache := cache.NewTTLCache(0)
+	newServiceWithTTL := func(accountID string, ttl time.Duration) *DiscoveryService {
+		s := NewDiscoveryService(accountID, ttl)
+		s.cache = sharedCache
+		return s
+	}
+	newService := func(accountID string) *DiscoveryService {
+		return newServiceWithTTL(accountID, 5*time.Minute)
+	}
+
+	calls := 0
+	list := func(context.Context) (interface{}, error) {
+		calls++
+		return []string{"i-1"}, nil
+	}
+
+	// A second metricset of the same account shares the listed resources
+	_, err := newService("123456789012").Get(context.Background(), "ec2:DescribeInstances", "us-east-1", "", list)
+	assert.NoError(t, err)
+	resources, err := newService("123456789012").Get(context.Background(), "ec2:DescribeInstances", "us-east-1", "", list)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, []string{"i-1"}, resources)
+
+	_, err = newService("123456789012").Get(context.Background(), "ec2:DescribeInstances", "eu-west-1", "", list)
+	assert.NoError(t, err)
+	_, err = newService("123456789012").Get(context.Background(), "rds:DescribeDBInstances", "us-east-1", "", list)
+	assert.NoError(t, err)
+	_, err = newService("210987654321").Get(context.Background(), "ec2:DescribeInstances", "us-east-1", "", list)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, calls)
+
+	// Resources are listed again once they expired with the TTL of the service
+	_, err = newServiceWithTTL("123456789012", time.Nanosecond).Get(context.Background(), "ec2:DescribeInstances", "eu-central-1", "", list)
+	assert.NoError(t, err)
+	time.Sleep(time.Millisecond)
+	_, err = newServiceWithTTL("123456789012", time.Nanosecond).Get(context.Background(), "ec2:DescribeInstances", "eu-central-1", "", list)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, calls)
+
+	// Failed listings are not cached
+	failures := 0
+	fail := func(context.Context) (interface{}, error) {
+		failures++
+		return nil, errors.New("throttled")
+	}
+	_, err = newService("123456789012").Get(context.Background(), "cloudwatch:ListMetrics", "us-east-1", "AWS/EC2", fail)
+	assert.Error(t, err)
+	_, err = newService("123456789012").Get(context.Background(), "cloudwatch:ListMetrics", "us-east-1", "AWS/EC2", fail)
+	assert.Error(t, err)
+	assert.Equal(t, 2, failures)
+
+	// A nil DiscoveryService doesn't cache
+	assert.Nil(t, NewDiscoveryService("", 5*time.Minute))
+	assert.Nil(t, NewDiscoveryService("123456789012", -1))
+	var nilService *DiscoveryService
+	_, err = nilService.Get(context.Background(), "ec2:DescribeInstances", "us-east-1", "", list)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, calls)
+}
+
+func TestDiscoveryServiceGetWithTTL(t *testing.T) {
+	s := NewDiscoveryService("123456789012", time.Nanosecond)
+	s.cache = cache.NewTTLCache(0)
+
+	calls := 0
+	list := func(context.Context) (interface{}, error) {
+		calls++
+		return 10, nil
+	}
+
+	// Resources cached with a longer TTL outlive the TTL of the service
+	_, err := s.GetWithTTL(context.Background(), "lambda:GetFunctionConcurrency", "us-east-1", "f", time.Hour, list)
+	assert.NoError(t, err)
+	time.Sleep(time.Millisecond)
+	_, err = s.GetWithTTL(context.Background(), "lambda:GetFunctionConcurrency", "us-east-1", "f", time.Hour, list)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls)
+
+	// but never expire before it
+	s.ttl = time.Hour
+	_, err = s.GetWithTTL(context.Background(), "lambda:GetFunctionConcurrency", "us-east-1", "g", time.Nanosecond, list)
+	assert.NoError(t, err)
+	time.Sleep(time.Millisecond)
+	_, err = s.GetWithTTL(context.Background(), "lambda:GetFunctionConcurrency", "us-east-1", "g", time.Nanosecond, list)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestDiscoveryServiceConcurrentListing(t *testing.T) {
+	s := NewDiscoveryService("123456789012", 5*time.Minute)
+	s.cache = cache.NewTTLCache(0)
+
+	var calls int
+	var callsMutex sync.Mutex
+	started := make(chan struct{})
+	release := make(chan struct{})
+	list := func(context.Context) (interface{}, error) {
+		callsMutex.Lock()
+		calls++
+		callsMutex.Unlock()
+		close(started)
+		<-release
+		return []string{"i-1"}, nil
+	}
+
+	// Metricsets fetching at the same time wait for the listing in progress
+	var wg sync.WaitGroup
+	results := make([]interface{}, 3)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0], _ = s.Get(context.Background(), "ec2:DescribeInstances", "us-east-1", "", list)
+	}()
+	<-started
+	for i := 1; i < len(results); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = s.Get(context.Background(), "ec2:DescribeInstances", "us-east-1", "", list)
+		}(i)
+	}
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, 1, calls)
+	for _, result := range results {
+		assert.Equal(t, []string{"i-1"}, result)
+	}
+}
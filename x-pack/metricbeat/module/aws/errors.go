@@ -0,0 +1,68 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package aws
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+)
+
+// accessDeniedCodes are the error codes of the AWS APIs returned for missing
+// permissions or invalid credentials.
+var accessDeniedCodes = map[string]bool{
+	"AccessDenied":                true,
+	"AccessDeniedException":       true,
+	"AuthFailure":                 true,
+	"ExpiredToken":                true,
+	"ExpiredTokenException":       true,
+	"InvalidClientTokenId":        true,
+	"UnauthorizedOperation":       true,
+	"UnrecognizedClientException": true,
+}
+
+// ClassifyError wraps the errors of the AWS APIs in an mb.Error with their
+// type, AWS error code and request ID, so their events can be filtered by
+// cause. Other errors are returned unchanged.
+func ClassifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var structuredErr *mb.Error
+	if errors.As(err, &structuredErr) {
+		return err
+	}
+
+	classifiedErr := &mb.Error{Err: err}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		classifiedErr.Code = apiErr.ErrorCode()
+	}
+	var responseErr *awshttp.ResponseError
+	if errors.As(err, &responseErr) {
+		classifiedErr.ID = responseErr.ServiceRequestID()
+	}
+
+	var netErr net.Error
+	switch {
+	case isThrottle.IsErrorThrottle(err) == awssdk.TrueTernary:
+		classifiedErr.Type = mb.ErrorTypeThrottled
+	case accessDeniedCodes[classifiedErr.Code]:
+		classifiedErr.Type = mb.ErrorTypeAccessDenied
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		classifiedErr.Type = mb.ErrorTypeTimeout
+	}
+
+	if classifiedErr.Type == "" && classifiedErr.Code == "" {
+		return err
+	}
+	return classifiedErr
+}
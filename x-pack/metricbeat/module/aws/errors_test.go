@@ -0,0 +1,82 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+//go:build !integration
+// +build !integration
+
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+)
+
+func newResponseError(code string, requestID string) error {
+	return &awshttp.ResponseError{
+		ResponseError: &smithyhttp.ResponseError{
+			Response: &smithyhttp.Response{Response: &http.Response{StatusCode: http.StatusBadRequest}},
+			Err:      &smithy.GenericAPIError{Code: code, Message: "request failed"},
+		},
+		RequestID: requestID,
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	cases := map[string]struct {
+		err          error
+		expectedType mb.ErrorType
+		expectedCode string
+		expectedID   string
+	}{
+		"throttled": {
+			err:          newResponseError("ThrottlingException", "1234"),
+			expectedType: mb.ErrorTypeThrottled,
+			expectedCode: "ThrottlingException",
+			expectedID:   "1234",
+		},
+		"access denied": {
+			err:          fmt.Errorf("createEvents failed: %w", newResponseError("AccessDenied", "5678")),
+			expectedType: mb.ErrorTypeAccessDenied,
+			expectedCode: "AccessDenied",
+			expectedID:   "5678",
+		},
+		"timeout": {
+			err:          fmt.Errorf("operation error CloudWatch: GetMetricData: %w", context.DeadlineExceeded),
+			expectedType: mb.ErrorTypeTimeout,
+		},
+		"other API error": {
+			err:          newResponseError("InvalidParameterValue", "9012"),
+			expectedCode: "InvalidParameterValue",
+			expectedID:   "9012",
+		},
+	}
+
+	for title, c := range cases {
+		t.Run(title, func(t *testing.T) {
+			err := ClassifyError(c.err)
+			var structuredErr *mb.Error
+			require.True(t, errors.As(err, &structuredErr))
+			assert.Equal(t, c.expectedType, structuredErr.Type)
+			assert.Equal(t, c.expectedCode, structuredErr.Code)
+			assert.Equal(t, c.expectedID, structuredErr.ID)
+			assert.Equal(t, c.err.Error(), err.Error())
+		})
+	}
+
+	// Other errors are not modified
+	err := errors.New("checkStatistics failed")
+	assert.Equal(t, err, ClassifyError(err))
+	assert.NoError(t, ClassifyError(nil))
+}
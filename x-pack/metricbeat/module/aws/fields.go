@@ -19,5 +19,5 @@ func init() {
 // AssetAws returns asset data.
 // This is the base64 encoded zlib format compressed contents of module/aws.
 func AssetAws() string {
-	return "eJzsfVtzI7fx77s/BSov3k1pFXvXTp3yw6nSzbFOtFpZ1MZ+Y8CZJokIA8wCGGnpyoc/hQYwg7nxOkPJ//rvS2KRBH7daDS6G43ud+QRVj8R+qy/IcQww+En8pez3yZ/+YaQFHSiWG6YFD+R//sNIYT8mz7rf5NMpgUHkkjOITGanP02IZkUzEjFxIJkYBRLNJkrmeFnF1wW6TM1yfL0G0IUcKAafiIL+g0hcwY81T/h6O+IoBkENPafWeX2i0oWuf9LB6j6IPFAhi706V/LP4fx5Ow/kJjoz+4PU/fpI6yepUq7P55mNM+ZWPjv/uWvf4m+14nN/XugCzsweaK8AJJTpjx/6LMmCrQsVAL6tEWB/nA6K5JHMKf2v1uUtLGuwXBLMyByTiiZfCB+1NaEKctAaCbFK2HcRxSmGFYL8rd/PfUid/rX079+uyPqVBYzDmOA1sQsqSEKTKEEpG69q71Azu6uyZcC1KpNEmfiEdIpTRJZCNOiKN4QpEP+46FYWvtzv+RsoMn+u74khYaUGElYCsKw+cpDJR7qaSeGhuweiMLJsSKUM6q3BxTAzBjnTCw2MnUNin/7Mf5NEikMZcIuNRDQhmXUQEqSJVUL0GQuFVnJQqEa9IgIEw2NGP6VmnEGhm65vFdhzgs3ZSebuazR26LuI/3KsiLrIcBjX7O+F4VSIJLVvmt81Zo38SOSQrCeSSegnlgCtwfIlh8CB0RS7SpmfczohnGWSWXYH5BeSG06gTQFq29J41Fp1tj49SFbSquTvBIaSaQ2fWOGKS2neyfsZuamGVtDhrnOOYj0NbLMAzsaw2rz9bLrVqqMcsvXz5ou4KwL1wszroJICovxGMzrmbOfj5/F7LUKXgntaKLXmLGfaZa1vxZUGGa6NfzLMQ1X/YvHdhSm1WfsZZo2VJlpSs3+Z5MdgdgR8GRS1qSEJ+tf2fPYLpnunBlEetC8VyLdY1YUgWkKcyaYHWcwOXmEpsxtoqZF0cMSiDbomnqDPFegQRhNKDplllJKdA4JmzNIO3FGTuUq7xLMoSBZE8SOZT21NpA6v2ermpNG+l0estlZIzv4Pi2C2la6VbEEvuZcKlAOL5mtKidYtwzzpDSKD7LNq2Ea5nkWe2XPoIDoRNE8eGZlpOI39M6elyxZVgN0xDfselmSUjafg7L/YenQOU3qtmI94BH+rTPqy3GGc5qsxJXDRrL+vAThvNCI/4TmrCM0sBI0k+nsoNUJgxxpbewPL3HKy/NDXS0/9mCqbVIkCWg9L/g9fClAmxtqrM9zSp+a3hrZ8WBsrz/xMkCfQNkjjLu5rJbRJQ6iHBBtHerANutqn2X0DymqP02MApo1WeGBFF6vxWJmWAYkB8Vkero7QzL6dTSGBHfvNTLkk+BMwLVI4esdqASEoQu4U3KhQOtRxSQvp7MMSWSWc7C/cfqCEgHPZMHljHKiIZEipWpFmAVKmCYzsATTNHWhGUoMnXHop/NOySemmRSQ/qaYgQua04SZ1WfBzLh0iiKbgbI05hUG8mxBkMSjQCtPeysBKcHoUw/9W1F5DzR9aSIV0HRwGi+k0EV2bAKDUqsI7SIu8diIfALVvx1POqfRkqxkQRIqiFE0eSRL+UyyIlna2TDEF/PWLJUsFsu8MHY7FBrWbPJ+luki62VZR0hvB4bpIvuTcunI+qEtWZ264c/HtNFl68/Ep3vIOUuopeyYNhhwmutA+QzMM9izVZAiTzHuzAxkhOY5UDQgmECOlTaHRpvD6uzOmaQA61dawpxGPyFUpM7Cbo9MhTRLUOUv/GRe/284vzv4dwyT7X8M/x4UFZomlu4LKeacJWY0ATzzwqfAuvqeS+84PEFk7aYFWMPNVLgot5sXoemS14kU7qKmK65GquGkY4amGeB0ejdWjKSrpKH8tbLhzF239ZmMhnH2B+63oyiqujewyYgsEB2k1v+29HZeDa8ntn5gvRpqO8+0ncmdrLSB7EopqcY8h3d0XZ1iW4AA1Y4eu39UkF8eHu7Ij999R7ShprAHegoHOLgXUqTM7auLJSSPP1PGrag75CMyp7Ln5jglocZAljtu5aDmUmV2Xwd0bunXbNg7ECkTi+gkvEApOAYJeBq5Q88vI1WAiA0IS1D7KOscdVYY9/MlfQIipCErMGRmVVw02IGWAk0flkoaw+HqCcRoi3zfJf1IHHxNAO1D6NdknUMO5CIH8scW8505EFnMnGXMdEezpCC0zFgjb7S1v6musUQ4Frzt5wHq99cpB3UdP6Yg+GPvI/1qd4VeazIfpiqCwbw+PoJcsd7VDFxW1Wxl17L3PHOjM+2khaQSNCoNmud85dTOuxQyNJotl7RlUzeT1mnWik0PdpQba6K9YoZVEuFI7XZlGzFTOY85TX6Wqs08U7E6obmOc5V6zE6aBiPAA95CXJGeQq9R4V3r8Zs7HY+5IJ222OteEQd51CV51Qvx8rrkI/0aeRkov31+1ZgBjMP8qSVbLKGVv+T+tcZqyP4GOd+Fcb0+2stwrimG3UyLf7Jmj+7JtTIHZxbbTrtfksNMH/F+/Op8cli6wtAX4/+SvMhwY56vrDY73OkPQS/N/kDBAZos3f6QufV3mRSxF+uj0Ggi5saavE8ISVs3kSbLcK15y4yS72bUKjgmtKEigRPyvLTrY6KIQiO9J/y5Iwi+yWF2rMGtNypv3Db4UzLHys2nfAjOWIVjMErYsANLvmgM/bYg2i8alq05saN1HA9rYxEPBPtrAQXcgFiY5UB4G1y1h3tT7sog1jNlBiVQWpPCJySgZB1A0kPp8VbpFQPRVj+orv/2KV6HHJQ/Usib6093k7ckBc6eQIGDXq6l/bB2ys2df+1jeFfnE7/5Tslnu8+emVnGeQZugMnkstyjUvDVJrbEN9KjiKjP016z8Jq8EVV2t5Hk/Y9//2fDMHpbXSeul4JheHNeKG3OKbd6bABuVJj+gTFXTu4KlUsNCOnNIn//9oRUAko+5YZlyI1fLi/JG22+f+supC4kD39Lvn9bJ8bRm4Ld+nPLT9xUdCYx0tclpYmC1Bqdb6ykWRAEn8WUMGqfa/M9QsCJFWSUieiibWYZ1npo2C1yeBmDwUG7YOtCQfurQ7fjtJUTZ/xQzlv63DkuA6kXC8CFuo5MVWs3DUnWdcqPQdBajC4PTUi/fqpNsTOSi1nGjInv/ksbPXl/mI2evD+mjX7x/jAbPcmLU+T0ad5KDHfE64RySKdzLmnzC1vkFtc1CeVcJngHf3XxHuWuMBCHBqgC/8bPcOtUkUJDuB8NxmL3eztLiFNCU3z000nLpgePPfnRpQxe3H0uNV25sWJseBDbbxWR47sJ78wdHqMgBopvjGPgjtGiwryk2vqsqoCUaGb/wgx5pppwWgg03FGnU2WayTIxMbpQOS/09AhE+anqFOHlFF5KVSpPkEJg5CjyNZyKsD+7uPt8gSP409u/wmea/AFKbkupnrp3oN3vqQ8mFWnpJNjuFSENySlLSSqfhSW5vd7OGnBqxSwLq0CTAq1FmpbXmI6EnlfaYJ6lejxl4jSn9tDe7zFxN6VNLe9nIAoSYE9W9ASeXB4EYcKAmtMEdGvrMRFKT1hjpssp7KdomoOaakhG0IBt2iIzH3W5tbq2JnM9RbIwR1yk3dHvsUgRSf9TVomJ09nKbP8o35noP5GuH+2xfDjM0XYYznaUlXN0Reu2O4mbRfHlF+5ou+4FV26oHZcy/cjkqfUGjrdyuGphk1Fv5lsqyvXQRiqo4qNPlHG8WTBy33VrETrSup1XZEXLtTeFa4lB3+1Fli1OazrKukWkjrpwgbBo7fakcbMYNmsUrV24rRanClQ0wzPH3mJI29qV2p3Gi17qhthpu8R2OoVzzOVsx6WOu/HGXc4WdYfvvn1W06XmniZLSB6nLr11IFLvIZfKaOtZYwpoDemSapJTjcke0izrH4Z0YYvJP6MAojERuv6Zjx1zqg3JmCjM9kRO3XhHpnUMQsI8L0BK94ptS0x5aCRSrdMk1rxbQPPdze4hOql8jbLNJ1b5KcvoAk6HLItngV1fhps7HL8sS+dCa7vgqyLBp3YNBqwBcS1SlmCSeJCEFIxLf4/Cz0wTEFYX9SjUEmiu2BM1cJoKPR22wh8GlN3o5PJ24uqzefa2PIQtUbJmFoqXxOafd4B2fff0A6FpqkBrQrWWCcOYN97q7YW1mHGWjMVQHLzFzy2l0kMbkIuBcR7HlVUuLCHXd+UnbyyD35KZLNwBug9LcQudJjLt5ubeigjHbfLwxGXCf//3dzNmSCE0WwiMSOMkWyEdft07kZI3uXuwQv5LVCGE+396WRjDxOIdRpn/SwyojAmU6f9aiwULAoX/C+nbDRSZpTVwnaNjVfVYR4GfB82tcCx0XPjxwyrXAD9m0Zqrm+56NS+WlHdOk0cQ6YUUwlndAz1gqy9lUg4fs1VIExVl4SsC2tAZZ3ppjU3/ChMNFElT4m+kVGlnKlgwbTC7JsjmmhzhXx4e7i5kClNP8fT9778PTCW+onv/++9Egc6l0ODe0YXHd5i0eiDoD+OA/jAq6B/GAf3DqKB/HAf0j6OAvro5H5PLCWdWh4FVDQha11G39uiWkEfksQb1BGoQyP6t2TAPP5sJkj4PsoqlINxKW2a07yUumkpPlK95kZwzzuUTqOGgt/Nmwzu8UquXT+9nkNBCu6xgXSgssAnugt6q+zUyApSb5eoXGZh+6LuXOtOXbvhqg8W7Do18rDqypXRMLGVxEu0QYHvZ/AYFnFu0AtTbprS8ebiIPy3zDIJVqGQR0m1piw/9NH4WIy9JIYZdlOHKvVSrgflpvjbJCWEiZLSdOLMQs3vtV9oGCxqApnq779jfoepJIQzjrYCNMi7ooKG0fPwBsgSaglpzQpQl2M9uzs8Sw56gsvTcQg7Doqqqes3o87lgxIplLKcUoTjGucNFB0+wbeuV7K1/ZL9P1QLMluSH9Oebi89DpT13UV0H2Xjz9ebm4vPb+OXcWV4WFiA39pfnG2U7pukWno+3ngKeWwsZW+zHW807Ja3TAIM9JOoj2V9sh+m2X7SyHnb11UMd1fpQR/RZI3JfnfvardPGsHRegTa7wLEfbia3sJCG0dJdH8M0fbiZ1IjECuCx9eydApS4lKXozZfqgFCiQWssLRrCpnWCfREmihOhmb7eaZj+zL5COr33R990DJrndop35elKWxGLKlqxAew9pExBYkaBqfzggwD8rPj0hmXMTK+wcgakR8ScyIKn4ltTf/wVOw6f72/CNVW5LpiEbkXLmT/WoeB27yg7qCD/559bup8ffv99FFqjkIoj2mJ1PihSLRVbYPy1Rxls7/CPB7/H7R8S/49j4u+JAQyK/7vvRsT/3XcjAn8/JvD3IwL/MCbwDyMC/2FM4D8MCfz67unvDQN7DHuqw7RuGwn4WtwCWg93xAidHb4Kv5QZybtFEDvctDFY+uIO2msTmx+QoPXyc+/DlWMs0KYLsM5QaZ2UJVZ7cvUXmNEdhXqioV82hl0tyk78LzhcPVFeuOS6ocEVfLO4LNgTuPJ3LjynrNr0BSs8MVSQpSzWbPERokt7xZR2iZKOHNT16iJ6GSqFZilGPH249wVDzuvQleHodkDHJ6ocGsyphjliIOfWTfpKgzg/c/k8ZAhzTQBnzuWzJm/qlydv2+fjpvOuAXz6cHE3Pnh7wo9GwM3kCATcTEYj4PPlEVbg8+VwK/BnPDeOEIdsct/KzJKKVC/pY3BxfIlnfzkuKixV04AQwrBmiIs0hsvRtcZ6pYrGMtN7xGette4PLB8N26oQd0wLbu7R3I7+PT00Ta/EyTghTCS8wGv1h4u7v13fbb6NrUMfbUE64Meiv65NA67Hn2JnxxT5/e2kaQ11F3dTp7um96BhyOB8O2FDgyFv7icPb+vP7d0DsPLyRG4J++rm/EUw75szZTE7YXpxVjv2OlY7tv+vRzSkR/TIBGh2WGVUP8axfCFXZu+fbtJOX+gF+4f+A8w9JFKlejpUesMujdDCw3fsEA1RONCzy/dXOiEZUF2ojU2/+gU6IvTaWC0j1dkCPjLOmU+tGpf0Rfl8Ah/AKcSCLzM5j8CRhHLuEzHpwsqWIXQ4bth/ZwvMirS/Cl2HE6hlwAfXA4dyBYFAtLB7chrYsRZU9CTeuC7VQLOt1uYYrdNc4hx99E/bIwLKZ7fDCpz/36P0cVGelI49pZdUpcNS5rvtHoWyqKNu15L5l9JD6YtrkciMicX4WrFVsiV+pJIXJuyiuhLYRJirhO+OC+88YB0UOwNKxF3heYg7vPyvmKObuRMk+zj8CbI9Joe8csNnx0Nwqvz6Ec7XllPWy5pChxT/iriqYPK+e6ai9QXUeAchA6iBiqSg6o7XMTtSeK5zmHMrBjeNKol+QRtwJ1nVQwrrcYyOQHef1A5rfETEbSO3hx3RTR+5U0P6ov3UEOv0GN9ODvcryjmkkJ4gS8aUb18iemxzrB018AeXtaqxGkunKMfUp9TQUVgwKbXKMc3SSJcFZrwwH0KbyJcwzX0ihs9Kxrd1gnKMRBYKXpw1UXfBl+eOcWBCi+Rjs+UeaBp3LSqr2IcE5hEVa8WcVoDAhCUqK9dsZ/T20jkpZhbTDB7kxPqJ03tqYHQaIwNcE3Bl1l20geJNj3aocJTQXhW3iY6zmOy5whXQdGWHwaZK+Eai9msfUsbGyb7ngyJSEYZ93+NOjtE1LPI6qsBFOZfPJdNZJII7cHbsE7liathTcdGmJpw6l56p7q2SsD2J2I1zC2NyqO3RKFI7eMSjmz4XPDyHJROpNSH1+mSSw4gdIlTXWnqwdOwTsetmyMscF8dd9ONt3kgjwhOoVVhjv2pMu2pMeNUdb9lTco2fSmG3b6xTUVV+26ch+zmB7Ude/hDcwkLY7TDsjgDFw+0c/gks4zSbpfSgayo3xBEz9m5wwteVrXctnvz7q+ETlqwoaJeLNC9E9XAKd95XSArj3t6HxIvIh3Efu/eYIo3/ExdGgS649/TKoTc8O/SlkIYmklUM3B/bJdD0BowBNRjKn6UiVK9EslRSSOw0EYCeNKwwt05OOms99rFEQakQMTiWAk3fcYTqC4DMCm8xriNPGyZw7kvXQ231s3fFXjOlJeitaCy8nTqMgFXd13wZCmq6dpLOQaRldpDdRIGINdkc3rUZcy80SslQbCfpPao1tx7VxfFAcuHW0/fBzyjWxiv3aagG7w4z7YSl/yLZ/mkDay/KtNKrUmMNzuVSAqrqKlVpmEoQwHXs78X6WSjAN5XpSKh/9m0Qf5uQe1h07EaHsAI/A4u7lukWaPXfSqX41jcXCuCrVN5kTbpNZFx1Ujts7s1OK0SkqHWP3JueZOsGz9tQhKtHnkDhs3/7H5xRv0dcayY538RWkrInllbpZs3Gkj1kV53JdmVAbM0M+/RoG1tmwJUsi329PEVWglOq6ivkQkic9y4h075hXMejG2oW1MAzXR1kvlfD9JjwqNvRWH9GY916MoomjwRb0lkW3J49ED+GNcWpK/fvTotXl0mG0Z5r8bOSWWRPDSwUjUCP37cxn8owQGQf9Ut3BHqCbH0ZvCFJnQkn8P+6u9iA+VNhHuTYfC4b6/jerS3wPlq0PasR9oic9sXP1qLdidnVQ90zZ46P+163MvoxA7CHkm3gXlVx27GfGMdvLnZGjA7lnVTmjIdKK6McJE1hwGIwWEYonOaEBkM8l2qNER1a18piZGHwVplRVGjsvRhHOUMADwtzh4YfKfd/WXOeu6T1SyXzMdCHnPhUYXXvDo23EdrYZ0ira+TBp0gN+CjabWvMOyk3j3vks6TVAHKI0ySGPirHBz9RuovIjVHHNXpE6rVFs17HRm0dQKv0sCcXKj3acwvrzl9ODotiY9f50JV4n2bXobWcT+j7Zs3KrW2F7e7+e1r7O5jvXHP8WuP9shVUd3OFg/p4D0Wa93F27X/9v/26j92vO6WGzqiGaaQ5RiEnTNSoBFn30mvIZmWXq1OqRCeovVqe+Ddb997XJrc0gzdn97dvUQSAJkurETeDSjjV3bzaC9ZFrEBF1H8nNMenIiUZZFKtqvf3iCF88fJ8U0/GCD1LQRg2Z63GKkOQQO2yqne6yHPOIK0Wv5rV389WfwjPlgrBvhRgATh5L79hh92JRNegbDjyJv6eWdeSM6LuOT4/zVLag47pxyneXE1TyM2yE9vBPXRlYTBuZk/Q60+avFFA07+53oPhauQteaasLOCOV5/+0Zh+7MYeWuh94VNXDXFKFyDM9D9yNo7G8E+3J7/ekIkrv3hmJyR2wriPwcaec3MFYM/Lqds9R23cXMWbqw6OiopUZoHrHlQv8qk2UtHFEdvf9sD2OIjOe5tq+ZJi00JDOkXX1tVonbJ0SBkJlcuiGcj1pVMX9kicAeDBkp66itPuccWd1GahYPLrTTd4ya1zMlVQ1qieai7NlNPFaTYbED6niwWmHLA/SiXvZy0/QytaarzKN6AyVPK/nd24BNjgKe5EH7arZTLvXok9tU77xYfVIO5+0xqtnb1O+/AhC5DfOzRfDZKe+jvwQ4TdJWFhWjC59ysSHTl2dax0LVnoT+osiPhUilfk42ry680J+UgVo5fnJy7FqFyl2jQ99oZ+prmzil9o+1sAbse7+j5StEyNZl4bht1KrWFtqkqFd1MZawouF3rqa0W0V/OQbYeCGZFiHYBIgdiJd9pPrmHwkTaUO7133FFfClBse6HZC52fo7q12wQqBZpymTyOC6ucJSRPlCboJnyu4zIeYS+15/xBWyuodVYoqWraCDvH4GTrCDldr/aHpyO6tWGchx7mDckta9cU2oDyUE/sESCxRQ015Md3zqYru1OtJ3PDbhyTTrc3cZs2yCxDiIeTiaYglwnlL2wQBumsq3gDWS4VVSti7N9c5qRVqZuklMsFE9PwOGpUneAdCpyxuovbpA9MmQ99msgsY90xtcG0vZtjFy0fAUyBQ08/6OGOI5yj1Pu7oEv5uNAuL2+iGsI7AMtGBsaEBmX0CSnylBrQzhR0nNwJqRvoGGD3WWBfinZQeKXeCX2dq/nITJpl440IdnS1Vp1/GmFkeYEzW7ngXmnNe8vAn6xorNvz1WvrSnHtwYKpRzUkK5gvy0He3LvB31Y8UXQ+Z0mHdR6nuCO7kkIbmYGqDKLwY8u6EBu9nJR/RivEqvjoXsZ+NfKTt+ZKWJkh2SILs5DIlgc/+p+HL9Y0GmMzNxO4y4oEbSNlI0YNHHoukgZTOW6OfVSOU6jjonNz7IMOLcNxwTkNFb3wwyXehJH7ohg7WjRDxlo8BNxCLaMHlW8W129bS8YulsVYNGBgLoU5djeTgnAqFoVdqzeXlzdvS7tkV8p2ME3Gomyt9bIjPTsaMOOSFLb0jjTspLUHoGAopR7w76jRx1qDutLfcQ121Ptj0VA/GnakYbfT4RUK0o7u5miat+aRbrkIeBXrI+sMw84vFE+JwtIySYqcuaDfjAmqVhhCCeZrRq1f0r5hcBE2tfYiISK3ecE17OVWR5Q9mpDYCcmccdgt1h7Bb14WjA7/oEuC6Mf61CXqjRrjKktGRPOGF8xiYSWJiuDxVlkZwSPeaNrG1My4TB6h+yQcipwaGc1IfvV2zyHZfPUQJYeks6l39KdjpMLsmdwSIsW+pEFCOXc6zjug1S2A/+ZmQpVsvZY8gK7Lc2IH1ISzRyC/3V8/XN0Tqcj91dnl1f3JkMBBLJiAqf1gOPxXNFnWrnRVITzv3XwnjrLm1W10bYs1AUzSTQBFOqf+SJlGd9pD7pPmhbWq7qqDBKlCCL/jPe+xe7I7MBKZ5dSwGePMrNbcaq9dK0/qgssZ5dN0Vh4skE7RtJkyuduZuoH061h5/QOnJZdeGTSf93bel1YAqzcAuWKZPWirl8LdtzaupoLTLvXvb8kdq7ZcAGwO6sh8qQRGQSrtKebc1QBHxRxxZkaDIQeRHlscmE0zFOXhlfdWpHO6cE9HSzhiEVzadfKwpUHpqfaDn45Ip08ZOYy+2i3yPtRNM/p1OArjtK46SXFBrCZ4p4utSm9fjwdzoRHR349UJgYmlYnXQOqMJo/4LHmaLKlYwNRXYTpNFLjtqvq87EOzO8upiZu6LACFU4fKXnP2BD63U6M5gbkQm06mXrK0kWpYizUxRb1hUh9ZtWSO7Ql4ZiKVz6dunkH9nM6Kc77DTUWFm99dq1X0Nj/flgreF/s7VJrCM1Bq1sG0VrjOKOehv/86kudYg8I1dHUlxcJEPal6Li/CJw7R5LHIpwqMte+lmPqiZEMe+w8dRS3cvGWORnmDidJnJNFFnkvlmJRLJsw7Jt6hEakANweZAzWFArQW6xekldB+q8NEJYFrBaHGGi1orpfSvBgvfH1Q3I2U80BewOX0DO1wWTCxnqWA3ZN3YkBCkyVMl8xM0RQ9nRV29w1Ie/3ZVbv+kS9X4988uekdqu0Au7piUw1Dbt/dQN8jBA1mHW7vMxY57tMdsoh397pKZVN7jYWp5973wkN47fmrUj01cuotjtz5mPoLn+6ZC71jCHURAdzBdLy/nMT+cEm/kURiJVohUyjDNRsPuiIPGW1TlzE4dc8XX0o/2O3vnqSuZOHiSy6RMT4Rtoxn+JX1OaUc5mYk4hRklKHDHz3YwDBmqJrZTEIsS6i28/NKvf1hmlLGV2F9vmli3eWVcHOwxpNh/KxcjDEfEE8+HPp+OHkEc6rZHy+Vgom+eymvzqh18QmPrRO3s5amcj6Vs/9AYobfW9ETNDdDBza3izgvlxqfMPZInz8TDpU7P0wkcaE//2uWs3AgutfcIy7WLw8Pd9Xx62rTSLSAXOx28sGv3QlRsKAq5eAfna7ynnO4xL4Y1GJoYP7H1UMDtxWuIHtMdNGwAW9ejIj37vPgeNdcwQ4C+fLq5urhamjUy74MikEw/3J1drmVPG+SBanHFIZPk6Y07IVyTTbHoTgrJJOrm6uLB/IJFx3feVtFN7BUOEqmOqFCHPnxTTOfLhyyHou7O9maHYdQr8AU6rWQH8Acg37Oxtxtde/SzuVrKyB0pHi99ZTKZ8ElTV9mZdyyVBhws213ZLvWXO6Nsc6lwPt+Xw2fkplMe96eF/lLkxsQuDXzZhfedjrjzWI/2V1zgqty/sPXZlGmAcXth69fQ2N2nI64WhSuxuk26+Z2HK2q3QJD1/o7IhX5fi1hP45J2I9fv7q4jDoiYSHfbM6wctPKwA63MYdnneWg3gWZw9BPGRFJZJZj7lkpklhFOi7k1sUCI6tOLuWmxDI9mFM0g1LxrucHGvLBuzkqS4DTXLuMmx7W4FrhRq7Y4S/WsWAHfqJDwft1e7f0B8VhZcq0OGaZssltd5myFyzie+c6ykzYH0NUuLdiECpYZKA1XUDUtKa/RN7k48S32bmnZiggytfgibp4TD5OAi6SusYPrPkINcZ1i4ru0/yjp+WuJGXY2oNtXtkd4N54+z1wOyFG5izZAu2tNJhuhQkuvr/FeJBrzcPSMJvbLd0UuEsn12sJy86LFO+ddiXNNXQciy5UABF2/1LYyEDkrmgZN5Yzn4qhS7DWIaPOKjsmzVb1dm9zREFyyVmylej30fDuWjxRztIzYxSbFQaGLgd/AFVxq8BynG8JLaFiBJ85Asg7V+PtK7Xn9kntt+UvyP+bfLp1NeQTqRQkxqUyZtSs7QqwkYu30uuWPw0fXbcLISN27kj/PaSKPYF4kJf8y6jUIlS8fsukNzY6OgbtpXYepCdjfCqwcrX41lqSe9Ix+Tj5KIVZPshLamCSgzCfJ5eDgE6WVC1c3wbH7nrtSdebjSpTVi70yegJ5SBSik9lzdI//nH16aJTuusK4MuBJt+Xo5p8vx5YmdZXIPP8mNLFTlfYAzyvyXMlv7IM66VXrYgcLCKkeOfCzWlpWPk73g6RrIxYv7gpcLoaLvmqZxPFgKpMAj83pjG1y1MpoCiLLMsgZdQA7wmJlLQIaaZPTLO2dTqMq13XCe4AI3POFsuemEaJ7CiomuwzisET5ZXzt6U8WFEaF2mQ152QBX91XGhlbHW2Cm2fpU90wem9rUDc65cNkHW7WPPQa56m4TBaw0PIcrMKxS/GKQvaYM/Z3XXZNZsakjK3wx13CQ0E9CSmgajU7dEv9Fve83Y8dh8N+yxm8uvE68zauLV3X2yQzkn1ofbunuSH+dN1UDpKC6IGc/ptxdC2Z7x2PaXi3RpT2W/jSF02dgU2PLtq3Sj2Q1V2fDnnNHlcSj5Wx4yy9UvlLa5IZjepNa/ILExPlGzVY14D+1be4/ePCDqcFAie0E2AcauMjNff8g2OdiyhWIN3C5EoLxr1oamFOMLeR4mWGaD/9mrPjgvK+Rj9nKrG6daKqpcctLaNy9zDwC5NEgTQizG0UxgDZ73Je7lM5RvXJkifERu+5jzAOROV0k9ZBkK7Ft9ay4Sh8YBXk5XwtEX1KRcHCepTLvYW03/d3b5+K+ehEAL4xAx3sxO1VwBicPhTfA9pP2CJZYs+Id8RJlJ82qvJ5affbtHT/z764+c796vzf9z5n8SfXk0ezs5vrie/XF3iL78jTFcF3ijnPrEdwawJgTryL6mhG8yX7elvWHhxUycrEZ4jWyDaZLfsCqnVOyuG8/8DAAD//0xoafA="
+	return "eJzsvVtz40ayLvp+fgViRewYe4It3+esMw8rgqLYbS7rZpKyPesFBgGQxAgEYFyklmP/+J2ZdUHhDpAFit5x/ODulsiqL7OqsjKz8vLBeHbf/mlYr8n/Yxipl/ruP43/mP66+g/4p+MmduxFqRcG/zT+C35gGL/DB383DqGT+a5hh77v2mliwOfhZ4GXhrEX7IyDm8aenRjbODzQ72Z+mDmvVmrvr2CU2PVdK4F5dhb8a+u5vpP8k0b/YATWwRVo8L/0LcIPxmEW8Z/UgCoOog6UWrvk6u/yx2K8cPNvwK38mP3AZL8FhryGsVP/a/NgRREQyT/7H3//D+VztdjYf2trhwMbL5afuUZkeTHnD9AKHEnCLLbd5KpCQfLd1Sazn930Cv9doaSKtQXDPYxghFvDMlbfGXzUyoSOd3CDBL59IYy7o82kwqpA/tvfr/iWu/r71d//NhC1E2Yb3x0DdGKkeyuF1U2zOHAdtt75WTCmjwvjj8yN36okWbYdZkF6ZfmelZy26lMcApc93bt0GvnY9G9xVDeuH8LJTcMJQ7mY3hnbMKbPqJ+3Y9dxg9Sz/MJ3Sp9EGgwvoNke4p0VeH9aaf3aWXFQIU897h3EzcJDFAaAKKdweS/+Ko4V/cN9gU8ZXmJYmzBT9n1VeKjwIisGoQgTF37bvA4dcPG/RzGkgnhi+N6zS7IAWAl/fLCDq1pAiRu/eLarD86KDUjDJ5Flu1VYyR8Ey7cOG8eqhxW7O61MWtJ45YWcGO4hSt9oZ+78cGP5daJTxcV3pOmV5z8B2+JG4BL7PXwN8N5ToTaxif8Wp9eHaA1frfKK1m6bBTZtNuSZZdzSGsqfdqAch2s5QvwXnjD17Frb1I1LxxdmrUqOUBEsp4gQkNWWY6VWZVG3qtyoEWYTw3IcEOuvXrqHD9h+5rimiso88KH7SpsMVKiRmK7igmODM5UIrt8LBKmkepwISqghp8ASv6woeDm2yq2P/3XoK6WPtF7/PSgFpS8pb6t+16LvBc+uY/IvdW7vtk2leT9lCWz5NIRhUQ/YvnGo7cs1xgYCcUaaTW9Acu9E3s5K3Vfr7RSx8Xs+zO9gCQWp5QVJQaci5e/VjeECtWMrEgqgNIh+JSXwde/B/+UANWZUggJo80ZfRJXxE5vVWM5X64nx43r9aFiBY/zqblYh6vT4IRBObgDf3gv5xIGpks6LaTj8bgLnqHBlSRttA9/pudE43tp1LjO2aSx1vBktX5IdKp8Qo6L+WfPLwqqtgfA0TEG4BNlhA1cLEI9kxy6o3gnojXAg6R5yYy90rhrRfP/bb/M4DmMtgHIotu/B8n5IYPcaLo6fMAsNFxdxNgP6YRxAqGC68TGAvv/8+TzMCdimb+eOdjANjOkD5hZObGC/XVkvdXM2mKGNkCyAAcfVSD1mZR083/cSF0SIg0ZZ+uq6AYgV+J8qLWLXdr0XF+wfsfW5/4FzmeQAfcsTJiv7bAIGVoJniBmA9OFuUg/WZw2kwijeITtcJqmLIHV3Md3gl7HAvvWm0szJ2FhwKQDBRaIVDnGqiUXKFwYR/r7LPSbhitag7WarqGT5cPUKUS23cuW+VfjU6F5HTRcoanvrhDW27VETpopVCxNOVIUHtL9f59erh9lP83UzEmVIHYCUH/RiBOylKPQCZkvoACAGlKzJr+WJMb/5NEcefVo83E9vkUOPy8Uv0/W8G6AObE/LhXof4gKpCmmDNwv1Tm3HaoydXtGMi1M6buSHbwe3zsV01Nz5oc6H7o0FTAgfrEauiJtuYIHgbYa1CUPQ8uuORgHWr3sXZo/l+ELRn6DOjP/Yhw65l8ReTEjk4i9hEVOXftdoplgx7msCynxUvi+MFRiXHI80StKTC2ls2Wiyayb+tw9LuGr44OhGVjFLWH1VZdsCy0w3RIsNC3pLlqAP7VSQVpaGJtuFuiBmEdifsJT8hq6VFLQjcO4DaBg2bIc3fhSYmd+wBcq+y2O9DWvV8RhZYDnL41jc/UUu8u1aj4n97hRExCh+0k7HQ+fpJAbRsW4D0nALsG/WuWSi5C2wT/TH0BjndMZE0QpmND7BgPufbxv9Lnw9Kq7gS3CyjOTWkN4V4vuWbZCNa1tZ4tZb9md3dHRBrNr7zRCXfCzNEPFx2HPZdXrIUuYsNqI4tN0EvZ6wD6VyDEcN/klmTei/4PfhxkZnH5dleAW4wd4K7PyoNhO0DsESSmYwXXZwHc1kpTQ4HjMaXZAhFgR0AvYR+EkE5NDrrvoglBiB6zrsOuDMyK2/Zpr4MR1jLwkJwP1CtKNC287iGFCCIZsvy4SZouWVUNWg9/AjTcrm9kTa24zztivtntzQxn3Jfo0+FPydlybSsH4PJ9F56YDzEbh2usps3IO6/Y1s1G3mK3eozWYkOYAnOnYt/wO5S5JsI4dqOdgc8ozk7xhHoR5rfv3GLj6vHX8bCJ6TdD4zAaddEiu2Qht3rP2ibABV35H7yH+jOA00DtqkzCMcPC/Z38DlcQdfBBExPmD+zH5g8zXCjxi0YejH2+udFPANg/cUg3Xsrq9Zk9EOwGlUDTkRUzhcL+4sP2aabzblJSkM6DYGWmrPdxfGVUG6ngFlizj/K3ikFZPovJ7pXhMXgw6PnlcJNew3bQYfDdCpQdFBurzCjru1Mj8tDV91n5MF8Nk6RD79wPxp/i80EqZ30/95uDdnD5/uF+sH82k1X5qPDw+3q2ZCsri8844CLrVm4dbu41T/HFtvZ3futSJ6tbbmq7sxLds39e8sdDT8Ov0Il+HGmM5uDStJQtuz0pKDoRkeHX3TD3emD8Lc1wGPhoSbZbdDftGwhZ12/3A/nxjz5fJhSTvs9rbZWYdG0VWVbYP8UaWYX2b+8vC9sqFFN5hFimgUxshGclQLJLU40Y42eUykPqiqdT4MrfLNesAhfKocGDkYqjiecrCBKOX3avx9KKlUl9cR7j4a4lzevoP1J9A/pTkNYN4zQa319snfXqzP71E4kK7fUvcorR7k4sFKgQgcYJh2SV9hC8TZmdhWEPA9w9IhxG/svRWj1om/we+Jj7boyUXSytGTvYjr4ZDjeR18s9OTBCeCyGvzq6WWP//s2hkOvwbDfTRlshA8ofI7DcNnVN7jDB1TxPEJj18WUezww8ydGJEPRMHPYJsLyCxckOcMcOcbfQtIaaF7HsBV4b4X4Zyk+E2lvRnsz/jRn5EF74bz1ZKBiewHtCLwYy9FbjPXT23mQ4WQR76I77vZcCsp5Cg7Z+uHry1OE7bVHuXn35kM7m7OKYFlAOU7UfIW2M9d2vEgiwNyWOCOC5TjpSZ9qfTSr7RJ+iS1Ki99+YgDND4aSNz/Qgryf8pwgNXTbDaf38xvJsbH6eJ2foPK32x6P5vD388bL9QE8eaGImNu7ho0Unl5a1uCMYxcibKZqeOsvJwYtPv76TVf4pvFiv7+noFYPVhixy6aTabVrBE49UyrAkCe0KsBut6LWh+Kbj5VW+QVSgcTBFCiiSdc3vARWVYEf1grHYYerCItxuQ6jQl3drjdmqCFmXXiKYerS1sULzq1WiNXWSrUGICW1LA2rgMU2zVBvm+9XVZrIuXUDHQikBbopng/V1lthLAwMfpEUxlZzHyk5a/wxWomIszSKEvBoLfb4Q/YO6vvDDEcPk/Gbs39VqEI7b0E7CV1m8v9Y9nPBUk53L5jQ5TsOxRGXpLyKBM0567pY8a/ww2POovDlL0vSf1okkf48k+LtBfuX/mK/1gxDWseyI+x3BgR5gv6507KoivfAGxggwYWP0MejJh2Wr5i89d4ZX68Dvg/61dC8UHOr1f48eXNqh41jqftGtZtCW6Ufcelfd9MIv7xc4LJPTRweHxf/HK2nE/XcIfTHd8MOHIDtAzfBzCfvBkdV6zfBx2fvGWxQ9zr77LccuoW3zC95J0fGpu35aL+HHnxewDjE4OMB0lF1+Abig6fUiTjluAia0O+oPMjJi8nn73lCGPRCavZ+T8aPD6x/9ZnOyben+5Vk5aoV8XEqYqXqbzHJNCWG1Vebqa83C72qqpc1HSL59czj2ZlSlCLnI1CcwMLjd5ujehq1ATQQcPENXwrScU28wC875CWzf1IQoeHLy4fH3gJGnEeAhefgCifyzHaiMJBk9Ss6KtFqvqahWw0wWYVP7lHWzQjdWlq1Gl0SxUYe4Q+zcYoKdQA1zvQ2RW+dtTQ3gBUoVQQHOxiDS/x3zFK8VzMOWNT1h6cmo1UoO6Om4j1BHDsLRUJZhRnYde72npow/PKvDYfkZXMqJ2UOzPvTzAE1HI98nn60MSMehjTA9wWIP+cWZiUBc3xYss6tMqtfm5ZCQ22KQZm1o+pFic51f4tzVgZUsx17YMieoks48DOxrDCfI3suscL2Ue+PmHk2LQO1zszLodoZIjxHMxrmLOZj0/B5lI3noR2tq1XmrGZacjanzMrSL1U32OKHqbRqv/BsZ2FacUZG5lGBo5Zo+r0v5twBOYbZw+Uaey5L/johfcxLllSOzMs6UnzzgPniFlpC5iOiy90jaEyx+wTQHzqmrGHl5i9F7JQA5EIY1EZUVbNLYlc2wM4Ti1O/S9sDZCkTQFKbBVIkd+bt8st14UiFj0APpj+McOLxpEs21q/j9BRYFsahTPzPetYLyJoLggimckGZ0voJRJ8m1WuRe5R2KCcjGGRbvkkFxw8wxfrdoi3W7BX9s3odIhIBFdQ38XcJcTNKPwQ7E5zA4xqto37M4pGM2g0geQ/v/5fYDe6jseqOXpBCoaA5Q8GmkWRRqA02jhAG6+jHGfP1VWupRIIVoFShNw71lvb02HtHTUYjLyraqFsvTghIOLXgfs5rTsB0jOQOTtXn+gZI1iBQTxv+Aebs/jcNHvA6jFPq+mnOT07Lcyn9eJ28T/T9eLhvgWed3BNXUIGtNcdLymAgQMgVj1fAYz+IDctPZPdPdyvf7z9V4vs8Q5eeqVNSjMoWEDxUHWfVOfVxRpV7PaGYNlpZvn6aGfjCaMM1Cvm+1KlBF+prkc+jiyqqDQ5rMS2sFrL1g9rI1KESxtmst1a6k6GP6HaWZjfJGtm9eW8ojjo4z7DrVwRgGrjnrQOCs4zr8XRxJywKkGYgj1g86qyuh8SCqP3Fe9FSJZvxTpzFVsg8VeEdA9CdR/6DuX1fLapcgCliU9vp8u78tu3fIRBdzcoqD2K77Z53fNhzliXhL74ESety09wPDTjNiyaW5aElbp4/uVyWtElpC5oLbRRLgv74rmvlAvEC4PwYoH0QqbyVJSpUqrysOAj/MUmBD7Lalf4l5UcsfmUULrCTfgagAByNBXcKJPHYugcOQmSxUhmjyaf5lhdbz69mRD0h0dUjHqDf4pGh05nRSDO+HwoI+m9Cs7DDk417XN1tTKKMn8E7Y/Ienxa9yCJ5Wlg+vISxYOeeHN+e4gSXLCDyjsOl4GddR5hRQUo/pawDYWiKsO6KY6Lwuz7z59RkcVKt410wGcun4rWKr4XDr+V+zN8LP/RS0eFT0XfMF21jgJFmlNiviPezlO8L0joY6kTGuOKpKsXY8EhxyGfKBxCeVGR9tJZouaBTqHeQjVMGrAqJ5HETXV0FPpqqv4CZlH4ldwJVDvpxaM0p2q938BNMbp1wt3InJecbfJ+ZGLmaF7JiHjlFtZ2O2ovQamAHD+bnuVYLkXxPXwlN76YLu+/HAbHCQ+gJJm6XBlsuIJHQ8VRtNWdb+g/a2M77vY/r3Lt7ypo05GZTNHjoSfpVAv0RlRRBMCL4DEOdzEWdWlxeWlNsq/onkqePRwYy8bSTJi3UBLFXFi1xLbBmXNN27cSLSyk4QwabtjG26dppDOjQ0R10LUj8joKOhBmPGRMgNnh4ZAFaAm5ZRWoNTj1UG/ODnefs6EGSg5s4NES7DdgfstP3ThA6pUDmxhfzO6nd/NkoAhhMl4LrgKacKtcIR27q2CIUtzV6YYoDXMmQ9TxtluXnBtEO3YWq0+n2PW1JeU4tfflcY2z9mrjs/w5lbQGlv+SMw4L7hxRdLbuIu+AtZRhgbQqSRpGuCC82pLC7UmehE6Qf0/DwwY+Hrgm8yUlv6OYTaqXT7cqQd10PDc+9RRUycP/FnL8cj7JBASf44oifLLvI3+CbT60O6D61LuqHus6xtTrvawhyBWUvZWg/wlUPfhNgv/D66puCfhfWlzpVpKaOESzttwjBLUe/S2GoZLyrBToLRBCp543c23SV7Mg3DBVWNcmFz0yWSAv3+oHPGiwm4OQo63s8ByIrHO+dfFLR291DmCcfX6Tf6ScjdxBeYtPm9F7kpelHu29WoosoWINL66YD32muV1cJqIDv2S2FYP5BRf/gMCsDuA5aLxJvcBOFXB8U4sGMFLYV/aVAsxkvzLF2/WxG6ve+al3ncok59LTErEeZLqg66u/LGXfJBvqrPDZ+rTYdoICE/TNDSwXnavzIFRnZL8Q3GTKHXK4jq/yRoUP7/apGWcVr8fRW38GihipjmTS0fiJgRPw3Q3bQTkCPFocf0/7GQ/07yqs5PehW1yHld2wAorB3Uhmi2mxA+t2xwrtyazhcZCuxPCyFaGYnOVRs02RZxdJWrBkBth3QBTPZugmxzVptBOdag10IJZtCTJ5GBXI4uWS7a/O51cwSUGNNtURRjit0yiKw8+U+qA8GrC5T0GvfPUqtoLnEaAvYdiarVEEOmHuS3JbpsY3/QDDnq62Rs0h18Zb4n89Yi5LH+uMu+zJi18KBwXx13BmIkIyfWuTlx1sFwYqW8Y7P+ouzCUAzde5wrVbMb+N4x2ouyPYv/fS9mWP5UJvIouATYsRfDwmE0sBT+jiYCoTfcC0dtj9iH/zd/atRPUZ0Afk0DTcfPYtG67hrrTjMEnMlqbiJ9gbcmmwRSzOw5uXV41pFYWpXCjH6oPKEOPcRNN8gqKtUbqDJMEJu36sl7b8evbhcRCzPu/ME6UsTBF10T4lVb7GTxbu0Bd1mpOMjXHeopIzNinzfEdh6NdWlZS/vdiqkitvFzxFvFT8cWUlWwJ9lG4I/IkAC9nzuqTd7/kM3Hofh2nqawfXjEjZNCmfvEXfQJSL4FwsLNWL7sfERTAqExswDWMjNa9ZulsM/DoDM3OQIfydZgXjWxxr6mTTD+tYnO0BcBiDP7oOL+17BvYyT3T6ZmzlrD32ag5xLK624RrGzhkYWfqanTe0OSe3ELUiwjfCXcgqqMkazW2xUJyC0SHWMO04xLPwAKr4wQNzfBa7tE6Wnyy95Hmsk8WqplnOCyuMja7TGPeG4/JKD3YOCculCEzNNExZ9YK19ew+vLjxu4K3AlkJIgU8WMvOiBFQI/oHXu7u2g/t0XDn+2WD0xQ786RKjZCCRpxrUpZzwOqseGeHcTMtY7KeFZKqcP5gxUiOlRCbW9KS7sP32BjycZDc/dWdIL0/wGoTWa1NydQd4SQ3Q0sA+wg5UT2m1d9JoseksoCIiR8+zVWHs+cFSYp6nnTE9cB02Fo9C3YOia3J/NT7sLVs9MSUlM52qTExHj5+hP/do+HMYqSnty3LyA+PKQ6PeQgdLfupRmqETnWHcbzTp5vFGiHP7z8+LGetxXopZEUHRB78EoHS6X0WyPYhZcM8LQbsS42le2koiqTyOs9jRY6ZrHLrVUUM9fbBFcWPFUW8GGz5bbi+pUb++XqgWvp+VPp91Ow08dioBj8y85Aaz1tJgjPN9lbQ2KgZbo3ajjSDsOIoxQY0LWD98HUC/3I85vXae7t91Y/keDEoPbw12EnepMJIZ0z/uaF5RaPEQhdDeqP0YsoSpNftrUVlWav+pvxbF+twyvvGrSiH69AitPpeWIVq5zkPJsY3eQCQwhoP81CIq1/LSHOP5djATgPlEuM3SV1O992FYSsEtbRHfReCeEDqEQRdR8mchRvrbd2w8VB7JwLDLGWFCIr5bHgiqNB2hQfDcOvtVXsG3ItgZIZ7gYKbvxFpQz0au/Wjfhxpb0cWdakcdZ88jrVPSuD1c/0Wo0Bu8eZff9aEfe9aPq/OsMdaHBsqmiNlI+o6chFgP223nl1dB0otdq5bYjnqaFiekQaxFoKE0nIMIYCS8/T5Bctpn3fTGVfueGP1MJBQHRfrYPYBOQNzRy9OpWPrG6jjNqhDDnDUwvJBoEbZzxIvD1VldynLUEO1jY5BWL59m4n5helsC6GyXc51WtEmBxFxEXfUCSRclug/jZCLuQc6yFCCevi5uVjvYp+jPYZ7sc+84zSIUi0M1TdgvVieT0nK8EO0JpqBbcD6ePWcdK8DnBysC+A3nzZRW4KGxm47Jfu8pvNO0SCDU3DAwOOu+v6BG2vLelV3Uwkvn4q5LiifGoOg8bVCuPb6bD/rNTHZFa7Fyy0VgpO46Ic7z7Z8M7/OTwWnJr8qeJIsovBsrOFJ3Qat+M24/vQIFrUrIwMTitt3HJTKxtY6eP7bxHhDd00Q4jHKguegcpIEKVyImlKIXqyQHHBrjbG7B0w/RqW6yvQTTEB+gX06Yfl+NnU2iK0gKVeVV6GNI81rwB0p1F/82uS7YWp3vmd+uZ3eH7GAm11k4gnTXxpLnN3kJFQtLSjGgMSDfvGDCTZXFf6/Gqd4aGeY3u1sTvOIy2HOG2J5w+e9uTZsP4M7KmaucPh2io8F9R5w9snLdX8/Pj2lnu/9yXqsa1bbC2VmYKpCQ0nBt5bwq9ilmjR37iGMddWBEeB4aT3MTpESCASkgxl+FDYGvMZpyZPR0ZD7BlZ2A0uZuwt0W0CBmlMpX2LCyA2EB6CbnRJlFidhPCJCNv5AdEvXcvRW/KmuNGu0amGoIj6EOvjuF8O8+WslrTbv3dyM9dfYS933APuKEw9Fe3O94NxfuhHoAtattdPsGs9R+9aOUKlNsCfCc0WzUwZSFuFTe5K7IUBfOaD+KjYKZcCJr/TYPtcZVopQamV54SjFskpBV3jJMOccl2obwsFrYTV7TkIf7hJW8i7B6qd69pBcBWypioBVecuvoj7y7CEi1QZuOGr+rtndKToJe0mS9W94mWOC3ezGun2wHg2qBIb2gJdXVyHOXphx5FWKY3Cc5wwX65xyrKpVcrfX2hpt9ap099QWRZeEIyRXI3m37b6scgMnCj09lZbEWGLyivjtCwqvTzc2dWJjQ1YgyhUlyYt1O42N5ZPmXQxPYfl4KY1E10eLpOO9lWMXq4PB13m5lSvHejs9cDGXLjicUlDRytLwYGGydxJYUbIP0YlDYVpojLQ5lyjk0LT+bMR2RD01YaNgKRppzNAdjpPhuZmyc+NhXQvjf8Kg7e7gVw/sCDt+i9q6mZ4Alaq+8fGboUhitFvqOZvazkkZx8VfEN1qVhzWqiLDz3jou+VZZT1zcdBJ28WavFwk1LgU3uDP8FSHAh/knNF1NOXN9aW5A1YyY4rXyNZn5DRUxVJMH5/N1ZIYJ9iGW0J4ZcSPVilskkOd0AYgGW85pZZnI/uqS+tsZMjp9lMzQ4RVdYkMeQh8uKEWgeN+fpSGkSwDOuY2KdphvL8yf/LC/K7AfTV2fgg6gfIc4iFQvC42LhW+cHixbwss61Y98DF/kyJrf2ZFlg3X3xOc63HpLHQ1lu9izPK3OQpqdJLwBm6p8J5bDfT3ohL9L+9NJPlidNM4A6UQNO4zE1h1i9URZ3NseTRs7XGc1E6TUM16qt+axqDHGvvwFXQ2m56pqZq9ylvMhsx2+yijWFx0DBzDslON7maGJaxw0F+QS2eWD9WdVSsb/npMG31v/ZX4tBTO0lBjHfzuTeX6YI8KykERfcWKpFggmvy1jgEMPGACkWuRAsE1dqlzJKRzoMyunQm4IF26JNFZkxDWR7IyshWEZPjlTmCajMv/jvu7hn/nUNn+r+HfGoMFLBb6GgZbGCAdbQNO+eaL3X+zVHGk5QOL2ZXarpOxHgs5LouKyhG0RPIafsL7YdVOJYcLlfAYnK6tdEENK0aSVRS6fKFs4LUEmlRGra/I3YKqaA10KZEZocvfQnghgiHEFi+si6G29k4bTO7qLYHFpwD3Me/hgaYrE2w7N8A3mTouGihaqbPBD19/XWiqc7yBC0dctGKZYRT+R8vzcatrahnWxyTa0pSGlcKaRIxbgBrbB+K5lo1iaOlbDuyjG2CTGuUm1JO80IcEuo3EI698KkXEKeYLhjVXWe2oG9CV6Ot7OApUKP3N5cXSlcFO1BQsR1SnmbNyguNwaFm3+4k41lGQP8Q0SLLaITWZyIL8sbf5YA4oGjM1iq33ZoUBa8TECtF/kaD+bSUFlgSMBV92xHRc5j4oyvgxN4Li3as11M4iO9rNNObtc3j7rdzpV2+flRyBfTx/Cg/Osh3qiBelq3h9U7Y7QhtUuvHp56rPnfUZJWN7Eu1pVAujqd1HRrSjhb3J41nyWlJVOtjoYGCTxAAVzGUdNsA28t/Y1fPBcQ9kOCEnEjwq9Qel7XbN2bTGUVgC5uUyLJcKA7YLBkfmnDY+Yu5qmXlpzmrAwZ/OGM4G08NyZG89BriHyBJdqoatx69MQzrngtQKr8teEQZ51CW56IV4f1kCHFIsTdq/Tbb1mE6s02xqrIQDG6h29MpYpb3fsc+HMK7RTn8fzpW3YT3T1K+0nNETuCbTj6wqocfHC4yRS9WhnowVMMj9qWq44HS2XvwyR34/Pd5M14v7Ty2BZBjvHOy0FWbj4xXKsXGIj8uHXxarxcP9/IbqZE3/ZT7Ol+Zy/vPTfLVuhhgGJpNVjfiOiMhiWxJkaaLIQrndMbSH4uuLh0L8viVLDofV146z0IiTs3G1nt7fTJfEQ/F3c3H/kfh4vzans9l8tWqJLkvdg6k9sAx9sCWg4u3AyltDwOUD+g2c+8T7bOzB7m0LyAN5c0UB30NxosfJSv9pNH25eqJgKr3glV1zhReHKbaOSZfQyZzvuuRQohY9Eu3SScVLMntMwLUqzlDE7Cox5VViCm1F99ZuUota5H47xou+xZpv6EpsYaVAY4eTZqwbsB5y05U4W847rsTxBeRQ99Y7icKhMC9A6LVv4b+eSDyBHpl+sVGhDg8zhu+fMcJ4fl1QXYY3StYdWsxS3tCspbQ3Dc+mImxAHDXXAv4U0znVd0Aex0NO9ijFR4MXgpTgQ5tFadvkHr330jj8gImyeW73RKkIYslohSh2E+6ezX9cE0bU9eTIWEOG66i8KWWP/pWYg/vmIdKVs1wuEVfcNAnrF1GGKHJye63jeFhLi3gi2J8zN3Nv3WCX7jXhLXEVzcHyvstbA1geZQPD1tq4IqRb9Os4lqS1fDPMA9RHSQVefPWgrgNqbuxuMb5YPDyuvoTv+x5seNeRKi6tJf6ycOFs2Qslj4IAyc0P35WBycGsmITi5mIDrFY38oyGgd9iSzO2qDG9o2zRPPu4aeET44sA9SjmAYNF//aHf/xUuqy/zF/62neBHt5cg/WZXrM0Qg3cyDF9oqgV33jM4gjroyCkL3bRt19OjHyDGg/wvQNx48cb+H2SfvMlC+mbhb74mf3Nl0ViGL0O5egxvRQPlbUJKVaibpdirxhUhL7AnYYgqBxQDqPwewBBEGji2MXi+0qo4gYZBv/HgnydJxH3BYVX4IK1vZ4eLw55iQHcJ0z5wV6zZXnO3P6axAsCYMECZ6aqcpp0krVw/HMQ1IqRvW1jMxhav7hKMVOSs80BQ3+cGh3d/vY0Hd3+9pw6+uzb03R0O8quiNNXUaULLyM+sS3fdcytH1a6XnQ8bVTvO9iDWGUQSMfOq7jvMlgd5WENHRk86tRH68qgLkptBWBUQpgQMrMEpqulpcYe7EFDvgexAJGQdPJgFRJQUfxRmSLl2agLL89BHwWxa8V4p6nAGaODHDNmbVu2HWOtisSjNFrYqPBD38oCUtxJpltxJVVaJSaBa8rPEvMMRPGpihRReB+F9eUiD/ZPQO+uiq0h+gkkyJQZjcBvb94j2UuMP9047Esp/Lm3sEPxOKQSLbUE41nBl+TI8hwqe4ckV9ebaQNMrKT7DAUoBgFZLARIhH8TCfUkB276GsbPV15wxaro1lv0x1FalvJ8Bl6jGrdeQDcXB6F0C6kcPS8QzXlRmWmrtlKlCIs2oHN4BAlYpU1R80mWo9bVm8x2imCoMy7ScPRHLJJC0v8tqwT7rs4L3LhEba7jI5aPhjnbCaPZzrJyjC5l3YaT2L0V33/hznbq3nHldJ04LHnnhfQEcr6Vo1UTh8ziaj5SIdcDK9y4uX9UVo/kleiPWLcKoSOt23VOlrJcR1PYSgzZbu+ybGpiyFnWTSF11IUThClrdySN3duw7t32aC2EFid3VJTdM+c+YkRb60oNp3HWSJ2OkzbEt1O7Ocdczqpf6rwHb9zlrFB3+uk7ZjVZqMYVNQYyWYKgJlKXLusi8KpE9RW8C5GVUKh0yGtaK+SyhEtqVsQS0eGHlEpa/B33HftWkmJDwyztT6TJxjszrWMQ0loFblxS6lesLzHy0rBhd7dIElTvdpUyqMNddDALS2XrvrHkb70DvvId31G3HljeK4DGZykDcJiZa20IvtwTfFXXZeEEnIvAwexeN98JDlaRxARixf1MBRNRFjUIVAmU9424coKkrg/MiQzloxs396tCtFzFQuiJ0itHofCdWP7xAGiLx5fvZcMUOEKh7ZHPW5b0H4yVGnCMxVDW3aPMz567kkPTyEXBOI5jjsIF8C0e5W++QAZ/afDugeFRLKUjdGVXQ+1PFESFviV5eAvlEn/zjw8bD9OjEm8XkEeaJumFVP+61yI1vohYyr/xv404CwL2t2SfpRhl8YG8zP9b6WGEv0zDKOKfw7+6zpcdFKV7VHCZoYOieqyrgM9D6pa4FuqhgWQO0iv+wAZ/HkyU0yZ/vKp51W95zOyAOK2tx8EaRTQ8eE3y2F7u9FDeCxn0Y6iqZjMdT9UdT547O1XJqxWde7FwTtC9LaaGn5G08VfsTKSh8XXuVaPuF+9B2virppW0PBDixGBl+6zByrMV2Y/454x9B6T+Aq/afVrpq1TsX4rfmT4uLq2S8iiNlWoaKhWjL2r6fdDzvxt39DkuotXbHGY01Kwv1Nhs5vcgK7KXaAQ7JpdPAD1WV5Mxsqzw8J+tqwkLz12KOkP3iOOL6fL+y0FoxsreUiZvStla3LO/t4BjGyK5wtKCL83LNTxvS4wsDZOwUOyf1asUWJnV1IgytZLn5IoPpBEjjSvcYgow/Ofy6f5+cf+pHzRuhp0J2uP8/qYHNFtcrNITCTx0dx4O1dKl44h29vIGz9tk5BOhbRiqZDR4UNl+uXjp0yn3zyp9OtGMKX345HXSZ2LcLKcLOkC95BBzsJoISgdW4a+F7zHXPkPKbkY4qEXIGN0K//w4XX6arltA4pk0HXfrBRSIpwMoDmnkQxbubSYCOL87F5oJIpjA03G4+TgVgTQMzVgSu4jioiR2PbSeEttxIz98O1DtOd151MrYJZATsNYom88KqEonmHLYOk35Bp4boCQSXT96EcA7jF3FoQ9mYmpqazbNByy6REU/MwV0mUr1yM8e7h5v5+v5zQSEk/m4fPi0nK9WTAosbuc3w0jkD360A8baUTUEkrLPa8emlETB36h6noQ6Unj5DrMSsJIT4tSvY40QTlLe1LQePwat8/maahe1CdzjdQMmAnSfsMJylQX71jp4LEeiUROqIuSvyn9vBBlusHJ4za/ZL8wxSYFbilaYgSweLy74J4Z4n6CUBHpu6KJ571p+uq8rejomMdLhBluSI+DXsBcr+i37FXtObxGDjJIseH9aJIYB1EiX4vZEl+L2bC5Fnkj7cQVH3xeRHLX92ZXfX2yPdspRLeS6XB28ZnVzgOsIdkqxaCLL7pS5J6JTMO+5WWbYvq2zZg3qsRqOa0TNU8YXD7eYeDSqiw5xYXIuKy0JPyinDKt7V3ry6FZvE5uP+LyL2ZF5mrFmxlebfuepxDn3lTi5hoXAOpxO7L20qCQr1kxUV1UMsluVwjwqmnzXEHDeQJsKx7UUTsdQyMUDw6e3ITVjYg3QPBQRmQ2qA92o9CiCL+YT9lcWtoiPKkKsKV9s81KTk2Yckg5s8H6k8eRm3GHWDiU+20DlivAtbxq+B4dtlncG1kyNTeMXWg9zx5ZCUr0iix9oiWt8v5awecBbIw3qpPr7hfeadgxfXq+Jtdmsvrd17TfbLwXyKCAGNy7nHULwotVWhFQZs1CItICT9Wfxxa0FqOGCWDy0qMPyCGsDqkiFRpykJXDjQ61J1szR/ENmPgHoXpuo2awfUCIhx1wuBlNzN915119xq6qgFzA/TF3DDGREM3GjtOcuYO7VottSOouboLRr2Q2VduWsxtsD/IX+1eusk3JjpphZPYL7jalOfPRhMuh8NQyx/zBcu88BBg6Ky7tLf2px6guhd0Xdy8hINdPQ9Cy9IjUKfc/mb9/5TAm7dcUdvQi2VIYKVmFKrZWLKl/pEePjer40v/vavJn+q6UicBOB3Ndl8hn0lTXuS/MGW1NywoXjrUQuI/GbStVjKS+eT7T+n89l/ePYFFT004o+H4e+EYEByrRh/Gl3iFFaDrHlHrpEiTv66SLjjqzIQw+3G5u8yprJCsToKeuTCzDquiYLuR0sR2Y5/ZTBRwIXVXzgj8HQtNwA9YDN73/77b1Bs60Zu0nm8/pKAMr4giv+LnbLwwpZSQQnrU3wNZH4wyWS+AOSyH95Oonff/v/XQaJr6xCJe9z1ocQIa3xwjM3Gj0QVqk2JyJ3U9uRIhmtdSyxWRI+kx53bCt8vd6sMeAnKIIzH+DzlwIzCh3dfFeqhcDgpSKUAkFTraS/QlDcTxcVFNcHzWhhKT+1BsVNZGOPPk+9sH0STXEeiqDiow5iF6gzKWrzpkZIOLEYtwyqExAc1ij0WiykAUDEWGLyeqHeF5kdu7oesOXbtbJG9GjN52gG0fXwd4SpLWMAsfobxmqSdhtwY9ejizEAaYc/gwuSZFOCn3bcXQxXZgta/AL7vH6ztwZT35VUYAkazoosj1cX06PQYK+r4u3US5Ks7X4r0EAhVXQ/n0wHqFgCbTVUCyeVxGhaAUaojuNuq+gKbKykvQzBLiUTy0DFR7PT7Nd8nHMmxtCsM5y1zkSVVxtxxKKiWbLZMyMvf/7JLdd80Eu0YM+VOZPPIX2u8Ak4O8Y+TFrelebBzgvcUVCWcfHNvXQd8qbivAbLi22G9zF2XXwpYPkmulRn+dS7heFFdkle4IQH8yPj+qj5syyO1Yc53ZXnq09z1NmAP5WqL3Z0KHhLUjo5LajnL95IeEFcVuqgujgbHHYnk+YtWF6c811Pn3i2f/TSJcb76QFLZXkNd7v1bOCt/Vbcm4Vc+bRy3oA+uMrC5yxSxeQetJRmGm64FcnzpnD6kev5M2Fe2tlCNBSUABb/2tr7dJkL4ltrpwHvj+GrsbVi2Bx7D0MqAAAvqj0hgHz/cmzUoJQ2+94Kdq7itxT+36DyPDSWjav7aZou4QuycHvi0Wfj8hrbJQ+1iqLh9RhDux1v+yZCMAMrSvYhVYdos+3w3qmrYXEceMLJL7OyhwiOn22JotUYz9UiIDgujSZw2eptRtquI2vLWxE11lkYD0km2S2DVD7KaBkCTb9Jx7hUsDFaE7uCpqotlyNaEOJESn6hlxda9FWlpworDmvl+TGpDXmJJFxvj9U+r2j5EynWmUmKv31nSdTOoTO9++eNqXJQ/Vw2Oj1aWJ0JVWPVSdvEJLmF8iU2aYlPKG5W3ONVE7FyfZBXCSUOO+N1VvXmRGt6c04r+va61sh9t15411ilPHByE2iOD23aAxTVyETJVmw6kGQU8rDNfOzFm2DDQy/BWxfM/lc0PnBF/BCsIt4IIpblHQuJvCKNuJFQfKqb4Y3IKTa/7XjyHE4lvQbCsPKVkjxKPCqNO49OBP3dOKC/GxV01/v5kaC/HxV014v4kaB/GAU0iJUxuayGGXAvaQF15Yz2hDwij9WwgRMhL9n7/gy1Vc1wZehAXsKY4ObSkmIKlO6YealeqlD4YvktKQuR5/vY51If9Gq7Sk5ALtVjFxP8SILbFtaQIthZvHONP7DFI97oKO5b9gh7o/oxFExvixYYznSRd1ZbEoIc2m9gX/fdHSukTO1dqQNsI5u/oA3uI1rYzF+Wd8sX65n6W/lMJJIdQUEQAQZWhQ/NND4FIy9JngyoZ1Fu4ZgHti6XM1sNenN1fStK3JLPSzq05LNsUWFJWEq0K5aRsb9G1AMfUs+nj6p1ksnUg+/AOELz4RcIcM1x4zZHcQKYUOZNb6+n9Diba3psIfWwyBXzFJU+YZThtlT3KX8nJsaxy0VGw1Z1Pcne4q/w8zwkuhf5ouvo7exJl9u8juoiyFL77y9g8i9lKQ2sMhTlFtAtfvO6c2+rNN27r+dbz8B9rSykqrGfbzUf4xCNBldb/+4mknn6oJiu/6LlQXDyo6caqsWhzmizKuRenPlaL9PG0HQuQJqxNML17ere3YWpZ0lzfQzVFKYpEEmp/Kr2zI0C2nGO55A1L8UBNpWAI4MnRIYIFAnmj4kWTURqervRYH70PruOueRXnzkGzVuc4oO8Xa2KxyL3VnSAxbfIGOtcjGM1sMG1AHyKfZMyzM35Z9t1HeDx+TDbYeY7wd/SYs911XB4Wt6K0iRyXaj3K24tpv6gQeHj2aEn0cD4z596mp/f/fbbKLQqLhVGNGJlNihRDaJ2R2XPG4RBf4N/PPgNZr9O/D+Mib/BB6AV/9dfj4j/669HBP7tmMC/HRH4d2MC/25E4N+PCfx7ncAXjy//KCnYY+hTNap1VUlA5xUBaoc7oocOh8/dL7IR6DAPYo2ZNgZL391Au7Rt8z0R1L5/ltxdOcYCdT2A1bpKi6TsKR6QBaKwUPrPpUJJytDv68POF2UQ/zPfnWPDdF4PRjO4zO/eLjs40gF55Jh7Dh8JRIoWJwbUyn2YtRzxEbxLR/mUhnhJR3bqipIC0gsNOzXxHPJ4cnfvO7qc29BJd3TVocP7Q53qzMmHOaMj555NeqFOnI9++KrThdniwNnCVHBwio8nX1bvx677rgTchMt3fPB4w49GwO3qDATcrkYj4OnmDCsAk2gj4K94b5zBD1nmPu6ZPSgTyd56FiYOry/MH8eDHIuMHbKECwPVEOZpFI+jrcp6LorGUtMbtk+rts4vLO4No7fGusbETbTQ4R7N7Gg+07ppuhAjQ614CCL5q8Vj92tsEfpoC1IDX936bUUkaT3+EidbpYifb7abWqibPZpMduEzgqvTOV8N2IDxjS+Wq/WXRoQRZSlXxVjfdfl4EvaEjU6k98B8bMwUYmab6d1ZzdjLWM3Y/v9bRDotIveg5mcdEYB9iM9cjPtuKYto1aUy51Vqi0n3KGS8NM8w5Hnfl5a0vEgWjq8jLbGhxkMQ0t6Cf/CanMhq6pm8ySgfMUk93zcsX1SCsGw7zngCIOww4Pk3mA2B+SOUIui01RL913R5z/IupyJ1bOTcy9g9wEZi+6eUgQkyBPG0avOsnNsjC7jTn04sJpA6E4upzDN28Rn+zU1Z5q7l01tpW3bJNIqSJWuKoB2tErIC2yLbsGrggo25oOFNGdpBjsXSviAxlVUGUnb0L0G8H0mJ0ezP7AOWtkHefEW8q7fcejcfV2PXOcA58kKPzJD238j8bEseZxbrUhzJT9faqwjkx50gUgtcaSnDmn+6bsZ35yXYs+saDtmzfut9Q8MiBnbFEb69RRK4u0TALYhfTFy/p1TLMZaU7sggf3Kkk0wPZPJGoPv6R8sJw6iFi8snoV3oBFuuOlMQ43dWtHSdDC7xf4cbfFWJn1nNQSswnu5/nE9v1z/+q+6U/2Uy0ztTcEco93a2RPi8SnuvXHcthNYVYS0mt4vmVvCjX6cLrOXWkpfLNEUTdqTr64CHWi0f1KBBW6GC0v3hH1f/uPq6rX5jftXo2igyL7twj5FC7eN1VezEWoTMxAhplKsIzmszcqGgm3YIo5IfSksefJ1CDBdYYizuV+vp/Wxuflo+PD2yvpL8Jx9v5/N1n1yuAEtiwxXsOrIpqolPjqe3bQNex+Fnim0uCEUxX27Q0Hxk9qFLutSluWWnZGloptjsJWg7ayeUlpODgxjf4g+4UoMXDewcMsvgg1FSL6wlQ5nFpk1oHyxijP7SdLynbsHO7C3xOCqlta72Zpeic3KerSNrutVBH4KVvcFrLain5hTx0fOSCqcBJrmiRTxiq1eJgkY9lqd2GLv6a2uEsXv0jiRE77If62D3x3nmvXgC2HPsw2HwsAXgKI0sj96FhOhddmEd7P44z7wLTwB7jl3YDk86pV/ATt3EnrM7sdZmPs6ZndQ48TVNzIPYUPElOMYGlc46zzV+8GJ7Ry6CF563oTvID5+Nk/ylB4vqeDDZM0/YY2wB214mkOBbUMCY2VK9kjxo46H28pELr56Ri22uyD3W8r4m2gdg+KR2fsbebkfPPmznSe/OxmVlCDpbF9y4lnPrpiCOz7rq6PsWK9+w3hNZPoadJfxawp9i8acOIP/gE3TmWm9ZA8GmMdZA4XxsBazsGkoSbJ7buXXv2CdJhugGRlNzZBIRwW2J0ZebYAqMPUTaQbG9oMpMi03EXOBsT8i9ooZuxCjl2uzyJXzg7UwEyM2cywbaoAxky3lTThkTW+vw2l1hkFZ44/8x4g6gBD46d9iwov85KtzT5qZSAu74a2YMv6a8dhsKpGUVj/Rl4W8/nfqdsu3zjeORxTknxvx+en07v0EX3M1iRX9vBqIMqAOO8oOejOANXkz3cxSzbGktbOHDGvmw9HSYN5ThzFK9qxjk88UP2Ic7S93ky27QWrs1koYG5lOCHuAcKOn5WBGbnGJw15C/j19/RoQyPg7afDVUpN/cNLe7GLK/QaPHjFLPdsXth8Mnyu7zsN2FWtQR9c8wcKv2yTZRI3eG2yXw/fPaIx9Xn2m//OoFDoZ5fsR+iytKsJ0Yt2B5x0D/vZtOo8h4uF9PH0lleYjc4H8+rgp9QusMF7V746XaL/iYuoTNOF6HbSr+LtPverXgRFC/Yn/t8VBh++4UCzF2dJSu49VD3udbd9IZSgU5ung4Gsaz0dCxjudHwrvj+380dDVd2AcBxLYOKxbJIQI0ruDeaEQ4oJ+bF1ByYx6dkT/ay9gREZtBV1qNOMK7v0bu9GkGgZTh7rgU6phcHUrFKP1ARMe6mu4lSoQ8Q9rRZpOnoa1lf+jx0PI0O0PpRR1uy0ipU4RV04FB7WZdHamlJGBx+6jk6e17WIVc2UcZVavDm7twOasXcr1RQ+166QPablDdMS69pJV+66bXtDriDSja/S2q63E9MX5d3N88/LoC5etptV7OJ3xhUfg9zu9B+DVjk72adQDMGz8X+x0WwBb6Hf4yXdyiZdZmmEV++HZA14AuPuZDNrNURXn3dLtemNP/Mb9Blj7Ol6vFaj2/X5vfthm2dPiudGEWh7kR8GpFlu6PNy1GrgAlJMLVzts0guvd5K3muirr8Rg76bUET+aS1JTYDptKdMZwcIqw71QWSjZLi+pwd/1Vy50LktjEtErZ61a5J7QQRQovmlRMtyxeaHR5JZQMlTYpDYif+kWsvWv8jtgXzYcwSExdzihsO6A6pHqJ0JfINnVfGb88zloxiLl3fnbawyEOcMbU/k8wHYaykhOlR1KLF9OncUPxb12auY0MNJ3YwwbS1m4Xuzsr5d2j0bIc2QDHoDJiF2u1lNBDIvwsL1JHMYj0Gi2vPMH/5i1dTxQvKrz2Dq4WsubrW1GomKXUwvE/eL7v8YrFQ/EBm2aC7jUSrL04jmCqFnbCwPwRZASsIq9CE9CfPH8koLUIFdnzTDMPhRzDDoqdMU4gH/m8Z3D+2bUzEKtTX9SUvCOnbmy6/DeJySDCJ8SHx2sCzwrJ+m8ykgipkUh0k3Rnfb6ngp85YXrN05ywAh1gS+OsVNEcACZbHmbMq2tghnEHqdPbW/PfLwdz71qRSe0gNS/JNmZlSpm7jLIAVTfHf/9yh/m6Ea2Zzwq2910mxM60DtOOshX9DYtIj0gBemm4Uk7p27L+eDt0ARsWT9utPsbrJuB7zze+1ulBVh1AydLtCYA5J7Sd3NQns5VJQrz4MZ+J/Q7NAIuyc/ET+b8o4wl+Er2l+zBI9q7PxnikfxvsB/ihjp2ssd0hqa+lnoetfKXiWbE274DKWzZ0UmC16hn4dPXNb8i+T1ff/tYFUH+PQ4FOZvaKd5nuiw/EsrS0R5HyN49PIizEwlIdx4BEfTVEo7nF5d7bEcDGqihBpA7TO3c7s3hs0Mk4qpxi4T5pSJE93HXOFMtuDtFvdW8r9vae80l0HUk8CsDlzISfHiKZlsAMvg6gVJAcr/uLAMt2p8TUgZ0tyQUBZ4A6UPNdf0Gw8beOEbY9mRDyUSLyT0KOQa+snEVniQKiwImyjqS9/vIVpGlDQp6uw6ooeCaMebHvPDUequKE40SU8Wlrc3wnxuppNpvPb1is2cfpojXSjEej6jTv9zLGdQibcJ+YVjMQp56RVT3Y4r2gZcsrPruYpFUlZub7uDjkNM1ImN1D2cHkenNtjQvEfW1cAhaA8dMc8z7uLVfoeeRJ58ah6vImiBQ0tXWcM1aung/IXjCK+k/VI88qRJzkk2dDlLzy6D1nhbVEYLM4XTYpWRMlvUYsmGFtt7xwN3Gw8IhxjPOchUFXn6rbGNv2RF0mql7u82jGk+ZUgiJLTBH5Fqr0xLQu+OfyZlWPiPEBEZh2U3P2nsiywPsDm006MCI2zJRSkwWP4lild+lfVybgM1f/Wq3nd+bddHG/nt9TEv/8l/n9uhsxyKJdGJdtq0GoxRh1YL0kyeAPGYA721vBDn7AN+p9iHTyrIEQ84VfsKXZjgWetIBP7PBEfquhvAyxlxiPT9e3i9nEmM5mD0/3a3P1OJ8tPi5miO3+4X7esCcpiODk1S/GIvCdCGTCbZ5FcDWgHwQLlfphpQBRXqFjV/VuDD4cbJQSkJ0fbizmdMllDv8hP00NqlpXs/pB+NTBDBysALNxfeKU7svamWvu7R53Ns+vcHdW00YNnHHmhIGb1t+3ktTMIvzmiZMfQmwq4GKoViMQDBrnk9XDaXZkMiCp+7msTrUDqXoyW5ZdyHYTpWnquXhCqx6IRl2p9d2n4VIdBmfzZrIzf/X3WlDhBlttlH7FfmiOAHuC/yJwb0IWoUgr3jiLu8fpYlm2Gxpp7G2f1QSPDOBxt33H6DKxaYcWbTC39CQ8gbjIMCsoJoYvWkwuDlJ/+J/EyGZoMfpeE5PfzdrjWPi49UwDSYqbGR2M7TZz/UV7FLTihVuzjmKzT4yne/XvP90//Ho/MR7n9ze8dtZyvnq4/aXNnO4SzTkFfe1IVTJKydxBU73MFhifvcBNPPXQDjdY+BjnzfT5iU16afFAn9x0yUIETF1df/+r5gNGw9O8CBDCF4EXV0nT4ezi72sToNtKslj0DaJtFLk2WiBOv/r2CqGLFBM0wni6c+/U+J1RSc+DyfGY8bgMKo3o+wo4MFV8HysnOvgd3FspWPHauIH/AdloSOC3HA9OX+wGJNxYCXg1ZoCGoujIPf+Nip2TU8JOx7eU3MRg91obfS3mm9eCSSLrGQBi5KZCgEzd0bvh+J8nh/Y0k1QX8FM5U8neih29lK1YY9yzUJY34a1dMhaGq01eLAJmz44vFcvSMKfTfzMwFJmfoqIQ6CIMhobPsuuCF2agWC+cgXbEY8Z5SCdc/kvlaDd3xM4+D3/E3h6TQ1y4kR6og1Py42e4Xyu9ShpZkyWs5IyrECepOfrM5LS+gxivIUSDGMhJEqJuTJKKmWeKwKP0hIR129CuGuU7+h11wEF7NdG5Wc+jdAi6m3atXuVDIa7Pvj3tii63jqmVkKhDxvi4aqDRk2L9BiFrZeDIhFgy5v5eI9Tx1bFqMx1+cZETH2tx1G5llXoWoj0CC1ZSqpxTLVVkmWDGO/OBJTO8j2rO+xM7mcvqaGHBFXy5AEhA3ruzJi+DdwHc4XXxWAXQ87MFczIe84z1PL1+/plHu40nWHPmVBwEslqgLDDaT+ltpHOVbRDTxl2HK7QTzSVciqPTqCjgieHytivkbbCoAWLCULH3FBGYg8ckUZt7473iY4bLGw6Ddf0o46P4be6aTzDEnYdqUFELb4tdOg2XSKWPKClUxGuyK8lHRKG3r5LpnrIFB3B27Bs5Z6o4U6/KS3IZTpFLVLhRNa+UPpP9SaSSgT2USV3HQ7gRsUvKGB6PevqY8/Da3XuBgypk0t5j+TRidbjqKkufP5IO9NjVM+R9rovzLvr5Dq8iEWGdMJ6drXEeCxJlogOsemSvjAX9Ngzw+KoylUTl35okZDMnqKzT+1+CPTSEYZdhvQdIHU6bo0xPIbHTnYiCMbH8AQmCU72k73Hyz0TiQ5buwrM4ggc8j+mScUXizrc9m0g6mZBLemo5/lS9xwMl33ynPFTq3JlNPDg9l7qZByKj7D15IANJcIwS0vfMHWnmWh4+w+mWlzD+84MPJ8PvTmvDKNJGjD1r39ZjVMNo2blkdltwhQEdmLRNP00mvAmWhaEo+BMQJxPYU1SH24K7BkxPtEn5sW+pWmTFFABMxt55OM+mFGJHWY1mlHsr2ZsAwYwx3vmKIlDb0sRORitmoJlxOAFU/puQHAefNZAdDzxvUDsG9KgSQJnjTkDCuI659cParB7sE22l/xTvRseTp9Y1KNCVRJatlEEkzcpGeZYHOzJqDbKRhD1GFaVBeMbY0Bo/IMfwuMZopLG13YLeXRwbP7kP+U5uiNbmxin/SI3g6Bd5WuVG8XrHgTmOIshqVJpvHTaOGsI1PCiNDXHG2la3NGFtPNruEprZ6G5Xy5LD38IsNrZZwHY7hmmSnU0pa/gAlbewUF4sZEYbbxeU/5N1EnGTzOfvOnJoysVqlgFz/LV+Iivdb47BlreZ0YbyI6gEVvIW2GBbB2GWKEAnJZ8rWye2O4XLl16+YfPmXVrYU3ilHYaxybh/uI28JMWulzD3jetjdZ+3j/zh5ZIplaB70ZjFWgv/ytrKPIoXdlbNSUqwj3uCBX7x5KhdBZqRiremMc+CaMempJ/36LakWCCa9gVbTx7mfLCiyKNwcnZORX0udsckbLM0WyL4ow7WzsKAu4fnUmJp57LcAXnzO8lkZSOwhKxmrE8BHD+sHeqMhJrOZUA335KSx8qnUe2/k3fl4iQwbUfQyj/lhMHfUlCVXigVhMDj7cvQ2y0pDIortZbaNhv3iNp6Q1YIS9arBbqPpsfuXaC7D0W0enkpI/iH71n8jFDCDMWdtLPVcEAvdXK9lQVp5KKtgey83M5QBozdpK1dl9G4kqvIAx68jN92rpsi3MGOFRdXiD0Y+37jEnoUUpElDe2yxIza9Ngx6rEJkM2L90cGpwJzsnVMTQcMT1qpfpiAkbt5KqVdYjcKqXIKPpyXx+mqGwHWrsYKaA3guUFlqZp/mRwqQgjYsVcLlip4db3dnhKVwiyt1KzJy3DIXadtN4nL5eTjVndL9d9eyuHS2D0375TbdHhFolhpI03w70qlSLFYaKHxjyTHEScF/snE5ZXaLoc40RzldOJkm5WBxPUDytIEdQiBYsJhxzWvSIvqoizuzcflw6flfLWaGMv59OZfTWWKZLaq3ZzRfYwskIhTM3dDmL538E4vg1QtqJfPpjo9SvuuXNpFWCesRkPzYmdS5TfHpKqVhjzhl99d6OuXYhKLuQZvPQ6Y+ERt+v5wyFXdvS+PpWPuj5OckYc/zpsce/ezMaWAFPgLiriltdl4KfxjE4dYl7Ou9D771cX2tZtF2bnaReW3DKbxY4Uu0GiwUg+Yc7IocZBzmHGueTf/6FrR0wjln6t9lSQkLP3MK0LTqT8KNzaIch/ZJOfBLxq2HI95xkQTGFuBy076DM/3yLXQ8WyK+einAxBTCsWMB9COC1ZUMhPhuomIV9vktW8Vx+hxpIDJ62T2uUiJ+GzHYb1jo5wFakK7m1ndQ4D+jH74lfen7tNXA9ArIutoLD8P6AOMebp7PwqWCYe9AkvZoW2PEuODs+znIHz1XWeXO8Hyo1Xaj71Qszr7l3bVldWHluQO/sE79/CUdESxDof+EvoZ898sp3fN8Po0n1SA3uJdowkp4mJ3FxWLPAHYjZc8Y5/P0Vi4hcENB2bJQxK0ANbJToJ3Gj/PK+G5FUGiJ6nF3IkU86PexoXLMnt0gn4KVFk4LvqsMJceMnJ1cVzsSvJPQWWkJ+neaH/Ob7axgB7FxUeWPoGJbpqBxVSOMOWGcH4B44ubzNkYxsSp/XxeoN3aQjt4AZz9TptdrbvkWxffx3jK6ZpTf3W9rhnHKfUurr2aSu9tFQZ3XuBq60HDhisjms7Wi1/msHOxMN70+nqxvvu5E9J471J1IJvhKH18D5rqDCp9fHHItgVcwerdzs3F/WqNVZyRg4ybJv7g5vpfpmjy20yBiETQtsqydiV+s/+m501lNYGoaywsODi/XiGn5m0NpOlqsP03LG+JQRltjzQ9I+LV2HfuMMUMaTk+c9+GWZp4bNXRkPrlcdYim7I0NA9eEMbiPJhZtIutln04ECsNLk8IH5zfSTD5AaxMzLsASyqKfC/3BLVdQqQf1N5BPYs6s+jCsKGZSeHFu8YNIWCkYeSVuyEMgkEDVLyn7XjoO/V4Xrw4zSzf3IdJ/RNVT1h8HAPHEeikctCOTv1qPcjgxKLlgSLQeoLCr9Q8qiTPp72qJM9nflahFpWOsSq0rJuC/Qxz/2Rtny3ji7vVT1/WPa/YfpZQNkzglJ9a8jQ3+LIxfVxc2tsLOxhgJKVx6Pt6Xbt1rnQ+DasUwRk3YfnXWOYo/4SR7MPMd6iJG/s27r7gzdjB3wMKgmopu0iV7B8xiUa35ZcTFYnh8ZaIwyRhIQgoQqTJKil0P4tgeTg9cHVZLY4Nhn6NA+lEXq60xJEq2PkTYgl8SybpduuDMib5rPVBpgxXYbeURCED0BvwKDuilq8qXNzquVur/z54Chw3XrKPgbjN2ax9K2c404dYTqWiF8FeXSoiCckbkHm34S5Bl6FGN3HRs624NslTjAhJ2Powc1+NltK4F8GjG69cWztDeaG+vBpOsfaGDcpYkCYTZWtQP9R6FaQC+yFLz4VbvNIcj5j7EcfjtUyTkz6ZHP8xeK3PINcSN721dprbUYc0ruFbO1XqKmeNXEVMfmwpjFtWX6K7va0Ay0E3aNly3VFxEyweaXcscBmgxcS1Nl1mDP8Tqm2d14ouJxRTRZeiuv894vhiurz/chCacRxUytSlrlXk0ZgYT4830zVvoNDVjfAZ7wqdTqKCol7yGAmthv+zk39usEf/iGPmVoMOiCy5H5+UcmOkCGli3Mw/Tp9u19iMYmleLx9+mi/Z39cPj4uZmf8UmVz8+eN0uV6sFw/3zYRxRmhv3cvFK1qC/bkswJzTv9UD4l/KFV+NxC+Y5l0qELvTzZfINr3ItBwnhgtUC85Hg49W4r/U03HiducZB5dkm8Bt3qwDQPFJ2YB9tUQ38LR3znExPREuSoCBvdRZTdMtarNWmlr2vt1NV0TnRCF8XcuiycHaeFPwzl3VXLjD3E3KRUsjdmzokuaW3/sp6imvlppHMtznlA/T4Hoi1Z2cTK/kZMJVii37GawQbpncT9cGHwO9O5barOfi+tlwC+gjUKXkeWuOqCqVm+SPoyqfpIesVxiVAnpFbH0fvNwcQoFGiXitokzYbOtwbD6TtRZifTdWdrACvhoJ2gf2iJxm9mU72kHMzuM9pqxMwBhxgnmAR16MgEpbNVDSB+48DyAZF3JSiFUZjJgKXTyCUJ7KLKBRElzLm8EmdzAVmOKRjWBmcksJ74iW6gSO765hTDgYY3OW9dqOrSAhw1itcyfKiJJRxXe2B8jYT9p8lvazmyY3cRiNgT5iwxsOjB/VSrxOaGPfIQKivlukAHwU6dYb8yDhxnGPfJcI7FpvExX6qBzXfqPIJ7JCHKGOwhPllwMl/45Li/XssSRfOqS11IndKM0KzZmPUIjZGOd9iL1nkwrrnOXxynIcbc+vl5re9il2D74XLHlhHa1e8LoIYF6/R3Hi813PgfAQqhZncmTFf/jvj3f1OF3+fNsJ9yFyg9lbtMe3sveGHEosnbB5f533RizdZXR60bVfY67WIJ+RCGVVz94DPUqfLfWlke0tUIISqg8UouUlSdZJxgrTl+NLI4OSquN+ZNxZUrZQ9Pgjq78pdpbmJ6wacl4tL+UtZ9iGwgLjLAgs4rVAZTZ5S9ADE068jJ5e1YCK+NFtL0QgqOj4SmC1FYZjYnBkQFzGAbtir22VZ49P50ocg6lkBpYqIVo8BRk23Jih7/JHL10ixFEe/qu16fLqA+SxJByGjUBaWMn0BR5Yof+VNy+T5Vs7SmQ6KFW5J+ytlIrY8YALSs6z4tzvG8XewYrfenD+F0r6Ik+Nzqw8SYGI01X3g3yt6k7RIhF7DYp5Fq3YSNfACm04y2XJNjSTBL2huaRLuQP3WK/Sep4P4CYIUqpY1fuFTX9qRK+n5zEK0dQ+PMuiOu+YjSCslWJWQo8nZvbWovPdJi/aUxQevUGhSHJjUyc2NmQFolxOkuN+CKJwY/ms3qJq7fIgmZSP1CNkjkkAM3ZTPC5hYPK+AY711rwth9/fOBztRWbeylh7IwmsKNmHacIDpdHqbauGeMj81DOtPxuxHZG8IOzhvZUodSzxGsLJKGSDnRxQJ9I343/CoE2Ei8wPN7Djt6it0tcJULFyoRi/OxVGfwRDzqY+sQHi0xd/RXTrD3HYkjgz5KDDOOVZKzHQ1JmZwvCYXKh6rdB8TlwrtvcnOa7yYc7ru0JPxIrmNdCu9IAHDggGjxdDL/3ajX2MkLAx3J0JvDrvFhvgYp1bXJle0XVt7mLXPb2CrZAQIs7QCxz3c6m9AGcLZTqJQhcT45u8p58QLVyRAAnDwPWk5M3F9pXaSFEv5m5CqGx5Eh5caSHw7/Cyax2Ecuw9KQW7Q9+KqS2rC/SWyAWU6kte19LFbdcCJ2eBu4Ta7yXXMOqzRrKKwDc4OkoBkmfSKJUk8Ab0VBibA2l+W2Cag+usuOLAy+vrrmvNPEyWmE3RUwrr0gz0HiPnNKMqRON1AXhHBwjlFgwC+9+/3N1RibZHDILrWs/hLoZqebX/BmvI2LtWdCJyrPPCTfUVZlhoYjSVpCk0yGmCxhwm7s7qcC3wU49eD51AmUuBeFiEOwgcu+XRNr0J7exA9fx1pz0kcg74Op+E+ZYEVl4XV9EwqIkz3aalX7RV2/LBmHLGoyMHD7fFs0jnwVm5na2ZILY22IQYy7vrlSMYuVLj8SN100uN1HrGi6H2RsQcmxD9GiyuhamJPVpm07UHt9FlExSwVaKY9x5twIn6EWq4lE9P8U3m4AVYIk2kuOR6mlVIjO4SoWJFRsVfZWeBhp5Qv/38WfPLRv4qhAYMrPuP6/UjzoNxsBGgdKlXQTOk784E6bv+kL4/E6Tv+0P64UyQfugPab1Hqz4KQ5+U8CXvPK0dJm9onVrJs8xUZpp4SggMhND3AOSgucw5J2oug46AzcA+zJ6038aKj2LGS0Y+5SUjUTQy1NT2rdaJ0S0Y3wc3TExxajumenoBdQ8bRoHYJHRU3vHGrVUW+AmW7XX7qUMLxpYwuDSqNpn/XDSxWRm4Xjoes2DQOFgEq++0P4tyf9/qu8oDaa1y2ueptLC1SHXQK4LKCk9/XqrARomG6ICmBsn038cjsLC6IwewsYxvFE72QDiQm8LuG4GdueHHRLPrMKHczE+ZfVZNFR1WGkrJDOUO8YkwW/CqUPueCAc+3XzytsBPdbrz68GzAbU54MdIeu9SP3RnRHbNpz+SoWtG/YEDPEJA6UpUdi2psQ353jK/vfrhfQIJOnfBWTK3+7kwOwugXULStupEGFJUTcWUi5eLliC1ivX5BEqP6fNdDAraRfNS+jdLlLVvIfrW+TiuBaSMSnDUB+rh7//w/TN2h1/eFAqVDm8Nv8niJDV5BNRVZNfXl0xsC1Q2c+uHVvkDcHMcrPSf4lmoTf35r8qyKmG2uH6f3ABMNt94zOIoTFxjtboxvthF337JYH7YZJhmZSy+ejDs2HXQxG4IxJMiK8qu6FnlPUlTn/aUZ8RGwIw2kzL7azHX6Ls90OTaLyIRDMRm4Gk53Jr0gaF4+SYaBTGoI6gQq8DZqc7bJVPMmWXbcYbvZx4FAbHmob6VBRTYEtK7flwJ9JLqMdz3Gzg/phIIOAo5YqJCxGFb61tnY8qAqqpG2tsAqeKqq/vEyj6RSmihbew0WRMKKNu3KvVbToA1UwWoeiGJXjKoy/JOZLYVWTZGExIG8cGb64YQtDr0efjaCCRYuKzxhyTjdZDl4uezXsH+wObHeRSdx0jPAg9MaQTA/VXiEzjsIBJrw6NPIG8VuTYDQsvCU0eVsl1AgaC0AZ2XPJuUq2M6bpTua7HVyeVBRw2LZmMzarxBFw+J8QU6vL8q+im+lHlE6AKgqttM2weE9diZD8NM/vBNpnCYIKuD1Px3uBlHYvAomtXPt9zgN6Y4oYETGk5GCgJCxvo+/CGuAXnsunhfmuz0XJFrsC9kcSPWfakHObkTM29KG8PaYPIf4zoH1Yjc5D7Pd4ctfK8UqFGPlwfMmOieNakug8W6j3qOzj0i4nKUGVA3JnGBV+LGdelica6MKUkgcss9hkm6i13YT/XgQx8z601RlhVhJ36Ymj6mLG00wocBd/Sg4/0phbyIPBS/Iy0aa5bj45UbH0jI/zq9ZQa6KHMwiD6UAldeGNWvxJFSp/pUTuVi6YkNldbSezl7imjCRywgfsPnhu50kAhWjaE+aLPjuwl2j0H0PGFNvXJwdXB37T3KJXVFPRT1VlJX5O4NFmNi3FmxZ91cT+gGz1epME2DvpG8WhHTit/p+CMAtfhvGFRUDXaRM1NNRppKqYE6VS7CGxzMiqTAosLmjqyiRrfLcceuXMcYDQBFgODEg84TXajnOlDs9h54oniaq0Ye1va3wjl4zjF3NLSBcmDXY5zuuLDkLOL1X6qgXfhYYz+6wt7rzJUeOalmyjSL4afqwevKrGSEXLWLff10KM9jLCG07i5g1Q9k6DmDOsm9VXCR//CBB1dRwcaXSu54icyO0zgmnexs0jEtkSnr35xOJqmCGL7vv7NCKHZnUcRjVTb4OdimWOvMZRk4KFK7dil8xgvMLYvDH1cmcIOCZswLyXXJA14DCEzwK7DED169T02btGdzDJHyCkCK4613R+m7jmgOKfeHoHP8caHd3NzWlf3oBnYYGRiIbDfGnJUsgnsI6yrh8WCcHISUDXQOsMcsMC+xqxWelDuifm8+n7EJ032p1QPylbQ6OEQpK/Egqo/BTUrOvXLCJL9ZSVnH+7VSuOAIFpgclU5WyMYLXyzZ4F/mPImt7Rbbb1W0c7UFCrHLBuJC7BQgFSLxZWSd8I3erOSPZUSf8i5jBTI7vCVspMoVsTI62RJm6S4ktqz56H8dvqBqNMZhLlm0GO8X0C6uKimdGBMXH3THFTlsjmNEDhOo46JjcxyDjjTDccExCYUTpS5lgdASd2H0edjpQI1Gp6+FQ6AjVFF6KsGr7WQM0SzGooEcc4679QKP+ROsYJfhWn0BasmXSt7qMMoGqCZjUdaqvQykZ6ACMy5J4kgPpGGQ1NZAgS6hLvAPlOhjrUFR6A9cg4FyfywailfDQBqG3Q4XuJEGmpujSd6CRdpzEegplnvWPXI7v5M/RXFLh7adRR5z+gEo9KawHntMfT1YVCSn8sLQVq2phtzyA5fex60aL7syoYETGlvPd4f52hX45ceC0eGf9EigfDm5YlWmR/VxiagEdV5RDxTTVwOMH+IWbx6VISziTtVWpYZX2xiVnAIZZU++DHLkSLqfHpTgEPg7N/TNMUJhjgxukbVYWKgJ6yZOrZGZAZq/ArSWzFIJrak5dQJdMDMOmBi+9+wavy4Xa9YdbTmf3mD3NI3AeVJATYzvCfjn6AFSn3TjLOC8Z/NNGGXlp1vl2RaVXzdt6FtuEZ0mv1JM5U1b5zkpP1jH+Vu12EFAV8BPPOc9ZRqzC4MqoKYeL0nX/Krdulac1B21TzadzVXe0NYk1cb0wmF3agfpC1V4sa7Nxg0XBhMWNld6kyk9xygdd2UDC1E2iUKL3R2lbNW92qD0sbh0KX6+J3dQbDEH2BY4el6+5Bsmdp0QbzFmrgo4scoRpmaUGHIS6arGQdE0uij/iLUj+pKO/VSpYpSEA8eDm7Rt+6GnQsmp5oNfjUgnDxk5jb7CK/Ix1JkH67M+CtWwriJJGzd9dXmpskqxVZLFKNKrz+NCXSh59I8j1Qs0k+oFl0Aq1m+lnnqmvbeCnYvPFiEGP9tgUtBxjZus7FOjO+XUBpva4FMbNDVGGmHE5xa7srAHcpYFSrEQXTdTI1n4dq1XY7XTrNBTppGsQjBHfwJe4VIOX6/YPFrtHKzd7uLmUXddasU7N1WoYPOzZ7Wc3vLv+1LhN/n+Tt1NoocZtgNrholaeHIA1ZSKDFrtJLPG2jvYgOQc8UJHTNQQqscr97LAoYbCyDqvfUqtbi/JLl8wafdhBY4swtATJmEw+/SDF3wgJTJ26XAYWzh9GfyJ2mLxgTTftH9LxESSwNaNUGCNqIX4brzgvdJZ/QjfF+TlNRq3hdtW0akxsJ56hKQDGUAtEsy9l5qkil6xzgkaadfVuKEJMOyOzE/NpNIb+HyglwQBELTh5jZjFtE5HRBFPNzqksKmkI1Foefc9qJLuPX+hYvBTEOTaxwRszExw+LIWOiBLtSdAnCA6oiv4Io9nOeRh0aY7l3R97tYj7r5igA5KQQEixg0Wfrie8mHvNA83NXMv5R3thA3Qk9/Bl9ZHlPqu9t0JOJiF7P0KQYhT9ggN2apH4cMQjy4FpZKdTri8wquJJ4yV+3RfZKnDNGjofWBecfg2BwOGYUR1hRZL8RwCKXq0Y3JH4+7cBEk3m6fNpAT5Z9EuuiTtbTUpaP30auEc5LaLGBMWo6XH6U6sBOROI+1yPEg/l4H9MoNkCvO76TIuJUerF3p8nU0da1eD7oF7RvQ3J/hDsxLbZSZgT904fikk2LebSqPJ35yQrlpJn2QElj/8Jsz62MrKAec5ST1qZLTTA8OLVAjIkMiQrFHeX8HnklcoHTCUm7pMKbGN83Y4Wzh5zXUy2omoupTF51x3SQhr1jdwWojlwyzlN3fqNM2kpevYl3r+pzEkzeemi2bIy+WeME8anz4D78iyfIVbKqvwARw/LYeLQoFOqquNFOglmDpoGDx0IwXT8rppSaaYeYFKNLw2Q28P3l+ptwc7dDkx0ZkZD2uCfN+o+LuY1cMkDQvlp+5zCcM1yRdI/S3PeYBxm0tWoASvBXPwmcpWPi/S1QJ4cn0Xq47s/QCILam1IfrJHtvqy7AEfU++CDnLPrBp+zTkDZXWMW3jOnjorZmyHt28hijwH5r9Q1ZT0BYs3FebLl5twtvet6QeYSa5HW1IYqvMM0Af3QtP92z7hoakC0Ch56I2J7e0+AlztV2zwCNjH34jZxlX7NPgCSHX2QB/1UzFdhWNAX1Dm7fO1wPnYS81reAQg9xPiucQMeVlDHcFUKCsEXCP8rNd+Mlz9QlQGOTRKVmTl2MRnFfU2XQ9n1NvRLXqAqwBuO69zQWwBC5UNggCVVBy4hQL7Qz34qZ+42M5EaXov7eiA2BDvmwA6pR8ViGqokmxW7n2cX/00KammoOygFrq7wYrMxL8TS3XPTa2ivW1Arp6rI4wQPpbd/oDR92i0XO00asltJjztQHXB22vkkkoZehdyop05yUpyD3SsAnFGHXTNF4XSTl/uRFIlkkTqKGTPTaHyhgtFVjpGqFqhlQKMLYC4v+Rn2i9NAwLMqtwl9+dDDo1XWf/Tfme43xaY3cq0/r2UQUg2EqZfIG6A6Fq822AoqtbMEMCv04xQS91ANjI9dw+OtmWXttqzk6QidIXpO9rBLIuQwyI5K0/l66wLKfdcU+KzU+i/LplnVLxbJvbJt/+LrFzTRC50bCXO7a6EtU/PA1Y4pi78XCMKtmn17tr6q6FRvIWDwaluNgDy0VYgsAUIs8W8P8NE7n9PlaAFt++O40G5aNcU4TFmc0fvhO2BRgxGJ5CtSx9yGV4/4TW8Oy5Br4jvw8qjD+i9LXt65xpRdfbM9KZqDNkFxupR0qBbnzGfrr2kV1ROWq5FTJnulhoBWic+A3X+eWnizYjAUW5TdIrw/4vcQPc5djtMoRve0fenCmF7jcovuRs2cMN0XtwQCNjZ1CfK33HDcuLkGnPb2G5ViHH704Sa/ftDWGEousRlYZsqtGGD5XaQhFgjoRsEVA9NyWF/7i7X4wyJK3AQLhj3+uxJt4M5m5VwYJfk8qASao916yByt3PXtUXDm5rtO9+Var2x/hdCZ769l9b4pkcxTyu65uv1rfroy9QNfiMbtf/czsIc0dHGBgmYRM4OXJYZvIIdjslZpfqcrNUq/KMbpNovvCVLrcCa4uTjPfdVliqhGmzlzUHvGATtgxndCGRz1yejt7up2u5zcthgaV09dmbGwzTLT4I7N8dMGI/hkFG0QKTba5pb+sH1e9yORK2YlKXlW7Ow0Y6vYnHy56KNECR8ZGRFalrmuOa8DK4jjiAmBg6G5A3YVdDgU9sg0YDWGK+lI18UTD+aaWbmDSVI0ilaFcVMYadOSidG3GystAmdijLcGHOK2+DLhlMQ3uxS2p4KKsttwAB7DFCAvzvCXsOmDxvFRAnClt9QKVJK6JEvdy5WnTraBOOoY7hEunITC4barT+aHMiyo+n6HtBGEINwY/6vewCbHBJ8GonqSTP6rMQcvQbOhLczk7rmLDnnffDZje8eLahi9HYZCDySAbRYVrwjYxFvfXD0/3Nyh9Hp7W9PdzvFIUzcYaXIWWSY/z5XS9eLif3iLO6Qz/bt7P5zdt2s9LZGsInCjurV8eZ0esc67XjOA3z3WdlnWuOraS70wHbp03EQ97koerPFjJ1UW/k8GvYzq+Vt/VeqQG9GvBFihXWC37vUo0cG85iw9mSUQsH5Rjq/eS03Yww60ZbrAfrf5YZqXkP5uhBlveX5svNbWMUKN9ld3HFbdT9x0fRtlxoqXoJe8zobWy7jkjLhbp8VJHPliOcP7kfU7Z2mGl2J0VO74wmgBEkybAse+0ZmiUMH+ar0u4cXOJvecFdTR04I2yEfE+PmnH21LyRgvkm/ntfD3XjXrfVLFKC+Yf59ObXvu5ay+EyZib4WFV3g1HoWypnnUqzhzJCrbBbG080KJTXx0UdJp3BaPETGwrCM5c7Lxcv1BcshwLcxn3Zscp1MdumsWXQr4Acw76fW/M01bM5sO52DM3g56wyNM2nJhHgskR77MybFlyDHTY+l3Zr3tUawpPO6zULNX42YROQ6+fLHpvcgUC+fCGahdVl2HKG2KfDJecLmu9/f3n+r6fWrYbDM7rPLPphC1rY4hFn3VjJ85iWQHkt/TIX/Q1GrfftBL2w5iEweC8v+cZCRP1/ei10sTNMaD6xelV/iI3/iD2HL3cyYB++SQnt6SL1oAsAY5xvzUsAM7IV3x5KClHizy7G1cK3nZ+kCIvrJuzssT1rShhkUwNrFFeliU7eAg9NUij35C51HV2FXtQ5OX6bqEr5FFGoTpWyReBNxy3Tm/dIBlmJU7YW2dC3S5FETvHeqM/LZtcO/Rqgv+uyqljImTeowEHMyLLycr1+5VrhbVurRG8Dg3B6yzmWTR1v3oPplVyu0UIK4/H5tjkzwcRNBaXq8UcB8EM4BBcMOtzeCeTNf4CHAuWeQnMgxVjMMmIAHnpWzFRvZoignovah8IyzUPOSZFhek6H6jMCv+V11RnLids/J2gAS7174jYxsj81MNcIJPp3Be1MhHpReFWAWxIwNxISCZ5mRZeHQMzluGH2PoAh3mj32EvwBcv8TDxw0raD00bf863wJKsNuqbTOu88wfMnXoB+/slLa4gUymFybVEYb9iRrH6E/lVrksNo/18CzcmReyN/iIXkv9TobXwkzKtyrnNGdabAedbzfHIAstj6+24JVatlnBiJZv8Wbps1DhWst+EVuwUETDgwoQppCE0lBTim1Y3crSqhLkka1sJSwwZa+12+ByVMm9Y057ZVe1bDcBiXoj2WFzc7quJLTkRnBplwr0N6NVlf/PhTvTbV5PHFtXErp4IjM2NblbOHiUKhSOaGNPZ7OHpfo3H6/pp9tN83V6/j56O9cIsvEpX8KkBJ6v19P5muqSomE+309livqzxWcBYB+u5kOB8hLdCjHKu9CDui4Fp73DaPNNHFN704ryKVF0NDBm6iJ9/waLlQXqxCUGL4CVk94ruCHnpEBWOLlq1EKykHhFBOazvf/ttzny7I8HL3wgYOPnuY5Enm7+gcEel3ZqDl6P+4R1R/3A06uTRjReiwFwb8GOKMHj5NNUtMQHrBFQzn6oF8VjvQhdMXrSNCyV+qlpKeOCgt9xVrDehS8Rui+KTvLyzHYc8/HrCu/hwMtj6UO4RJYb0BH2wyi8Zx4AWZbzHA/0AbMGQhndjtuU4uZmhNFHKwSNRuTwPOeCWMP0yTWdfixFoGqXGkUxyBLL40SwVOaKUAyx+ZGNBY5mJL69Puqy//l9I2Tdfw5/4MIAfbTkl7iGM38ajpZgQeaDZirVtmBaBmWlleloS0bzk+VyYm2ryHIP70ztum09YZK/XjoFPtpIw9p5RCBEbRi89uZtVc3LBGBH+3eqdpgpD0+V9/znHis2vD8lfBCsQ8p5NNXaesHozGBvN4HgpErPSpy8HWNNjsRYdNUEkU4VVxi4ly/OZmqGwUtPngEJv4VRZyWtyEHFF76K3exSHTsYyS4S113tT5hKYv21ptBVyrZmPjTkGuCnB6u2lROfgYBav2qxDD7g8rVIU0OwE9upiCWYN8psNVLIquP/ScUFNO1AlT8oz3Fu5AiN7mTetsxQ6TAj8kYWpdWLUhjpSyRdiUQ9Ex2C/pOjVX1di7mSiFiahvo15pAp87qsn+pHiLKlxkh0VmcHmv7KbKhT1cFSt98x6FZzmYxZlrWt/2+Ar4whOcTWWT7sYs3ZCWgCtBNOIpVpJH7759pt/zL7/f6dtIHTSzEas94A7/86o2kT9ZPUZoY3ZoDQRD4/DQmEbevqLces1XBCssZm+ub2EDymlkeKLp7cP1M/ilo5rWdDQxL0n4/H7BcYzfjTdj/Cr2tlqhWBVgeWSQ0Ykdiw3a/t66qwYwwdXf2FSJpiY6CmS31LIpwiLffmkJpXlnd8iH4sgGYJ6cIrZETVkKyS2hc022jo1cxuvc/sk3ArMsTnei+cwOxBvssKa111Zp15U5espgftRdbWP5bKfr+DqDnfsSEt3vJgdtXYvSemlKuB3Zcvj8yU451cAXbMDGblRSghgK5HZoCok26ylvcON63tg8L5phoTdUvx6LKCxOmzS3B0Wu7YXYXT53zDW2vN53E8z7GtY2g5/tgbQsftv1jKEe1saYGLkzR4fnjeEqs1hhxEvWDhzbOQUsOYgsCSyDhX8LRCXRPM5OQtnHA6kjQV4WWCTcGrhw8eLF5crgahoHyI3GBsrVpeu2QPJhAW9U/Fp6myHEomKfFITmZZd4Hv2s2bUvhc8U6JSBb6Ns7Xgp98PI2AJAsdFsf2R1ZMZewUwyo1dDHybUHZT6uJhonh8DgfAJ2Wtqog7ylLWd5MJkKXVUbqu5Q7H/9rv8VoymYwwYqVQtdRE0ZVj+Vi+XDkaLDNKADeiLI7CpE3I1FPZ9t6hn0rxLnJWaqV0fYdltcXc70Lr+yzuaDTnamvgmLmOp00Ho26mKVjeJ3tDKcmJuUI5+dwO+uYH2VZXvg3K93wuij+5KWqDq1yJLfVRKaLmWtObaaUo+ir1LnL0OvQ1lzdPyEmSBc4aEXL9ZyRcbHRnMCq5TccCRneSo8wzGCJTh0Zd0LLGddza1vWrzmHqFzUUw0kRf2xrocEgzoEhzkHeMY1eH9LXkIrZtwhSuVTvRo+yWY4nqeAd1CYdQbyb335v7sMsNlECa/DJixujZn/KC4Ocg2TKUlL0t99/QASdtZ4RLd0TrSs5FlR0HLIIj5ZHSsypwmUUXG0+58fEYHF4wnGiwuR9G9iDlvvihVkCfDUIQ42TaI8b4zQ/EQ1xxqLvK5pQ+nWjOEyZlBN1/2qjOcFcoUa77Mr2YjHM1Hmx6IqBY2iVin5egu/o5iZc3biMwladr899IfzkFg3Lm1FSrXYwpl6tN8lUwUpZ7B1LeYpPOxwOr/lO/YlfvaStfDPMNiX+XnspRieu2PnRG72hVABB/2h+To1ww/te89Oh0j8ctd4wsvFQP1qU1jE+uyM20ajYx2L6CNiX3C07PuOlA7gnevYASQ8HLIQXVI03lAdV2defuLFW5mzE/ULdu9/ztOTXpBOHEV6TITUBe7Vi/uBjgWmZejzlxLKrGbE9CDrjEdJAkHyZZsun7ULVXZeU4zOsHSovaeH6zC0u+EVVCytDg7lDr3b+UcDRIu1hg5KzFR2qjvFW7YCuvFLDtR+2qLJDmuARMDGi9CMN5WUpnelf9+bH24eHlmK5zPbV0NAZicgTE7lJzamqg98JidxKJrqVxvNQnYIP7S0t6FwRSNgb28TwtthVZG8lbJvWGDKnFtOpFNAZ1YS5X12anfHIsmVXWBFYz82HxYVZ6H2CsQxJXlOoea+t7lYr9mrc6c/vD0R4qvP3aJxH4BKOGK/NC39PEvVhe8dpeZSk6H0Gq/IKXUVv6BQR0RH3K/hRVEl+rUN7H2ITWZ6sdSMe4MeC3PDcT6elngKkLck2OPQGj4pM0RxIGj5JjkcXCQAF+5ZmU1x4Q9F6foqcech0P7kXIVdcwJzV9FWQB4jCiELQlntt/SYaPiyCF8v3HDAPYBnx9eNyqFJjDOQ4f8OrhkPlVUmIAKBYUSYmhe/Kbxj/vXq4Z52+7TDGTgT+G3cKt0bjd3LxPuSy5S/DR6E6KuwcSP/SdWI4QME6vPH/GJVagrrBXjqHkFfxs+DblvPBd1OkFEzNtiiCFrGzDjkZ41MBpovvBH/DmIgj6YB77w50lD1ghUtxFYFO8rS60QLa3lsxIgVRz9ht2XacAcbEC2y2c5heWqoXh+8tgWPFqDOlezqCItRPuaXrfNd/nKjy/XFWle/nepWvdyn90McaQCbnh4lhqtWuUyOWy7SiKA7B9KdcoDw0lcHCqm4fWOE0RypW3GCr2ZK5EssXF75qvVUSU44uf9NwiFRAhixuw+cmbzYawnlbdRa4YNFe9A4H1/GAeL+h1rCkBcYweX0uXfS0yAR2gRlbHzNgOpCdBVWZfXAUXIyEkLkqPfeDW23PrRmp2K+DkIlCsONCU8NGQED6iYiaXbLpua7Q6jiTkJPqQ7XuNRd57lYbD/GV+83kDNTZuSVHVGLP9HEh2EepYB474Yy76IJkn2uMRMrF7dk75VSs5348Zr/SW5MJri4uMwvj5gm5brTNAtqJJ97I6kjnvJthXuOjmJgyDNDPbe+9oPYx+YIbg88/Uz9GIGOFypZ2qzhho4KVJOZpVjYVLOgacB39aFSXgygtORTcKN4D7iQYhmS6CUdYMouNSiUbKRPWGs4j7Bs9gsMiB8HONXMLZ7zBVvCG2lfS9tIlEa73cZim+tcRAzXdeUBmOntmZGlHTK+pWJephNEDsr5e3OKRsbYnt9pFlllgvACaYfshD6wP8qXoi1zva+LYyCNsHCgJYHVENDOfVwyRRUWxOW52yEN/lc3OgHNUBtzIz1s/fO2P/5rKA9+Igpt6SnzxmsPCQqyshkiS100GXwytRJTX4jjsaskRrDxOCoG2q1p/8ZSC4tK8DGMUz+g5dU1NzqOmxi/UTj0p1LSc//a4nK9WzXjGKiZTwoSdXH+ZIyLqRbe4//ROJWQKuPrVkYlD3zX179XF9I6GLpS26rmL/HC3A13epHqsOnDJwq4FIWHsPSyl+kbzMdtLMTFuwx1We729nRjz5fJhOTE+Ttesce/Dx48tRyC2bATPM+4a4Q9swv3bh6X1JgZXMvpkSGcDbxVYQeKlWPP31Xo7yYwrDtVgx5HVRux8JXaifwPzEZXEdzaMwcdB88oqhPlemu11jVfNQnduKrvA1HZMyMYSc1qypvHr+jV33rYXjWjpPuuNiUemaWeVCEQ7mlkcmH52CWRqxP5AVDdxGM0wdu3ah3/vQTiMhFFE7BXysw94SCk7eyOmB/GdpS1SugT7PlzS588IWvj7CDw23WsHTEdlZLy8CaJ2tGNtiha8PbdEIY5zf2jyRR8jcHVH8ZVvnBzyRF6j4v6K3Yi5VSIRYkwfPK/qK2Mj1fhJBbAIjHyJ7An8L5jAJsOYhQ9wJQf4J6d0AlSwbPoQy7bS79qVZi2kMJWkA7t8T0KQ7F0YfktvN62lGGVgo+5dwoAqikgPDOFr4MambiSV9hIwTTIYIx5Zk0p/aQdYUeFwLoPmMqwkCW3PShW/eq9zBHtZO07Jrl8eZ2z3wV8UNC0+UjhV48FZean7IQ0/4J8A6V6cTfiOgHlfD7NQUeskbZ5GOFqJT8KD6DZyoVr7zPJ9ukJ1v01Erk0VUekVMoRrghfNh7/h2yDLaqfAyNqeSSrGJWfeGDjJNpRY5TIZcRYEZEqWQLKQWvkxFkGx9ZQaB44Hm5E19qs75GwBWys91i7ucWXfxJB19ekmue0iqvxK7PX4ai7xQeBqL2/WcJcOjutMaLvg2ZYbk1w4YgvQrwr7QaFijcN3kCCm1cZjdXcrTaWayJNgJUUdgE/qkEORaWofnE5YM/p0DSZF4p8kUOH7R4tTlPcX7wdZg+RwfXo505VKoCqKKQ1/ZXzknbs9G9mSTIyvQVY51KYsMW4efr2nc/ON8sOnR/at60+P/Cvqb+er9fT6drH6cX7DM5sxMzrhLjQ4jCzTmYFp0QgY+TdWanU4OAa8ahR9QPjKyFtC0o7gHOmBqMuzMRQSawDTA06efScUmIu1AluUrnPbRHUqXw+7qM2dP4YZ2pdJdpakoA/GJrcHtCvOYgLFgmfKC+acDQSLiv1YOF+8OM0s34hi7wWXW4Er1RbPGcxfbm6NBrviH6nR7oYcGM82ySJMzDDw3xqxDnwJqaJAKZ6Iu4LNaOCMEypf71q0N+BSaOEsibTkqiKccpRHaN5s0PpVzqN7s6gblwOW//mR4awNejINoE3E/5/mrmY5cRgGvwq3vbT7DlkKu8xQYPjZzp4yJgkl0zTp5Gd3+vYrybJxShIScDJceihgfbFlRZalT7BkuB/csH76Kz8612XM5ImD/BG1gMIQsxVmVlISQPPm/JYpGJI56yDYGTd8kkZt7+OeWcp9AB0h43+2HucYVEarDSwyO8u88z5HFmKeRcPlBpEkMTTvKOIGZNdde+sq4ywfSQGqDqAKcD1QOP8G2KScTZbF3aZG1qapGpAC8k8cbjp/wO8HzK19caYw5H7kjOecOVdE1QxN/C39JflQd5tp60RR8i/wFR+K7aRNOTpNiiJCabgLihI8xfaEZS9Hb4mFYhK9YTExSH09XUOR0pA6CcnycZEoZSw+4MdiMKxHcDXGzmo7/uXQBkqTSPP4N6A8gjYEYLgGxekpqR2QrsAjHHDpP0icPn8K02ygHmAmMakHUQXFRP6GHFtkgqodFxDiCs+e59JHrh0byvplsO33XxRoPz3sosjMS+wcZGmgL2IfRuvJz9lyIdO5xvPl7mm6Xi629XCMYW2AMv7Rekb84CCKKHdFVXjlKhRyJK7jMzeen2AVMicqYJo+7ruvU0ivMJw/en80RAXEh/DCvP7819ZUKAdCDUgteLJSgiHt/NYz+i5i8JR9d//pHsIUDgFR5Mr/pbVgrzisKtxhpiQq52nKUkfPUmrDLS6ZNNvcVpeny5R/10YTAdZPH5z1wIW1oINqIFOsOm/onSGnFU/QWBlbUAAFCXAzjwN5p1FSv2nVrW91AzU1yQqZljjFY6/gKGScxIFMFKHbJ+IHDbGq8gBHB2JsNt/EA2fEEPLTX1d6A4BWbebTf+s12a3S2vvSZ2UpDJdHL0DJ3DXRucV+Um/JOgCTI3WGVg5og8FrwppmllSdh7I4kfbdnw5gtOeapG/ULPq2GtzTMEMFCSSz0gvI3ZDcqtiA/pSiA/si9qPgfiMD6mbG8gHoNEVyETRJgBgdAxHlx08OadVr6i7mbw4GLQYvrC26sY4xO5IT3zJKI4atSPfbgGFGut5qlw1crfD004GJK5ZbYdkAcPjGU5ipZNG+Fqrc1SrJMFUjjL2oID8JXPv0MQTTIi+h0DZ0eYwd/Jyn1Xrh8NcdISmRCLEC10iEmFPGdc+g0AXNWFItlGdsWRHEwn7zvSo4REWPnfa0zEtaOBdF7B17qqZGbWNS44wbSoOrm4s32ZdE6R6srPEwmQTWBbn9aup+kM9ijR3Wx7NdQw1vW7y3gc9Oz2AWIT+UasIN4F6E/e10fbj+pMOT2F2CAZ9kvNpRb1vLa8EtbjjzDISUIiktcMnC7v6hcbF3R3TrJMklUTq8xt76R+mDlBF51uWIFMAY/SUcyvlvAR7fXHcAnt5mrcCfnVKsedu2g90tZl8W0YDOWc+w0SN3QIOLUNUs+yootKLtZSPnTpFblG+GOLR86iuYoa1kveN8Y+wG/ZmBIz90Xp6BrBTB+O3M5s6POVX6b7bL1WrSQEXPCfbue+LbycbghH0crwKoM39x/mzc5QLBObvt0kWEDUGNIk9cdA6J9T0p8u/c3PBmB5DSMsQhp0ZEpHBR8oph5oPuNaTxGSf/MCNM75jFJZDw6aLnytrp2go08nilgGONKpgsFKvJerpcPzuL8aSpDAtpI8mUSrK513B/80wrErnrXzRkXPqE1f0VIqM/d/v+4OBU+ynuw3TWgvgPcpH4iw=="
 }
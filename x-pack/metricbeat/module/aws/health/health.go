@@ -0,0 +1,318 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package health
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/health"
+	healthtypes "github.com/aws/aws-sdk-go-v2/service/health/types"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws"
+	"github.com/elastic/elastic-agent-libs/logp"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+var (
+	metricsetName = "health"
+
+	// The AWS Health API is a global service, served from us-east-1.
+	regionName = "us-east-1"
+
+	// globalRegion is the region of the AWS Health events of global services.
+	globalRegion = "global"
+
+	// maxEventArnsPerRequest is the maximum number of event ARNs accepted by
+	// DescribeEventDetails and DescribeAffectedEntities.
+	maxEventArnsPerRequest = 10
+
+	supportedEventStatusCodes    = []string{"open", "closed", "upcoming"}
+	supportedEventTypeCategories = []string{"issue", "accountNotification", "scheduledChange", "investigation"}
+
+	defaultEventStatusCodes    = []string{"open", "upcoming"}
+	defaultEventTypeCategories = []string{"issue", "scheduledChange"}
+)
+
+// init registers the MetricSet with the central registry as soon as the program
+// starts. The New function will be called later to instantiate an instance of
+// the MetricSet for each host defined in the module's configuration. After the
+// MetricSet has been created then Fetch will begin to be called periodically.
+func init() {
+	mb.Registry.MustAddMetricSet(aws.ModuleName, metricsetName, New)
+}
+
+// healthAPI is the part of the AWS Health client used by the metricset.
+type healthAPI interface {
+	health.DescribeEventsAPIClient
+	health.DescribeAffectedEntitiesAPIClient
+	DescribeEventDetails(ctx context.Context, params *health.DescribeEventDetailsInput, optFns ...func(*health.Options)) (*health.DescribeEventDetailsOutput, error)
+}
+
+// MetricSet holds any configuration or state information. It must implement
+// the mb.MetricSet interface. And this is best achieved by embedding
+// mb.BaseMetricSet because it implements all of the required mb.MetricSet
+// interface methods except for Fetch.
+type MetricSet struct {
+	*aws.MetricSet
+	logger       *logp.Logger
+	HealthConfig Config `config:"health_config"`
+}
+
+// Config holds the filters of the AWS Health events collected by the health metricset.
+type Config struct {
+	EventStatusCodes    []string `config:"event_status_codes"`
+	EventTypeCategories []string `config:"event_type_categories"`
+	Services            []string `config:"services"`
+}
+
+// Validate checks if given event status codes and type categories are supported.
+func (c Config) Validate() error {
+	for _, statusCode := range c.EventStatusCodes {
+		if supported, _ := aws.StringInSlice(statusCode, supportedEventStatusCodes); !supported {
+			return fmt.Errorf("health DescribeEvents does not support event status code: %s", statusCode)
+		}
+	}
+	for _, category := range c.EventTypeCategories {
+		if supported, _ := aws.StringInSlice(category, supportedEventTypeCategories); !supported {
+			return fmt.Errorf("health DescribeEvents does not support event type category: %s", category)
+		}
+	}
+	return nil
+}
+
+// New creates a new instance of the MetricSet. New is responsible for unpacking
+// any MetricSet specific configuration options if there are any.
+func New(base mb.BaseMetricSet) (mb.MetricSet, error) {
+	logger := logp.NewLogger(metricsetName)
+	metricSet, err := aws.NewMetricSet(base)
+	if err != nil {
+		return nil, fmt.Errorf("error creating aws metricset: %w", err)
+	}
+
+	config := struct {
+		HealthConfig Config `config:"health_config"`
+	}{
+		HealthConfig: Config{
+			EventStatusCodes:    defaultEventStatusCodes,
+			EventTypeCategories: defaultEventTypeCategories,
+		},
+	}
+
+	err = base.Module().UnpackConfig(&config)
+	if err != nil {
+		return nil, fmt.Errorf("error unpack raw module config using UnpackConfig: %w", err)
+	}
+
+	logger.Debugf("health config = %s", config)
+
+	return &MetricSet{
+		MetricSet:    metricSet,
+		logger:       logger,
+		HealthConfig: config.HealthConfig,
+	}, nil
+}
+
+// Fetch methods implements the data gathering and data conversion to the right
+// format. It publishes the event which is then forwarded to the output. In case
+// of an error set the Error field of mb.Event or simply call report.Error().
+func (m *MetricSet) Fetch(ctx context.Context, report mb.ReporterV2) error {
+	return m.MetricSet.ForEachProfile(func(profile *aws.MetricSet) error {
+		profileMetricSet := *m
+		profileMetricSet.MetricSet = profile
+		return profileMetricSet.fetch(ctx, profile.OrganizationReporter(ctx, report))
+	})
+}
+
+// fetch collects the data with the credentials of m.MetricSet.
+func (m *MetricSet) fetch(ctx context.Context, report mb.ReporterV2) error {
+	awsBeatsConfig := m.MetricSet.AwsConfig.Copy()
+	awsBeatsConfig.Region = regionName
+	svcHealth := health.NewFromConfig(awsBeatsConfig)
+
+	events, err := m.getHealthEvents(ctx, svcHealth, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		if reported := report.Event(event); !reported {
+			m.Logger().Debug("Fetch interrupted, failed to emit event")
+			return nil
+		}
+	}
+	return nil
+}
+
+// getHealthEvents returns an event for each AWS Health event of the configured
+// regions and of global services matching the configured filters, with its
+// description and the resources affected by it.
+func (m *MetricSet) getHealthEvents(ctx context.Context, svcHealth healthAPI, now time.Time) ([]mb.Event, error) {
+	filter := &healthtypes.EventFilter{
+		Services: m.HealthConfig.Services,
+	}
+	for _, statusCode := range m.HealthConfig.EventStatusCodes {
+		filter.EventStatusCodes = append(filter.EventStatusCodes, healthtypes.EventStatusCode(statusCode))
+	}
+	for _, category := range m.HealthConfig.EventTypeCategories {
+		filter.EventTypeCategories = append(filter.EventTypeCategories, healthtypes.EventTypeCategory(category))
+	}
+
+	var healthEvents []healthtypes.Event
+	paginator := health.NewDescribeEventsPaginator(svcHealth, &health.DescribeEventsInput{Filter: filter})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error DescribeEvents with Paginator: %w", err)
+		}
+		for _, healthEvent := range output.Events {
+			region := awssdk.ToString(healthEvent.Region)
+			if inRegions, _ := aws.StringInSlice(region, m.RegionsList); inRegions || region == globalRegion {
+				healthEvents = append(healthEvents, healthEvent)
+			}
+		}
+	}
+
+	var events []mb.Event
+	for start := 0; start < len(healthEvents); start += maxEventArnsPerRequest {
+		end := start + maxEventArnsPerRequest
+		if end > len(healthEvents) {
+			end = len(healthEvents)
+		}
+		batch := healthEvents[start:end]
+
+		eventArns := make([]string, 0, len(batch))
+		for _, healthEvent := range batch {
+			eventArns = append(eventArns, awssdk.ToString(healthEvent.Arn))
+		}
+
+		descriptions := m.getEventDescriptions(ctx, svcHealth, eventArns)
+		affectedEntities := m.getAffectedEntities(ctx, svcHealth, eventArns)
+		for _, healthEvent := range batch {
+			arn := awssdk.ToString(healthEvent.Arn)
+			events = append(events, m.createEvent(healthEvent, descriptions[arn], affectedEntities[arn], now))
+		}
+	}
+	return events, nil
+}
+
+// getEventDescriptions returns the latest description of the given events, by event ARN.
+func (m *MetricSet) getEventDescriptions(ctx context.Context, svcHealth healthAPI, eventArns []string) map[string]string {
+	descriptions := map[string]string{}
+	output, err := svcHealth.DescribeEventDetails(ctx, &health.DescribeEventDetailsInput{EventArns: eventArns})
+	if err != nil {
+		m.logger.Warnf("error DescribeEventDetails: %s", err)
+		return descriptions
+	}
+	for _, details := range output.SuccessfulSet {
+		if details.Event == nil || details.EventDescription == nil {
+			continue
+		}
+		descriptions[awssdk.ToString(details.Event.Arn)] = awssdk.ToString(details.EventDescription.LatestDescription)
+	}
+	return descriptions
+}
+
+// getAffectedEntities returns the resources affected by the given events, by event ARN.
+func (m *MetricSet) getAffectedEntities(ctx context.Context, svcHealth healthAPI, eventArns []string) map[string][]healthtypes.AffectedEntity {
+	affectedEntities := map[string][]healthtypes.AffectedEntity{}
+	input := &health.DescribeAffectedEntitiesInput{
+		Filter: &healthtypes.EntityFilter{EventArns: eventArns},
+	}
+	paginator := health.NewDescribeAffectedEntitiesPaginator(svcHealth, input)
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			m.logger.Warnf("error DescribeAffectedEntities with Paginator: %s", err)
+			return affectedEntities
+		}
+		for _, entity := range output.Entities {
+			arn := awssdk.ToString(entity.EventArn)
+			affectedEntities[arn] = append(affectedEntities[arn], entity)
+		}
+	}
+	return affectedEntities
+}
+
+func (m *MetricSet) createEvent(healthEvent healthtypes.Event, description string, affectedEntities []healthtypes.AffectedEntity, now time.Time) mb.Event {
+	region := awssdk.ToString(healthEvent.Region)
+	eventRegion := region
+	if region == globalRegion {
+		eventRegion = ""
+	}
+
+	timestamp := now
+	if healthEvent.LastUpdatedTime != nil {
+		timestamp = *healthEvent.LastUpdatedTime
+	}
+
+	event := aws.InitEvent(eventRegion, m.AccountName, m.AccountID, timestamp)
+	// The same update of an event is only collected once per account and region
+	event.ID = generateEventID(m.AccountID + region + awssdk.ToString(healthEvent.Arn) + timestamp.String())
+	event.MetricSetFields = mapstr.M{
+		"event_arn":           awssdk.ToString(healthEvent.Arn),
+		"service":             awssdk.ToString(healthEvent.Service),
+		"event_type_code":     awssdk.ToString(healthEvent.EventTypeCode),
+		"event_type_category": string(healthEvent.EventTypeCategory),
+		"event_scope_code":    string(healthEvent.EventScopeCode),
+		"status_code":         string(healthEvent.StatusCode),
+		"region":              region,
+	}
+	if healthEvent.AvailabilityZone != nil {
+		_, _ = event.MetricSetFields.Put("availability_zone", *healthEvent.AvailabilityZone)
+	}
+	if healthEvent.StartTime != nil {
+		_, _ = event.MetricSetFields.Put("start_time", *healthEvent.StartTime)
+	}
+	if healthEvent.EndTime != nil {
+		_, _ = event.MetricSetFields.Put("end_time", *healthEvent.EndTime)
+	}
+	if healthEvent.LastUpdatedTime != nil {
+		_, _ = event.MetricSetFields.Put("last_updated_time", *healthEvent.LastUpdatedTime)
+	}
+	if description != "" {
+		_, _ = event.MetricSetFields.Put("description", description)
+	}
+
+	entities := make([]mapstr.M, 0, len(affectedEntities))
+	statusCounts := mapstr.M{}
+	for _, entity := range affectedEntities {
+		fields := mapstr.M{
+			"entity_value": awssdk.ToString(entity.EntityValue),
+			"status_code":  string(entity.StatusCode),
+		}
+		if entity.EntityArn != nil {
+			fields["entity_arn"] = *entity.EntityArn
+		}
+		if entity.AwsAccountId != nil {
+			fields["aws_account_id"] = *entity.AwsAccountId
+		}
+		if entity.LastUpdatedTime != nil {
+			fields["last_updated_time"] = *entity.LastUpdatedTime
+		}
+		entities = append(entities, fields)
+
+		count, _ := statusCounts[string(entity.StatusCode)].(int)
+		statusCounts[string(entity.StatusCode)] = count + 1
+	}
+	_, _ = event.MetricSetFields.Put("affected_entities_count", len(entities))
+	if len(entities) > 0 {
+		_, _ = event.MetricSetFields.Put("affected_entities", entities)
+		_, _ = event.MetricSetFields.Put("affected_entities_by_status", statusCounts)
+	}
+	return event
+}
+
+func generateEventID(eventID string) string {
+	h := sha256.New()
+	h.Write([]byte(eventID))
+	prefix := hex.EncodeToString(h.Sum(nil))
+	return prefix[:20]
+}
@@ -0,0 +1,171 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+//go:build !integration
+// +build !integration
+
+package health
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/health"
+	healthtypes "github.com/aws/aws-sdk-go-v2/service/health/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws"
+	"github.com/elastic/elastic-agent-libs/logp"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+var (
+	ec2EventArn = "arn:aws:health:us-east-1::event/EC2/AWS_EC2_OPERATIONAL_ISSUE/1"
+	iamEventArn = "arn:aws:health:global::event/IAM/AWS_IAM_OPERATIONAL_ISSUE/2"
+	rdsEventArn = "arn:aws:health:eu-west-1::event/RDS/AWS_RDS_MAINTENANCE_SCHEDULED/3"
+	lastUpdated = time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+)
+
+// MockHealthClient struct is used for unit tests.
+type MockHealthClient struct {
+	eventsInput *health.DescribeEventsInput
+}
+
+func (m *MockHealthClient) DescribeEvents(_ context.Context, params *health.DescribeEventsInput, _ ...func(*health.Options)) (*health.DescribeEventsOutput, error) {
+	m.eventsInput = params
+	return &health.DescribeEventsOutput{
+		Events: []healthtypes.Event{
+			{
+				Arn:               awssdk.String(ec2EventArn),
+				Service:           awssdk.String("EC2"),
+				EventTypeCode:     awssdk.String("AWS_EC2_OPERATIONAL_ISSUE"),
+				EventTypeCategory: healthtypes.EventTypeCategoryIssue,
+				EventScopeCode:    healthtypes.EventScopeCodeAccountSpecific,
+				StatusCode:        healthtypes.EventStatusCodeOpen,
+				Region:            awssdk.String("us-east-1"),
+				LastUpdatedTime:   &lastUpdated,
+			},
+			{
+				Arn:               awssdk.String(iamEventArn),
+				Service:           awssdk.String("IAM"),
+				EventTypeCode:     awssdk.String("AWS_IAM_OPERATIONAL_ISSUE"),
+				EventTypeCategory: healthtypes.EventTypeCategoryIssue,
+				EventScopeCode:    healthtypes.EventScopeCodePublic,
+				StatusCode:        healthtypes.EventStatusCodeOpen,
+				Region:            awssdk.String("global"),
+			},
+			{
+				Arn:     awssdk.String(rdsEventArn),
+				Service: awssdk.String("RDS"),
+				Region:  awssdk.String("eu-west-1"),
+			},
+		},
+	}, nil
+}
+
+func (m *MockHealthClient) DescribeAffectedEntities(_ context.Context, params *health.DescribeAffectedEntitiesInput, _ ...func(*health.Options)) (*health.DescribeAffectedEntitiesOutput, error) {
+	return &health.DescribeAffectedEntitiesOutput{
+		Entities: []healthtypes.AffectedEntity{
+			{
+				EventArn:     awssdk.String(ec2EventArn),
+				EntityValue:  awssdk.String("i-1"),
+				AwsAccountId: awssdk.String("123456789012"),
+				StatusCode:   healthtypes.EntityStatusCodeImpaired,
+			},
+			{
+				EventArn:     awssdk.String(ec2EventArn),
+				EntityValue:  awssdk.String("i-2"),
+				AwsAccountId: awssdk.String("123456789012"),
+				StatusCode:   healthtypes.EntityStatusCodeImpaired,
+			},
+			{
+				EventArn:    awssdk.String(ec2EventArn),
+				EntityValue: awssdk.String("i-3"),
+				StatusCode:  healthtypes.EntityStatusCodeUnimpaired,
+			},
+		},
+	}, nil
+}
+
+func (m *MockHealthClient) DescribeEventDetails(_ context.Context, params *health.DescribeEventDetailsInput, _ ...func(*health.Options)) (*health.DescribeEventDetailsOutput, error) {
+	output := &health.DescribeEventDetailsOutput{}
+	for _, arn := range params.EventArns {
+		output.SuccessfulSet = append(output.SuccessfulSet, healthtypes.EventDetails{
+			Event:            &healthtypes.Event{Arn: awssdk.String(arn)},
+			EventDescription: &healthtypes.EventDescription{LatestDescription: awssdk.String("description of " + arn)},
+		})
+	}
+	return output, nil
+}
+
+func TestConfigValidate(t *testing.T) {
+	assert.NoError(t, Config{EventStatusCodes: []string{"open"}, EventTypeCategories: []string{"issue"}}.Validate())
+	assert.Error(t, Config{EventStatusCodes: []string{"resolved"}}.Validate())
+	assert.Error(t, Config{EventTypeCategories: []string{"Issue"}}.Validate())
+}
+
+func TestGetHealthEvents(t *testing.T) {
+	m := MetricSet{
+		logger:       logp.NewLogger("test"),
+		HealthConfig: Config{EventStatusCodes: defaultEventStatusCodes, EventTypeCategories: defaultEventTypeCategories},
+	}
+	m.MetricSet = &aws.MetricSet{RegionsList: []string{"us-east-1"}, AccountID: "123456789012"}
+
+	svc := &MockHealthClient{}
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	events, err := m.getHealthEvents(context.Background(), svc, now)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []healthtypes.EventStatusCode{"open", "upcoming"}, svc.eventsInput.Filter.EventStatusCodes)
+	assert.Equal(t, []healthtypes.EventTypeCategory{"issue", "scheduledChange"}, svc.eventsInput.Filter.EventTypeCategories)
+
+	// the event of eu-west-1 is not in the configured regions
+	assert.Equal(t, 2, len(events))
+
+	ec2Event := events[0]
+	assert.Equal(t, lastUpdated, ec2Event.Timestamp)
+	region, _ := ec2Event.RootFields.GetValue("cloud.region")
+	assert.Equal(t, "us-east-1", region)
+	assert.Equal(t, "EC2", ec2Event.MetricSetFields["service"])
+	assert.Equal(t, "description of "+ec2EventArn, ec2Event.MetricSetFields["description"])
+	assert.Equal(t, 3, ec2Event.MetricSetFields["affected_entities_count"])
+	byStatus, _ := ec2Event.MetricSetFields.GetValue("affected_entities_by_status")
+	assert.Equal(t, mapstr.M{"IMPAIRED": 2, "UNIMPAIRED": 1}, byStatus)
+
+	iamEvent := events[1]
+	assert.Equal(t, now, iamEvent.Timestamp)
+	_, err = iamEvent.RootFields.GetValue("cloud.region")
+	assert.Error(t, err)
+	assert.Equal(t, "global", iamEvent.MetricSetFields["region"])
+	assert.Equal(t, 0, iamEvent.MetricSetFields["affected_entities_count"])
+	assert.NotEqual(t, ec2Event.ID, iamEvent.ID)
+}
+
+func TestCreateEventID(t *testing.T) {
+	healthEvent := healthtypes.Event{
+		Arn:             awssdk.String(ec2EventArn),
+		Region:          awssdk.String("us-east-1"),
+		LastUpdatedTime: awssdk.Time(lastUpdated),
+	}
+	newMetricSet := func(accountID string) *MetricSet {
+		return &MetricSet{
+			MetricSet: &aws.MetricSet{AccountID: accountID},
+			logger:    logp.NewLogger("test"),
+		}
+	}
+	now := time.Now()
+
+	event := newMetricSet("123456789012").createEvent(healthEvent, "", nil, now)
+	assert.Equal(t, event.ID, newMetricSet("123456789012").createEvent(healthEvent, "", nil, now).ID)
+
+	// The same event seen from another account is another document
+	otherAccount := newMetricSet("210987654321").createEvent(healthEvent, "", nil, now)
+	assert.NotEqual(t, event.ID, otherAccount.ID)
+
+	otherRegion := healthEvent
+	otherRegion.Region = awssdk.String("eu-west-1")
+	assert.NotEqual(t, event.ID, newMetricSet("123456789012").createEvent(otherRegion, "", nil, now).ID)
+}
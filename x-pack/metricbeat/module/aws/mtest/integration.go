@@ -22,6 +22,19 @@ func GetConfigForTest(t *testing.T, metricSetName string, period string) map[str
 		defaultRegion = "us-west-1"
 	}
 
+	// Run the tests against an emulator like LocalStack when
+	// $AWS_EMULATOR_ENDPOINT is set, it doesn't need valid credentials
+	if emulatorEndpoint := os.Getenv("AWS_EMULATOR_ENDPOINT"); emulatorEndpoint != "" {
+		return map[string]interface{}{
+			"module":            "aws",
+			"period":            period,
+			"metricsets":        []string{metricSetName},
+			"emulator_endpoint": emulatorEndpoint,
+			"regions":           []string{defaultRegion},
+			"latency":           "5m",
+		}
+	}
+
 	config := map[string]interface{}{}
 	if !okAccessKeyID || accessKeyID == "" {
 		t.Fatal("$AWS_ACCESS_KEY_ID not set or set to empty")
@@ -0,0 +1,174 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	organizationstypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/elastic-agent-libs/logp"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+// organizationMetadataTTL is how long the organization metadata of an account
+// is cached.
+const organizationMetadataTTL = 24 * time.Hour
+
+type organizationsClient interface {
+	ListParents(ctx context.Context, params *organizations.ListParentsInput, optFns ...func(*organizations.Options)) (*organizations.ListParentsOutput, error)
+	DescribeOrganizationalUnit(ctx context.Context, params *organizations.DescribeOrganizationalUnitInput, optFns ...func(*organizations.Options)) (*organizations.DescribeOrganizationalUnitOutput, error)
+	ListTagsForResource(ctx context.Context, params *organizations.ListTagsForResourceInput, optFns ...func(*organizations.Options)) (*organizations.ListTagsForResourceOutput, error)
+}
+
+// OrganizationMetadata is the metadata of an account in AWS Organizations.
+type OrganizationMetadata struct {
+	// UnitID and UnitName are the organizational unit of the account, empty
+	// when the account is at the root of the organization.
+	UnitID   string
+	UnitName string
+	// Tags are the tags of the account.
+	Tags map[string]string
+}
+
+// Fields returns the metadata as the fields under aws.organization.
+func (o OrganizationMetadata) Fields() mapstr.M {
+	fields := mapstr.M{}
+	if o.UnitID != "" {
+		unit := mapstr.M{"id": o.UnitID}
+		if o.UnitName != "" {
+			unit["name"] = o.UnitName
+		}
+		fields["unit"] = unit
+	}
+	if len(o.Tags) > 0 {
+		tags := mapstr.M{}
+		for key, value := range o.Tags {
+			tags[key] = value
+		}
+		fields["account"] = mapstr.M{"tags": tags}
+	}
+	return fields
+}
+
+type cachedOrganizationMetadata struct {
+	metadata   OrganizationMetadata
+	expiration time.Time
+}
+
+// OrganizationResolver resolves and caches the organizational unit and tags of
+// the accounts of an organization.
+type OrganizationResolver struct {
+	svc organizationsClient
+
+	mutex    sync.Mutex
+	accounts map[string]cachedOrganizationMetadata
+}
+
+// NewOrganizationResolver creates a resolver of the organization metadata of
+// the accounts, the credentials must be allowed to read the organization.
+func NewOrganizationResolver(svc organizationsClient) *OrganizationResolver {
+	return &OrganizationResolver{
+		svc:      svc,
+		accounts: map[string]cachedOrganizationMetadata{},
+	}
+}
+
+// Resolve returns the organization metadata of the given account ID. Failed
+// lookups are cached as well, so they are not retried for every event.
+func (r *OrganizationResolver) Resolve(ctx context.Context, accountID string) (OrganizationMetadata, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	now := time.Now()
+	if cached, ok := r.accounts[accountID]; ok && now.Before(cached.expiration) {
+		return cached.metadata, nil
+	}
+
+	metadata, err := r.lookup(ctx, accountID)
+	r.accounts[accountID] = cachedOrganizationMetadata{metadata: metadata, expiration: now.Add(organizationMetadataTTL)}
+	return metadata, err
+}
+
+func (r *OrganizationResolver) lookup(ctx context.Context, accountID string) (OrganizationMetadata, error) {
+	var metadata OrganizationMetadata
+
+	parents, err := r.svc.ListParents(ctx, &organizations.ListParentsInput{ChildId: &accountID})
+	if err != nil {
+		return metadata, fmt.Errorf("failed to list the parents of account %s: %w", accountID, err)
+	}
+	// Accounts have a single parent, the root or an organizational unit
+	for _, parent := range parents.Parents {
+		if parent.Type != organizationstypes.ParentTypeOrganizationalUnit || parent.Id == nil {
+			continue
+		}
+		metadata.UnitID = *parent.Id
+		unit, err := r.svc.DescribeOrganizationalUnit(ctx, &organizations.DescribeOrganizationalUnitInput{OrganizationalUnitId: parent.Id})
+		if err != nil {
+			return metadata, fmt.Errorf("failed to describe organizational unit %s: %w", *parent.Id, err)
+		}
+		if unit.OrganizationalUnit != nil && unit.OrganizationalUnit.Name != nil {
+			metadata.UnitName = *unit.OrganizationalUnit.Name
+		}
+	}
+
+	input := &organizations.ListTagsForResourceInput{ResourceId: &accountID}
+	for {
+		output, err := r.svc.ListTagsForResource(ctx, input)
+		if err != nil {
+			return metadata, fmt.Errorf("failed to list the tags of account %s: %w", accountID, err)
+		}
+		for _, tag := range output.Tags {
+			if tag.Key == nil || tag.Value == nil {
+				continue
+			}
+			if metadata.Tags == nil {
+				metadata.Tags = map[string]string{}
+			}
+			metadata.Tags[*tag.Key] = *tag.Value
+		}
+		if output.NextToken == nil || *output.NextToken == "" {
+			return metadata, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+// organizationReporter adds the organization metadata of the account in
+// cloud.account.id to the events before reporting them.
+type organizationReporter struct {
+	mb.ReporterV2
+	ctx      context.Context
+	resolver *OrganizationResolver
+	logger   *logp.Logger
+}
+
+func (r organizationReporter) Event(event mb.Event) bool {
+	if accountID, err := event.RootFields.GetValue("cloud.account.id"); err == nil {
+		metadata, err := r.resolver.Resolve(r.ctx, fmt.Sprint(accountID))
+		if err != nil {
+			r.logger.Warnf("could not resolve organization metadata: %s", err)
+		}
+		if fields := metadata.Fields(); len(fields) > 0 {
+			_, _ = event.RootFields.Put("aws.organization", fields)
+		}
+	}
+	return r.ReporterV2.Event(event)
+}
+
+// OrganizationReporter returns a reporter adding the aws.organization.* fields
+// to the events when include_organization_metadata is set, or report itself
+// otherwise. The organization metadata is resolved with ctx.
+func (m *MetricSet) OrganizationReporter(ctx context.Context, report mb.ReporterV2) mb.ReporterV2 {
+	if m.Organization == nil {
+		return report
+	}
+	return organizationReporter{ReporterV2: report, ctx: ctx, resolver: m.Organization, logger: m.Logger()}
+}
@@ -0,0 +1,154 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+//go:build !integration
+// +build !integration
+
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	organizationstypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	mbtest "github.com/elastic/beats/v7/metricbeat/mb/testing"
+	"github.com/elastic/elastic-agent-libs/logp"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+// mockOrganizationsMetadataClient has the account 111111111111 in the
+// organizational unit ou-abcd-12345678 and the account 222222222222 at the
+// root of the organization.
+type mockOrganizationsMetadataClient struct {
+	calls int
+}
+
+func (m *mockOrganizationsMetadataClient) ListParents(ctx context.Context, params *organizations.ListParentsInput, optFns ...func(*organizations.Options)) (*organizations.ListParentsOutput, error) {
+	m.calls++
+	switch *params.ChildId {
+	case "111111111111":
+		return &organizations.ListParentsOutput{Parents: []organizationstypes.Parent{{
+			Id:   awssdk.String("ou-abcd-12345678"),
+			Type: organizationstypes.ParentTypeOrganizationalUnit,
+		}}}, nil
+	case "222222222222":
+		return &organizations.ListParentsOutput{Parents: []organizationstypes.Parent{{
+			Id:   awssdk.String("r-abcd"),
+			Type: organizationstypes.ParentTypeRoot,
+		}}}, nil
+	default:
+		return nil, errors.New("ChildNotFoundException")
+	}
+}
+
+func (m *mockOrganizationsMetadataClient) DescribeOrganizationalUnit(ctx context.Context, params *organizations.DescribeOrganizationalUnitInput, optFns ...func(*organizations.Options)) (*organizations.DescribeOrganizationalUnitOutput, error) {
+	return &organizations.DescribeOrganizationalUnitOutput{
+		OrganizationalUnit: &organizationstypes.OrganizationalUnit{
+			Id:   params.OrganizationalUnitId,
+			Name: awssdk.String("Payments"),
+		},
+	}, nil
+}
+
+func (m *mockOrganizationsMetadataClient) ListTagsForResource(ctx context.Context, params *organizations.ListTagsForResourceInput, optFns ...func(*organizations.Options)) (*organizations.ListTagsForResourceOutput, error) {
+	if *params.ResourceId != "111111111111" {
+		return &organizations.ListTagsForResourceOutput{}, nil
+	}
+	if params.NextToken == nil {
+		return &organizations.ListTagsForResourceOutput{
+			Tags:      []organizationstypes.Tag{{Key: awssdk.String("BusinessUnit"), Value: awssdk.String("finance")}},
+			NextToken: awssdk.String("page-2"),
+		}, nil
+	}
+	return &organizations.ListTagsForResourceOutput{
+		Tags: []organizationstypes.Tag{{Key: awssdk.String("CostCenter"), Value: awssdk.String("1234")}},
+	}, nil
+}
+
+func TestOrganizationResolver(t *testing.T) {
+	svc := &mockOrganizationsMetadataClient{}
+	resolver := NewOrganizationResolver(svc)
+
+	metadata, err := resolver.Resolve(context.Background(), "111111111111")
+	assert.NoError(t, err)
+	assert.Equal(t, OrganizationMetadata{
+		UnitID:   "ou-abcd-12345678",
+		UnitName: "Payments",
+		Tags:     map[string]string{"BusinessUnit": "finance", "CostCenter": "1234"},
+	}, metadata)
+
+	// Accounts at the root have no organizational unit
+	metadata, err = resolver.Resolve(context.Background(), "222222222222")
+	assert.NoError(t, err)
+	assert.Equal(t, OrganizationMetadata{}, metadata)
+
+	_, err = resolver.Resolve(context.Background(), "999999999999")
+	assert.Error(t, err)
+
+	// Resolved accounts and failures are cached
+	_, err = resolver.Resolve(context.Background(), "111111111111")
+	assert.NoError(t, err)
+	_, err = resolver.Resolve(context.Background(), "999999999999")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, svc.calls)
+
+	// Expired entries are resolved again
+	resolver.accounts["111111111111"] = cachedOrganizationMetadata{expiration: time.Now().Add(-time.Minute)}
+	_, err = resolver.Resolve(context.Background(), "111111111111")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, svc.calls)
+}
+
+func TestOrganizationMetadataFields(t *testing.T) {
+	assert.Equal(t, mapstr.M{}, OrganizationMetadata{}.Fields())
+	assert.Equal(t, mapstr.M{
+		"unit": mapstr.M{"id": "ou-abcd-12345678", "name": "Payments"},
+		"account": mapstr.M{
+			"tags": mapstr.M{"BusinessUnit": "finance"},
+		},
+	}, OrganizationMetadata{
+		UnitID:   "ou-abcd-12345678",
+		UnitName: "Payments",
+		Tags:     map[string]string{"BusinessUnit": "finance"},
+	}.Fields())
+}
+
+func TestOrganizationReporter(t *testing.T) {
+	capturing := &mbtest.CapturingReporterV2{}
+	report := organizationReporter{
+		ReporterV2: capturing,
+		ctx:        context.Background(),
+		resolver:   NewOrganizationResolver(&mockOrganizationsMetadataClient{}),
+		logger:     logp.NewLogger("test"),
+	}
+
+	report.Event(mb.Event{RootFields: mapstr.M{"cloud": mapstr.M{"account": mapstr.M{"id": "111111111111"}}}})
+	report.Event(mb.Event{RootFields: mapstr.M{"cloud": mapstr.M{"account": mapstr.M{"id": "222222222222"}}}})
+	report.Event(mb.Event{MetricSetFields: mapstr.M{"foo": "bar"}})
+
+	events := capturing.GetEvents()
+	assert.Len(t, events, 3)
+
+	unitName, err := events[0].RootFields.GetValue("aws.organization.unit.name")
+	assert.NoError(t, err)
+	assert.Equal(t, "Payments", unitName)
+	tag, err := events[0].RootFields.GetValue("aws.organization.account.tags.CostCenter")
+	assert.NoError(t, err)
+	assert.Equal(t, "1234", tag)
+
+	_, err = events[1].RootFields.GetValue("aws.organization")
+	assert.Error(t, err)
+	assert.Nil(t, events[2].RootFields)
+
+	// Without include_organization_metadata the reporter is unchanged
+	m := &MetricSet{}
+	assert.Equal(t, mb.ReporterV2(capturing), m.OrganizationReporter(context.Background(), capturing))
+}
@@ -0,0 +1,99 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+	"golang.org/x/time/rate"
+)
+
+// RateLimitConfig holds the limits of the AWS API requests of all the aws
+// metricsets of an account, per service.
+type RateLimitConfig struct {
+	RequestsPerSecond float64            `config:"requests_per_second" validate:"min=0"`
+	Burst             int                `config:"burst" validate:"min=0"`
+	Services          map[string]float64 `config:"services"`
+}
+
+// Enabled returns true if the requests of any service are limited.
+func (c RateLimitConfig) Enabled() bool {
+	return c.RequestsPerSecond > 0 || len(c.Services) > 0
+}
+
+// requestsPerSecond returns the limit of a service, the limit configured for
+// the service ID, like cloudwatch or ec2, or the default limit.
+func (c RateLimitConfig) requestsPerSecond(serviceID string) float64 {
+	for service, limit := range c.Services {
+		if strings.EqualFold(service, serviceID) {
+			return limit
+		}
+	}
+	return c.RequestsPerSecond
+}
+
+// rateLimiters holds the token buckets shared by all the aws metricsets,
+// by account and service.
+var rateLimiters = &rateLimiterRegistry{limiters: map[rateLimiterKey]*rate.Limiter{}}
+
+type rateLimiterKey struct {
+	accountID string
+	serviceID string
+}
+
+type rateLimiterRegistry struct {
+	sync.Mutex
+	limiters map[rateLimiterKey]*rate.Limiter
+}
+
+// get returns the limiter of the requests of a service in an account, nil when
+// they are not limited. The limiter is created with the limits of the first
+// metricset sending requests to the service.
+func (r *rateLimiterRegistry) get(config RateLimitConfig, accountID string, serviceID string) *rate.Limiter {
+	requestsPerSecond := config.requestsPerSecond(serviceID)
+	if requestsPerSecond <= 0 {
+		return nil
+	}
+
+	key := rateLimiterKey{accountID: accountID, serviceID: strings.ToLower(serviceID)}
+	r.Lock()
+	defer r.Unlock()
+	limiter, ok := r.limiters[key]
+	if !ok {
+		burst := config.Burst
+		if burst <= 0 {
+			burst = 1
+		}
+		limiter = rate.NewLimiter(rate.Limit(requestsPerSecond), burst)
+		r.limiters[key] = limiter
+	}
+	return limiter
+}
+
+// addRateLimiter makes the AWS clients created from awsConfig wait for the
+// token bucket of their service in the account before sending a request.
+func addRateLimiter(awsConfig *awssdk.Config, config RateLimitConfig, accountID string) {
+	awsConfig.APIOptions = append(awsConfig.APIOptions, func(stack *middleware.Stack) error {
+		return stack.Initialize.Add(rateLimitMiddleware(rateLimiters, config, accountID), middleware.After)
+	})
+}
+
+func rateLimitMiddleware(registry *rateLimiterRegistry, config RateLimitConfig, accountID string) middleware.InitializeMiddleware {
+	return middleware.InitializeMiddlewareFunc("RateLimit", func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+		limiter := registry.get(config, accountID, awsmiddleware.GetServiceID(ctx))
+		if limiter != nil {
+			if err := limiter.Wait(ctx); err != nil {
+				return middleware.InitializeOutput{}, middleware.Metadata{}, fmt.Errorf("waiting for the %s rate limit failed: %w", awsmiddleware.GetServiceID(ctx), err)
+			}
+		}
+		return next.HandleInitialize(ctx, in)
+	})
+}
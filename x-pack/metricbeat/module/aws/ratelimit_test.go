@@ -0,0 +1,35 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+//go:build !integration
+// +build !integration
+
+package aws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+)
+
+func TestRateLimiterRegistry(t *testing.T) {
+	registry := &rateLimiterRegistry{limiters: map[rateLimiterKey]*rate.Limiter{}}
+	config := RateLimitConfig{
+		RequestsPerSecond: 10,
+		Burst:             5,
+		Services:          map[string]float64{"cloudwatch": 20, "ec2": 0},
+	}
+
+	// Metricsets of the same account share the limiter of a service
+	limiter := registry.get(config, "123456789012", "CloudWatch")
+	assert.Equal(t, rate.Limit(20), limiter.Limit())
+	assert.Equal(t, 5, limiter.Burst())
+	assert.Same(t, limiter, registry.get(config, "123456789012", "CloudWatch"))
+	assert.NotSame(t, limiter, registry.get(config, "210987654321", "CloudWatch"))
+
+	assert.Equal(t, rate.Limit(10), registry.get(config, "123456789012", "RDS").Limit())
+	assert.Nil(t, registry.get(config, "123456789012", "EC2"))
+	assert.Nil(t, registry.get(RateLimitConfig{}, "123456789012", "SQS"))
+}
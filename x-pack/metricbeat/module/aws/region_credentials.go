@@ -0,0 +1,125 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package aws
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	awscommon "github.com/elastic/beats/v7/x-pack/libbeat/common/aws"
+)
+
+// RegionCredentials are the credentials of some regions in region_credentials.
+// They replace the credentials of the module in these regions: the access keys,
+// credential profile and credential process replace the ones of the module
+// when one of them is set, and role_arn and web_identity_token_file replace
+// the ones of the module when they are set.
+type RegionCredentials struct {
+	AccessKeyID          string `config:"access_key_id"`
+	SecretAccessKey      string `config:"secret_access_key"`
+	SessionToken         string `config:"session_token"`
+	ProfileName          string `config:"credential_profile_name"`
+	SharedCredentialFile string `config:"shared_credential_file"`
+	CredentialProcess    string `config:"credential_process"`
+	RoleArn              string `config:"role_arn"`
+	WebIdentityTokenFile string `config:"web_identity_token_file"`
+}
+
+// apply returns the AWS config of the module with the credentials of the regions.
+func (c RegionCredentials) apply(awsConfig awscommon.ConfigAWS) awscommon.ConfigAWS {
+	if c.AccessKeyID != "" || c.ProfileName != "" || c.CredentialProcess != "" {
+		awsConfig.AccessKeyID = c.AccessKeyID
+		awsConfig.SecretAccessKey = c.SecretAccessKey
+		awsConfig.SessionToken = c.SessionToken
+		awsConfig.ProfileName = c.ProfileName
+		awsConfig.CredentialProcess = c.CredentialProcess
+	}
+	if c.SharedCredentialFile != "" {
+		awsConfig.SharedCredentialFile = c.SharedCredentialFile
+	}
+	if c.RoleArn != "" {
+		awsConfig.RoleArn = c.RoleArn
+	}
+	if c.WebIdentityTokenFile != "" {
+		awsConfig.WebIdentityTokenFile = c.WebIdentityTokenFile
+	}
+	return awsConfig
+}
+
+// regionCredentialsGroup are the regions of region_credentials with the same
+// credentials.
+type regionCredentialsGroup struct {
+	credentials RegionCredentials
+	regions     []string
+}
+
+// groupRegionCredentials groups the regions of region_credentials by
+// credentials, sorted by region.
+func groupRegionCredentials(regionCredentials map[string]RegionCredentials) []regionCredentialsGroup {
+	regionsByCredentials := map[RegionCredentials][]string{}
+	for region, credentials := range regionCredentials {
+		regionsByCredentials[credentials] = append(regionsByCredentials[credentials], region)
+	}
+
+	groups := make([]regionCredentialsGroup, 0, len(regionsByCredentials))
+	for credentials, regions := range regionsByCredentials {
+		sort.Strings(regions)
+		groups = append(groups, regionCredentialsGroup{credentials: credentials, regions: regions})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].regions[0] < groups[j].regions[0]
+	})
+	return groups
+}
+
+// newRegionCredentialsMetricSets creates a metricset collecting the regions of
+// each credentials of region_credentials, and one collecting the other regions
+// with the credentials of the module. The metricset of the module is skipped
+// when its regions are all in region_credentials.
+func newRegionCredentialsMetricSets(base mb.BaseMetricSet, config Config) (*MetricSet, error) {
+	var profiles []*MetricSet
+
+	moduleConfig := config
+	moduleConfig.ExcludeRegions = append([]string{}, config.ExcludeRegions...)
+	moduleRegionsLeft := len(config.Regions) == 0 || hasRegionPatterns(config.Regions)
+	for _, region := range config.Regions {
+		if _, ok := config.RegionCredentials[region]; !ok {
+			moduleRegionsLeft = true
+		}
+	}
+	for region := range config.RegionCredentials {
+		moduleConfig.ExcludeRegions = append(moduleConfig.ExcludeRegions, region)
+	}
+	if moduleRegionsLeft {
+		metricSet, err := newMetricSet(base, moduleConfig)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, metricSet)
+	}
+
+	for _, group := range groupRegionCredentials(config.RegionCredentials) {
+		groupConfig := config
+		groupConfig.Regions = group.regions
+		groupConfig.ExcludeRegions = nil
+		groupConfig.AWSConfig = group.credentials.apply(config.AWSConfig)
+		// Get the credentials from the STS endpoint of the regions, that
+		// can be in another partition than the module regions
+		groupConfig.AWSConfig.DefaultRegion = group.regions[0]
+		metricSet, err := newMetricSet(base, groupConfig)
+		if err != nil {
+			releaseAWSConfigs(profiles)
+			return nil, fmt.Errorf("failed to create metricset for region_credentials of %s: %w", strings.Join(group.regions, ", "), err)
+		}
+		metricSet.ProfileName = "region_credentials:" + strings.Join(group.regions, ",")
+		metricSet.credentialRegions = group.regions
+		profiles = append(profiles, metricSet)
+	}
+
+	profiles[0].Profiles = profiles
+	return profiles[0], nil
+}
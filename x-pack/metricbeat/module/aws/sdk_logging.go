@@ -0,0 +1,45 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package aws
+
+import (
+	"fmt"
+	"regexp"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/smithy-go/logging"
+
+	"github.com/elastic/elastic-agent-libs/logp"
+)
+
+// sdkLogMode logs the requests, responses, retries and signing of the AWS SDK
+// clients. The bodies are not logged as they can contain credentials, for
+// example in the responses of STS.
+const sdkLogMode = awssdk.LogRequest | awssdk.LogResponse | awssdk.LogRetries | awssdk.LogSigning
+
+// sdkSecretHeaders matches the values of the headers carrying credentials in
+// the logged requests and responses.
+var sdkSecretHeaders = regexp.MustCompile(`(?im)^((?:Authorization|X-Amz-Security-Token|X-Amz-Credential|X-Amz-Signature):[ \t]*)[^\r\n]*`)
+
+// enableSDKLogging routes the request level logs of the AWS SDK clients
+// created from awsConfig to logp, with the credentials redacted.
+func enableSDKLogging(awsConfig *awssdk.Config) {
+	logger := logp.NewLogger("aws.sdk")
+	awsConfig.ClientLogMode = sdkLogMode
+	awsConfig.Logger = logging.LoggerFunc(func(classification logging.Classification, format string, v ...interface{}) {
+		message := redactSDKLog(fmt.Sprintf(format, v...))
+		if classification == logging.Warn {
+			logger.Warn(message)
+			return
+		}
+		logger.Debug(message)
+	})
+}
+
+// redactSDKLog replaces the values of the headers carrying credentials in a
+// log message of the AWS SDK.
+func redactSDKLog(message string) string {
+	return sdkSecretHeaders.ReplaceAllString(message, "${1}[REDACTED]")
+}
@@ -0,0 +1,32 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+//go:build !integration
+// +build !integration
+
+package aws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactSDKLog(t *testing.T) {
+	message := "Request\n" +
+		"POST / HTTP/1.1\r\n" +
+		"Host: monitoring.us-east-1.amazonaws.com\r\n" +
+		"Authorization: AWS4-HMAC-SHA256 Credential=EXAMPLEKEY/20221016/us-east-1/monitoring/aws4_request, SignedHeaders=host;x-amz-date, Signature=abcdef\r\n" +
+		"X-Amz-Date: 20221016T120000Z\r\n" +
+		"X-Amz-Security-Token: fake-session-token\r\n"
+
+	expected := "Request\n" +
+		"POST / HTTP/1.1\r\n" +
+		"Host: monitoring.us-east-1.amazonaws.com\r\n" +
+		"Authorization: [REDACTED]\r\n" +
+		"X-Amz-Date: 20221016T120000Z\r\n" +
+		"X-Amz-Security-Token: [REDACTED]\r\n"
+
+	assert.Equal(t, expected, redactSDKLog(message))
+}
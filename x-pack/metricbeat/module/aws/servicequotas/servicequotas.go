@@ -0,0 +1,251 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package servicequotas
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/servicequotas"
+	servicequotastypes "github.com/aws/aws-sdk-go-v2/service/servicequotas/types"
+
+	"github.com/elastic/beats/v7/metricbeat/mb"
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws"
+	"github.com/elastic/elastic-agent-libs/logp"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+var (
+	metricsetName = "servicequotas"
+
+	// The quotas of these services are collected when no service code is configured.
+	defaultServiceCodes = []string{"ebs", "ec2", "elasticloadbalancing", "lambda", "vpc"}
+)
+
+// init registers the MetricSet with the central registry as soon as the program
+// starts. The New function will be called later to instantiate an instance of
+// the MetricSet for each host defined in the module's configuration. After the
+// MetricSet has been created then Fetch will begin to be called periodically.
+func init() {
+	mb.Registry.MustAddMetricSet(aws.ModuleName, metricsetName, New)
+}
+
+// MetricSet holds any configuration or state information. It must implement
+// the mb.MetricSet interface. And this is best achieved by embedding
+// mb.BaseMetricSet because it implements all of the required mb.MetricSet
+// interface methods except for Fetch.
+type MetricSet struct {
+	*aws.MetricSet
+	logger              *logp.Logger
+	ServiceQuotasConfig Config `config:"servicequotas_config"`
+}
+
+// Config holds a configuration specific for servicequotas metricset.
+type Config struct {
+	ServiceCodes []string `config:"service_codes"`
+}
+
+// New creates a new instance of the MetricSet. New is responsible for unpacking
+// any MetricSet specific configuration options if there are any.
+func New(base mb.BaseMetricSet) (mb.MetricSet, error) {
+	logger := logp.NewLogger(metricsetName)
+	metricSet, err := aws.NewMetricSet(base)
+	if err != nil {
+		return nil, fmt.Errorf("error creating aws metricset: %w", err)
+	}
+
+	config := struct {
+		ServiceQuotasConfig Config `config:"servicequotas_config"`
+	}{
+		ServiceQuotasConfig: Config{ServiceCodes: defaultServiceCodes},
+	}
+
+	err = base.Module().UnpackConfig(&config)
+	if err != nil {
+		return nil, fmt.Errorf("error unpack raw module config using UnpackConfig: %w", err)
+	}
+
+	logger.Debugf("servicequotas config = %s", config)
+
+	return &MetricSet{
+		MetricSet:           metricSet,
+		logger:              logger,
+		ServiceQuotasConfig: config.ServiceQuotasConfig,
+	}, nil
+}
+
+// Fetch methods implements the data gathering and data conversion to the right
+// format. It publishes the event which is then forwarded to the output. In case
+// of an error set the Error field of mb.Event or simply call report.Error().
+func (m *MetricSet) Fetch(ctx context.Context, report mb.ReporterV2) error {
+	return m.MetricSet.ForEachProfile(func(profile *aws.MetricSet) error {
+		profileMetricSet := *m
+		profileMetricSet.MetricSet = profile
+		return profileMetricSet.fetch(ctx, profile.OrganizationReporter(ctx, report))
+	})
+}
+
+// fetch collects the data with the credentials of m.MetricSet.
+func (m *MetricSet) fetch(ctx context.Context, report mb.ReporterV2) error {
+	// Usage metrics are published every minute, the latest value of the last
+	// period is used.
+	startTime, endTime := aws.GetStartTimeEndTime(time.Now(), m.Period, m.Latency)
+
+	for _, regionName := range m.MetricSet.RegionsList {
+		awsBeatsConfig := m.MetricSet.AwsConfig.Copy()
+		awsBeatsConfig.Region = regionName
+
+		svcServiceQuotas := servicequotas.NewFromConfig(awsBeatsConfig)
+		svcCloudwatch := cloudwatch.NewFromConfig(awsBeatsConfig)
+
+		quotas := m.getServiceQuotas(ctx, svcServiceQuotas, regionName)
+		events := m.createEvents(ctx, svcCloudwatch, quotas, regionName, startTime, endTime)
+		for _, event := range events {
+			if reported := report.Event(event); !reported {
+				m.Logger().Debug("Fetch interrupted, failed to emit event")
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// getServiceQuotas returns the applied quotas of the configured services in a region.
+func (m *MetricSet) getServiceQuotas(ctx context.Context, svc servicequotas.ListServiceQuotasAPIClient, regionName string) []servicequotastypes.ServiceQuota {
+	var quotas []servicequotastypes.ServiceQuota
+	for _, serviceCode := range m.ServiceQuotasConfig.ServiceCodes {
+		input := &servicequotas.ListServiceQuotasInput{ServiceCode: awssdk.String(serviceCode)}
+		paginator := servicequotas.NewListServiceQuotasPaginator(svc, input)
+		for paginator.HasMorePages() {
+			output, err := paginator.NextPage(ctx)
+			if err != nil {
+				m.logger.Warnf("error ListServiceQuotas of service %s in region %s: %s", serviceCode, regionName, err)
+				break
+			}
+			quotas = append(quotas, output.Quotas...)
+		}
+	}
+	return quotas
+}
+
+// createEvents returns an event for each quota, with the usage and utilization
+// of the quotas that have a usage metric in CloudWatch.
+func (m *MetricSet) createEvents(ctx context.Context, svcCloudwatch cloudwatch.GetMetricDataAPIClient, quotas []servicequotastypes.ServiceQuota, regionName string, startTime time.Time, endTime time.Time) []mb.Event {
+	usages := m.getUsages(ctx, svcCloudwatch, quotas, regionName, startTime, endTime)
+
+	events := make([]mb.Event, 0, len(quotas))
+	for i, quota := range quotas {
+		event := aws.InitEvent(regionName, m.AccountName, m.AccountID, endTime)
+		event.MetricSetFields = mapstr.M{
+			"service": mapstr.M{
+				"code": awssdk.ToString(quota.ServiceCode),
+				"name": awssdk.ToString(quota.ServiceName),
+			},
+			"quota": mapstr.M{
+				"code": awssdk.ToString(quota.QuotaCode),
+				"name": awssdk.ToString(quota.QuotaName),
+			},
+			"adjustable": quota.Adjustable,
+			"global":     quota.GlobalQuota,
+		}
+		if quota.Unit != nil {
+			_, _ = event.MetricSetFields.Put("unit", *quota.Unit)
+		}
+		if quota.Value == nil {
+			events = append(events, event)
+			continue
+		}
+		_, _ = event.MetricSetFields.Put("value", *quota.Value)
+
+		usage, ok := usages[i]
+		if ok {
+			_, _ = event.MetricSetFields.Put("usage.value", usage)
+			_, _ = event.MetricSetFields.Put("usage.metric_name", awssdk.ToString(quota.UsageMetric.MetricName))
+			if *quota.Value > 0 {
+				_, _ = event.MetricSetFields.Put("utilization.pct", usage / *quota.Value)
+			}
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// getUsages returns the latest value of the usage metric of each quota in the
+// time range, by quota index.
+func (m *MetricSet) getUsages(ctx context.Context, svcCloudwatch cloudwatch.GetMetricDataAPIClient, quotas []servicequotastypes.ServiceQuota, regionName string, startTime time.Time, endTime time.Time) map[int]float64 {
+	usages := map[int]float64{}
+
+	var metricDataQueries []types.MetricDataQuery
+	for i, quota := range quotas {
+		if quota.UsageMetric == nil || quota.UsageMetric.MetricName == nil {
+			continue
+		}
+		metricDataQueries = append(metricDataQueries, createMetricDataQuery(*quota.UsageMetric, i, m.Period))
+	}
+	if len(metricDataQueries) == 0 {
+		return usages
+	}
+
+	metricDataResults, err := aws.GetMetricDataResults(ctx, metricDataQueries, svcCloudwatch, startTime, endTime)
+	if err != nil {
+		m.logger.Warnf("aws GetMetricDataResults failed with %s, skipping usage of region %s", err, regionName)
+		return usages
+	}
+
+	for _, result := range metricDataResults {
+		if len(result.Values) == 0 {
+			continue
+		}
+		index, err := strconv.Atoi(awssdk.ToString(result.Id)[len(metricsetName):])
+		if err != nil {
+			continue
+		}
+		// Results are ordered by descending timestamp, the first one is the latest
+		usages[index] = result.Values[0]
+	}
+	return usages
+}
+
+func createMetricDataQuery(usageMetric servicequotastypes.MetricInfo, index int, period time.Duration) types.MetricDataQuery {
+	// Usage metrics recommend a statistic, Maximum for most of them
+	statistic := "Maximum"
+	if usageMetric.MetricStatisticRecommendation != nil {
+		statistic = *usageMetric.MetricStatisticRecommendation
+	}
+	periodInSeconds := int32(period.Seconds())
+	id := metricsetName + strconv.Itoa(index)
+
+	names := make([]string, 0, len(usageMetric.MetricDimensions))
+	for name := range usageMetric.MetricDimensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	dimensions := make([]types.Dimension, 0, len(names))
+	for _, name := range names {
+		dimensions = append(dimensions, types.Dimension{
+			Name:  awssdk.String(name),
+			Value: awssdk.String(usageMetric.MetricDimensions[name]),
+		})
+	}
+
+	return types.MetricDataQuery{
+		Id: &id,
+		MetricStat: &types.MetricStat{
+			Period: &periodInSeconds,
+			Stat:   &statistic,
+			Metric: &types.Metric{
+				Namespace:  usageMetric.MetricNamespace,
+				MetricName: usageMetric.MetricName,
+				Dimensions: dimensions,
+			},
+		},
+	}
+}
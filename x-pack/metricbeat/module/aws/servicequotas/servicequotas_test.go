@@ -0,0 +1,138 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+//go:build !integration
+// +build !integration
+
+package servicequotas
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cloudwatchtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/servicequotas"
+	servicequotastypes "github.com/aws/aws-sdk-go-v2/service/servicequotas/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/beats/v7/x-pack/metricbeat/module/aws"
+	"github.com/elastic/elastic-agent-libs/logp"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+var vCPUQuota = servicequotastypes.ServiceQuota{
+	ServiceCode: awssdk.String("ec2"),
+	ServiceName: awssdk.String("Amazon Elastic Compute Cloud (Amazon EC2)"),
+	QuotaCode:   awssdk.String("L-1216C47A"),
+	QuotaName:   awssdk.String("Running On-Demand Standard (A, C, D, H, I, M, R, T, Z) instances"),
+	Adjustable:  true,
+	Unit:        awssdk.String("None"),
+	Value:       awssdk.Float64(64),
+	UsageMetric: &servicequotastypes.MetricInfo{
+		MetricNamespace: awssdk.String("AWS/Usage"),
+		MetricName:      awssdk.String("ResourceCount"),
+		MetricDimensions: map[string]string{
+			"Service":  "EC2",
+			"Type":     "Resource",
+			"Resource": "vCPU",
+			"Class":    "Standard/OnDemand",
+		},
+		MetricStatisticRecommendation: awssdk.String("Maximum"),
+	},
+}
+
+var eipQuota = servicequotastypes.ServiceQuota{
+	ServiceCode: awssdk.String("ec2"),
+	QuotaCode:   awssdk.String("L-0263D0A3"),
+	QuotaName:   awssdk.String("EC2-VPC Elastic IPs"),
+	Value:       awssdk.Float64(5),
+}
+
+// MockServiceQuotasClient struct is used for unit tests.
+type MockServiceQuotasClient struct{}
+
+// ListServiceQuotas implements servicequotas.ListServiceQuotasAPIClient interface
+func (m *MockServiceQuotasClient) ListServiceQuotas(_ context.Context, params *servicequotas.ListServiceQuotasInput, _ ...func(*servicequotas.Options)) (*servicequotas.ListServiceQuotasOutput, error) {
+	if *params.ServiceCode != "ec2" {
+		return &servicequotas.ListServiceQuotasOutput{}, nil
+	}
+	return &servicequotas.ListServiceQuotasOutput{
+		Quotas: []servicequotastypes.ServiceQuota{vCPUQuota, eipQuota},
+	}, nil
+}
+
+// MockCloudWatchClient struct is used for unit tests.
+type MockCloudWatchClient struct {
+	queries []cloudwatchtypes.MetricDataQuery
+}
+
+// GetMetricData implements cloudwatch.GetMetricDataAPIClient interface
+func (m *MockCloudWatchClient) GetMetricData(_ context.Context, params *cloudwatch.GetMetricDataInput, _ ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+	m.queries = append(m.queries, params.MetricDataQueries...)
+	now := time.Now()
+	return &cloudwatch.GetMetricDataOutput{
+		MetricDataResults: []cloudwatchtypes.MetricDataResult{
+			{
+				Id:         params.MetricDataQueries[0].Id,
+				Values:     []float64{48, 32},
+				Timestamps: []time.Time{now, now.Add(-time.Minute)},
+			},
+		},
+	}, nil
+}
+
+func TestCreateEvents(t *testing.T) {
+	m := MetricSet{
+		logger:              logp.NewLogger("test"),
+		ServiceQuotasConfig: Config{ServiceCodes: []string{"ec2", "vpc"}},
+	}
+	m.MetricSet = &aws.MetricSet{Period: 5 * time.Minute}
+
+	quotas := m.getServiceQuotas(context.Background(), &MockServiceQuotasClient{}, "us-east-1")
+	assert.Equal(t, 2, len(quotas))
+
+	svcCloudwatch := &MockCloudWatchClient{}
+	endTime := time.Now()
+	events := m.createEvents(context.Background(), svcCloudwatch, quotas, "us-east-1", endTime.Add(-5*time.Minute), endTime)
+	assert.Equal(t, 2, len(events))
+
+	// only the quota with a usage metric is queried
+	assert.Equal(t, 1, len(svcCloudwatch.queries))
+	query := svcCloudwatch.queries[0]
+	assert.Equal(t, "ResourceCount", *query.MetricStat.Metric.MetricName)
+	assert.Equal(t, "Maximum", *query.MetricStat.Stat)
+	assert.Equal(t, int32(300), *query.MetricStat.Period)
+	assert.Equal(t, "Class", *query.MetricStat.Metric.Dimensions[0].Name)
+	assert.Equal(t, "Standard/OnDemand", *query.MetricStat.Metric.Dimensions[0].Value)
+
+	assert.Equal(t, mapstr.M{
+		"service": mapstr.M{
+			"code": "ec2",
+			"name": "Amazon Elastic Compute Cloud (Amazon EC2)",
+		},
+		"quota": mapstr.M{
+			"code": "L-1216C47A",
+			"name": "Running On-Demand Standard (A, C, D, H, I, M, R, T, Z) instances",
+		},
+		"adjustable": true,
+		"global":     false,
+		"unit":       "None",
+		"value":      float64(64),
+		"usage": mapstr.M{
+			"value":       float64(48),
+			"metric_name": "ResourceCount",
+		},
+		"utilization": mapstr.M{
+			"pct": 0.75,
+		},
+	}, events[0].MetricSetFields)
+
+	_, err := events[1].MetricSetFields.GetValue("utilization.pct")
+	assert.Error(t, err)
+	value, _ := events[1].MetricSetFields.GetValue("value")
+	assert.Equal(t, float64(5), value)
+}
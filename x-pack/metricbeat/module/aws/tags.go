@@ -0,0 +1,135 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package aws
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
+	resourcegroupstaggingapitypes "github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/metricbeat/mb"
+)
+
+// TagSourceResourceGroupsTaggingAPI is the source of the resource tags
+// retrieved with the Resource Groups Tagging API.
+const TagSourceResourceGroupsTaggingAPI = "resourcegroupstaggingapi"
+
+// tagsCache holds the resource tag mappings retrieved by all the aws
+// metricsets, so metricsets collecting the same resource types in the same
+// account and region share them.
+var tagsCache = &resourceTagsCache{entries: map[resourceTagsKey]resourceTagsEntry{}}
+
+type resourceTagsKey struct {
+	source       string
+	accountID    string
+	regionName   string
+	resourceType string
+}
+
+type resourceTagsEntry struct {
+	resourceTagMap map[string][]resourcegroupstaggingapitypes.Tag
+	expiration     time.Time
+}
+
+type resourceTagsCache struct {
+	sync.Mutex
+	entries map[resourceTagsKey]resourceTagsEntry
+}
+
+// TagService retrieves and caches the tags of the resources of the aws
+// metricsets. A nil TagService retrieves the tags without caching them.
+type TagService struct {
+	accountID string
+	ttl       time.Duration
+	cache     *resourceTagsCache
+	now       func() time.Time
+}
+
+// NewTagService returns a TagService for the resources of an account, caching
+// the resource tag mappings for ttl.
+func NewTagService(accountID string, ttl time.Duration) *TagService {
+	return &TagService{
+		accountID: accountID,
+		ttl:       ttl,
+		cache:     tagsCache,
+		now:       time.Now,
+	}
+}
+
+// Get returns the resource tag mapping of a resource type in a region from a
+// tag source. The mapping is retrieved with fetch when it is not cached or
+// expired. The returned mapping is shared, use FilterResourcesByTags instead
+// of modifying it.
+func (s *TagService) Get(source string, regionName string, resourceType string, fetch func() (map[string][]resourcegroupstaggingapitypes.Tag, error)) (map[string][]resourcegroupstaggingapitypes.Tag, error) {
+	if s == nil {
+		return fetch()
+	}
+
+	key := resourceTagsKey{source: source, accountID: s.accountID, regionName: regionName, resourceType: resourceType}
+	now := s.now()
+
+	s.cache.Lock()
+	entry, ok := s.cache.entries[key]
+	s.cache.Unlock()
+	if ok && now.Before(entry.expiration) {
+		return entry.resourceTagMap, nil
+	}
+
+	resourceTagMap, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	if s.ttl > 0 {
+		s.cache.Lock()
+		s.cache.entries[key] = resourceTagsEntry{resourceTagMap: resourceTagMap, expiration: now.Add(s.ttl)}
+		s.cache.Unlock()
+	}
+	return resourceTagMap, nil
+}
+
+// GetResourcesTags returns the resource tag mapping of a resource type in a
+// region from the Resource Groups Tagging API.
+func (s *TagService) GetResourcesTags(ctx context.Context, svc resourcegroupstaggingapi.GetResourcesAPIClient, regionName string, resourceType string) (map[string][]resourcegroupstaggingapitypes.Tag, error) {
+	return s.Get(TagSourceResourceGroupsTaggingAPI, regionName, resourceType, func() (map[string][]resourcegroupstaggingapitypes.Tag, error) {
+		return GetResourcesTags(ctx, svc, []string{resourceType})
+	})
+}
+
+// FilterResourcesByTags returns the resources of a resource tag mapping whose
+// tags match tagsFilter.
+func FilterResourcesByTags(resourceTagMap map[string][]resourcegroupstaggingapitypes.Tag, tagsFilter []Tag) map[string][]resourcegroupstaggingapitypes.Tag {
+	filtered := make(map[string][]resourcegroupstaggingapitypes.Tag, len(resourceTagMap))
+	for identifier, tags := range resourceTagMap {
+		if CheckTagFiltersExist(tagsFilter, tags) {
+			filtered[identifier] = tags
+		}
+	}
+	return filtered
+}
+
+// FindTags returns the tags of a resource in a resource tag mapping. Resources
+// identified by an ARN are also looked up with the resource ID of the ARN.
+func FindTags(resourceTagMap map[string][]resourcegroupstaggingapitypes.Tag, identifier string) []resourcegroupstaggingapitypes.Tag {
+	tags := resourceTagMap[identifier]
+	if len(tags) == 0 && strings.HasPrefix(identifier, "arn:") {
+		if resourceID, err := FindShortIdentifierFromARN(identifier); err == nil {
+			tags = resourceTagMap[resourceID]
+		}
+	}
+	return tags
+}
+
+// AddTags adds the tags to the event as aws.tags.*. Dots in tag keys are
+// replaced with underscores, tag values are not dedotted.
+func AddTags(event mb.Event, tags []resourcegroupstaggingapitypes.Tag) {
+	for _, tag := range tags {
+		_, _ = event.RootFields.Put("aws.tags."+common.DeDot(*tag.Key), *tag.Value)
+	}
+}
@@ -0,0 +1,384 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package aws
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	resourcegroupstaggingapitypes "github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
+)
+
+// TagsExpression is a compiled tags_filter_expression. It selects resources by
+// their tags with comparisons combined with AND, OR, NOT and parentheses:
+//
+//	Owner = "team-a*" AND (Environment = prod OR Environment = staging) AND NOT exists(Temporary)
+//
+// Key = value and key != value compare the value of a tag, with * and ? globs
+// in the value. exists(key) checks that a resource has the tag.
+type TagsExpression struct {
+	source string
+	root   tagsExpressionNode
+}
+
+type tagsExpressionNode interface {
+	match(tags map[string]string) bool
+}
+
+type andNode []tagsExpressionNode
+
+func (n andNode) match(tags map[string]string) bool {
+	for _, node := range n {
+		if !node.match(tags) {
+			return false
+		}
+	}
+	return true
+}
+
+type orNode []tagsExpressionNode
+
+func (n orNode) match(tags map[string]string) bool {
+	for _, node := range n {
+		if node.match(tags) {
+			return true
+		}
+	}
+	return false
+}
+
+type notNode struct {
+	node tagsExpressionNode
+}
+
+func (n notNode) match(tags map[string]string) bool {
+	return !n.node.match(tags)
+}
+
+type existsNode struct {
+	key string
+}
+
+func (n existsNode) match(tags map[string]string) bool {
+	_, ok := tags[n.key]
+	return ok
+}
+
+// compareNode compares the value of a tag. Resources without the tag don't
+// match, with = and with !=.
+type compareNode struct {
+	key    string
+	value  *regexp.Regexp
+	negate bool
+}
+
+func (n compareNode) match(tags map[string]string) bool {
+	value, ok := tags[n.key]
+	if !ok {
+		return false
+	}
+	return n.value.MatchString(value) != n.negate
+}
+
+// ParseTagsExpression compiles a tags filter expression.
+func ParseTagsExpression(expression string) (*TagsExpression, error) {
+	tokens, err := tokenizeTagsExpression(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tags filter expression %q: %w", expression, err)
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("invalid tags filter expression %q: expression is empty", expression)
+	}
+
+	p := &tagsExpressionParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err == nil && p.pos < len(p.tokens) {
+		err = fmt.Errorf("unexpected %s", p.tokens[p.pos])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid tags filter expression %q: %w", expression, err)
+	}
+	return &TagsExpression{source: expression, root: root}, nil
+}
+
+// String returns the source of the expression.
+func (e *TagsExpression) String() string {
+	if e == nil {
+		return ""
+	}
+	return e.source
+}
+
+// Match returns true if the tags of a resource match the expression. All the
+// resources match a nil expression.
+func (e *TagsExpression) Match(tags []resourcegroupstaggingapitypes.Tag) bool {
+	if e == nil {
+		return true
+	}
+	tagsByKey := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		if tag.Key != nil && tag.Value != nil {
+			tagsByKey[*tag.Key] = *tag.Value
+		}
+	}
+	return e.root.match(tagsByKey)
+}
+
+// FilterResources returns the resources of a resource tag mapping whose tags
+// match the expression. A nil expression returns the mapping unchanged.
+func (e *TagsExpression) FilterResources(resourceTagMap map[string][]resourcegroupstaggingapitypes.Tag) map[string][]resourcegroupstaggingapitypes.Tag {
+	if e == nil {
+		return resourceTagMap
+	}
+	filtered := make(map[string][]resourcegroupstaggingapitypes.Tag, len(resourceTagMap))
+	for identifier, tags := range resourceTagMap {
+		if e.Match(tags) {
+			filtered[identifier] = tags
+		}
+	}
+	return filtered
+}
+
+type tagsExpressionTokenKind int
+
+const (
+	tokenWord tagsExpressionTokenKind = iota
+	tokenString
+	tokenLeftParen
+	tokenRightParen
+	tokenEqual
+	tokenNotEqual
+)
+
+type tagsExpressionToken struct {
+	kind  tagsExpressionTokenKind
+	value string
+}
+
+func (t tagsExpressionToken) String() string {
+	if t.kind == tokenString {
+		return fmt.Sprintf("%q", t.value)
+	}
+	return fmt.Sprintf("'%s'", t.value)
+}
+
+// isKeyword returns true if the token is the given operator keyword, keywords
+// are case insensitive and quoted strings are never keywords.
+func (t tagsExpressionToken) isKeyword(keyword string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.value, keyword)
+}
+
+func tokenizeTagsExpression(expression string) ([]tagsExpressionToken, error) {
+	var tokens []tagsExpressionToken
+	runes := []rune(expression)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, tagsExpressionToken{kind: tokenLeftParen, value: "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, tagsExpressionToken{kind: tokenRightParen, value: ")"})
+			i++
+		case r == '=':
+			tokens = append(tokens, tagsExpressionToken{kind: tokenEqual, value: "="})
+			i++
+		case r == '!' && i+1 < len(runes) && runes[i+1] == '=':
+			tokens = append(tokens, tagsExpressionToken{kind: tokenNotEqual, value: "!="})
+			i += 2
+		case r == '"' || r == '\'':
+			var value strings.Builder
+			j := i + 1
+			for ; j < len(runes) && runes[j] != r; j++ {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					j++
+				}
+				value.WriteRune(runes[j])
+			}
+			if j == len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %d", i)
+			}
+			tokens = append(tokens, tagsExpressionToken{kind: tokenString, value: value.String()})
+			i = j + 1
+		default:
+			j := i
+			for ; j < len(runes) && !unicode.IsSpace(runes[j]) && !strings.ContainsRune(`()="'`, runes[j]) &&
+				!(runes[j] == '!' && j+1 < len(runes) && runes[j+1] == '='); j++ {
+			}
+			tokens = append(tokens, tagsExpressionToken{kind: tokenWord, value: string(runes[i:j])})
+			i = j
+		}
+	}
+	return tokens, nil
+}
+
+type tagsExpressionParser struct {
+	tokens []tagsExpressionToken
+	pos    int
+}
+
+func (p *tagsExpressionParser) peek() (tagsExpressionToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return tagsExpressionToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *tagsExpressionParser) next() (tagsExpressionToken, error) {
+	token, ok := p.peek()
+	if !ok {
+		return token, fmt.Errorf("unexpected end of expression")
+	}
+	p.pos++
+	return token, nil
+}
+
+func (p *tagsExpressionParser) parseOr() (tagsExpressionNode, error) {
+	node, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	nodes := orNode{node}
+	for token, ok := p.peek(); ok && token.isKeyword("OR"); token, ok = p.peek() {
+		p.pos++
+		node, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return nodes, nil
+}
+
+func (p *tagsExpressionParser) parseAnd() (tagsExpressionNode, error) {
+	node, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	nodes := andNode{node}
+	for token, ok := p.peek(); ok && token.isKeyword("AND"); token, ok = p.peek() {
+		p.pos++
+		node, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return nodes, nil
+}
+
+func (p *tagsExpressionParser) parseNot() (tagsExpressionNode, error) {
+	if token, ok := p.peek(); ok && token.isKeyword("NOT") {
+		p.pos++
+		node, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{node: node}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *tagsExpressionParser) parsePrimary() (tagsExpressionNode, error) {
+	token, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case token.kind == tokenLeftParen:
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokenRightParen); err != nil {
+			return nil, err
+		}
+		return node, nil
+	case token.isKeyword("exists"):
+		if next, ok := p.peek(); ok && next.kind == tokenLeftParen {
+			p.pos++
+			key, err := p.operand()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(tokenRightParen); err != nil {
+				return nil, err
+			}
+			return existsNode{key: key}, nil
+		}
+	case token.kind != tokenWord && token.kind != tokenString:
+		return nil, fmt.Errorf("unexpected %s", token)
+	}
+
+	operator, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if operator.kind != tokenEqual && operator.kind != tokenNotEqual {
+		return nil, fmt.Errorf("expected = or != after tag key %s, got %s", token, operator)
+	}
+	value, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	return compareNode{
+		key:    token.value,
+		value:  compileTagValueGlob(value),
+		negate: operator.kind == tokenNotEqual,
+	}, nil
+}
+
+// operand returns the next token as a tag key or value.
+func (p *tagsExpressionParser) operand() (string, error) {
+	token, err := p.next()
+	if err != nil {
+		return "", err
+	}
+	if token.kind != tokenWord && token.kind != tokenString {
+		return "", fmt.Errorf("unexpected %s", token)
+	}
+	return token.value, nil
+}
+
+func (p *tagsExpressionParser) expect(kind tagsExpressionTokenKind) error {
+	token, err := p.next()
+	if err != nil {
+		return err
+	}
+	if token.kind != kind {
+		return fmt.Errorf("unexpected %s", token)
+	}
+	return nil
+}
+
+// compileTagValueGlob compiles a tag value with * and ? globs to a regular
+// expression matching the whole value.
+func compileTagValueGlob(glob string) *regexp.Regexp {
+	var pattern strings.Builder
+	pattern.WriteString("(?s)^")
+	for _, r := range glob {
+		switch r {
+		case '*':
+			pattern.WriteString(".*")
+		case '?':
+			pattern.WriteString(".")
+		default:
+			pattern.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	pattern.WriteString("$")
+	return regexp.MustCompile(pattern.String())
+}
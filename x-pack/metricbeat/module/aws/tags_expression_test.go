@@ -0,0 +1,192 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+//go:build !integration
+// +build !integration
+
+package aws
+
+import (
+	"testing"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	resourcegroupstaggingapitypes "github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func resourceTags(keyValues ...string) []resourcegroupstaggingapitypes.Tag {
+	var tags []resourcegroupstaggingapitypes.Tag
+	for i := 0; i+1 < len(keyValues); i += 2 {
+		tags = append(tags, resourcegroupstaggingapitypes.Tag{
+			Key:   awssdk.String(keyValues[i]),
+			Value: awssdk.String(keyValues[i+1]),
+		})
+	}
+	return tags
+}
+
+func TestTagsExpressionMatch(t *testing.T) {
+	cases := []struct {
+		title      string
+		expression string
+		tags       []resourcegroupstaggingapitypes.Tag
+		expected   bool
+	}{
+		{
+			"equal",
+			"Environment = prod",
+			resourceTags("Environment", "prod"),
+			true,
+		},
+		{
+			"equal with a different value",
+			"Environment = prod",
+			resourceTags("Environment", "staging"),
+			false,
+		},
+		{
+			"equal without the tag",
+			"Environment = prod",
+			resourceTags("Owner", "team-a"),
+			false,
+		},
+		{
+			"not equal",
+			"Environment != prod",
+			resourceTags("Environment", "staging"),
+			true,
+		},
+		{
+			"not equal without the tag",
+			"Environment != prod",
+			resourceTags("Owner", "team-a"),
+			false,
+		},
+		{
+			"glob",
+			`Owner = "team-a*"`,
+			resourceTags("Owner", "team-a-platform"),
+			true,
+		},
+		{
+			"glob matching the whole value",
+			"Owner = team-?",
+			resourceTags("Owner", "team-ab"),
+			false,
+		},
+		{
+			"regular expression characters are literal",
+			"Owner = team.a",
+			resourceTags("Owner", "team-a"),
+			false,
+		},
+		{
+			"quoted key and value with spaces",
+			`"cost center" = 'Research and development'`,
+			resourceTags("cost center", "Research and development"),
+			true,
+		},
+		{
+			"exists",
+			"exists(Temporary)",
+			resourceTags("Temporary", ""),
+			true,
+		},
+		{
+			"not exists",
+			"NOT exists(Temporary)",
+			resourceTags("Temporary", "true"),
+			false,
+		},
+		{
+			"and",
+			"Environment = prod AND Owner = team-a",
+			resourceTags("Environment", "prod", "Owner", "team-b"),
+			false,
+		},
+		{
+			"or",
+			"Environment = prod OR Environment = staging",
+			resourceTags("Environment", "staging"),
+			true,
+		},
+		{
+			"and has precedence over or",
+			"Owner = team-a OR Owner = team-b AND Environment = prod",
+			resourceTags("Owner", "team-a", "Environment", "dev"),
+			true,
+		},
+		{
+			"parentheses",
+			"(Owner = team-a OR Owner = team-b) AND Environment = prod",
+			resourceTags("Owner", "team-a", "Environment", "dev"),
+			false,
+		},
+		{
+			"case insensitive keywords",
+			"Owner = team-a and not exists(Temporary)",
+			resourceTags("Owner", "team-a"),
+			true,
+		},
+		{
+			"complex expression",
+			`Owner = "team-a*" AND (Environment = prod OR Environment = staging) AND NOT exists(Temporary)`,
+			resourceTags("Owner", "team-a-web", "Environment", "staging"),
+			true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			expression, err := ParseTagsExpression(c.expression)
+			require.NoError(t, err)
+			assert.Equal(t, c.expression, expression.String())
+			assert.Equal(t, c.expected, expression.Match(c.tags))
+		})
+	}
+}
+
+func TestParseTagsExpressionErrors(t *testing.T) {
+	cases := []string{
+		"",
+		"   ",
+		"Environment",
+		"Environment =",
+		"Environment prod",
+		"= prod",
+		"(Environment = prod",
+		"Environment = prod)",
+		"Environment = prod AND",
+		"Environment = prod Owner = team-a",
+		"NOT",
+		"exists(Temporary",
+		"exists()",
+		`Owner = "team-a`,
+	}
+
+	for _, c := range cases {
+		t.Run(c, func(t *testing.T) {
+			_, err := ParseTagsExpression(c)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestTagsExpressionFilterResources(t *testing.T) {
+	resourceTagMap := map[string][]resourcegroupstaggingapitypes.Tag{
+		"i-1": resourceTags("Owner", "team-a", "Environment", "prod"),
+		"i-2": resourceTags("Owner", "team-a", "Environment", "prod", "Temporary", "true"),
+		"i-3": resourceTags("Owner", "team-b", "Environment", "prod"),
+	}
+
+	var nilExpression *TagsExpression
+	assert.Equal(t, resourceTagMap, nilExpression.FilterResources(resourceTagMap))
+	assert.True(t, nilExpression.Match(nil))
+
+	expression, err := ParseTagsExpression("Owner = team-a AND NOT exists(Temporary)")
+	require.NoError(t, err)
+	filtered := expression.FilterResources(resourceTagMap)
+	assert.Equal(t, map[string][]resourcegroupstaggingapitypes.Tag{"i-1": resourceTagMap["i-1"]}, filtered)
+}
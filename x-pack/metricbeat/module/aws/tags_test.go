@@ -0,0 +1,87 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+//go:build !integration
+// +build !integration
+
+package aws
+
+import (
+	"testing"
+	"time"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	resourcegroupstaggingapitypes "github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagServiceCache(t *testing.T) {
+	now := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	cache := &resourceTagsCache{entries: map[resourceTagsKey]resourceTagsEntry{}}
+	newService := func() *TagService {
+		s := NewTagService("123456789012", 5*time.Minute)
+		s.cache = cache
+		s.now = func() time.Time { return now }
+		return s
+	}
+
+	calls := 0
+	fetch := func() (map[string][]resourcegroupstaggingapitypes.Tag, error) {
+		calls++
+		return map[string][]resourcegroupstaggingapitypes.Tag{
+			"i-1": {{Key: awssdk.String("Name"), Value: awssdk.String("web")}},
+		}, nil
+	}
+
+	// A second metricset of the same account shares the cached tags
+	_, err := newService().Get(TagSourceResourceGroupsTaggingAPI, "us-east-1", "ec2:instance", fetch)
+	assert.NoError(t, err)
+	resourceTagMap, err := newService().Get(TagSourceResourceGroupsTaggingAPI, "us-east-1", "ec2:instance", fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, "web", *resourceTagMap["i-1"][0].Value)
+
+	_, err = newService().Get(TagSourceResourceGroupsTaggingAPI, "eu-west-1", "ec2:instance", fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	now = now.Add(5 * time.Minute)
+	_, err = newService().Get(TagSourceResourceGroupsTaggingAPI, "us-east-1", "ec2:instance", fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+
+	// A nil TagService doesn't cache
+	var nilService *TagService
+	_, err = nilService.Get(TagSourceResourceGroupsTaggingAPI, "us-east-1", "ec2:instance", fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, calls)
+}
+
+func TestFilterResourcesByTags(t *testing.T) {
+	resourceTagMap := map[string][]resourcegroupstaggingapitypes.Tag{
+		"i-1": {{Key: awssdk.String("Organization"), Value: awssdk.String("Engineering")}},
+		"i-2": {{Key: awssdk.String("Organization"), Value: awssdk.String("Sales")}},
+	}
+
+	filtered := FilterResourcesByTags(resourceTagMap, []Tag{{Key: "Organization", Value: []string{"Engineering"}}})
+	assert.Equal(t, 1, len(filtered))
+	assert.Contains(t, filtered, "i-1")
+	// The filtered mapping is a copy, as the mapping may be cached
+	assert.Equal(t, 2, len(resourceTagMap))
+}
+
+func TestFindTags(t *testing.T) {
+	resourceTagMap := map[string][]resourcegroupstaggingapitypes.Tag{
+		"abc": {{Key: awssdk.String("Name"), Value: awssdk.String("shield")}},
+	}
+
+	tags := FindTags(resourceTagMap, "arn:aws:shield::123456789012:protection/abc")
+	assert.Equal(t, 1, len(tags))
+	assert.Empty(t, FindTags(resourceTagMap, "i-1"))
+
+	event := InitEvent("us-east-1", "", "", time.Now())
+	AddTags(event, []resourcegroupstaggingapitypes.Tag{{Key: awssdk.String("app.name"), Value: awssdk.String("web.api")}})
+	value, _ := event.RootFields.GetValue("aws.tags.app_name")
+	assert.Equal(t, "web.api", value)
+}
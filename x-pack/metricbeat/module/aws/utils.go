@@ -6,14 +6,17 @@ package aws
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/configservice"
 	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
 	resourcegroupstaggingapitypes "github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
 )
@@ -36,12 +39,46 @@ func GetStartTimeEndTime(now time.Time, period time.Duration, latency time.Durat
 	return startTime, endTime
 }
 
+// GetStartTimeEndTimeInLocation works like GetStartTimeEndTime, but aligns the
+// interval to the period in the given location instead of UTC, so for example
+// daily periods start at midnight of that location.
+func GetStartTimeEndTimeInLocation(now time.Time, period time.Duration, latency time.Duration, location *time.Location) (time.Time, time.Time) {
+	_, offset := now.In(location).Zone()
+	shift := time.Duration(offset) * time.Second
+	startTime, endTime := GetStartTimeEndTime(now.Add(shift), period, latency)
+	return startTime.Add(-shift), endTime.Add(-shift)
+}
+
+// ParseLabelTimezone parses a timezone in the format of GetMetricData
+// LabelOptions, + or - followed by four digits for hours and minutes (e.g. +0130),
+// into a fixed location.
+func ParseLabelTimezone(timezone string) (*time.Location, error) {
+	if len(timezone) != 5 || (timezone[0] != '+' && timezone[0] != '-') {
+		return nil, fmt.Errorf("invalid timezone %s, expected + or - followed by four digits", timezone)
+	}
+
+	hours, err := strconv.Atoi(timezone[1:3])
+	if err != nil || hours > 23 {
+		return nil, fmt.Errorf("invalid hours in timezone %s", timezone)
+	}
+	minutes, err := strconv.Atoi(timezone[3:5])
+	if err != nil || minutes > 59 {
+		return nil, fmt.Errorf("invalid minutes in timezone %s", timezone)
+	}
+
+	offset := hours*3600 + minutes*60
+	if timezone[0] == '-' {
+		offset = -offset
+	}
+	return time.FixedZone(timezone, offset), nil
+}
+
 // GetListMetricsOutput function gets listMetrics results from cloudwatch ~~per namespace~~ for each region.
 // ListMetrics Cloudwatch API is used to list the specified metrics. The returned metrics can be used with GetMetricData
 // to obtain statistical data.
 // Note: We are not using Dimensions and MetricName in ListMetricsInput because with that we will have to make one ListMetrics
 // API call per metric name and set of dimensions. This will increase API cost.
-func GetListMetricsOutput(namespace string, regionName string, period time.Duration, svcCloudwatch cloudwatch.ListMetricsAPIClient) ([]types.Metric, error) {
+func GetListMetricsOutput(ctx context.Context, namespace string, regionName string, period time.Duration, svcCloudwatch cloudwatch.ListMetricsAPIClient) ([]types.Metric, error) {
 	var metricsTotal []types.Metric
 	var nextToken *string
 
@@ -64,7 +101,7 @@ func GetListMetricsOutput(namespace string, regionName string, period time.Durat
 
 	// List metrics of a given namespace for each region
 	for paginator.HasMorePages() {
-		page, err := paginator.NextPage(context.TODO())
+		page, err := paginator.NextPage(ctx)
 		if err != nil {
 			return metricsTotal, fmt.Errorf("error ListMetrics with Paginator, skipping region %s: %w", regionName, err)
 		}
@@ -74,9 +111,35 @@ func GetListMetricsOutput(namespace string, regionName string, period time.Durat
 	return metricsTotal, nil
 }
 
+// MaxMetricDataQueriesPerRequest is the maximum number of MetricDataQueries in a
+// GetMetricData API call.
+const MaxMetricDataQueriesPerRequest = 500
+
+// GetMetricDataOptions are optional parameters of the GetMetricData API calls.
+type GetMetricDataOptions struct {
+	// LabelOptions is passed to each GetMetricData API call.
+	LabelOptions *types.LabelOptions
+	// MaxDatapoints is the maximum number of data points in each page of
+	// results. The default of the API is used when it is zero.
+	MaxDatapoints int32
+	// QueriesPerRequest is the number of MetricDataQueries in each GetMetricData
+	// API call, at most MaxMetricDataQueriesPerRequest, which is also the default.
+	QueriesPerRequest int
+}
+
 // GetMetricDataResults function uses MetricDataQueries to get metric data output.
-func GetMetricDataResults(metricDataQueries []types.MetricDataQuery, svc cloudwatch.GetMetricDataAPIClient, startTime time.Time, endTime time.Time) ([]types.MetricDataResult, error) {
-	maxNumberOfMetricsRetrieved := 500
+func GetMetricDataResults(ctx context.Context, metricDataQueries []types.MetricDataQuery, svc cloudwatch.GetMetricDataAPIClient, startTime time.Time, endTime time.Time) ([]types.MetricDataResult, error) {
+	return GetMetricDataResultsWithOptions(ctx, metricDataQueries, svc, startTime, endTime, GetMetricDataOptions{})
+}
+
+// GetMetricDataResultsWithOptions function uses MetricDataQueries to get metric data output,
+// with the given options. All the pages of results are consumed, and the results of
+// a query split across pages are merged into one result.
+func GetMetricDataResultsWithOptions(ctx context.Context, metricDataQueries []types.MetricDataQuery, svc cloudwatch.GetMetricDataAPIClient, startTime time.Time, endTime time.Time, options GetMetricDataOptions) ([]types.MetricDataResult, error) {
+	maxNumberOfMetricsRetrieved := MaxMetricDataQueriesPerRequest
+	if options.QueriesPerRequest > 0 && options.QueriesPerRequest < maxNumberOfMetricsRetrieved {
+		maxNumberOfMetricsRetrieved = options.QueriesPerRequest
+	}
 	getMetricDataOutput := &cloudwatch.GetMetricDataOutput{NextToken: nil}
 
 	// Split metricDataQueries into smaller slices that length no longer than 500.
@@ -92,22 +155,54 @@ func GetMetricDataResults(metricDataQueries []types.MetricDataQuery, svc cloudwa
 			StartTime:         &startTime,
 			EndTime:           &endTime,
 			MetricDataQueries: metricDataQueriesPartial,
+			LabelOptions:      options.LabelOptions,
+		}
+		if options.MaxDatapoints > 0 {
+			getMetricDataInput.MaxDatapoints = &options.MaxDatapoints
 		}
 
 		paginator := cloudwatch.NewGetMetricDataPaginator(svc, getMetricDataInput)
 		var err error
 		var page *cloudwatch.GetMetricDataOutput
+		var pageResults []types.MetricDataResult
 		for paginator.HasMorePages() {
-			if page, err = paginator.NextPage(context.TODO()); err != nil {
+			if page, err = paginator.NextPage(ctx); err != nil {
 				return getMetricDataOutput.MetricDataResults, fmt.Errorf("error GetMetricData with Paginator: %w", err)
 			}
-			getMetricDataOutput.MetricDataResults = append(getMetricDataOutput.MetricDataResults, page.MetricDataResults...)
+			pageResults = append(pageResults, page.MetricDataResults...)
 		}
+		getMetricDataOutput.MetricDataResults = append(getMetricDataOutput.MetricDataResults, mergeMetricDataResults(pageResults)...)
 	}
 
 	return getMetricDataOutput.MetricDataResults, nil
 }
 
+// mergeMetricDataResults merges the results with the same ID, returned in
+// different pages when a query has more data points than MaxDatapoints.
+func mergeMetricDataResults(metricDataResults []types.MetricDataResult) []types.MetricDataResult {
+	mergedResults := make([]types.MetricDataResult, 0, len(metricDataResults))
+	resultIdx := map[string]int{}
+	for _, result := range metricDataResults {
+		if result.Id == nil {
+			mergedResults = append(mergedResults, result)
+			continue
+		}
+
+		idx, ok := resultIdx[*result.Id]
+		if !ok {
+			resultIdx[*result.Id] = len(mergedResults)
+			mergedResults = append(mergedResults, result)
+			continue
+		}
+
+		mergedResults[idx].Timestamps = append(mergedResults[idx].Timestamps, result.Timestamps...)
+		mergedResults[idx].Values = append(mergedResults[idx].Values, result.Values...)
+		mergedResults[idx].Messages = append(mergedResults[idx].Messages, result.Messages...)
+		mergedResults[idx].StatusCode = result.StatusCode
+	}
+	return mergedResults
+}
+
 // CheckTimestampInArray checks if input timestamp exists in timestampArray and if it exists, return the position.
 func CheckTimestampInArray(timestamp time.Time, timestampArray []time.Time) (bool, int) {
 	for i := 0; i < len(timestampArray); i++ {
@@ -163,9 +258,56 @@ func FindTimestamp(getMetricDataResults []types.MetricDataResult) time.Time {
 	return timestamp
 }
 
+// FindLatestTimestamp function returns the most recent timestamp from all MetricDataResults.
+func FindLatestTimestamp(getMetricDataResults []types.MetricDataResult) time.Time {
+	timestamp := time.Time{}
+	for _, output := range getMetricDataResults {
+		for _, t := range output.Timestamps {
+			if t.After(timestamp) {
+				timestamp = t
+			}
+		}
+	}
+	return timestamp
+}
+
+// FindLatestCompleteTimestamp function returns the most recent timestamp that exists
+// in all MetricDataResults with data points, so every metric has a value for it.
+// If there is no such timestamp, a zero timestamp is returned.
+func FindLatestCompleteTimestamp(getMetricDataResults []types.MetricDataResult) time.Time {
+	timestamps := map[int64]time.Time{}
+	counts := map[int64]int{}
+	numberOfResults := 0
+	for _, output := range getMetricDataResults {
+		if len(output.Timestamps) == 0 {
+			continue
+		}
+		numberOfResults++
+
+		seen := map[int64]bool{}
+		for _, t := range output.Timestamps {
+			key := t.UnixNano()
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			timestamps[key] = t
+			counts[key]++
+		}
+	}
+
+	timestamp := time.Time{}
+	for key, count := range counts {
+		if count == numberOfResults && timestamps[key].After(timestamp) {
+			timestamp = timestamps[key]
+		}
+	}
+	return timestamp
+}
+
 // GetResourcesTags function queries AWS resource groupings tagging API
 // to get a resource tag mapping with specific resource type filters
-func GetResourcesTags(svc resourcegroupstaggingapi.GetResourcesAPIClient, resourceTypeFilters []string) (map[string][]resourcegroupstaggingapitypes.Tag, error) {
+func GetResourcesTags(ctx context.Context, svc resourcegroupstaggingapi.GetResourcesAPIClient, resourceTypeFilters []string) (map[string][]resourcegroupstaggingapitypes.Tag, error) {
 	if resourceTypeFilters == nil {
 		return map[string][]resourcegroupstaggingapitypes.Tag{}, nil
 	}
@@ -180,7 +322,7 @@ func GetResourcesTags(svc resourcegroupstaggingapi.GetResourcesAPIClient, resour
 	var err error
 	var page *resourcegroupstaggingapi.GetResourcesOutput
 	for paginator.HasMorePages() {
-		if page, err = paginator.NextPage(context.TODO()); err != nil {
+		if page, err = paginator.NextPage(ctx); err != nil {
 			err = fmt.Errorf("error GetResources with Paginator: %w", err)
 			return nil, err
 		}
@@ -207,11 +349,97 @@ func GetResourcesTags(svc resourcegroupstaggingapi.GetResourcesAPIClient, resour
 	return resourceTagMap, nil
 }
 
+// ConfigAggregatorClient is a client that implements the SelectAggregateResourceConfig
+// operation of AWS Config.
+type ConfigAggregatorClient interface {
+	SelectAggregateResourceConfig(ctx context.Context, params *configservice.SelectAggregateResourceConfigInput, optFns ...func(*configservice.Options)) (*configservice.SelectAggregateResourceConfigOutput, error)
+}
+
+// configAggregatorResource is a single result of the query sent to an AWS Config aggregator.
+type configAggregatorResource struct {
+	ResourceID   string `json:"resourceId"`
+	ResourceName string `json:"resourceName"`
+	ARN          string `json:"arn"`
+	Tags         []struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	} `json:"tags"`
+}
+
+// configQueryValuePattern matches the values allowed in the queries sent to
+// an AWS Config aggregator, which can't be escaped.
+var configQueryValuePattern = regexp.MustCompile(`^[A-Za-z0-9:_-]+$`)
+
+// GetResourcesTagsFromConfigAggregator function queries an AWS Config aggregator
+// to get a resource tag mapping for a specific resource type in the given account
+// and region. The resource type uses the AWS Config format, for example AWS::EC2::Instance.
+// The returned map uses the same keys as GetResourcesTags, plus resource ID and name.
+func GetResourcesTagsFromConfigAggregator(ctx context.Context, svc ConfigAggregatorClient, aggregatorName string, resourceType string, accountID string, regionName string) (map[string][]resourcegroupstaggingapitypes.Tag, error) {
+	if !configQueryValuePattern.MatchString(resourceType) {
+		return nil, fmt.Errorf("invalid resource type %q in AWS Config aggregator query", resourceType)
+	}
+	if !configQueryValuePattern.MatchString(regionName) {
+		return nil, fmt.Errorf("invalid region %q in AWS Config aggregator query", regionName)
+	}
+	if accountID != "" && !configQueryValuePattern.MatchString(accountID) {
+		return nil, fmt.Errorf("invalid account ID %q in AWS Config aggregator query", accountID)
+	}
+
+	resourceTagMap := make(map[string][]resourcegroupstaggingapitypes.Tag)
+	expression := fmt.Sprintf("SELECT resourceId, resourceName, arn, tags WHERE resourceType = '%s' AND awsRegion = '%s'", resourceType, regionName)
+	if accountID != "" {
+		expression += fmt.Sprintf(" AND accountId = '%s'", accountID)
+	}
+
+	input := &configservice.SelectAggregateResourceConfigInput{
+		ConfigurationAggregatorName: &aggregatorName,
+		Expression:                  &expression,
+	}
+
+	paginator := configservice.NewSelectAggregateResourceConfigPaginator(svc, input)
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error SelectAggregateResourceConfig: %w", err)
+		}
+
+		for _, result := range output.Results {
+			var resource configAggregatorResource
+			if err := json.Unmarshal([]byte(result), &resource); err != nil {
+				return nil, fmt.Errorf("error occurs when processing SelectAggregateResourceConfig result: %w", err)
+			}
+
+			tags := make([]resourcegroupstaggingapitypes.Tag, 0, len(resource.Tags))
+			for i := range resource.Tags {
+				tags = append(tags, resourcegroupstaggingapitypes.Tag{
+					Key:   &resource.Tags[i].Key,
+					Value: &resource.Tags[i].Value,
+				})
+			}
+
+			identifiers := []string{resource.ResourceID, resource.ResourceName}
+			if resource.ARN != "" {
+				if shortIdentifier, err := FindShortIdentifierFromARN(resource.ARN); err == nil {
+					identifiers = append(identifiers, shortIdentifier)
+				}
+				if wholeIdentifier, err := FindWholeIdentifierFromARN(resource.ARN); err == nil {
+					identifiers = append(identifiers, wholeIdentifier)
+				}
+			}
+			for _, identifier := range identifiers {
+				if identifier != "" {
+					resourceTagMap[identifier] = tags
+				}
+			}
+		}
+	}
+	return resourceTagMap, nil
+}
+
 // FindShortIdentifierFromARN function extracts short resource id from resource filed of ARN.
 func FindShortIdentifierFromARN(resourceARN string) (string, error) {
-	arnParsed, err := arn.Parse(resourceARN)
+	arnParsed, err := ParseARN(resourceARN)
 	if err != nil {
-		err = fmt.Errorf("error Parse arn: %w", err)
 		return "", err
 	}
 
@@ -230,9 +458,8 @@ func FindShortIdentifierFromARN(resourceARN string) (string, error) {
 
 // FindWholeIdentifierFromARN function extracts whole resource filed of ARN
 func FindWholeIdentifierFromARN(resourceARN string) (string, error) {
-	arnParsed, err := arn.Parse(resourceARN)
+	arnParsed, err := ParseARN(resourceARN)
 	if err != nil {
-		err = fmt.Errorf("error Parse arn: %w", err)
 		return "", err
 	}
 	return arnParsed.Resource, nil
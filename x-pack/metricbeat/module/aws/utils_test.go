@@ -12,6 +12,7 @@ import (
 	awssdk "github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	cloudwatchtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/configservice"
 	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
 	resourcegroupstaggingapitypes "github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
 	"github.com/stretchr/testify/assert"
@@ -137,7 +138,7 @@ func (m *MockResourceGroupsTaggingClient) GetResources(_ context.Context, _ *res
 
 func TestGetListMetricsOutput(t *testing.T) {
 	svcCloudwatch := &MockCloudWatchClient{}
-	listMetricsOutput, err := GetListMetricsOutput("AWS/EC2", "us-west-1", time.Minute*5, svcCloudwatch)
+	listMetricsOutput, err := GetListMetricsOutput(context.Background(), "AWS/EC2", "us-west-1", time.Minute*5, svcCloudwatch)
 	assert.NoError(t, err)
 	assert.Equal(t, 1, len(listMetricsOutput))
 	assert.Equal(t, namespace, *listMetricsOutput[0].Namespace)
@@ -149,7 +150,7 @@ func TestGetListMetricsOutput(t *testing.T) {
 
 func TestGetListMetricsOutputWithWildcard(t *testing.T) {
 	svcCloudwatch := &MockCloudWatchClient{}
-	listMetricsOutput, err := GetListMetricsOutput("*", "us-west-1", time.Minute*5, svcCloudwatch)
+	listMetricsOutput, err := GetListMetricsOutput(context.Background(), "*", "us-west-1", time.Minute*5, svcCloudwatch)
 	assert.NoError(t, err)
 	assert.Equal(t, 1, len(listMetricsOutput))
 	assert.Equal(t, namespace, *listMetricsOutput[0].Namespace)
@@ -209,7 +210,7 @@ func TestGetMetricDataResults(t *testing.T) {
 			MetricStat: &metricStat,
 		},
 	}
-	getMetricDataResults, err := GetMetricDataResults(metricDataQueries, mockSvc, startTime, endTime)
+	getMetricDataResults, err := GetMetricDataResults(context.Background(), metricDataQueries, mockSvc, startTime, endTime)
 	assert.NoError(t, err)
 
 	assert.Equal(t, 4, len(getMetricDataResults))
@@ -230,6 +231,27 @@ func TestGetMetricDataResults(t *testing.T) {
 	assert.Equal(t, 0.0, getMetricDataResults[3].Values[0])
 }
 
+func TestMergeMetricDataResults(t *testing.T) {
+	timestamp1 := time.Date(2022, 8, 15, 13, 30, 0, 0, time.UTC)
+	timestamp2 := timestamp1.Add(-time.Minute)
+	timestamp3 := timestamp1.Add(-2 * time.Minute)
+
+	metricDataResults := []cloudwatchtypes.MetricDataResult{
+		{Id: &id1, Label: &label1, Timestamps: []time.Time{timestamp1, timestamp2}, Values: []float64{1, 2}, StatusCode: cloudwatchtypes.StatusCodePartialData},
+		{Id: &id2, Label: &label2, Timestamps: []time.Time{timestamp1}, Values: []float64{10}, StatusCode: cloudwatchtypes.StatusCodeComplete},
+		{Id: &id1, Label: &label1, Timestamps: []time.Time{timestamp3}, Values: []float64{3}, StatusCode: cloudwatchtypes.StatusCodeComplete},
+	}
+
+	mergedResults := mergeMetricDataResults(metricDataResults)
+	assert.Equal(t, 2, len(mergedResults))
+	assert.Equal(t, id1, *mergedResults[0].Id)
+	assert.Equal(t, []time.Time{timestamp1, timestamp2, timestamp3}, mergedResults[0].Timestamps)
+	assert.Equal(t, []float64{1, 2, 3}, mergedResults[0].Values)
+	assert.Equal(t, cloudwatchtypes.StatusCodeComplete, mergedResults[0].StatusCode)
+	assert.Equal(t, id2, *mergedResults[1].Id)
+	assert.Equal(t, []float64{10}, mergedResults[1].Values)
+}
+
 func TestCheckTimestampInArray(t *testing.T) {
 	timestamp1 := time.Now()
 	timestamp2 := timestamp1.Add(5 * time.Minute)
@@ -335,6 +357,55 @@ func TestFindTimestamp(t *testing.T) {
 	}
 }
 
+func TestFindLatestTimestamp(t *testing.T) {
+	timestamp1 := time.Date(2019, 3, 11, 17, 45, 0, 0, time.UTC)
+	timestamp2 := time.Date(2019, 3, 11, 17, 40, 0, 0, time.UTC)
+	timestamp3 := time.Date(2019, 3, 11, 17, 35, 0, 0, time.UTC)
+
+	cases := []struct {
+		title                  string
+		getMetricDataResults   []cloudwatchtypes.MetricDataResult
+		expectedLatest         time.Time
+		expectedLatestComplete time.Time
+	}{
+		{
+			"all metrics share the latest timestamp",
+			[]cloudwatchtypes.MetricDataResult{
+				{Timestamps: []time.Time{timestamp1, timestamp2}},
+				{Timestamps: []time.Time{timestamp1}},
+			},
+			timestamp1,
+			timestamp1,
+		},
+		{
+			"one metric is published with a delay",
+			[]cloudwatchtypes.MetricDataResult{
+				{Timestamps: []time.Time{timestamp1, timestamp2, timestamp3}},
+				{Timestamps: []time.Time{timestamp2, timestamp3}},
+				{},
+			},
+			timestamp1,
+			timestamp2,
+		},
+		{
+			"no common timestamp",
+			[]cloudwatchtypes.MetricDataResult{
+				{Timestamps: []time.Time{timestamp1}},
+				{Timestamps: []time.Time{timestamp3}},
+			},
+			timestamp1,
+			time.Time{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			assert.Equal(t, c.expectedLatest, FindLatestTimestamp(c.getMetricDataResults))
+			assert.Equal(t, c.expectedLatestComplete, FindLatestCompleteTimestamp(c.getMetricDataResults))
+		})
+	}
+}
+
 func TestFindIdentifierFromARN(t *testing.T) {
 	cases := []struct {
 		resourceARN             string
@@ -387,7 +458,7 @@ func TestFindIdentifierFromARN(t *testing.T) {
 
 func TestGetResourcesTags(t *testing.T) {
 	mockSvc := &MockResourceGroupsTaggingClient{}
-	resourceTagMap, err := GetResourcesTags(mockSvc, []string{"rds"})
+	resourceTagMap, err := GetResourcesTags(context.Background(), mockSvc, []string{"rds"})
 	assert.NoError(t, err)
 	assert.Equal(t, 4, len(resourceTagMap))
 
@@ -435,6 +506,81 @@ func TestGetResourcesTags(t *testing.T) {
 	assert.Equal(t, expectedResourceTagMap, resourceTagMap)
 }
 
+// MockConfigAggregatorClient is used for unit tests.
+type MockConfigAggregatorClient struct {
+	expressions []string
+}
+
+// SelectAggregateResourceConfig implements ConfigAggregatorClient.
+func (m *MockConfigAggregatorClient) SelectAggregateResourceConfig(_ context.Context, params *configservice.SelectAggregateResourceConfigInput, _ ...func(*configservice.Options)) (*configservice.SelectAggregateResourceConfigOutput, error) {
+	m.expressions = append(m.expressions, *params.Expression)
+	if params.NextToken == nil {
+		return &configservice.SelectAggregateResourceConfigOutput{
+			Results: []string{
+				`{"resourceId":"i-1","resourceName":"","arn":"arn:aws:ec2:eu-west-1:123456789012:instance/i-1","tags":[{"key":"organization","value":"engineering"}]}`,
+			},
+			NextToken: awssdk.String("token"),
+		}, nil
+	}
+	return &configservice.SelectAggregateResourceConfigOutput{
+		Results: []string{
+			`{"resourceId":"i-2","resourceName":"web","tags":[{"key":"organization","value":"finance"}]}`,
+		},
+	}, nil
+}
+
+func TestGetResourcesTagsFromConfigAggregator(t *testing.T) {
+	mockSvc := &MockConfigAggregatorClient{}
+	resourceTagMap, err := GetResourcesTagsFromConfigAggregator(context.Background(), mockSvc, "aggregator", "AWS::EC2::Instance", "123456789012", "eu-west-1")
+	assert.NoError(t, err)
+
+	engineeringTags := []resourcegroupstaggingapitypes.Tag{
+		{
+			Key:   awssdk.String("organization"),
+			Value: awssdk.String("engineering"),
+		},
+	}
+	financeTags := []resourcegroupstaggingapitypes.Tag{
+		{
+			Key:   awssdk.String("organization"),
+			Value: awssdk.String("finance"),
+		},
+	}
+	expectedResourceTagMap := map[string][]resourcegroupstaggingapitypes.Tag{
+		"i-1":          engineeringTags,
+		"instance/i-1": engineeringTags,
+		"i-2":          financeTags,
+		"web":          financeTags,
+	}
+	assert.Equal(t, expectedResourceTagMap, resourceTagMap)
+
+	expression := "SELECT resourceId, resourceName, arn, tags WHERE resourceType = 'AWS::EC2::Instance' AND awsRegion = 'eu-west-1' AND accountId = '123456789012'"
+	assert.Equal(t, []string{expression, expression}, mockSvc.expressions)
+}
+
+func TestGetResourcesTagsFromConfigAggregatorInvalidValues(t *testing.T) {
+	cases := []struct {
+		title        string
+		resourceType string
+		accountID    string
+		regionName   string
+	}{
+		{"resource type", "AWS::EC2::Instance' OR resourceType LIKE '%", "123456789012", "eu-west-1"},
+		{"account ID", "AWS::EC2::Instance", "123456789012' OR accountId LIKE '%", "eu-west-1"},
+		{"region", "AWS::EC2::Instance", "123456789012", "eu-west-1'"},
+		{"empty region", "AWS::EC2::Instance", "123456789012", ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			mockSvc := &MockConfigAggregatorClient{}
+			_, err := GetResourcesTagsFromConfigAggregator(context.Background(), mockSvc, "aggregator", c.resourceType, c.accountID, c.regionName)
+			assert.Error(t, err)
+			assert.Empty(t, mockSvc.expressions)
+		})
+	}
+}
+
 func parseTime(t *testing.T, in string) time.Time {
 	time, err := time.Parse(time.RFC3339, in)
 	if err != nil {
@@ -486,6 +632,66 @@ func TestGetStartTimeEndTime(t *testing.T) {
 	}
 }
 
+func TestGetStartTimeEndTimeInLocation(t *testing.T) {
+	var cases = []struct {
+		title         string
+		timezone      string
+		now           string
+		period        time.Duration
+		latency       time.Duration
+		expectedStart string
+		expectedEnd   string
+	}{
+		{"UTC, 1 day", "+0000", "2022-08-15T13:38:45Z", time.Hour * 24, 0, "2022-08-14T00:00:00Z", "2022-08-15T00:00:00Z"},
+		{"+0200, 1 day", "+0200", "2022-08-15T13:38:45Z", time.Hour * 24, 0, "2022-08-13T22:00:00Z", "2022-08-14T22:00:00Z"},
+		{"-0500, 1 day", "-0500", "2022-08-15T13:38:45Z", time.Hour * 24, 0, "2022-08-14T05:00:00Z", "2022-08-15T05:00:00Z"},
+		{"+0130, 1 hour", "+0130", "2022-08-15T13:38:45Z", time.Hour, 0, "2022-08-15T12:30:00Z", "2022-08-15T13:30:00Z"},
+		{"+0200, 5 minutes, 4 minutes latency", "+0200", "2022-08-15T23:38:45Z", time.Second * 60 * 5, time.Second * 60 * 4, "2022-08-15T23:25:00Z", "2022-08-15T23:30:00Z"},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.title, func(t *testing.T) {
+			now := parseTime(t, tt.now)
+			location, err := ParseLabelTimezone(tt.timezone)
+			assert.NoError(t, err)
+
+			start, end := GetStartTimeEndTimeInLocation(now, tt.period, tt.latency, location)
+			assert.True(t, parseTime(t, tt.expectedStart).Equal(start), "got start %s, want %s", start, tt.expectedStart)
+			assert.True(t, parseTime(t, tt.expectedEnd).Equal(end), "got end %s, want %s", end, tt.expectedEnd)
+		})
+	}
+}
+
+func TestParseLabelTimezone(t *testing.T) {
+	cases := []struct {
+		timezone       string
+		expectedOffset int
+		expectedErr    bool
+	}{
+		{"+0000", 0, false},
+		{"+0130", 5400, false},
+		{"-0800", -28800, false},
+		{"0800", 0, true},
+		{"+080", 0, true},
+		{"+2500", 0, true},
+		{"+0060", 0, true},
+		{"+ab00", 0, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.timezone, func(t *testing.T) {
+			location, err := ParseLabelTimezone(c.timezone)
+			if c.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			_, offset := time.Date(2022, 8, 15, 0, 0, 0, 0, location).Zone()
+			assert.Equal(t, c.expectedOffset, offset)
+		})
+	}
+}
+
 func TestGetStartTimeEndTime_AlwaysCreatesContinuousIntervals(t *testing.T) {
 	type interval struct {
 		start, end string